@@ -0,0 +1,170 @@
+// Package cache caches encoded values by key, LRU keeps them in memory of the process and other
+// implementations of Cache may share them between instances
+package cache
+
+import (
+	"container/list"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Namespaces of cached values
+const (
+	// NamespaceBooks caches catalogue reads, it is invalidated by every change of books
+	NamespaceBooks = "books"
+)
+
+// Cache stores values for limited time, values may be evicted earlier
+type Cache interface {
+	// Get returns value of key and whether it was found
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value of key for ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes key, deleting missing key is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// LRU is Cache of the process evicting least recently used values above its capacity
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[string]*list.Element
+	now      func() time.Time
+}
+
+type entry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRU returns empty LRU of capacity values
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+// Get implements Cache
+func (c *LRU) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	e := element.Value.(*entry)
+	if !c.now().Before(e.expires) {
+		c.remove(element)
+		return nil, false, nil
+	}
+	c.order.MoveToFront(element)
+	return e.value, true, nil
+}
+
+// Set implements Cache
+func (c *LRU) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(ttl)
+	if element, ok := c.items[key]; ok {
+		e := element.Value.(*entry)
+		e.value, e.expires = value, expires
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.items[key] = c.order.PushFront(&entry{key: key, value: value, expires: expires})
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// Delete implements Cache
+func (c *LRU) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.remove(element)
+	}
+	return nil
+}
+
+// Len returns number of stored values, including expired ones not evicted yet
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRU) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.items, element.Value.(*entry).key)
+}
+
+// versionTTL keeps version of namespace longer than its values, losing it only misses them
+const versionTTL = 24 * time.Hour
+
+// Namespace prefixes keys by version of the namespace kept in cache, so that Invalidate drops all of them
+// at once without listing keys, which shared caches may not support. Namespace of nil Cache caches nothing.
+type Namespace struct {
+	cache Cache
+	name  string
+}
+
+// NewNamespace returns namespace name of c
+func NewNamespace(c Cache, name string) Namespace {
+	return Namespace{cache: c, name: name}
+}
+
+// Key returns key of current version of the namespace
+func (n Namespace) Key(ctx context.Context, key string) (string, error) {
+	versionKey := n.name + ":version"
+	version, ok, err := n.cache.Get(ctx, versionKey)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		// random version keeps values of a version lost by eviction from coming back
+		version = newVersion()
+		if err := n.cache.Set(ctx, versionKey, version, versionTTL); err != nil {
+			return "", err
+		}
+	}
+	return n.name + ":" + string(version) + ":" + key, nil
+}
+
+// Get returns value of key returned by Key
+func (n Namespace) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return n.cache.Get(ctx, key)
+}
+
+// Set stores value of key returned by Key
+func (n Namespace) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return n.cache.Set(ctx, key, value, ttl)
+}
+
+// Invalidate drops values of the namespace, they expire from cache by their TTL
+func (n Namespace) Invalidate(ctx context.Context) error {
+	if n.cache == nil {
+		return nil
+	}
+	return n.cache.Set(ctx, n.name+":version", newVersion(), versionTTL)
+}
+
+func newVersion() []byte {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return []byte(hex.EncodeToString(b))
+}
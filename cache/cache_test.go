@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	c := NewLRU(2)
+	c.now = func() time.Time { return now }
+
+	_ = c.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = c.Set(ctx, "b", []byte("2"), time.Second)
+	// reading a makes b least recently used
+	if value, ok, _ := c.Get(ctx, "a"); !ok || string(value) != "1" {
+		t.Errorf("Get(a) returns %q, %v\n expected %q, true", value, ok, "1")
+	}
+	_ = c.Set(ctx, "c", []byte("3"), time.Minute)
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Errorf("Get(b) found value evicted above capacity")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() returns %d\n expected %d", c.Len(), 2)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Errorf("Get(a) found expired value")
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len() returns %d\n expected %d", c.Len(), 1)
+	}
+
+	_ = c.Delete(ctx, "c")
+	if _, ok, _ := c.Get(ctx, "c"); ok {
+		t.Errorf("Get(c) found deleted value")
+	}
+}
+
+func TestNamespaceInvalidate(t *testing.T) {
+	ctx := context.TODO()
+	c := NewLRU(10)
+	books := NewNamespace(c, NamespaceBooks)
+	other := NewNamespace(c, "other")
+
+	bookKey, _ := books.Key(ctx, "all")
+	otherKey, _ := other.Key(ctx, "all")
+	_ = books.Set(ctx, bookKey, []byte("books"), time.Minute)
+	_ = other.Set(ctx, otherKey, []byte("other"), time.Minute)
+
+	if key, _ := books.Key(ctx, "all"); key != bookKey {
+		t.Errorf("Key() returns %q\n expected %q", key, bookKey)
+	}
+	if err := books.Invalidate(ctx); err != nil {
+		t.Fatalf("Invalidate() got error %v", err)
+	}
+
+	key, _ := books.Key(ctx, "all")
+	if key == bookKey {
+		t.Errorf("Key() returns %q of invalidated version", key)
+	}
+	if _, ok, _ := books.Get(ctx, key); ok {
+		t.Errorf("Get() found value of invalidated namespace")
+	}
+	if value, ok, _ := other.Get(ctx, otherKey); !ok || string(value) != "other" {
+		t.Errorf("Get() of other namespace returns %q, %v\n expected %q, true", value, ok, "other")
+	}
+
+	if err := NewNamespace(nil, NamespaceBooks).Invalidate(ctx); err != nil {
+		t.Errorf("Invalidate() of nil cache got error %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/zap"
+
+	"book-management-system/configs"
+	"book-management-system/entities/constants"
+	"book-management-system/lifecycle"
+	"book-management-system/logging"
+	"book-management-system/repositories"
+	"book-management-system/usecases"
+)
+
+// appName is binary name shown in usage
+const appName = "book_management_system"
+
+// commandShutdownTimeout bounds how long commands other than serve wait for background tasks before exiting
+const commandShutdownTimeout = 15 * time.Second
+
+// Exit codes of commands
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// command is subcommand of the binary
+type command struct {
+	name    string
+	summary string
+	// run executes command with arguments following its name and returns exit code
+	run func(args []string) int
+}
+
+// commands returns all subcommands in order shown by help
+func commands() []command {
+	return []command{
+		{name: "serve", summary: "serve REST API", run: runServe},
+		{name: "migrate", summary: "apply, roll back, list or create database migrations", run: runMigrate},
+		{name: "import", summary: "import books from csv, jsonl, marc or marcxml file", run: runImport},
+		{name: "export", summary: "export books as csv, jsonl or marc", run: runExport},
+		{name: "reindex", summary: "index all stored books into search backend", run: runReindex},
+		{name: "seed", summary: "generate demo books and members", run: runSeed},
+		{name: "duplicates", summary: "detect duplicate books for review", run: runDetectDuplicates},
+		{name: "config", summary: "print effective config with secrets redacted or validate it", run: runConfig},
+		{name: "member", summary: "list, add and rename library members", run: runMember},
+		{name: "version", summary: "print version", run: runVersion},
+	}
+}
+
+// run parses global flags and runs the command named by the first other argument
+func run(args []string) int {
+	flags := flag.NewFlagSet(appName, flag.ContinueOnError)
+	configFile := flags.String("config", "", "config file in json, yaml or toml format (default "+configs.DefaultConfigFile+" when present)")
+	logLevel := flags.String("log-level", "", "log level, debug, info, warn or error (default from config, else info)")
+	overrides := overrideFlag{}
+	flags.Var(overrides, "set", "set config key above file and environment, e.g. -set mysql.host=db (repeatable)")
+	flags.Usage = func() {
+		printUsage(flags.Output(), flags)
+	}
+	if err := flags.Parse(args); err != nil {
+		return flagsExitCode(err)
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	name, commandArgs := flags.Arg(0), flags.Args()[1:]
+	if name == "help" {
+		return runHelp(flags, commandArgs)
+	}
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(flags.Output(), "unknown command %q\n\n", name)
+		flags.Usage()
+		return exitUsage
+	}
+
+	switch *logLevel {
+	case "", configs.LogLevelDebug, configs.LogLevelInfo, configs.LogLevelWarn, configs.LogLevelError:
+	default:
+		fmt.Fprintf(flags.Output(), "invalid log level %q\n", *logLevel)
+		return exitUsage
+	}
+
+	configs.SetConfigFile(*configFile)
+	for key, value := range overrides {
+		configs.SetOverride(key, value)
+	}
+	if *logLevel != "" {
+		configs.SetOverride("log.level", *logLevel)
+	}
+
+	return cmd.run(commandArgs)
+}
+
+// runHelp prints usage of the binary or of the named command
+func runHelp(flags *flag.FlagSet, args []string) int {
+	if len(args) == 0 {
+		printUsage(os.Stdout, flags)
+		return exitOK
+	}
+
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		fmt.Fprintf(flags.Output(), "unknown command %q\n", args[0])
+		return exitUsage
+	}
+	// every command prints its usage on -h without touching config or database
+	return cmd.run([]string{"-h"})
+}
+
+func printUsage(w io.Writer, flags *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: %s [global flags] COMMAND [flags] [args]\n\nCommands:\n", appName)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands() {
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.name, cmd.summary)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\nGlobal flags:\n")
+	out := flags.Output()
+	flags.SetOutput(w)
+	flags.PrintDefaults()
+	flags.SetOutput(out)
+	fmt.Fprintf(w, "\nRun '%s help COMMAND' for usage of the command.\n", appName)
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// flagsExitCode returns exit code of failed flag parsing, help request is not a failure
+func flagsExitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	return exitUsage
+}
+
+// overrideFlag collects key=value config overrides
+type overrideFlag map[string]string
+
+func (overrides overrideFlag) String() string {
+	return ""
+}
+
+func (overrides overrideFlag) Set(value string) error {
+	i := strings.Index(value, "=")
+	if i <= 0 {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	overrides[value[:i]] = value[i+1:]
+	return nil
+}
+
+// initUseCase connects configured backends, commands call it after their flags are parsed
+// and defer returned shutdown
+func initUseCase() (useCase *usecases.UseCase, shutdown func()) {
+	repo := repositories.Init()
+	useCase = usecases.Init(repo)
+	reindexStaleSearch(repo, useCase)
+	return useCase, func() { shutdownCommand(repo) }
+}
+
+// shutdownCommand waits for background tasks started by the command, e.g. search indexing of changed books
+// or their cache invalidation, within commandShutdownTimeout, then closes connections of repo
+func shutdownCommand(repo *repositories.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandShutdownTimeout)
+	defer cancel()
+	if err := lifecycle.Wait(ctx); err != nil {
+		logging.Logger(logging.PackageLifecycle).Error("gave up waiting for background tasks", zap.Error(err))
+	}
+	if err := repo.Close(); err != nil {
+		logging.Logger(logging.PackageLifecycle).Error("failed to close repositories", zap.Error(err))
+	}
+}
+
+func runVersion(args []string) int {
+	flags := flag.NewFlagSet("version", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s version\n", appName)
+	}
+	if err := flags.Parse(args); err != nil {
+		return flagsExitCode(err)
+	}
+
+	fmt.Printf("%s %s\n", constants.ServiceName, constants.ServiceVersion)
+	return exitOK
+}
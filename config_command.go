@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"book-management-system/configs"
+)
+
+// runConfig prints effective config as JSON, secrets redacted, or validates it.
+// It returns exit code, non-zero when the command failed.
+func runConfig(args []string) int {
+	flags := flag.NewFlagSet("config", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s config print|validate\n", appName)
+		fmt.Fprintf(flags.Output(), "\nConfig layers, each overriding the previous one: defaults, -config file, "+
+			"%s_* environment variables (%s_*_FILE reads value from file), -set and -log-level flags.\n",
+			configs.EnvPrefix, configs.EnvPrefix)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return flagsExitCode(err)
+	}
+	if flags.NArg() != 1 || (flags.Arg(0) != "print" && flags.Arg(0) != "validate") {
+		flags.Usage()
+		return exitUsage
+	}
+
+	// invalid config is fatal in GetConfig
+	cfg := configs.GetConfig()
+	if flags.Arg(0) == "validate" {
+		fmt.Println("config is valid")
+		return exitOK
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(cfg.Redacted()); err != nil {
+		fmt.Fprintf(os.Stderr, "failed print config: %s\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
@@ -4,21 +4,118 @@ package configs
 import (
 	"log"
 	"sync"
-
-	"github.com/spf13/viper"
+	"sync/atomic"
 )
 
 var (
-	configs Configs
-	once    sync.Once
+	// current holds *Configs, replaced as a whole by Reload
+	current    atomic.Value
+	configFile string
+	overrides  = make(map[string]string)
+	once       sync.Once
+)
+
+// Database backends
+const (
+	DatabaseMySQL    = "mysql"
+	DatabasePostgres = "postgres"
+	DatabaseSQLite   = "sqlite"
+	DatabaseMemory   = "memory"
+)
+
+// Search backends
+const (
+	SearchElasticSearch = "elasticsearch"
+	SearchPostgres      = "postgres"
+	SearchMemory        = "memory"
+)
+
+// Log levels
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// Log formats
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+// Tracing exporters
+const (
+	TracingExporterNone   = "none"
+	TracingExporterOTLP   = "otlp"
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
 )
 
 // Configs consists all configuration
 type Configs struct {
 	Production    bool
+	Log           LogConfig
+	Tracing       TracingConfig
 	Server        ServerConfig
+	Backend       BackendConfig
 	Mysql         MySQLConfig
+	Postgres      PostgresConfig
+	SQLite        SQLiteConfig
 	ElasticSearch ESConfig
+	Idempotency   IdempotencyConfig
+	RateLimit     RateLimitConfig
+	CORS          CORSConfig
+	Cache         CacheConfig
+	Events        EventsConfig
+	Webhooks      WebhooksConfig
+}
+
+// LogConfig consists logging configuration
+type LogConfig struct {
+	// Level is debug, info, warn or error, info when empty
+	Level string
+	// Format is json or text, json in production and text otherwise when empty
+	Format string
+	// Packages sets level of package loggers like rest, services, pipelines, repositories, gorm or access
+	Packages map[string]string
+	Sampling LogSamplingConfig
+}
+
+// LogSamplingConfig limits access log lines of the same level per second,
+// first Initial are logged and then every Thereafter-th. Zero Initial logs all.
+type LogSamplingConfig struct {
+	Initial    int
+	Thereafter int
+}
+
+// LevelOf returns level of package logger pkg
+func (cfg LogConfig) LevelOf(pkg string) string {
+	if level, ok := cfg.Packages[pkg]; ok {
+		return level
+	}
+	return cfg.Level
+}
+
+// TracingConfig consists OpenTelemetry tracing configuration of serve command
+type TracingConfig struct {
+	// Exporter is none, otlp, stdout or file
+	Exporter string
+	// Endpoint is host:port of OTLP/HTTP collector, e.g. localhost:4318
+	Endpoint string
+	Insecure bool
+	// File is path spans are appended to as JSON by file exporter
+	File string
+	// SampleRatio is fraction of traces started here that are sampled, from 0 to 1
+	SampleRatio float64
+}
+
+// BackendConfig selects repository implementations, MySQL and ElasticSearch when empty.
+// SQLite and memory backends need no external service and are meant for local development and tests.
+// Postgres search backend uses full-text search of the Postgres database backend.
+type BackendConfig struct {
+	Database string
+	Search   string
 }
 
 // ServerConfig consists server configuration
@@ -27,6 +124,12 @@ type ServerConfig struct {
 	WriteTimeout int
 	ReadTimeout  int
 	IdleTimeout  int
+	// MaxBodyBytes limits request bodies of JSON routes
+	MaxBodyBytes int
+	// MaxImportBytes limits request bodies of book import routes
+	MaxImportBytes int
+	// HSTSMaxAge is max-age of Strict-Transport-Security header in seconds, zero omits the header
+	HSTSMaxAge int
 }
 
 // MySQLConfig consists MySQL database configuration
@@ -41,6 +144,25 @@ type MySQLConfig struct {
 	ConnMaxLifetime int
 }
 
+// PostgresConfig consists PostgreSQL database configuration
+type PostgresConfig struct {
+	Host            string
+	Port            string
+	User            string
+	Pass            string
+	Name            string
+	SSLMode         string
+	MaxIdleConn     int
+	MaxOpenConn     int
+	ConnMaxLifetime int
+}
+
+// SQLiteConfig consists SQLite database configuration
+type SQLiteConfig struct {
+	// Path is database file, in-memory database when empty
+	Path string
+}
+
 // ESConfig consists ElasticSearch configuration
 type ESConfig struct {
 	Address  string
@@ -49,21 +171,104 @@ type ESConfig struct {
 	Password string
 }
 
-// GetConfig return Configs object read from config.json file
+// IdempotencyConfig consists Idempotency-Key handling configuration
+type IdempotencyConfig struct {
+	TTL         int
+	WaitTimeout int
+}
+
+// RateLimitConfig consists request rate limits per client, applied by token buckets per route class
+type RateLimitConfig struct {
+	Enabled bool
+	// TrustedProxies are IPs or CIDRs of proxies whose X-Forwarded-For and X-Real-IP headers name the client
+	TrustedProxies []string
+	// APIKeys are X-API-Key header values limited per key instead of per client IP
+	APIKeys []string
+	// Search limits listing and searching books and export
+	Search RateLimitRule
+	// Read limits other GET requests
+	Read RateLimitRule
+	// Write limits POST, PUT, PATCH and DELETE requests
+	Write RateLimitRule
+}
+
+// RateLimitRule refills bucket of Burst requests by Rate requests per second, zero Rate means no limit
+type RateLimitRule struct {
+	Rate  float64
+	Burst int
+}
+
+// CORSConfig consists cross-origin resource sharing configuration of browser clients
+type CORSConfig struct {
+	// AllowedOrigins are origins like https://app.example.com allowed to call the API, * allows any.
+	// Empty list disables CORS.
+	AllowedOrigins []string
+	AllowedMethods []string
+	// AllowedHeaders are request headers clients may send besides CORS-safelisted ones
+	AllowedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache preflight responses in seconds
+	MaxAge int
+}
+
+// CacheConfig consists caching configuration of catalogue reads
+type CacheConfig struct {
+	Enabled bool
+	// Size is number of cached values kept in memory
+	Size int
+	// TTL is how long values are cached in seconds
+	TTL int
+	// MaxAge is max-age of Cache-Control header of catalogue responses in seconds,
+	// zero makes clients revalidate every time with If-Modified-Since
+	MaxAge int
+}
+
+// EventsConfig consists configuration of the change feed served by /v1/events
+type EventsConfig struct {
+	// LogSize is number of last events kept for subscribers resuming with Last-Event-ID
+	LogSize int
+	// Buffer is number of events a subscriber may fall behind before it is disconnected
+	Buffer int
+	// Heartbeat is interval of keep-alive messages on idle streams in seconds
+	Heartbeat int
+}
+
+// WebhooksConfig consists delivery configuration of webhooks managed by /v1/webhooks
+type WebhooksConfig struct {
+	// Workers is number of deliveries sent at once
+	Workers int
+	// Timeout limits one delivery request in seconds
+	Timeout int
+	// MaxAttempts is number of failed attempts before delivery is moved to dead letters
+	MaxAttempts int
+	// Backoff is delay before first retry in seconds, it doubles with every next retry up to MaxBackoff
+	Backoff    int
+	MaxBackoff int
+}
+
+// SetConfigFile sets file GetConfig reads instead of DefaultConfigFile, it has no effect once config is read
+func SetConfigFile(path string) {
+	configFile = path
+}
+
+// SetOverride sets value of key like "mysql.host" above all other layers, it has no effect once config is read
+func SetOverride(key, value string) {
+	overrides[key] = value
+}
+
+// GetConfig return Configs object loaded from defaults, config file, environment variables and overrides.
+// Invalid config is fatal. Returned object must not be modified, Reload replaces it with a new one.
 func GetConfig() *Configs {
 	once.Do(func() {
-		conf := viper.New()
-		conf.SetConfigFile("./configs/config.json")
-
-		err := conf.ReadInConfig()
+		cfg, err := Load(configFile, overrides)
 		if err != nil {
-			log.Fatalf("failed to read config file: %s", err)
+			log.Fatalf("failed to load config: %s", err)
 		}
-
-		if err := conf.Unmarshal(&configs); err != nil {
-			log.Fatalf("failed to unmarshal config: %s", err)
+		if err := cfg.Validate(); err != nil {
+			log.Fatal(err)
 		}
+		current.Store(cfg)
 	})
 
-	return &configs
+	return current.Load().(*Configs)
 }
@@ -0,0 +1,330 @@
+package configs
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	withMySQL := func(host, pass string) Configs {
+		cfg := Defaults()
+		cfg.Mysql.Host = host
+		cfg.Mysql.Pass = pass
+		return cfg
+	}
+
+	tests := []struct {
+		name      string
+		file      string
+		env       map[string]string
+		overrides map[string]string
+		output    Configs
+	}{
+		{
+			name:   "defaults",
+			output: Defaults(),
+		},
+		{
+			name:   "json file",
+			file:   writeFile(t, "config.json", `{"Mysql": {"Host": "json", "Pass": "secret"}}`),
+			output: withMySQL("json", "secret"),
+		},
+		{
+			name:   "yaml file",
+			file:   writeFile(t, "config.yaml", "mysql:\n  host: yaml\n"),
+			output: withMySQL("yaml", ""),
+		},
+		{
+			name:   "toml file",
+			file:   writeFile(t, "config.toml", "[mysql]\nhost = \"toml\"\n"),
+			output: withMySQL("toml", ""),
+		},
+		{
+			name:   "environment over file",
+			file:   writeFile(t, "config.json", `{"Mysql": {"Host": "json", "Pass": "secret"}}`),
+			env:    map[string]string{"BMS_MYSQL_HOST": "env"},
+			output: withMySQL("env", "secret"),
+		},
+		{
+			name:   "environment value from file",
+			env:    map[string]string{"BMS_MYSQL_PASS_FILE": writeFile(t, "pass", "mounted\n")},
+			output: withMySQL("localhost", "mounted"),
+		},
+		{
+			name:      "override over environment",
+			env:       map[string]string{"BMS_MYSQL_HOST": "env"},
+			overrides: map[string]string{"MySQL.Host": "flag"},
+			output:    withMySQL("flag", ""),
+		},
+		{
+			name: "list from environment",
+			env:  map[string]string{"BMS_RATELIMIT_TRUSTEDPROXIES": "10.0.0.0/8,127.0.0.1"},
+			output: func() Configs {
+				cfg := Defaults()
+				cfg.RateLimit.TrustedProxies = []string{"10.0.0.0/8", "127.0.0.1"}
+				return cfg
+			}(),
+		},
+		{
+			name:      "package log level",
+			file:      writeFile(t, "config.json", `{"Log": {"Packages": {"gorm": "debug"}}}`),
+			overrides: map[string]string{"log.packages.access": "warn"},
+			output: func() Configs {
+				cfg := Defaults()
+				cfg.Log.Packages = map[string]string{"gorm": "debug", "access": "warn"}
+				return cfg
+			}(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+
+			got, err := Load(test.file, test.overrides)
+			if err != nil {
+				t.Fatalf("Load() got error %v", err)
+			}
+			if !reflect.DeepEqual(*got, test.output) {
+				t.Errorf("Load() got %+v\n expected %+v", *got, test.output)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := Load(filepath.Join(t.TempDir(), "missing.json"), nil); err == nil {
+			t.Error("Load() of missing file got no error")
+		}
+	})
+
+	t.Run("misspelled key in file", func(t *testing.T) {
+		if _, err := Load(writeFile(t, "config.json", `{"Mysql": {"Hots": "db"}}`), nil); err == nil {
+			t.Error("Load() got no error")
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		if _, err := Load("", map[string]string{"mysql.hots": "db"}); !errors.Is(err, ErrUnknownKey) {
+			t.Errorf("Load() got error %v\n expected %v", err, ErrUnknownKey)
+		}
+	})
+
+	t.Run("value and file both set", func(t *testing.T) {
+		t.Setenv("BMS_MYSQL_PASS", "env")
+		t.Setenv("BMS_MYSQL_PASS_FILE", writeFile(t, "pass", "mounted"))
+		if _, err := Load("", nil); err == nil {
+			t.Error("Load() got no error")
+		}
+	})
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Defaults()
+	cfg.Mysql.User = "user"
+	cfg.Mysql.Pass = "secret"
+	cfg.ElasticSearch.Password = "secret"
+	cfg.RateLimit.APIKeys = []string{"key"}
+
+	got := cfg.Redacted()
+	if got.Mysql.Pass != redacted || got.ElasticSearch.Password != redacted || got.RateLimit.APIKeys[0] != redacted {
+		t.Errorf("Redacted() got %+v\n expected secrets replaced", got)
+	}
+	if got.Mysql.User != "user" || got.Postgres.Pass != "" {
+		t.Errorf("Redacted() got %+v\n expected other fields unchanged", got)
+	}
+	if cfg.Mysql.Pass != "secret" || cfg.RateLimit.APIKeys[0] != "key" {
+		t.Errorf("Redacted() modified original config")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  func(cfg *Configs)
+		output ValidationError
+	}{
+		{
+			name:  "defaults",
+			input: func(cfg *Configs) {},
+		},
+		{
+			name: "zero timeouts and empty host",
+			input: func(cfg *Configs) {
+				cfg.Server.ReadTimeout = 0
+				cfg.Mysql.Host = ""
+				cfg.Mysql.Port = "port"
+			},
+			output: ValidationError{
+				"server.readtimeout must be positive, got 0",
+				"mysql.host is required",
+				`mysql.port must be number from 1 to 65535, got "port"`,
+			},
+		},
+		{
+			name: "unknown backends and log level",
+			input: func(cfg *Configs) {
+				cfg.Log.Level = "verbose"
+				cfg.Backend.Database = "oracle"
+				cfg.Backend.Search = SearchMemory
+			},
+			output: ValidationError{
+				`log.level must be one of debug, info, warn, error, got "verbose"`,
+				`backend.database must be one of mysql, postgres, sqlite, memory, got "oracle"`,
+			},
+		},
+		{
+			name: "rate limits",
+			input: func(cfg *Configs) {
+				cfg.RateLimit.TrustedProxies = []string{"10.0.0.0/8", "proxy"}
+				cfg.RateLimit.Search = RateLimitRule{Rate: 1}
+				cfg.RateLimit.Write = RateLimitRule{Rate: -1}
+				cfg.RateLimit.Read = RateLimitRule{}
+			},
+			output: ValidationError{
+				`ratelimit.trustedproxies[1] must be IP or CIDR, got "proxy"`,
+				"ratelimit.search.burst must be positive when rate is set, got 0",
+				"ratelimit.write.rate must not be negative, got -1",
+			},
+		},
+		{
+			name: "body limits and CORS",
+			input: func(cfg *Configs) {
+				cfg.Server.MaxBodyBytes = 0
+				cfg.Server.HSTSMaxAge = -1
+				cfg.CORS.AllowedOrigins = []string{"https://app.example.com", "*", "app.example.com", "https://app.example.com/path"}
+				cfg.CORS.AllowCredentials = true
+				cfg.CORS.AllowedMethods = []string{"GET", "TRACE"}
+			},
+			output: ValidationError{
+				"server.maxbodybytes must be positive, got 0",
+				"server.hstsmaxage must not be negative, got -1",
+				`cors.allowedorigins[1] must name origin when cors.allowcredentials is set, got "*"`,
+				`cors.allowedorigins[2] must be * or origin like https://app.example.com, got "app.example.com"`,
+				`cors.allowedorigins[3] must be * or origin like https://app.example.com, got "https://app.example.com/path"`,
+				`cors.allowedmethods[1] must be one of GET, HEAD, POST, PUT, PATCH, DELETE, got "TRACE"`,
+			},
+		},
+		{
+			name: "log format and package level",
+			input: func(cfg *Configs) {
+				cfg.Log.Format = "xml"
+				cfg.Log.Packages = map[string]string{"gorm": "debug", "access": "all"}
+				cfg.Log.Sampling.Initial = -1
+			},
+			output: ValidationError{
+				`log.format must be one of json, text, got "xml"`,
+				`log.packages.access must be one of debug, info, warn, error, got "all"`,
+				"log.sampling.initial must not be negative, got -1",
+			},
+		},
+		{
+			name: "postgres search of mysql database",
+			input: func(cfg *Configs) {
+				cfg.Backend.Search = SearchPostgres
+			},
+			output: ValidationError{`backend.search "postgres" needs backend.database "postgres", got "mysql"`},
+		},
+		{
+			name: "postgres without elasticsearch",
+			input: func(cfg *Configs) {
+				cfg.Backend = BackendConfig{Database: DatabasePostgres, Search: SearchPostgres}
+				cfg.Mysql = MySQLConfig{}
+				cfg.ElasticSearch = ESConfig{}
+				cfg.Postgres.SSLMode = "on"
+				cfg.Postgres.MaxOpenConn = -1
+			},
+			output: ValidationError{
+				`postgres.sslmode must be one of disable, allow, prefer, require, verify-ca, verify-full, got "on"`,
+				"postgres.maxopenconn must not be negative, got -1",
+			},
+		},
+		{
+			name: "elasticsearch",
+			input: func(cfg *Configs) {
+				cfg.ElasticSearch = ESConfig{Address: "localhost:9200", IsAuth: true}
+			},
+			output: ValidationError{
+				`elasticsearch.address must be URL like http://localhost:9200, got "localhost:9200"`,
+				"elasticsearch.username is required",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := Defaults()
+			test.input(&cfg)
+
+			err := cfg.Validate()
+			if test.output == nil {
+				if err != nil {
+					t.Errorf("Validate() got error %v", err)
+				}
+				return
+			}
+			var got ValidationError
+			if !errors.As(err, &got) || !reflect.DeepEqual(got, test.output) {
+				t.Errorf("Validate() got error %v\n expected %v", err, test.output)
+			}
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	file := writeFile(t, "config.json", `{"Log": {"Level": "info"}, "Mysql": {"MaxOpenConn": 10}}`)
+	SetConfigFile(file)
+	if level := GetConfig().Log.Level; level != LogLevelInfo {
+		t.Fatalf("GetConfig() got log level %q", level)
+	}
+
+	var notified []Configs
+	OnChange(func(old, new Configs) {
+		notified = append(notified, old, new)
+	})
+
+	if err := ioutil.WriteFile(file, []byte(`{"Log": {"Level": "debug"}, "Mysql": {"MaxOpenConn": 20}, `+
+		`"Server": {"Address": ":4000"}, "CORS": {"AllowedOrigins": ["https://desk.example.com"]}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	applied, err := Reload()
+	if err != nil {
+		t.Fatalf("Reload() got error %v", err)
+	}
+	if expected := []string{"log.level", "mysql.maxopenconn", "cors.allowedorigins"}; !reflect.DeepEqual(applied, expected) {
+		t.Errorf("Reload() got applied keys %v\n expected %v", applied, expected)
+	}
+	cfg := GetConfig()
+	if cfg.Log.Level != LogLevelDebug || cfg.Mysql.MaxOpenConn != 20 || cfg.Server.Address != ":3000" ||
+		!reflect.DeepEqual(cfg.CORS.AllowedOrigins, []string{"https://desk.example.com"}) {
+		t.Errorf("GetConfig() after Reload() got %+v\n expected new log level, pool size and origins, old address", cfg)
+	}
+	if len(notified) != 2 || notified[0].Log.Level != LogLevelInfo || notified[1].Log.Level != LogLevelDebug {
+		t.Errorf("OnChange() listener got %+v\n expected old and new config", notified)
+	}
+
+	// invalid config is not applied
+	if err := ioutil.WriteFile(file, []byte(`{"Log": {"Level": "verbose"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Reload(); err == nil {
+		t.Error("Reload() of invalid config got no error")
+	}
+	if level := GetConfig().Log.Level; level != LogLevelDebug {
+		t.Errorf("GetConfig() after failed Reload() got log level %q\n expected %q", level, LogLevelDebug)
+	}
+}
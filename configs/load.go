@@ -0,0 +1,260 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultConfigFile is config file read when no other is set, it may be missing
+const DefaultConfigFile = "./configs/config.json"
+
+// EnvPrefix prefixes environment variables of config keys, e.g. BMS_MYSQL_HOST sets mysql.host
+const EnvPrefix = "BMS"
+
+// fileEnvSuffix marks environment variable holding path of file with the value, e.g. BMS_MYSQL_PASS_FILE
+const fileEnvSuffix = "_FILE"
+
+// redacted replaces secret values shown by Redacted
+const redacted = "******"
+
+// ErrUnknownKey is returned by Load for override of key no config field has
+var ErrUnknownKey = errors.New("unknown config key")
+
+// secretFields are names of fields Redacted hides
+var secretFields = map[string]bool{
+	"pass":     true,
+	"password": true,
+	"secret":   true,
+	"token":    true,
+	"apikeys":  true,
+}
+
+// Defaults returns configuration used for keys no other layer sets
+func Defaults() Configs {
+	return Configs{
+		Log: LogConfig{
+			Level:    LogLevelInfo,
+			Packages: map[string]string{},
+			Sampling: LogSamplingConfig{
+				Initial:    100,
+				Thereafter: 100,
+			},
+		},
+		Tracing: TracingConfig{
+			Exporter:    TracingExporterNone,
+			Endpoint:    "localhost:4318",
+			SampleRatio: 1,
+		},
+		Server: ServerConfig{
+			Address:        ":3000",
+			WriteTimeout:   15,
+			ReadTimeout:    15,
+			IdleTimeout:    60,
+			MaxBodyBytes:   1 << 20,
+			MaxImportBytes: 64 << 20,
+		},
+		Backend: BackendConfig{
+			Database: DatabaseMySQL,
+			Search:   SearchElasticSearch,
+		},
+		Mysql: MySQLConfig{
+			Host:            "localhost",
+			Port:            "3306",
+			Name:            "book_management_db",
+			MaxIdleConn:     10,
+			MaxOpenConn:     10,
+			ConnMaxLifetime: 5,
+		},
+		Postgres: PostgresConfig{
+			Host:            "localhost",
+			Port:            "5432",
+			Name:            "book_management_db",
+			SSLMode:         "disable",
+			MaxIdleConn:     10,
+			MaxOpenConn:     10,
+			ConnMaxLifetime: 5,
+		},
+		ElasticSearch: ESConfig{
+			Address: "http://localhost:9200",
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:         24,
+			WaitTimeout: 5,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Search:  RateLimitRule{Rate: 5, Burst: 20},
+			Read:    RateLimitRule{Rate: 20, Burst: 50},
+			Write:   RateLimitRule{Rate: 5, Burst: 10},
+		},
+		CORS: CORSConfig{
+			AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+			AllowedHeaders: []string{"Content-Type", "Idempotency-Key", "X-API-Key", "X-Request-ID"},
+			MaxAge:         600,
+		},
+		Cache: CacheConfig{
+			Enabled: true,
+			Size:    10000,
+			TTL:     60,
+		},
+		Events: EventsConfig{
+			LogSize:   1000,
+			Buffer:    256,
+			Heartbeat: 15,
+		},
+		Webhooks: WebhooksConfig{
+			Workers:     4,
+			Timeout:     10,
+			MaxAttempts: 8,
+			Backoff:     30,
+			MaxBackoff:  3600,
+		},
+	}
+}
+
+// Load returns configuration of layers, each overriding the previous one:
+// defaults, file in JSON, YAML or TOML format, environment variables and overrides.
+// Empty file means DefaultConfigFile when it exists.
+// Environment variable with _FILE suffix reads the value from file, meant for mounted secrets.
+func Load(file string, overrides map[string]string) (*Configs, error) {
+	conf := viper.New()
+
+	keys := make([]string, 0)
+	mapKeys := make([]string, 0)
+	walkFields(reflect.ValueOf(Defaults()), "", func(key string, value reflect.Value) {
+		conf.SetDefault(key, value.Interface())
+		keys = append(keys, key)
+		if value.Kind() == reflect.Map {
+			mapKeys = append(mapKeys, key)
+		}
+	})
+
+	if file = resolveFile(file); file != "" {
+		conf.SetConfigFile(file)
+		if err := conf.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+
+	conf.SetEnvPrefix(EnvPrefix)
+	conf.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	conf.AutomaticEnv()
+	for _, key := range keys {
+		name := envName(key) + fileEnvSuffix
+		path, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if _, ok := os.LookupEnv(envName(key)); ok {
+			return nil, fmt.Errorf("both %s and %s are set", envName(key), name)
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		conf.Set(key, strings.TrimRight(string(content), "\r\n"))
+	}
+
+	for key, value := range overrides {
+		key = strings.ToLower(key)
+		if contains(keys, key) {
+			conf.Set(key, value)
+			continue
+		}
+		mapKey := mapKeyOf(mapKeys, key)
+		if mapKey == "" {
+			return nil, fmt.Errorf("%w %q", ErrUnknownKey, key)
+		}
+		// entry is added to map of lower layers, setting it alone would replace the whole map
+		entries := conf.GetStringMapString(mapKey)
+		entries[strings.TrimPrefix(key, mapKey+".")] = value
+		conf.Set(mapKey, entries)
+	}
+
+	var cfg Configs
+	// keys no field has are mistakes like misspelled names, which would leave zero values
+	if err := conf.UnmarshalExact(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// resolveFile returns config file Load reads, DefaultConfigFile when file is empty and it exists
+func resolveFile(file string) string {
+	if file == "" {
+		if _, err := os.Stat(DefaultConfigFile); err == nil {
+			return DefaultConfigFile
+		}
+	}
+	return file
+}
+
+// Redacted returns copy of cfg with non-empty secrets like passwords replaced
+func (cfg Configs) Redacted() Configs {
+	value := reflect.ValueOf(&cfg).Elem()
+	walkFields(value, "", func(key string, field reflect.Value) {
+		name := key[strings.LastIndex(key, ".")+1:]
+		if !secretFields[name] {
+			return
+		}
+		switch {
+		case field.Kind() == reflect.String && field.String() != "":
+			field.SetString(redacted)
+		case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String && field.Len() > 0:
+			// new slice, the copy shares elements of cfg
+			values := make([]string, field.Len())
+			for i := range values {
+				values[i] = redacted
+			}
+			field.Set(reflect.ValueOf(values))
+		}
+	})
+	return cfg
+}
+
+// walkFields calls fn with lowercase dotted key of every non-struct field of struct value
+func walkFields(value reflect.Value, prefix string, fn func(key string, field reflect.Value)) {
+	for i := 0; i < value.NumField(); i++ {
+		key := strings.ToLower(value.Type().Field(i).Name)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if field := value.Field(i); field.Kind() == reflect.Struct {
+			walkFields(field, key, fn)
+		} else {
+			fn(key, field)
+		}
+	}
+}
+
+func envName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// mapKeyOf returns key of map field key is entry of, e.g. log.packages of log.packages.gorm,
+// empty when there is none
+func mapKeyOf(mapKeys []string, key string) string {
+	for _, mapKey := range mapKeys {
+		if strings.HasPrefix(key, mapKey+".") {
+			return mapKey
+		}
+	}
+	return ""
+}
+
+func contains(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package configs
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ReloadableKeys are keys Reload applies to the running app, changes of other keys need restart
+var ReloadableKeys = []string{
+	"log.level",
+	"log.packages",
+	"mysql.maxidleconn",
+	"mysql.maxopenconn",
+	"mysql.connmaxlifetime",
+	"postgres.maxidleconn",
+	"postgres.maxopenconn",
+	"postgres.connmaxlifetime",
+	"ratelimit.enabled",
+	"ratelimit.trustedproxies",
+	"ratelimit.apikeys",
+	"ratelimit.search.rate",
+	"ratelimit.search.burst",
+	"ratelimit.read.rate",
+	"ratelimit.read.burst",
+	"ratelimit.write.rate",
+	"ratelimit.write.burst",
+	"cors.allowedorigins",
+	"cors.allowedmethods",
+	"cors.allowedheaders",
+	"cors.allowcredentials",
+	"cors.maxage",
+	"cache.maxage",
+}
+
+// ChangeListener is called by Reload with previous and new config after reloadable keys changed
+type ChangeListener func(old, new Configs)
+
+var (
+	reloadMu  sync.Mutex
+	listeners []ChangeListener
+)
+
+// OnChange registers listener notified of every applied reload
+func OnChange(listener ChangeListener) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	listeners = append(listeners, listener)
+}
+
+// Reload loads config again and applies changes of ReloadableKeys, notifying listeners registered by OnChange.
+// Invalid config is returned as error and leaves current config in place.
+// It returns applied keys, changes of other keys are logged and ignored.
+func Reload() ([]string, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	old := *GetConfig()
+	loaded, err := Load(configFile, overrides)
+	if err != nil {
+		return nil, err
+	}
+	if err := loaded.Validate(); err != nil {
+		return nil, err
+	}
+
+	next := old
+	applied := mergeKeys(&next, *loaded, ReloadableKeys)
+	if ignored := changedKeys(next, *loaded); len(ignored) > 0 {
+		log.Printf("config changes of %s need restart, ignored", strings.Join(ignored, ", "))
+	}
+	if len(applied) == 0 {
+		return nil, nil
+	}
+
+	current.Store(&next)
+	for _, listener := range listeners {
+		listener(old, next)
+	}
+	return applied, nil
+}
+
+// Watch reloads config on SIGHUP and on change of config file, checked every interval, until ctx is done.
+// Reload errors are logged, the app keeps running with current config.
+func Watch(ctx context.Context, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	file := resolveFile(configFile)
+	modified := fileModified(file)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			log.Println("reloading config on SIGHUP")
+		case <-ticker.C:
+			m := fileModified(file)
+			if m.Equal(modified) {
+				continue
+			}
+			modified = m
+			log.Printf("reloading config on change of %s", file)
+		}
+
+		applied, err := Reload()
+		if err != nil {
+			log.Printf("failed to reload config, keeping current one: %s", err)
+			continue
+		}
+		if len(applied) > 0 {
+			log.Printf("reloaded config keys %s", strings.Join(applied, ", "))
+		}
+	}
+}
+
+// fileModified returns modification time of file, zero when there is no file
+func fileModified(file string) time.Time {
+	if file == "" {
+		return time.Time{}
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// mergeKeys sets fields of keys in dst to values of src and returns keys whose values changed
+func mergeKeys(dst *Configs, src Configs, keys []string) []string {
+	values := fieldValues(src)
+	changed := make([]string, 0)
+	walkFields(reflect.ValueOf(dst).Elem(), "", func(key string, field reflect.Value) {
+		if !contains(keys, key) || reflect.DeepEqual(field.Interface(), values[key]) {
+			return
+		}
+		field.Set(reflect.ValueOf(values[key]))
+		changed = append(changed, key)
+	})
+	return changed
+}
+
+// changedKeys returns sorted keys of fields whose values differ between a and b
+func changedKeys(a, b Configs) []string {
+	values := fieldValues(b)
+	changed := make([]string, 0)
+	for key, value := range fieldValues(a) {
+		if !reflect.DeepEqual(value, values[key]) {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func fieldValues(cfg Configs) map[string]interface{} {
+	values := make(map[string]interface{})
+	walkFields(reflect.ValueOf(cfg), "", func(key string, field reflect.Value) {
+		values[key] = field.Interface()
+	})
+	return values
+}
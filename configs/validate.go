@@ -0,0 +1,222 @@
+package configs
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidationError lists every problem Validate found, keys are named as in Load overrides
+type ValidationError []string
+
+func (err ValidationError) Error() string {
+	return "invalid config: " + strings.Join(err, "; ")
+}
+
+// postgresSSLModes are sslmode values accepted by Postgres
+var postgresSSLModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// corsMethods are methods of REST routes browsers may be allowed to call
+var corsMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"}
+
+// Validate returns ValidationError when cfg has missing or out of range values, nil otherwise
+func (cfg Configs) Validate() error {
+	v := validator{}
+
+	v.oneOf("log.level", cfg.Log.Level, LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError)
+	v.oneOf("log.format", cfg.Log.Format, "", LogFormatJSON, LogFormatText)
+	for _, pkg := range sortedKeys(cfg.Log.Packages) {
+		v.oneOf("log.packages."+pkg, cfg.Log.Packages[pkg], LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError)
+	}
+	v.notNegative("log.sampling.initial", cfg.Log.Sampling.Initial)
+	v.notNegative("log.sampling.thereafter", cfg.Log.Sampling.Thereafter)
+
+	v.oneOf("tracing.exporter", cfg.Tracing.Exporter,
+		TracingExporterNone, TracingExporterOTLP, TracingExporterStdout, TracingExporterFile)
+	switch cfg.Tracing.Exporter {
+	case TracingExporterOTLP:
+		v.required("tracing.endpoint", cfg.Tracing.Endpoint)
+	case TracingExporterFile:
+		v.required("tracing.file", cfg.Tracing.File)
+	}
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		v.addf("tracing.sampleratio must be from 0 to 1, got %v", cfg.Tracing.SampleRatio)
+	}
+
+	v.required("server.address", cfg.Server.Address)
+	v.positive("server.writetimeout", cfg.Server.WriteTimeout)
+	v.positive("server.readtimeout", cfg.Server.ReadTimeout)
+	v.positive("server.idletimeout", cfg.Server.IdleTimeout)
+	v.positive("server.maxbodybytes", cfg.Server.MaxBodyBytes)
+	v.positive("server.maximportbytes", cfg.Server.MaxImportBytes)
+	v.notNegative("server.hstsmaxage", cfg.Server.HSTSMaxAge)
+
+	// empty backends mean MySQL and ElasticSearch
+	v.oneOf("backend.database", cfg.Backend.Database,
+		"", DatabaseMySQL, DatabasePostgres, DatabaseSQLite, DatabaseMemory)
+	v.oneOf("backend.search", cfg.Backend.Search, "", SearchElasticSearch, SearchPostgres, SearchMemory)
+	if cfg.Backend.Search == SearchPostgres && cfg.Backend.Database != DatabasePostgres {
+		v.addf("backend.search %q needs backend.database %q, got %q",
+			SearchPostgres, DatabasePostgres, cfg.Backend.Database)
+	}
+
+	switch cfg.Backend.Database {
+	case "", DatabaseMySQL:
+		v.required("mysql.host", cfg.Mysql.Host)
+		v.port("mysql.port", cfg.Mysql.Port)
+		v.required("mysql.name", cfg.Mysql.Name)
+		v.pool("mysql", cfg.Mysql.MaxIdleConn, cfg.Mysql.MaxOpenConn, cfg.Mysql.ConnMaxLifetime)
+	case DatabasePostgres:
+		v.required("postgres.host", cfg.Postgres.Host)
+		v.port("postgres.port", cfg.Postgres.Port)
+		v.required("postgres.name", cfg.Postgres.Name)
+		v.oneOf("postgres.sslmode", cfg.Postgres.SSLMode, append([]string{""}, postgresSSLModes...)...)
+		v.pool("postgres", cfg.Postgres.MaxIdleConn, cfg.Postgres.MaxOpenConn, cfg.Postgres.ConnMaxLifetime)
+	}
+
+	if cfg.Backend.Search == "" || cfg.Backend.Search == SearchElasticSearch {
+		if v.required("elasticsearch.address", cfg.ElasticSearch.Address) {
+			if u, err := url.Parse(cfg.ElasticSearch.Address); err != nil || u.Scheme == "" || u.Host == "" {
+				v.addf("elasticsearch.address must be URL like http://localhost:9200, got %q",
+					cfg.ElasticSearch.Address)
+			}
+		}
+		if cfg.ElasticSearch.IsAuth {
+			v.required("elasticsearch.username", cfg.ElasticSearch.Username)
+		}
+	}
+
+	v.positive("idempotency.ttl", cfg.Idempotency.TTL)
+	v.notNegative("idempotency.waittimeout", cfg.Idempotency.WaitTimeout)
+
+	for i, proxy := range cfg.RateLimit.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			v.addf("ratelimit.trustedproxies[%d] must be IP or CIDR, got %q", i, proxy)
+		}
+	}
+	v.rule("ratelimit.search", cfg.RateLimit.Search)
+	v.rule("ratelimit.read", cfg.RateLimit.Read)
+	v.rule("ratelimit.write", cfg.RateLimit.Write)
+
+	for i, origin := range cfg.CORS.AllowedOrigins {
+		if origin == "*" {
+			if cfg.CORS.AllowCredentials {
+				v.addf("cors.allowedorigins[%d] must name origin when cors.allowcredentials is set, got %q", i, origin)
+			}
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Host == "" || (u.Path != "" && u.Path != "/") {
+			v.addf("cors.allowedorigins[%d] must be * or origin like https://app.example.com, got %q", i, origin)
+		}
+	}
+	for i, method := range cfg.CORS.AllowedMethods {
+		v.oneOf(fmt.Sprintf("cors.allowedmethods[%d]", i), method, corsMethods...)
+	}
+	v.notNegative("cors.maxage", cfg.CORS.MaxAge)
+
+	if cfg.Cache.Enabled {
+		v.positive("cache.size", cfg.Cache.Size)
+		v.positive("cache.ttl", cfg.Cache.TTL)
+	}
+	v.notNegative("cache.maxage", cfg.Cache.MaxAge)
+
+	v.positive("events.logsize", cfg.Events.LogSize)
+	v.positive("events.buffer", cfg.Events.Buffer)
+	v.positive("events.heartbeat", cfg.Events.Heartbeat)
+
+	v.positive("webhooks.workers", cfg.Webhooks.Workers)
+	v.positive("webhooks.timeout", cfg.Webhooks.Timeout)
+	v.positive("webhooks.maxattempts", cfg.Webhooks.MaxAttempts)
+	v.positive("webhooks.backoff", cfg.Webhooks.Backoff)
+	if cfg.Webhooks.MaxBackoff < cfg.Webhooks.Backoff {
+		v.addf("webhooks.maxbackoff must be at least webhooks.backoff, got %d", cfg.Webhooks.MaxBackoff)
+	}
+
+	if len(v) > 0 {
+		return ValidationError(v)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// validator collects problems of Validate
+type validator ValidationError
+
+func (v *validator) addf(format string, args ...interface{}) {
+	*v = append(*v, fmt.Sprintf(format, args...))
+}
+
+// required reports empty value and returns whether value is set
+func (v *validator) required(key, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.addf("%s is required", key)
+		return false
+	}
+	return true
+}
+
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+
+	names := make([]string, 0, len(allowed))
+	for _, a := range allowed {
+		if a != "" {
+			names = append(names, a)
+		}
+	}
+	v.addf("%s must be one of %s, got %q", key, strings.Join(names, ", "), value)
+}
+
+func (v *validator) positive(key string, value int) {
+	if value <= 0 {
+		v.addf("%s must be positive, got %d", key, value)
+	}
+}
+
+func (v *validator) notNegative(key string, value int) {
+	if value < 0 {
+		v.addf("%s must not be negative, got %d", key, value)
+	}
+}
+
+func (v *validator) port(key, value string) {
+	if !v.required(key, value) {
+		return
+	}
+	if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+		v.addf("%s must be number from 1 to 65535, got %q", key, value)
+	}
+}
+
+// rule checks rate limit rule, zero rate needs no burst
+func (v *validator) rule(prefix string, rule RateLimitRule) {
+	if rule.Rate < 0 {
+		v.addf("%s.rate must not be negative, got %v", prefix, rule.Rate)
+	}
+	if rule.Rate > 0 && rule.Burst < 1 {
+		v.addf("%s.burst must be positive when rate is set, got %d", prefix, rule.Burst)
+	}
+}
+
+// pool checks connection pool settings, zero means driver default
+func (v *validator) pool(prefix string, maxIdleConn, maxOpenConn, connMaxLifetime int) {
+	v.notNegative(prefix+".maxidleconn", maxIdleConn)
+	v.notNegative(prefix+".maxopenconn", maxOpenConn)
+	v.notNegative(prefix+".connmaxlifetime", connMaxLifetime)
+}
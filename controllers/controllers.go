@@ -3,10 +3,11 @@ package controllers
 
 import (
 	REST "book-management-system/controllers/rest"
+	"book-management-system/lifecycle"
 	"book-management-system/usecases"
 )
 
-// Init sets controllers
-func Init(useCase *usecases.UseCase) {
-	REST.Init(useCase)
+// Init sets controllers and returns components serving them, fail is called when one stops serving
+func Init(useCase *usecases.UseCase, fail func(error)) []lifecycle.Component {
+	return REST.Init(useCase, fail)
 }
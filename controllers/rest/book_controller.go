@@ -1,7 +1,7 @@
 package rest
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -29,6 +29,10 @@ func NewBookController(route *mux.Router, useCase *usecases.UseCase) *BookContro
 	v1BookRoute.HandleFunc("", ctrl.CreateBook).Methods(http.MethodPost)
 	v1BookRoute.HandleFunc("", ctrl.GetBooks).Methods(http.MethodGet)
 	v1BookRoute.HandleFunc("", ctrl.UpdateBook).Methods(http.MethodPut)
+	v1BookRoute.HandleFunc("/{id:[0-9]+}", ctrl.GetBook).Methods(http.MethodGet)
+	v1BookRoute.HandleFunc("/isbn/{isbn}", ctrl.GetBookByISBN).Methods(http.MethodGet)
+	v1BookRoute.HandleFunc("/{id:[0-9]+}", ctrl.UpdateBook).Methods(http.MethodPut)
+	v1BookRoute.HandleFunc("/{id:[0-9]+}", ctrl.PatchBook).Methods(http.MethodPatch)
 
 	return ctrl
 }
@@ -39,21 +43,26 @@ func NewBookController(route *mux.Router, useCase *usecases.UseCase) *BookContro
 // @Tags Book
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Unique key to safely retry the request"
 // @Param request body models.Book true "Request Body"
 // @Success 201 {object} models.Book "Created"
 // @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 409 {object} responses.ErrorResponse "Conflict"
+// @Failure 413 {object} responses.ErrorResponse "Request Entity Too Large"
+// @Failure 415 {object} responses.ErrorResponse "Unsupported Media Type"
+// @Failure 422 {object} responses.ErrorResponse "Unprocessable Entity"
 // @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
 // @Router /v1/book [post]
 func (ctrl *BookController) CreateBook(w http.ResponseWriter, r *http.Request) {
 	var book models.Book
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&book); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+	if err := decodeJSON(r, &book); err != nil {
+		respondWithError(w, err.code,
+			fmt.Sprintf("Invalid request payload: %s", err.Error()))
 		return
 	}
 
 	if err := ctrl.bookService.CreateBook(r.Context(), &book); err != nil {
-		respondWithError(w, http.StatusInternalServerError,
+		respondWithError(w, serviceErrorStatus(err),
 			fmt.Sprintf("Failed create book: %s", err.Error()))
 		return
 	}
@@ -67,8 +76,10 @@ func (ctrl *BookController) CreateBook(w http.ResponseWriter, r *http.Request) {
 // @Tags Book
 // @Accept json
 // @Produce json
+// @Param If-Modified-Since header string false "Responds with 304 when not modified since"
 // @Param search query string false "Search"
 // @Success 200 {object} models.Books "OK"
+// @Success 304 "Not Modified"
 // @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
 // @Router /v1/book [get]
 func (ctrl *BookController) GetBooks(w http.ResponseWriter, r *http.Request) {
@@ -86,33 +97,159 @@ func (ctrl *BookController) GetBooks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, books)
+	respondWithCacheable(w, r, books.LastModified(), books)
+}
+
+// GetBook handle get book by id request
+// @Summary Get a book
+// @Description Get a book by ID
+// @Tags Book
+// @Accept json
+// @Produce json
+// @Param If-Modified-Since header string false "Responds with 304 when not modified since"
+// @Param id path int true "Book ID"
+// @Success 200 {object} models.Book "OK"
+// @Success 304 "Not Modified"
+// @Failure 301 {object} responses.ErrorResponse "Moved Permanently, book was merged into book at Location"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 404 {object} responses.ErrorResponse "Not Found"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/book/{id} [get]
+func (ctrl *BookController) GetBook(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	book, err := ctrl.bookService.GetBook(r.Context(), id)
+	var moved *models.MovedError
+	if errors.As(err, &moved) {
+		w.Header().Set("Location", fmt.Sprintf("/v1/book/%d", moved.ID))
+		respondWithError(w, http.StatusMovedPermanently,
+			fmt.Sprintf("Book has been merged into book %d", moved.ID))
+		return
+	}
+	if err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed get book: %s", err.Error()))
+		return
+	}
+
+	respondWithCacheable(w, r, book.UpdatedAt, book)
+}
+
+// GetBookByISBN handle get book by ISBN request
+// @Summary Get a book by ISBN
+// @Description Get a book by ISBN-10 or ISBN-13, with or without hyphens
+// @Tags Book
+// @Accept json
+// @Produce json
+// @Param If-Modified-Since header string false "Responds with 304 when not modified since"
+// @Param isbn path string true "ISBN"
+// @Success 200 {object} models.Book "OK"
+// @Success 304 "Not Modified"
+// @Failure 404 {object} responses.ErrorResponse "Not Found"
+// @Failure 422 {object} responses.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/book/isbn/{isbn} [get]
+func (ctrl *BookController) GetBookByISBN(w http.ResponseWriter, r *http.Request) {
+	book, err := ctrl.bookService.GetBookByISBN(r.Context(), mux.Vars(r)["isbn"])
+	if err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed get book: %s", err.Error()))
+		return
+	}
+
+	respondWithCacheable(w, r, book.UpdatedAt, book)
 }
 
 // UpdateBook handle update book request
-// @Summary Update a book
-// @Description Update a book
+// @Summary Replace a book
+// @Description Replace all fields of a book, fields missing from request body are cleared
 // @Tags Book
 // @Accept json
 // @Produce json
 // @Param request body models.Book true "Request Body"
 // @Success 200 {object} models.Book "Updated"
 // @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 413 {object} responses.ErrorResponse "Request Entity Too Large"
+// @Failure 415 {object} responses.ErrorResponse "Unsupported Media Type"
+// @Failure 404 {object} responses.ErrorResponse "Not Found"
+// @Failure 422 {object} responses.ErrorResponse "Unprocessable Entity"
 // @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
 // @Router /v1/book [put]
 func (ctrl *BookController) UpdateBook(w http.ResponseWriter, r *http.Request) {
 	var book models.Book
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&book); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+	if err := decodeJSON(r, &book); err != nil {
+		respondWithError(w, err.code,
+			fmt.Sprintf("Invalid request payload: %s", err.Error()))
 		return
 	}
 
+	if _, ok := mux.Vars(r)["id"]; ok {
+		id, err := getIDFromPath(r)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid book ID")
+			return
+		}
+		book.ID = id
+	}
+
 	if err := ctrl.bookService.UpdateBook(r.Context(), &book); err != nil {
-		respondWithError(w, http.StatusInternalServerError,
+		respondWithError(w, serviceErrorStatus(err),
 			fmt.Sprintf("Failed update book: %s", err.Error()))
 		return
 	}
 
 	respondWithJSON(w, http.StatusOK, book)
 }
+
+// PatchBook handle partial update book request
+// @Summary Patch a book
+// @Description Partially update a book with JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902).
+// @Description A null member in merge patch clears the field.
+// @Tags Book
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path int true "Book ID"
+// @Param request body models.Book true "Patch document"
+// @Success 200 {object} models.Book "Updated"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 404 {object} responses.ErrorResponse "Not Found, also when the book was merged into another book"
+// @Failure 409 {object} responses.ErrorResponse "Conflict"
+// @Failure 415 {object} responses.ErrorResponse "Unsupported Media Type"
+// @Failure 422 {object} responses.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/book/{id} [patch]
+func (ctrl *BookController) PatchBook(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	original, err := ctrl.bookService.GetBook(r.Context(), id)
+	if err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed patch book: %s", err.Error()))
+		return
+	}
+
+	var book models.Book
+	if err := applyPatch(r, original, &book); err != nil {
+		respondWithError(w, err.code,
+			fmt.Sprintf("Failed patch book: %s", err.Error()))
+		return
+	}
+	book.Model = original.Model
+
+	if err := ctrl.bookService.UpdateBook(r.Context(), &book); err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed patch book: %s", err.Error()))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, book)
+}
@@ -8,11 +8,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
+	"gorm.io/gorm"
 
+	"book-management-system/controllers/rest/patches"
 	"book-management-system/controllers/rest/responses"
 	"book-management-system/entities/models"
 	mocks "book-management-system/mocks/services"
@@ -23,7 +26,7 @@ import (
 
 func TestNewBookController(t *testing.T) {
 	repo := &repositories.Repository{}
-	bookService := services.NewBookService(repo)
+	bookService := services.NewBookService(repo, nil)
 	usecase := &usecases.UseCase{
 		Service: &services.Services{
 			BookService: bookService,
@@ -71,17 +74,17 @@ func TestBookControllerCreateBook(t *testing.T) {
 			name: "failed: invalid request body",
 			givenInput: input{
 				valid: false,
-				ctx:   context.TODO(),
+				ctx:   context.Background(),
 				invalidRequestBody: models.Books{
 					{
 						Name: "C++",
-						ISBN: "1234",
+						ISBN: "9780062315007",
 					},
 				},
 			},
 			expectedOutput: output{
 				responseBody: responses.ErrorResponse{
-					"error": "Invalid request payload",
+					"error": "Invalid request payload: json: cannot unmarshal array into Go value of type models.Book",
 				},
 			},
 			configureMock: func(mockConfig) {
@@ -92,10 +95,10 @@ func TestBookControllerCreateBook(t *testing.T) {
 			name: "failed: create book service returns error",
 			givenInput: input{
 				valid: true,
-				ctx:   context.TODO(),
+				ctx:   context.Background(),
 				requestBody: &models.Book{
 					Name: "C++",
-					ISBN: "1234",
+					ISBN: "9780062315007",
 				},
 			},
 			expectedOutput: output{
@@ -113,16 +116,16 @@ func TestBookControllerCreateBook(t *testing.T) {
 			name: "success: create book",
 			givenInput: input{
 				valid: true,
-				ctx:   context.TODO(),
+				ctx:   context.Background(),
 				requestBody: &models.Book{
 					Name: "C++",
-					ISBN: "1234",
+					ISBN: "9780062315007",
 				},
 			},
 			expectedOutput: output{
 				responseBody: models.Book{
 					Name: "C++",
-					ISBN: "1234",
+					ISBN: "9780062315007",
 				},
 			},
 			configureMock: func(conf mockConfig) {
@@ -198,7 +201,7 @@ func TestBookControllerGetBook(t *testing.T) {
 		{
 			name: "failed: get books service returns error",
 			givenInput: input{
-				ctx:            context.TODO(),
+				ctx:            context.Background(),
 				httpRequestURL: v1BookURL,
 			},
 			expectedOutput: output{
@@ -215,14 +218,14 @@ func TestBookControllerGetBook(t *testing.T) {
 		{
 			name: "success: get books",
 			givenInput: input{
-				ctx:            context.TODO(),
+				ctx:            context.Background(),
 				httpRequestURL: v1BookURL,
 			},
 			expectedOutput: output{
 				responseBody: models.Books{
 					{
 						Name: "C++",
-						ISBN: "1234",
+						ISBN: "9780062315007",
 					},
 				},
 			},
@@ -235,7 +238,7 @@ func TestBookControllerGetBook(t *testing.T) {
 		{
 			name: "success: search books",
 			givenInput: input{
-				ctx:            context.TODO(),
+				ctx:            context.Background(),
 				httpRequestURL: v1BookURL + "?search=1234",
 				query:          "1234",
 			},
@@ -243,7 +246,7 @@ func TestBookControllerGetBook(t *testing.T) {
 				responseBody: models.Books{
 					{
 						Name: "C++",
-						ISBN: "1234",
+						ISBN: "9780062315007",
 					},
 				},
 			},
@@ -315,17 +318,17 @@ func TestBookControllerUpdateBook(t *testing.T) {
 			name: "failed: invalid request body",
 			givenInput: input{
 				valid: false,
-				ctx:   context.TODO(),
+				ctx:   context.Background(),
 				invalidRequestBody: models.Books{
 					{
 						Name: "C++",
-						ISBN: "1234",
+						ISBN: "9780062315007",
 					},
 				},
 			},
 			expectedOutput: output{
 				responseBody: responses.ErrorResponse{
-					"error": "Invalid request payload",
+					"error": "Invalid request payload: json: cannot unmarshal array into Go value of type models.Book",
 				},
 			},
 			configureMock: func(confMock) {
@@ -336,10 +339,10 @@ func TestBookControllerUpdateBook(t *testing.T) {
 			name: "failed: update book service returns error",
 			givenInput: input{
 				valid: true,
-				ctx:   context.TODO(),
+				ctx:   context.Background(),
 				requestBody: &models.Book{
 					Name: "C++",
-					ISBN: "1234",
+					ISBN: "9780062315007",
 				},
 			},
 			expectedOutput: output{
@@ -357,16 +360,16 @@ func TestBookControllerUpdateBook(t *testing.T) {
 			name: "success: update book",
 			givenInput: input{
 				valid: true,
-				ctx:   context.TODO(),
+				ctx:   context.Background(),
 				requestBody: &models.Book{
 					Name: "C++",
-					ISBN: "1234",
+					ISBN: "9780062315007",
 				},
 			},
 			expectedOutput: output{
 				responseBody: models.Book{
 					Name: "C++",
-					ISBN: "1234",
+					ISBN: "9780062315007",
 				},
 			},
 			configureMock: func(conf confMock) {
@@ -417,3 +420,420 @@ func TestBookControllerUpdateBook(t *testing.T) {
 		})
 	}
 }
+
+func TestBookControllerGetBookByID(t *testing.T) {
+	type input struct {
+		id string
+	}
+	type output struct {
+		code         int
+		location     string
+		responseBody interface{}
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     *mocks.MockBookService
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "failed: invalid id",
+			givenInput: input{
+				id: "99999999999999999999999",
+			},
+			expectedOutput: output{
+				code: http.StatusBadRequest,
+				responseBody: responses.ErrorResponse{
+					"error": "Invalid book ID",
+				},
+			},
+			configureMock: func(mockConfig) {
+				// do nothing
+			},
+		},
+		{
+			name: "failed: book not found",
+			givenInput: input{
+				id: "1",
+			},
+			expectedOutput: output{
+				code: http.StatusNotFound,
+				responseBody: responses.ErrorResponse{
+					"error": fmt.Sprintf("Failed get book: %s", models.ErrNotFound.Error()),
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetBook(gomock.Any(), uint(1)).
+					Return(nil, models.ErrNotFound)
+			},
+		},
+		{
+			name: "success: merged book redirects",
+			givenInput: input{
+				id: "2",
+			},
+			expectedOutput: output{
+				code:     http.StatusMovedPermanently,
+				location: v1BookURL + "/1",
+				responseBody: responses.ErrorResponse{
+					"error": "Book has been merged into book 1",
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetBook(gomock.Any(), uint(2)).
+					Return(nil, &models.MovedError{ID: 1})
+			},
+		},
+		{
+			name: "success: get book",
+			givenInput: input{
+				id: "1",
+			},
+			expectedOutput: output{
+				code: http.StatusOK,
+				responseBody: &models.Book{
+					Name: "C++",
+					ISBN: "9780062315007",
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetBook(gomock.Any(), uint(1)).
+					Return(conf.expected.responseBody, nil)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				v1BookURL+"/"+tt.givenInput.id,
+				nil,
+			)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.givenInput.id})
+			resp := httptest.NewRecorder()
+
+			bookServiceMock := mocks.NewMockBookService(ctrl)
+			tt.configureMock(mockConfig{
+				given:    tt.givenInput,
+				expected: tt.expectedOutput,
+				mock:     bookServiceMock,
+			})
+
+			bookController := &BookController{
+				bookService: bookServiceMock,
+			}
+			bookController.GetBook(resp, req)
+
+			if resp.Code != tt.expectedOutput.code {
+				t.Errorf("GetBook() got status code %d\n expected %d",
+					resp.Code, tt.expectedOutput.code)
+			}
+			if location := resp.Header().Get("Location"); location != tt.expectedOutput.location {
+				t.Errorf("GetBook() got location %s\n expected %s",
+					location, tt.expectedOutput.location)
+			}
+			got := resp.Body.String()
+			expected, _ := json.Marshal(tt.expectedOutput.responseBody)
+			if got != string(expected) {
+				t.Errorf("GetBook() got response body %s\n expected %s",
+					got, string(expected))
+			}
+		})
+	}
+}
+
+func TestBookControllerGetBookByISBN(t *testing.T) {
+	type input struct {
+		isbn string
+	}
+	type output struct {
+		code         int
+		responseBody interface{}
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     *mocks.MockBookService
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "failed: invalid isbn",
+			givenInput: input{
+				isbn: "1234",
+			},
+			expectedOutput: output{
+				code: http.StatusUnprocessableEntity,
+				responseBody: responses.ErrorResponse{
+					"error": fmt.Sprintf("Failed get book: %s", models.ErrInvalid.Error()),
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetBookByISBN(gomock.Any(), conf.given.isbn).
+					Return(nil, models.ErrInvalid)
+			},
+		},
+		{
+			name: "success: get book",
+			givenInput: input{
+				isbn: "0-06-231500-5",
+			},
+			expectedOutput: output{
+				code: http.StatusOK,
+				responseBody: &models.Book{
+					Name: "C++",
+					ISBN: "9780062315007",
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetBookByISBN(gomock.Any(), conf.given.isbn).
+					Return(conf.expected.responseBody, nil)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				v1BookURL+"/isbn/"+tt.givenInput.isbn,
+				nil,
+			)
+			req = mux.SetURLVars(req, map[string]string{"isbn": tt.givenInput.isbn})
+			resp := httptest.NewRecorder()
+
+			bookServiceMock := mocks.NewMockBookService(ctrl)
+			tt.configureMock(mockConfig{
+				given:    tt.givenInput,
+				expected: tt.expectedOutput,
+				mock:     bookServiceMock,
+			})
+
+			bookController := &BookController{
+				bookService: bookServiceMock,
+			}
+			bookController.GetBookByISBN(resp, req)
+
+			if resp.Code != tt.expectedOutput.code {
+				t.Errorf("GetBookByISBN() got status code %d\n expected %d",
+					resp.Code, tt.expectedOutput.code)
+			}
+			got := resp.Body.String()
+			expected, _ := json.Marshal(tt.expectedOutput.responseBody)
+			if got != string(expected) {
+				t.Errorf("GetBookByISBN() got response body %s\n expected %s",
+					got, string(expected))
+			}
+		})
+	}
+}
+
+func TestBookControllerPatchBook(t *testing.T) {
+	type input struct {
+		contentType string
+		requestBody string
+	}
+	type output struct {
+		code         int
+		responseBody interface{}
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     *mocks.MockBookService
+	}
+
+	original := &models.Book{
+		Model: gorm.Model{
+			ID: 1,
+		},
+		Name: "C++",
+		ISBN: "9780062315007",
+	}
+	patched := &models.Book{
+		Model: gorm.Model{
+			ID: 1,
+		},
+		Name: "C++",
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "failed: book not found",
+			givenInput: input{
+				contentType: patches.MergePatchContentType,
+				requestBody: `{"isbn":null}`,
+			},
+			expectedOutput: output{
+				code: http.StatusNotFound,
+				responseBody: responses.ErrorResponse{
+					"error": fmt.Sprintf("Failed patch book: %s", models.ErrNotFound.Error()),
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetBook(gomock.Any(), uint(1)).
+					Return(nil, models.ErrNotFound)
+			},
+		},
+		{
+			name: "failed: book merged into another book",
+			givenInput: input{
+				contentType: patches.MergePatchContentType,
+				requestBody: `{"isbn":null}`,
+			},
+			expectedOutput: output{
+				code: http.StatusNotFound,
+				responseBody: responses.ErrorResponse{
+					"error": "Failed patch book: record moved to 2",
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetBook(gomock.Any(), uint(1)).
+					Return(nil, &models.MovedError{ID: 2})
+			},
+		},
+		{
+			name: "failed: unsupported content type",
+			givenInput: input{
+				contentType: "application/json",
+				requestBody: `{"isbn":null}`,
+			},
+			expectedOutput: output{
+				code: http.StatusUnsupportedMediaType,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetBook(gomock.Any(), uint(1)).
+					Return(original, nil)
+			},
+		},
+		{
+			name: "failed: invalid patched book",
+			givenInput: input{
+				contentType: patches.MergePatchContentType,
+				requestBody: `{"name":null}`,
+			},
+			expectedOutput: output{
+				code: http.StatusUnprocessableEntity,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetBook(gomock.Any(), uint(1)).
+					Return(original, nil)
+				conf.mock.EXPECT().
+					UpdateBook(gomock.Any(), gomock.Any()).
+					Return(models.ErrInvalid)
+			},
+		},
+		{
+			name: "success: merge patch book",
+			givenInput: input{
+				contentType: patches.MergePatchContentType,
+				requestBody: `{"isbn":null}`,
+			},
+			expectedOutput: output{
+				code:         http.StatusOK,
+				responseBody: patched,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetBook(gomock.Any(), uint(1)).
+					Return(original, nil)
+				conf.mock.EXPECT().
+					UpdateBook(gomock.Any(), patched).
+					Return(nil)
+			},
+		},
+		{
+			name: "success: json patch book",
+			givenInput: input{
+				contentType: patches.JSONPatchContentType,
+				requestBody: `[{"op":"remove","path":"/isbn"}]`,
+			},
+			expectedOutput: output{
+				code:         http.StatusOK,
+				responseBody: patched,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetBook(gomock.Any(), uint(1)).
+					Return(original, nil)
+				conf.mock.EXPECT().
+					UpdateBook(gomock.Any(), patched).
+					Return(nil)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(
+				http.MethodPatch,
+				v1BookURL+"/1",
+				strings.NewReader(tt.givenInput.requestBody),
+			)
+			req.Header.Set("Content-Type", tt.givenInput.contentType)
+			req = mux.SetURLVars(req, map[string]string{"id": "1"})
+			resp := httptest.NewRecorder()
+
+			bookServiceMock := mocks.NewMockBookService(ctrl)
+			tt.configureMock(mockConfig{
+				given:    tt.givenInput,
+				expected: tt.expectedOutput,
+				mock:     bookServiceMock,
+			})
+
+			bookController := &BookController{
+				bookService: bookServiceMock,
+			}
+			bookController.PatchBook(resp, req)
+
+			if resp.Code != tt.expectedOutput.code {
+				t.Errorf("PatchBook() got status code %d\n expected %d",
+					resp.Code, tt.expectedOutput.code)
+			}
+			if tt.expectedOutput.responseBody == nil {
+				return
+			}
+			got := resp.Body.String()
+			expected, _ := json.Marshal(tt.expectedOutput.responseBody)
+			if got != string(expected) {
+				t.Errorf("PatchBook() got response body %s\n expected %s",
+					got, string(expected))
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"book-management-system/controllers/rest/responses"
+	"book-management-system/entities/models"
+	"book-management-system/usecases"
+	"book-management-system/usecases/pipelines"
+)
+
+// MergeDuplicateRequest is optional body of merge duplicate request
+type MergeDuplicateRequest struct {
+	SurvivorID uint `json:"survivor_id" example:"1"`
+}
+
+// BookDuplicateController will handle duplicate book review requests
+type BookDuplicateController struct {
+	bookDuplicatePipeline pipelines.BookDuplicatePipeline
+}
+
+// NewBookDuplicateController returns new BookDuplicateController
+func NewBookDuplicateController(route *mux.Router, useCase *usecases.UseCase) *BookDuplicateController {
+	ctrl := &BookDuplicateController{
+		bookDuplicatePipeline: useCase.Pipeline.BookDuplicatePipeline,
+	}
+
+	v1Route := route.PathPrefix("/v1").Subrouter()
+	v1BookDuplicateRoute := v1Route.PathPrefix("/book/duplicates").Subrouter()
+	v1BookDuplicateRoute.HandleFunc("", ctrl.GetDuplicates).Methods(http.MethodGet)
+	v1BookDuplicateRoute.HandleFunc("/detect", ctrl.DetectDuplicates).Methods(http.MethodPost)
+	v1BookDuplicateRoute.HandleFunc("/{id:[0-9]+}/merge", ctrl.MergeDuplicate).Methods(http.MethodPost)
+	v1BookDuplicateRoute.HandleFunc("/{id:[0-9]+}/dismiss", ctrl.DismissDuplicate).Methods(http.MethodPost)
+
+	return ctrl
+}
+
+// GetDuplicates handle get duplicate candidates request
+// @Summary Get duplicate books
+// @Description Get candidate pairs of duplicate books for review, the most likely duplicates first.
+// @Description Pairs are scored by ISBN and title similarity.
+// @Tags Book
+// @Accept json
+// @Produce json
+// @Param status query string false "Review status" Enums(pending, merged, dismissed) default(pending)
+// @Success 200 {array} models.BookDuplicate "OK"
+// @Failure 422 {object} responses.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/book/duplicates [get]
+func (ctrl *BookDuplicateController) GetDuplicates(w http.ResponseWriter, r *http.Request) {
+	status := models.BookDuplicatePending
+	if value := r.URL.Query().Get("status"); value != "" {
+		status = models.BookDuplicateStatus(value)
+	}
+
+	duplicates, err := ctrl.bookDuplicatePipeline.GetDuplicates(r.Context(), status)
+	if err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed get duplicates: %s", err.Error()))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, duplicates)
+}
+
+// DetectDuplicates handle start duplicate detection request
+// @Summary Detect duplicate books
+// @Description Start duplicate detection of whole catalogue in background.
+// @Description Dismissed pairs are not reported again.
+// @Tags Book
+// @Accept json
+// @Produce json
+// @Success 202 {object} responses.StatusResponse "Accepted"
+// @Failure 409 {object} responses.ErrorResponse "Conflict"
+// @Router /v1/book/duplicates/detect [post]
+func (ctrl *BookDuplicateController) DetectDuplicates(w http.ResponseWriter, r *http.Request) {
+	err := ctrl.bookDuplicatePipeline.StartDetection(r.Context())
+	if errors.Is(err, pipelines.ErrDetectionRunning) {
+		respondWithError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError,
+			fmt.Sprintf("Failed start duplicate detection: %s", err.Error()))
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, responses.StatusResponse{"status": "started"})
+}
+
+// MergeDuplicate handle merge duplicate books request
+// @Summary Merge duplicate books
+// @Description Merge one book of candidate pair into the other and return the surviving book.
+// @Description The other book is deleted and its ID redirects to the surviving book.
+// @Description The older book survives unless survivor_id is given.
+// @Tags Book
+// @Accept json
+// @Produce json
+// @Param id path int true "Duplicate ID"
+// @Param request body MergeDuplicateRequest false "Request Body"
+// @Success 200 {object} models.Book "OK"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 413 {object} responses.ErrorResponse "Request Entity Too Large"
+// @Failure 415 {object} responses.ErrorResponse "Unsupported Media Type"
+// @Failure 404 {object} responses.ErrorResponse "Not Found"
+// @Failure 422 {object} responses.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/book/duplicates/{id}/merge [post]
+func (ctrl *BookDuplicateController) MergeDuplicate(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid duplicate ID")
+		return
+	}
+
+	var request MergeDuplicateRequest
+	// body is optional
+	if err := decodeJSON(r, &request); err != nil && !errors.Is(err, io.EOF) {
+		respondWithError(w, err.code,
+			fmt.Sprintf("Invalid request payload: %s", err.Error()))
+		return
+	}
+
+	book, err := ctrl.bookDuplicatePipeline.MergeDuplicate(r.Context(), id, request.SurvivorID)
+	if err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed merge duplicate: %s", err.Error()))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, book)
+}
+
+// DismissDuplicate handle dismiss duplicate request
+// @Summary Dismiss duplicate books
+// @Description Mark candidate pair as different books
+// @Tags Book
+// @Accept json
+// @Produce json
+// @Param id path int true "Duplicate ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 404 {object} responses.ErrorResponse "Not Found"
+// @Failure 422 {object} responses.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/book/duplicates/{id}/dismiss [post]
+func (ctrl *BookDuplicateController) DismissDuplicate(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid duplicate ID")
+		return
+	}
+
+	if err := ctrl.bookDuplicatePipeline.DismissDuplicate(r.Context(), id); err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed dismiss duplicate: %s", err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,302 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+
+	"book-management-system/controllers/rest/responses"
+	"book-management-system/entities/models"
+	mocks "book-management-system/mocks/pipelines"
+	"book-management-system/usecases/pipelines"
+)
+
+const (
+	v1BookDuplicatesURL = "/v1/book/duplicates"
+)
+
+func TestBookDuplicateControllerGetDuplicates(t *testing.T) {
+	type input struct {
+		query string
+	}
+	type output struct {
+		code         int
+		responseBody interface{}
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     *mocks.MockBookDuplicatePipeline
+	}
+
+	invalidStatusErr := fmt.Errorf("%w: unknown duplicate status %q", models.ErrInvalid, "open")
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "failed: unknown status",
+			givenInput: input{
+				query: "?status=open",
+			},
+			expectedOutput: output{
+				code: http.StatusUnprocessableEntity,
+				responseBody: responses.ErrorResponse{
+					"error": fmt.Sprintf("Failed get duplicates: %s", invalidStatusErr.Error()),
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetDuplicates(gomock.Any(), models.BookDuplicateStatus("open")).
+					Return(nil, invalidStatusErr)
+			},
+		},
+		{
+			name: "success: get pending duplicates by default",
+			expectedOutput: output{
+				code: http.StatusOK,
+				responseBody: []models.BookDuplicate{
+					{
+						ID:          1,
+						BookID:      1,
+						DuplicateID: 2,
+						Score:       1,
+						Reasons:     models.BookDuplicateReasons{models.BookDuplicateSameISBN},
+						Status:      models.BookDuplicatePending,
+						Book:        &models.Book{Model: gorm.Model{ID: 1}, Name: "C++", ISBN: "9780062315007"},
+						Duplicate:   &models.Book{Model: gorm.Model{ID: 2}, Name: "C ++", ISBN: "9780062315007"},
+					},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetDuplicates(gomock.Any(), models.BookDuplicatePending).
+					Return(conf.expected.responseBody, nil)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				v1BookDuplicatesURL+tt.givenInput.query,
+				nil,
+			)
+			resp := httptest.NewRecorder()
+
+			bookDuplicatePipelineMock := mocks.NewMockBookDuplicatePipeline(ctrl)
+			tt.configureMock(mockConfig{
+				given:    tt.givenInput,
+				expected: tt.expectedOutput,
+				mock:     bookDuplicatePipelineMock,
+			})
+
+			bookDuplicateController := &BookDuplicateController{
+				bookDuplicatePipeline: bookDuplicatePipelineMock,
+			}
+			bookDuplicateController.GetDuplicates(resp, req)
+
+			if resp.Code != tt.expectedOutput.code {
+				t.Errorf("GetDuplicates() got status code %d\n expected %d",
+					resp.Code, tt.expectedOutput.code)
+			}
+			got := resp.Body.String()
+			expected, _ := json.Marshal(tt.expectedOutput.responseBody)
+			if got != string(expected) {
+				t.Errorf("GetDuplicates() got response body %s\n expected %s",
+					got, string(expected))
+			}
+		})
+	}
+}
+
+func TestBookDuplicateControllerDetectDuplicates(t *testing.T) {
+	type output struct {
+		code         int
+		responseBody interface{}
+	}
+
+	tests := []struct {
+		name           string
+		startErr       error
+		expectedOutput output
+	}{
+		{
+			name:     "failed: detection already running",
+			startErr: pipelines.ErrDetectionRunning,
+			expectedOutput: output{
+				code: http.StatusConflict,
+				responseBody: responses.ErrorResponse{
+					"error": pipelines.ErrDetectionRunning.Error(),
+				},
+			},
+		},
+		{
+			name: "success: detection started",
+			expectedOutput: output{
+				code:         http.StatusAccepted,
+				responseBody: responses.StatusResponse{"status": "started"},
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, v1BookDuplicatesURL+"/detect", nil)
+			resp := httptest.NewRecorder()
+
+			bookDuplicatePipelineMock := mocks.NewMockBookDuplicatePipeline(ctrl)
+			bookDuplicatePipelineMock.EXPECT().
+				StartDetection(gomock.Any()).
+				Return(tt.startErr)
+
+			bookDuplicateController := &BookDuplicateController{
+				bookDuplicatePipeline: bookDuplicatePipelineMock,
+			}
+			bookDuplicateController.DetectDuplicates(resp, req)
+
+			if resp.Code != tt.expectedOutput.code {
+				t.Errorf("DetectDuplicates() got status code %d\n expected %d",
+					resp.Code, tt.expectedOutput.code)
+			}
+			got := resp.Body.String()
+			expected, _ := json.Marshal(tt.expectedOutput.responseBody)
+			if got != string(expected) {
+				t.Errorf("DetectDuplicates() got response body %s\n expected %s",
+					got, string(expected))
+			}
+		})
+	}
+}
+
+func TestBookDuplicateControllerMergeDuplicate(t *testing.T) {
+	type input struct {
+		id   string
+		body string
+	}
+	type output struct {
+		code         int
+		responseBody interface{}
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     *mocks.MockBookDuplicatePipeline
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "failed: invalid request payload",
+			givenInput: input{
+				id:   "1",
+				body: `{"survivor_id":"one"}`,
+			},
+			expectedOutput: output{
+				code: http.StatusBadRequest,
+				responseBody: responses.ErrorResponse{
+					"error": "Invalid request payload: json: cannot unmarshal string into Go struct field MergeDuplicateRequest.survivor_id of type uint",
+				},
+			},
+			configureMock: func(mockConfig) {
+				// do nothing
+			},
+		},
+		{
+			name: "failed: duplicate not found",
+			givenInput: input{
+				id: "1",
+			},
+			expectedOutput: output{
+				code: http.StatusNotFound,
+				responseBody: responses.ErrorResponse{
+					"error": fmt.Sprintf("Failed merge duplicate: %s", models.ErrNotFound.Error()),
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					MergeDuplicate(gomock.Any(), uint(1), uint(0)).
+					Return(nil, models.ErrNotFound)
+			},
+		},
+		{
+			name: "success: merge into chosen survivor",
+			givenInput: input{
+				id:   "1",
+				body: `{"survivor_id":2}`,
+			},
+			expectedOutput: output{
+				code: http.StatusOK,
+				responseBody: &models.Book{
+					Model: gorm.Model{ID: 2},
+					Name:  "C++",
+					ISBN:  "9780062315007",
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					MergeDuplicate(gomock.Any(), uint(1), uint(2)).
+					Return(conf.expected.responseBody, nil)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				v1BookDuplicatesURL+"/"+tt.givenInput.id+"/merge",
+				strings.NewReader(tt.givenInput.body),
+			)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.givenInput.id})
+			resp := httptest.NewRecorder()
+
+			bookDuplicatePipelineMock := mocks.NewMockBookDuplicatePipeline(ctrl)
+			tt.configureMock(mockConfig{
+				given:    tt.givenInput,
+				expected: tt.expectedOutput,
+				mock:     bookDuplicatePipelineMock,
+			})
+
+			bookDuplicateController := &BookDuplicateController{
+				bookDuplicatePipeline: bookDuplicatePipelineMock,
+			}
+			bookDuplicateController.MergeDuplicate(resp, req)
+
+			if resp.Code != tt.expectedOutput.code {
+				t.Errorf("MergeDuplicate() got status code %d\n expected %d",
+					resp.Code, tt.expectedOutput.code)
+			}
+			got := resp.Body.String()
+			expected, _ := json.Marshal(tt.expectedOutput.responseBody)
+			if got != string(expected) {
+				t.Errorf("MergeDuplicate() got response body %s\n expected %s",
+					got, string(expected))
+			}
+		})
+	}
+}
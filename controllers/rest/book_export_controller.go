@@ -0,0 +1,102 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"book-management-system/logging"
+	"book-management-system/usecases"
+	"book-management-system/usecases/pipelines"
+)
+
+// BookExportController will handle book export requests
+type BookExportController struct {
+	bookExportPipeline pipelines.BookExportPipeline
+}
+
+// NewBookExportController returns new BookExportController
+func NewBookExportController(route *mux.Router, useCase *usecases.UseCase) *BookExportController {
+	ctrl := &BookExportController{
+		bookExportPipeline: useCase.Pipeline.BookExportPipeline,
+	}
+
+	v1Route := route.PathPrefix("/v1").Subrouter()
+	v1Route.HandleFunc("/book/export", ctrl.ExportBooks).Methods(http.MethodGet)
+
+	return ctrl
+}
+
+// ExportBooks handle export books request
+// @Summary Export books
+// @Description Stream catalogue as CSV, JSON Lines or MARC 21 file.
+// @Description Accepts the same filters as get all books.
+// @Tags Book
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/marc
+// @Param format query string false "Export format" Enums(csv, jsonl, marc) default(csv)
+// @Param search query string false "Search"
+// @Success 200 {file} file "OK"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/book/export [get]
+func (ctrl *BookExportController) ExportBooks(w http.ResponseWriter, r *http.Request) {
+	format := pipelines.ExportFormatCSV
+	if value := r.URL.Query().Get("format"); value != "" {
+		format = pipelines.ExportFormat(value)
+	}
+	filter := pipelines.BookExportFilter{
+		Search: r.URL.Query().Get("search"),
+	}
+
+	ew := &exportResponseWriter{
+		ResponseWriter: w,
+		contentType:    format.ContentType(),
+		filename: fmt.Sprintf("books-%s.%s",
+			time.Now().UTC().Format("20060102"), format.FileExtension()),
+	}
+
+	err := ctrl.bookExportPipeline.ExportBooks(r.Context(), ew, format, filter)
+	switch {
+	case err == nil:
+		// empty export still is a download
+		ew.writeHeader()
+	case ew.written:
+		// response is already streaming, the client sees truncated file
+		logging.FromContext(r.Context(), logging.PackageRest).Error("failed to export books", zap.Error(err))
+	case errors.Is(err, pipelines.ErrUnsupportedExportFormat):
+		respondWithError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondWithError(w, http.StatusInternalServerError,
+			fmt.Sprintf("Failed export books: %s", err.Error()))
+	}
+}
+
+// exportResponseWriter sets download headers on first write,
+// so error response can still be sent while nothing has been written
+type exportResponseWriter struct {
+	http.ResponseWriter
+	contentType string
+	filename    string
+	written     bool
+}
+
+func (w *exportResponseWriter) Write(b []byte) (int, error) {
+	w.writeHeader()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *exportResponseWriter) writeHeader() {
+	if w.written {
+		return
+	}
+	w.written = true
+	w.Header().Set("Content-Type", w.contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.filename))
+	w.WriteHeader(http.StatusOK)
+}
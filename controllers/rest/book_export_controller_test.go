@@ -0,0 +1,159 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"book-management-system/controllers/rest/responses"
+	mocks "book-management-system/mocks/pipelines"
+	"book-management-system/usecases/pipelines"
+)
+
+const (
+	v1BookExportURL = "/v1/book/export"
+)
+
+func TestBookExportControllerExportBooks(t *testing.T) {
+	type input struct {
+		query string
+	}
+	type output struct {
+		code         int
+		contentType  string
+		responseBody string
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     *mocks.MockBookExportPipeline
+	}
+
+	errorBody := func(message string) string {
+		body, _ := json.Marshal(responses.ErrorResponse{"error": message})
+		return string(body)
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success: default csv export",
+			expectedOutput: output{
+				code:         http.StatusOK,
+				contentType:  "text/csv; charset=utf-8",
+				responseBody: "id,name,isbn,created_at,updated_at\n",
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					ExportBooks(gomock.Any(), gomock.Any(), pipelines.ExportFormatCSV, pipelines.BookExportFilter{}).
+					DoAndReturn(func(_ context.Context, w io.Writer, _ pipelines.ExportFormat, _ pipelines.BookExportFilter) error {
+						_, err := io.WriteString(w, conf.expected.responseBody)
+						return err
+					})
+			},
+		},
+		{
+			name: "success: empty marc export of search",
+			givenInput: input{
+				query: "?format=marc&search=alchemist",
+			},
+			expectedOutput: output{
+				code:        http.StatusOK,
+				contentType: "application/marc",
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					ExportBooks(gomock.Any(), gomock.Any(), pipelines.ExportFormatMARC,
+						pipelines.BookExportFilter{Search: "alchemist"}).
+					Return(nil)
+			},
+		},
+		{
+			name: "failed: unsupported format",
+			givenInput: input{
+				query: "?format=xml",
+			},
+			expectedOutput: output{
+				code:         http.StatusBadRequest,
+				contentType:  "application/json",
+				responseBody: errorBody(`unsupported export format: "xml"`),
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					ExportBooks(gomock.Any(), gomock.Any(), pipelines.ExportFormat("xml"), pipelines.BookExportFilter{}).
+					Return(fmt.Errorf("%w: %q", pipelines.ErrUnsupportedExportFormat, "xml"))
+			},
+		},
+		{
+			name: "failed: search error",
+			givenInput: input{
+				query: "?format=jsonl&search=alchemist",
+			},
+			expectedOutput: output{
+				code:         http.StatusInternalServerError,
+				contentType:  "application/json",
+				responseBody: errorBody("Failed export books: " + errService.Error()),
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					ExportBooks(gomock.Any(), gomock.Any(), pipelines.ExportFormatJSONL,
+						pipelines.BookExportFilter{Search: "alchemist"}).
+					Return(errService)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				v1BookExportURL+tt.givenInput.query,
+				nil,
+			)
+			resp := httptest.NewRecorder()
+
+			bookExportPipelineMock := mocks.NewMockBookExportPipeline(ctrl)
+			tt.configureMock(mockConfig{
+				given:    tt.givenInput,
+				expected: tt.expectedOutput,
+				mock:     bookExportPipelineMock,
+			})
+
+			bookExportController := &BookExportController{
+				bookExportPipeline: bookExportPipelineMock,
+			}
+			bookExportController.ExportBooks(resp, req)
+
+			if resp.Code != tt.expectedOutput.code {
+				t.Errorf("ExportBooks() got status code %d\n expected %d",
+					resp.Code, tt.expectedOutput.code)
+			}
+			if contentType := resp.Header().Get("Content-Type"); contentType != tt.expectedOutput.contentType {
+				t.Errorf("ExportBooks() got content type %s\n expected %s",
+					contentType, tt.expectedOutput.contentType)
+			}
+			attachment := strings.HasPrefix(resp.Header().Get("Content-Disposition"), "attachment; ")
+			if expected := resp.Code == http.StatusOK; attachment != expected {
+				t.Errorf("ExportBooks() got attachment %t\n expected %t", attachment, expected)
+			}
+			if got := resp.Body.String(); got != tt.expectedOutput.responseBody {
+				t.Errorf("ExportBooks() got response body %s\n expected %s",
+					got, tt.expectedOutput.responseBody)
+			}
+		})
+	}
+}
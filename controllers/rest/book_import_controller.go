@@ -0,0 +1,213 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"book-management-system/usecases"
+	"book-management-system/usecases/pipelines"
+)
+
+// asyncImportThreshold is number of rows above which import runs as asynchronous job
+const asyncImportThreshold = 1000
+
+// importContentTypes maps request media type to import format
+var importContentTypes = map[string]pipelines.ImportFormat{
+	"text/csv":             pipelines.ImportFormatCSV,
+	"application/x-ndjson": pipelines.ImportFormatJSONL,
+	"application/jsonl":    pipelines.ImportFormatJSONL,
+}
+
+// catalogContentTypes maps request media type to cataloguing record format
+var catalogContentTypes = map[string]pipelines.ImportFormat{
+	"application/marc":        pipelines.ImportFormatMARC,
+	"application/marcxml+xml": pipelines.ImportFormatMARCXML,
+	"application/xml":         pipelines.ImportFormatMARCXML,
+	"text/xml":                pipelines.ImportFormatMARCXML,
+}
+
+// BookImportController will handle book import requests
+type BookImportController struct {
+	bookImportPipeline pipelines.BookImportPipeline
+}
+
+// NewBookImportController returns new BookImportController
+func NewBookImportController(route *mux.Router, useCase *usecases.UseCase) *BookImportController {
+	ctrl := &BookImportController{
+		bookImportPipeline: useCase.Pipeline.BookImportPipeline,
+	}
+
+	v1Route := route.PathPrefix("/v1").Subrouter()
+	v1BookImportRoute := v1Route.PathPrefix("/book/import").Subrouter()
+	v1BookImportRoute.HandleFunc("", ctrl.ImportBooks).Methods(http.MethodPost)
+	v1BookImportRoute.HandleFunc("/{id:[0-9]+}", ctrl.GetImportJob).Methods(http.MethodGet)
+	v1BookImportRoute.HandleFunc("/marc", ctrl.ImportCatalogRecords).Methods(http.MethodPost)
+	v1BookImportRoute.HandleFunc("/marc/preview", ctrl.PreviewCatalogRecords).Methods(http.MethodPost)
+
+	return ctrl
+}
+
+// ImportBooks handle bulk import books request
+// @Summary Import books
+// @Description Import books from CSV (with header row) or JSON Lines.
+// @Description Every row is validated and books with ISBN already in catalogue or earlier in the file are reported as duplicates.
+// @Description Imports larger than 1000 rows, or requested with async=true, run as asynchronous job.
+// @Tags Book
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Param format query string false "Import format, defaults to request Content-Type" Enums(csv, jsonl)
+// @Param columns query string false "Column mapping of book field to source column, e.g. name=Title,isbn=ISBN 13"
+// @Param async query bool false "Run import as asynchronous job"
+// @Success 200 {object} models.BookImportReport "OK"
+// @Success 202 {object} models.ImportJob "Accepted"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 415 {object} responses.ErrorResponse "Unsupported Media Type"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/book/import [post]
+func (ctrl *BookImportController) ImportBooks(w http.ResponseWriter, r *http.Request) {
+	format, err := getImportFormat(r, importContentTypes)
+	if err != nil {
+		respondWithError(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+
+	columns, err := pipelines.ParseColumnMapping(r.URL.Query().Get("columns"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	records, err := pipelines.ReadBookRecords(r.Body, format, columns)
+	if errors.Is(err, pipelines.ErrUnsupportedImportFormat) {
+		respondWithError(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+	if err != nil {
+		respondWithError(w, bodyErrorStatus(err, http.StatusBadRequest),
+			fmt.Sprintf("Failed read import file: %s", err.Error()))
+		return
+	}
+
+	async, _ := strconv.ParseBool(r.URL.Query().Get("async"))
+	if async || len(records) > asyncImportThreshold {
+		job, err := ctrl.bookImportPipeline.StartImportJob(r.Context(), format, records)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError,
+				fmt.Sprintf("Failed start import job: %s", err.Error()))
+			return
+		}
+
+		w.Header().Set("Location", fmt.Sprintf("/v1/book/import/%d", job.ID))
+		respondWithJSON(w, http.StatusAccepted, job)
+		return
+	}
+
+	report := ctrl.bookImportPipeline.ImportBooks(r.Context(), records)
+	respondWithJSON(w, http.StatusOK, report)
+}
+
+// GetImportJob handle get import job status request
+// @Summary Get import job
+// @Description Get status and report of asynchronous book import job
+// @Tags Book
+// @Accept json
+// @Produce json
+// @Param id path int true "Import job ID"
+// @Success 200 {object} models.ImportJob "OK"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 404 {object} responses.ErrorResponse "Not Found"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/book/import/{id} [get]
+func (ctrl *BookImportController) GetImportJob(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid import job ID")
+		return
+	}
+
+	job, err := ctrl.bookImportPipeline.GetImportJob(r.Context(), id)
+	if err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed get import job: %s", err.Error()))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, job)
+}
+
+// PreviewCatalogRecords handle dry run of cataloguing records import request
+// @Summary Preview MARC import
+// @Description Parse MARC 21 or MARCXML records and show mapped books and whether each would be created or merged by ISBN.
+// @Description Nothing is stored. Authors, publisher, year and subjects are mapped for review only.
+// @Tags Book
+// @Accept application/marc
+// @Accept application/marcxml+xml
+// @Produce json
+// @Param format query string false "Record format, defaults to request Content-Type" Enums(marc, marcxml)
+// @Success 200 {object} models.BookImportReport "OK"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 415 {object} responses.ErrorResponse "Unsupported Media Type"
+// @Router /v1/book/import/marc/preview [post]
+func (ctrl *BookImportController) PreviewCatalogRecords(w http.ResponseWriter, r *http.Request) {
+	ctrl.importCatalogRecords(w, r, true)
+}
+
+// ImportCatalogRecords handle cataloguing records import request
+// @Summary Import MARC records
+// @Description Create books from MARC 21 or MARCXML records, records with ISBN already in catalogue are merged into the existing book.
+// @Tags Book
+// @Accept application/marc
+// @Accept application/marcxml+xml
+// @Produce json
+// @Param format query string false "Record format, defaults to request Content-Type" Enums(marc, marcxml)
+// @Success 200 {object} models.BookImportReport "OK"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 415 {object} responses.ErrorResponse "Unsupported Media Type"
+// @Router /v1/book/import/marc [post]
+func (ctrl *BookImportController) ImportCatalogRecords(w http.ResponseWriter, r *http.Request) {
+	ctrl.importCatalogRecords(w, r, false)
+}
+
+func (ctrl *BookImportController) importCatalogRecords(w http.ResponseWriter, r *http.Request, dryRun bool) {
+	format, err := getImportFormat(r, catalogContentTypes)
+	if err != nil {
+		respondWithError(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+
+	records, err := pipelines.ReadCatalogRecords(r.Body, format)
+	if errors.Is(err, pipelines.ErrUnsupportedImportFormat) {
+		respondWithError(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+	if err != nil {
+		respondWithError(w, bodyErrorStatus(err, http.StatusBadRequest),
+			fmt.Sprintf("Failed read records: %s", err.Error()))
+		return
+	}
+
+	report := ctrl.bookImportPipeline.ImportCatalogRecords(r.Context(), records, dryRun)
+	respondWithJSON(w, http.StatusOK, report)
+}
+
+// getImportFormat returns import format from format query or request Content-Type
+func getImportFormat(r *http.Request, contentTypes map[string]pipelines.ImportFormat) (pipelines.ImportFormat, error) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return pipelines.ImportFormat(format), nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return "", errors.New("missing import format, set format query or Content-Type")
+	}
+	if format, ok := contentTypes[mediaType]; ok {
+		return format, nil
+	}
+	return "", fmt.Errorf("%w: %s", pipelines.ErrUnsupportedImportFormat, mediaType)
+}
@@ -0,0 +1,291 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+
+	"book-management-system/controllers/rest/responses"
+	"book-management-system/entities/models"
+	mocks "book-management-system/mocks/pipelines"
+	"book-management-system/usecases/pipelines"
+)
+
+const (
+	v1BookImportURL = "/v1/book/import"
+)
+
+func TestBookImportControllerImportBooks(t *testing.T) {
+	type input struct {
+		query       string
+		contentType string
+		body        string
+	}
+	type output struct {
+		code         int
+		location     string
+		responseBody interface{}
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     *mocks.MockBookImportPipeline
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "failed: unsupported content type",
+			givenInput: input{
+				contentType: "application/xml",
+				body:        "<books/>",
+			},
+			expectedOutput: output{
+				code: http.StatusUnsupportedMediaType,
+				responseBody: responses.ErrorResponse{
+					"error": "unsupported import format: application/xml",
+				},
+			},
+			configureMock: func(mockConfig) {
+				// do nothing
+			},
+		},
+		{
+			name: "failed: csv without name column",
+			givenInput: input{
+				contentType: "text/csv",
+				body:        "isbn\n1234\n",
+			},
+			expectedOutput: output{
+				code: http.StatusBadRequest,
+				responseBody: responses.ErrorResponse{
+					"error": "Failed read import file: invalid column mapping: header has no name column",
+				},
+			},
+			configureMock: func(mockConfig) {
+				// do nothing
+			},
+		},
+		{
+			name: "success: synchronous csv import",
+			givenInput: input{
+				query:       "?columns=name=Title",
+				contentType: "text/csv",
+				body:        "Title,ISBN\nC++,1234\n",
+			},
+			expectedOutput: output{
+				code: http.StatusOK,
+				responseBody: &models.BookImportReport{
+					Total:   1,
+					Created: 1,
+					Rows: []models.BookImportRow{
+						{Row: 2, Status: models.BookImportRowCreated, BookID: 1, ISBN: "1234"},
+					},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					ImportBooks(gomock.Any(), []pipelines.BookImportRecord{
+						{Row: 2, Book: models.Book{Name: "C++", ISBN: "1234"}},
+					}).
+					Return(conf.expected.responseBody)
+			},
+		},
+		{
+			name: "success: asynchronous jsonl import",
+			givenInput: input{
+				query: "?format=jsonl&async=true",
+				body:  `{"name":"C++","isbn":"1234"}`,
+			},
+			expectedOutput: output{
+				code:     http.StatusAccepted,
+				location: v1BookImportURL + "/3",
+				responseBody: &models.ImportJob{
+					Model:  gorm.Model{ID: 3},
+					Status: models.ImportJobPending,
+					Format: string(pipelines.ImportFormatJSONL),
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					StartImportJob(gomock.Any(), pipelines.ImportFormatJSONL, []pipelines.BookImportRecord{
+						{Row: 1, Book: models.Book{Name: "C++", ISBN: "1234"}},
+					}).
+					Return(conf.expected.responseBody, nil)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				v1BookImportURL+tt.givenInput.query,
+				strings.NewReader(tt.givenInput.body),
+			)
+			if tt.givenInput.contentType != "" {
+				req.Header.Set("Content-Type", tt.givenInput.contentType)
+			}
+			resp := httptest.NewRecorder()
+
+			bookImportPipelineMock := mocks.NewMockBookImportPipeline(ctrl)
+			tt.configureMock(mockConfig{
+				given:    tt.givenInput,
+				expected: tt.expectedOutput,
+				mock:     bookImportPipelineMock,
+			})
+
+			bookImportController := &BookImportController{
+				bookImportPipeline: bookImportPipelineMock,
+			}
+			bookImportController.ImportBooks(resp, req)
+
+			if resp.Code != tt.expectedOutput.code {
+				t.Errorf("ImportBooks() got status code %d\n expected %d",
+					resp.Code, tt.expectedOutput.code)
+			}
+			if location := resp.Header().Get("Location"); location != tt.expectedOutput.location {
+				t.Errorf("ImportBooks() got location %s\n expected %s",
+					location, tt.expectedOutput.location)
+			}
+			got := resp.Body.String()
+			expected, _ := json.Marshal(tt.expectedOutput.responseBody)
+			if got != string(expected) {
+				t.Errorf("ImportBooks() got response body %s\n expected %s",
+					got, string(expected))
+			}
+		})
+	}
+}
+
+func TestBookImportControllerPreviewCatalogRecords(t *testing.T) {
+	type input struct {
+		contentType string
+		body        string
+	}
+	type output struct {
+		code         int
+		responseBody interface{}
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     *mocks.MockBookImportPipeline
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "failed: unsupported content type",
+			givenInput: input{
+				contentType: "text/csv",
+				body:        "name\nC++\n",
+			},
+			expectedOutput: output{
+				code: http.StatusUnsupportedMediaType,
+				responseBody: responses.ErrorResponse{
+					"error": "unsupported import format: text/csv",
+				},
+			},
+			configureMock: func(mockConfig) {
+				// do nothing
+			},
+		},
+		{
+			name: "failed: malformed marcxml",
+			givenInput: input{
+				contentType: "application/marcxml+xml",
+				body:        "<record>",
+			},
+			expectedOutput: output{
+				code: http.StatusBadRequest,
+				responseBody: responses.ErrorResponse{
+					"error": "Failed read records: XML syntax error on line 1: unexpected EOF",
+				},
+			},
+			configureMock: func(mockConfig) {
+				// do nothing
+			},
+		},
+		{
+			name: "success: preview marcxml",
+			givenInput: input{
+				contentType: "application/marcxml+xml",
+				body: `<record><datafield tag="245" ind1="1" ind2="0">` +
+					`<subfield code="a">C++</subfield></datafield></record>`,
+			},
+			expectedOutput: output{
+				code: http.StatusOK,
+				responseBody: &models.BookImportReport{
+					DryRun:  true,
+					Total:   1,
+					Created: 1,
+					Rows: []models.BookImportRow{
+						{Row: 1, Status: models.BookImportRowCreated},
+					},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					ImportCatalogRecords(gomock.Any(), []pipelines.CatalogImportRecord{
+						{Row: 1, Record: models.CatalogRecord{Book: models.Book{Name: "C++"}}},
+					}, true).
+					Return(conf.expected.responseBody)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				v1BookImportURL+"/marc/preview",
+				strings.NewReader(tt.givenInput.body),
+			)
+			req.Header.Set("Content-Type", tt.givenInput.contentType)
+			resp := httptest.NewRecorder()
+
+			bookImportPipelineMock := mocks.NewMockBookImportPipeline(ctrl)
+			tt.configureMock(mockConfig{
+				given:    tt.givenInput,
+				expected: tt.expectedOutput,
+				mock:     bookImportPipelineMock,
+			})
+
+			bookImportController := &BookImportController{
+				bookImportPipeline: bookImportPipelineMock,
+			}
+			bookImportController.PreviewCatalogRecords(resp, req)
+
+			if resp.Code != tt.expectedOutput.code {
+				t.Errorf("PreviewCatalogRecords() got status code %d\n expected %d",
+					resp.Code, tt.expectedOutput.code)
+			}
+			got := resp.Body.String()
+			expected, _ := json.Marshal(tt.expectedOutput.responseBody)
+			if got != string(expected) {
+				t.Errorf("PreviewCatalogRecords() got response body %s\n expected %s",
+					got, string(expected))
+			}
+		})
+	}
+}
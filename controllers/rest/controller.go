@@ -1,20 +1,165 @@
 package rest
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/gorilla/mux"
+
+	"book-management-system/configs"
+	"book-management-system/controllers/rest/patches"
 	"book-management-system/controllers/rest/responses"
+	"book-management-system/entities/models"
+	"book-management-system/logging"
 )
 
+// jsonContentType is media type of JSON request and response bodies
+const jsonContentType = "application/json"
+
+// respondWithError responds with message and request ID set by requestID middleware, if any
 func respondWithError(w http.ResponseWriter, code int, message string) {
-	respondWithJSON(w, code, responses.ErrorResponse{"error": message})
+	response := responses.ErrorResponse{"error": message}
+	if id := w.Header().Get(logging.RequestIDHeader); id != "" {
+		response["request_id"] = id
+	}
+	respondWithJSON(w, code, response)
 }
 
 func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	response, _ := json.Marshal(payload)
 
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", jsonContentType)
 	w.WriteHeader(code)
 	_, _ = w.Write(response)
 }
+
+// respondWithCacheable responds with payload last modified at lastModified, or with 304 when the client
+// has it already. Clients may reuse it for cache.maxage seconds, then revalidate with If-Modified-Since.
+func respondWithCacheable(w http.ResponseWriter, r *http.Request, lastModified time.Time, payload interface{}) {
+	if maxAge := configs.GetConfig().Cache.MaxAge; maxAge > 0 {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+
+	if !lastModified.IsZero() {
+		// HTTP dates have second precision
+		lastModified = lastModified.UTC().Truncate(time.Second)
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !lastModified.After(since) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	respondWithJSON(w, http.StatusOK, payload)
+}
+
+// serviceErrorStatus maps service error into HTTP status code.
+// MovedError is not found, only reads redirect to the record it was merged into.
+func serviceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvalid):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// getIDFromPath returns id route variable
+func getIDFromPath(r *http.Request) (uint, error) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 0)
+	return uint(id), err
+}
+
+// requestError is returned by decodeJSON and applyPatch with HTTP status code to respond with
+type requestError struct {
+	code int
+	err  error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+// decodeJSON decodes JSON request body into v rejecting unknown fields and trailing data.
+// Content-Type other than JSON is rejected, missing one is taken as JSON. Empty body is error wrapping io.EOF.
+func decodeJSON(r *http.Request, v interface{}) *requestError {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || mediaType != jsonContentType {
+			return &requestError{
+				code: http.StatusUnsupportedMediaType,
+				err:  fmt.Errorf("Content-Type must be %s, got %q", jsonContentType, contentType),
+			}
+		}
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(v)
+	if err == nil {
+		if _, err = decoder.Token(); err == io.EOF {
+			return nil
+		} else if err == nil {
+			err = errors.New("unexpected data after JSON value")
+		}
+	}
+	return &requestError{code: bodyErrorStatus(err, http.StatusBadRequest), err: err}
+}
+
+// bodyErrorStatus returns status of error reading request body, 413 when body is over limit, else code
+func bodyErrorStatus(err error, code int) int {
+	if errors.Is(err, errBodyTooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return code
+}
+
+// applyPatch applies request body patch document to original and decodes the result into patched.
+// patched must point to zero value so members removed by patch are cleared.
+func applyPatch(r *http.Request, original, patched interface{}) *requestError {
+	patch, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return &requestError{code: bodyErrorStatus(err, http.StatusBadRequest), err: err}
+	}
+
+	doc, err := json.Marshal(original)
+	if err != nil {
+		return &requestError{code: http.StatusInternalServerError, err: err}
+	}
+
+	doc, err = patches.Apply(r.Header.Get("Content-Type"), doc, patch)
+	switch {
+	case errors.Is(err, patches.ErrUnsupportedContentType):
+		return &requestError{
+			code: http.StatusUnsupportedMediaType,
+			err: fmt.Errorf("%w, use %s or %s", err,
+				patches.MergePatchContentType, patches.JSONPatchContentType),
+		}
+	case errors.Is(err, patches.ErrTestFailed):
+		return &requestError{code: http.StatusConflict, err: err}
+	case err != nil:
+		return &requestError{code: http.StatusBadRequest, err: err}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(patched); err != nil {
+		return &requestError{code: http.StatusUnprocessableEntity, err: err}
+	}
+	return nil
+}
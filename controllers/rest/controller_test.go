@@ -2,6 +2,101 @@ package rest
 
 import (
 	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
 )
 
 var errService = errors.New("service error")
+
+func TestDecodeJSON(t *testing.T) {
+	type request struct {
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		code        int
+		isEOF       bool
+	}{
+		{name: "valid", body: `{"name":"Go"}`},
+		{name: "JSON with charset", contentType: "application/json; charset=utf-8", body: `{"name":"Go"}`},
+		{name: "other content type", contentType: "text/plain", body: `{"name":"Go"}`, code: http.StatusUnsupportedMediaType},
+		{name: "malformed content type", contentType: "application/", body: `{"name":"Go"}`, code: http.StatusUnsupportedMediaType},
+		{name: "unknown field", body: `{"name":"Go","title":"Go"}`, code: http.StatusBadRequest},
+		{name: "trailing data", body: `{"name":"Go"} {}`, code: http.StatusBadRequest},
+		{name: "trailing whitespace", body: "{\"name\":\"Go\"}\n"},
+		{name: "empty body", code: http.StatusBadRequest, isEOF: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/book", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			var v request
+			err := decodeJSON(req, &v)
+			switch {
+			case tt.code == 0 && err != nil:
+				t.Errorf("decodeJSON() got error %v", err)
+			case tt.code != 0 && (err == nil || err.code != tt.code):
+				t.Errorf("decodeJSON() got error %v\n expected status %d", err, tt.code)
+			case err != nil && errors.Is(err, io.EOF) != tt.isEOF:
+				t.Errorf("decodeJSON() got error %v, is EOF %v\n expected %v", err, !tt.isEOF, tt.isEOF)
+			}
+		})
+	}
+}
+
+func TestRespondWithCacheable(t *testing.T) {
+	lastModified := time.Date(2022, 3, 1, 10, 0, 0, 500, time.UTC)
+
+	tests := []struct {
+		name            string
+		lastModified    time.Time
+		ifModifiedSince string
+		code            int
+		expectedHeader  string
+	}{
+		{name: "no conditional header", lastModified: lastModified, code: http.StatusOK,
+			expectedHeader: "Tue, 01 Mar 2022 10:00:00 GMT"},
+		{name: "not modified since", lastModified: lastModified, ifModifiedSince: "Tue, 01 Mar 2022 10:00:00 GMT",
+			code: http.StatusNotModified, expectedHeader: "Tue, 01 Mar 2022 10:00:00 GMT"},
+		{name: "modified since", lastModified: lastModified, ifModifiedSince: "Tue, 01 Mar 2022 09:59:59 GMT",
+			code: http.StatusOK, expectedHeader: "Tue, 01 Mar 2022 10:00:00 GMT"},
+		{name: "malformed conditional header", lastModified: lastModified, ifModifiedSince: "yesterday",
+			code: http.StatusOK, expectedHeader: "Tue, 01 Mar 2022 10:00:00 GMT"},
+		{name: "unknown modification time", ifModifiedSince: "Tue, 01 Mar 2022 10:00:00 GMT", code: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/book", nil)
+			if tt.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", tt.ifModifiedSince)
+			}
+			rec := httptest.NewRecorder()
+
+			respondWithCacheable(rec, req, tt.lastModified, map[string]string{"name": "Go"})
+			if rec.Code != tt.code {
+				t.Errorf("respondWithCacheable() got status %d\n expected %d", rec.Code, tt.code)
+			}
+			if got := rec.Header().Get("Last-Modified"); got != tt.expectedHeader {
+				t.Errorf("respondWithCacheable() got Last-Modified %q\n expected %q", got, tt.expectedHeader)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != "no-cache" {
+				t.Errorf("respondWithCacheable() got Cache-Control %q\n expected %q", got, "no-cache")
+			}
+			if tt.code == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("respondWithCacheable() got body %q\n expected none", rec.Body.String())
+			}
+		})
+	}
+}
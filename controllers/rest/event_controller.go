@@ -0,0 +1,290 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+
+	"book-management-system/configs"
+	"book-management-system/events"
+	"book-management-system/metrics"
+	"book-management-system/usecases"
+	"book-management-system/usecases/services"
+)
+
+// Transports of the change feed
+const (
+	transportSSE       = "sse"
+	transportWebSocket = "websocket"
+)
+
+// resetMessage tells the subscriber that events since its Last-Event-ID are no longer kept,
+// it should reload the state it follows before applying next events
+var resetMessage = []byte(`{"type":"reset"}`)
+
+const (
+	// wsWriteWait limits writing of one WebSocket message to a slow client
+	wsWriteWait = 10 * time.Second
+	// wsReadLimit limits messages of subscribers, they are expected to send none
+	wsReadLimit = 512
+)
+
+// EventController will stream the change feed over Server-Sent Events or WebSocket
+type EventController struct {
+	eventService services.EventService
+	heartbeat    time.Duration
+	// sseTimeout ends SSE streams before server write timeout cuts them,
+	// clients reconnect with Last-Event-ID and resume where they stopped
+	sseTimeout time.Duration
+	upgrader   websocket.Upgrader
+	done       chan struct{}
+	shutDown   sync.Once
+}
+
+// NewEventController returns new EventController accepting WebSocket subscribers of origins allowed by crossOrigin
+func NewEventController(route *mux.Router, useCase *usecases.UseCase, crossOrigin *cors) *EventController {
+	cfg := configs.GetConfig()
+	ctrl := &EventController{
+		eventService: useCase.Service.EventService,
+		heartbeat:    time.Duration(cfg.Events.Heartbeat) * time.Second,
+		sseTimeout:   time.Duration(cfg.Server.WriteTimeout) * time.Second * 9 / 10,
+		upgrader:     websocket.Upgrader{CheckOrigin: checkOrigin(crossOrigin)},
+		done:         make(chan struct{}),
+	}
+
+	v1Route := route.PathPrefix("/v1").Subrouter()
+	v1Route.HandleFunc("/events", ctrl.GetEvents).Methods(http.MethodGet)
+
+	return ctrl
+}
+
+// ShutDown ends streams of subscribers, so that they reconnect to another instance
+func (ctrl *EventController) ShutDown() {
+	ctrl.shutDown.Do(func() {
+		close(ctrl.done)
+	})
+}
+
+// GetEvents handle change feed subscription request
+// @Summary Stream changes
+// @Description Stream domain events as Server-Sent Events, or as WebSocket text messages when the request
+// @Description upgrades to WebSocket. Every message is an event object with id, type, time and data.
+// @Description Subscribers resuming after Last-Event-ID first receive events published since, or a message of
+// @Description type reset when those are no longer kept. Subscribers falling behind are disconnected.
+// @Tags Event
+// @Produce text/event-stream
+// @Param types query string false "Comma separated event types, all types when empty"
+// @Param Last-Event-ID header int false "ID of last received event to resume after"
+// @Param last_event_id query int false "Last-Event-ID of clients that cannot set headers"
+// @Success 200 {object} events.Event "Event stream"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 422 {object} responses.ErrorResponse "Unprocessable Entity"
+// @Router /v1/events [get]
+func (ctrl *EventController) GetEvents(w http.ResponseWriter, r *http.Request) {
+	var types []string
+	if param := r.URL.Query().Get("types"); param != "" {
+		for _, t := range strings.Split(param, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				types = append(types, t)
+			}
+		}
+	}
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("last_event_id")
+	}
+	var lastEventID uint64
+	if lastID != "" {
+		id, err := strconv.ParseUint(lastID, 10, 64)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid Last-Event-ID")
+			return
+		}
+		lastEventID = id
+	}
+
+	sub, err := ctrl.eventService.Subscribe(r.Context(), types, lastEventID)
+	if err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed subscribe to events: %s", err.Error()))
+		return
+	}
+	defer sub.Close()
+
+	if websocket.IsWebSocketUpgrade(r) {
+		ctrl.streamWebSocket(w, r, sub)
+	} else {
+		ctrl.streamSSE(w, r, sub)
+	}
+}
+
+// streamSSE writes events as unnamed Server-Sent Events, so that EventSource onmessage receives all of them
+func (ctrl *EventController) streamSSE(w http.ResponseWriter, r *http.Request, sub *events.Subscription) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondWithError(w, http.StatusInternalServerError, "Streaming is not supported")
+		return
+	}
+	metrics.EventSubscribers.WithLabelValues(transportSSE).Inc()
+	defer metrics.EventSubscribers.WithLabelValues(transportSSE).Dec()
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	// keeps reverse proxies from buffering the stream
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// streams end by sseTimeout, clients reconnect at once
+	_, err := io.WriteString(w, "retry: 1000\n\n")
+	if err == nil && sub.Reset {
+		_, err = fmt.Fprintf(w, "data: %s\n\n", resetMessage)
+	}
+	if err != nil {
+		return
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(ctrl.heartbeat)
+	defer heartbeat.Stop()
+	timeout := time.NewTimer(ctrl.sseTimeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				// resuming with Last-Event-ID catches up from the event log
+				if sub.Overflowed() {
+					metrics.EventSubscribersDropped.WithLabelValues(transportSSE).Inc()
+				}
+				return
+			}
+			err = writeSSE(w, event)
+		case <-heartbeat.C:
+			_, err = io.WriteString(w, ": heartbeat\n\n")
+		case <-timeout.C:
+			return
+		case <-r.Context().Done():
+			return
+		case <-ctrl.done:
+			return
+		}
+		if err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func writeSSE(w io.Writer, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", event.ID, data)
+	return err
+}
+
+// streamWebSocket writes events as WebSocket text messages and pings the client every heartbeat
+func (ctrl *EventController) streamWebSocket(w http.ResponseWriter, r *http.Request, sub *events.Subscription) {
+	conn, err := ctrl.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade responded with error status
+		return
+	}
+	defer conn.Close()
+	metrics.EventSubscribers.WithLabelValues(transportWebSocket).Inc()
+	defer metrics.EventSubscribers.WithLabelValues(transportWebSocket).Dec()
+
+	// reading handles control frames and notices clients gone without closing, missing two pongs
+	closed := make(chan struct{})
+	conn.SetReadLimit(wsReadLimit)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * ctrl.heartbeat))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * ctrl.heartbeat))
+	})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if sub.Reset {
+		if err := writeWebSocket(conn, resetMessage); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(ctrl.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				if sub.Overflowed() {
+					metrics.EventSubscribersDropped.WithLabelValues(transportWebSocket).Inc()
+					closeWebSocket(conn, websocket.CloseTryAgainLater, "subscriber fell behind, resume with last_event_id")
+				}
+				return
+			}
+			data, err := json.Marshal(event)
+			if err == nil {
+				err = writeWebSocket(conn, data)
+			}
+			if err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		case <-ctrl.done:
+			closeWebSocket(conn, websocket.CloseGoingAway, "server shutting down")
+			return
+		}
+	}
+}
+
+func writeWebSocket(conn *websocket.Conn, data []byte) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(wsWriteWait)); err != nil {
+		return err
+	}
+	return conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func closeWebSocket(conn *websocket.Conn, code int, reason string) {
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(wsWriteWait))
+}
+
+// checkOrigin accepts WebSocket upgrades of clients without origin, of the same origin and of origins
+// allowed by CORS, so that other pages cannot subscribe with credentials of the user
+func checkOrigin(c *cors) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+		return c.allowsOrigin(strings.TrimSuffix(origin, "/"))
+	}
+}
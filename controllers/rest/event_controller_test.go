@@ -0,0 +1,224 @@
+package rest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+
+	"book-management-system/configs"
+	"book-management-system/entities/models"
+	"book-management-system/events"
+	mocks "book-management-system/mocks/services"
+	"book-management-system/usecases"
+	"book-management-system/usecases/services"
+)
+
+const v1EventsURL = "/v1/events"
+
+// newEventServer serves EventController of feed, subscribing through mock of EventService
+func newEventServer(t *testing.T, feed *events.Feed) (*httptest.Server, *EventController) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	eventService := mocks.NewMockEventService(ctrl)
+	eventService.EXPECT().
+		Subscribe(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, types []string, lastEventID uint64) (*events.Subscription, error) {
+			return feed.Subscribe(types, lastEventID, 10), nil
+		}).
+		AnyTimes()
+
+	route := mux.NewRouter()
+	eventCtrl := NewEventController(route, &usecases.UseCase{
+		Service: &services.Services{EventService: eventService},
+	}, newCORS(configs.CORSConfig{}))
+	server := httptest.NewServer(route)
+	t.Cleanup(server.Close)
+	return server, eventCtrl
+}
+
+func TestEventControllerGetEventsError(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		lastEventID string
+		serviceErr  error
+		code        int
+		expected    string
+	}{
+		{
+			name:        "invalid Last-Event-ID",
+			url:         v1EventsURL,
+			lastEventID: "first",
+			code:        http.StatusBadRequest,
+			expected:    "Invalid Last-Event-ID",
+		},
+		{
+			name:     "invalid last_event_id",
+			url:      v1EventsURL + "?last_event_id=-1",
+			code:     http.StatusBadRequest,
+			expected: "Invalid Last-Event-ID",
+		},
+		{
+			name:       "unknown type",
+			url:        v1EventsURL + "?types=book.created,+loan.created",
+			serviceErr: fmt.Errorf("%w: unknown event type %q", models.ErrInvalid, "loan.created"),
+			code:       http.StatusUnprocessableEntity,
+			expected:   `Failed subscribe to events: invalid model: unknown event type "loan.created"`,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventService := mocks.NewMockEventService(ctrl)
+			if tt.serviceErr != nil {
+				eventService.EXPECT().
+					Subscribe(gomock.Any(), []string{events.TypeBookCreated, "loan.created"}, uint64(0)).
+					Return(nil, tt.serviceErr)
+			}
+			route := mux.NewRouter()
+			NewEventController(route, &usecases.UseCase{
+				Service: &services.Services{EventService: eventService},
+			}, newCORS(configs.CORSConfig{}))
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+			rec := httptest.NewRecorder()
+			route.ServeHTTP(rec, req)
+
+			var body map[string]string
+			_ = json.Unmarshal(rec.Body.Bytes(), &body)
+			if rec.Code != tt.code || body["error"] != tt.expected {
+				t.Errorf("GetEvents() got %d %q\n expected %d %q",
+					rec.Code, body["error"], tt.code, tt.expected)
+			}
+		})
+	}
+}
+
+// readSSE returns data of next message of stream, skipping comments and fields other than data
+func readSSE(t *testing.T, stream *bufio.Reader) string {
+	t.Helper()
+	for {
+		line, err := stream.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read event stream: %v", err)
+		}
+		if data := strings.TrimPrefix(line, "data: "); data != line {
+			return strings.TrimSpace(data)
+		}
+	}
+}
+
+func TestEventControllerSSE(t *testing.T) {
+	feed := events.NewFeed(10)
+	server, eventCtrl := newEventServer(t, feed)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+v1EventsURL+"?types=book.created", nil)
+	req.Header.Set("Last-Event-ID", "7")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GetEvents() got error %v", err)
+	}
+	defer resp.Body.Close()
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("GetEvents() got Content-Type %q\n expected %q", contentType, "text/event-stream")
+	}
+	stream := bufio.NewReader(resp.Body)
+
+	// event of previous run is not kept
+	if data := readSSE(t, stream); data != string(resetMessage) {
+		t.Errorf("GetEvents() sent %s\n expected %s", data, resetMessage)
+	}
+
+	_ = feed.Publish(events.TypeMemberCreated, models.Member{Name: "John Lennon"})
+	_ = feed.Publish(events.TypeBookCreated, models.Book{Name: "Go"})
+	var event events.Event
+	if err := json.Unmarshal([]byte(readSSE(t, stream)), &event); err != nil {
+		t.Fatalf("GetEvents() sent event not in JSON: %v", err)
+	}
+	if event.Type != events.TypeBookCreated || !strings.Contains(string(event.Data), `"name":"Go"`) {
+		t.Errorf("GetEvents() sent %+v\n expected %s event of book Go", event, events.TypeBookCreated)
+	}
+
+	eventCtrl.ShutDown()
+	if rest, err := ioutil.ReadAll(stream); err != nil || strings.TrimSpace(string(rest)) != "" {
+		t.Errorf("GetEvents() stream continued with %q, %v after ShutDown", rest, err)
+	}
+}
+
+func TestEventControllerWebSocket(t *testing.T) {
+	feed := events.NewFeed(10)
+	server, eventCtrl := newEventServer(t, feed)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + v1EventsURL
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("GetEvents() got error %v", err)
+	}
+	defer conn.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("GetEvents() got status %d\n expected %d", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+
+	// subscription is made before upgrade, publishing after dial cannot be missed
+	_ = feed.Publish(events.TypeMemberUpdated, models.Member{Name: "John Lennon"})
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var event events.Event
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatalf("GetEvents() got error %v", err)
+	}
+	if event.Type != events.TypeMemberUpdated {
+		t.Errorf("GetEvents() sent %+v\n expected %s event", event, events.TypeMemberUpdated)
+	}
+
+	eventCtrl.ShutDown()
+	_, _, err = conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("GetEvents() got error %v after ShutDown\n expected close %d", err, websocket.CloseGoingAway)
+	}
+}
+
+func TestCheckOrigin(t *testing.T) {
+	crossOrigin := newCORS(configs.CORSConfig{AllowedOrigins: []string{"https://kiosk.example.com"}})
+	check := checkOrigin(crossOrigin)
+	// reloaded CORS config applies to WebSocket upgrades too
+	crossOrigin.configure(configs.CORSConfig{AllowedOrigins: []string{"https://desk.example.com"}})
+
+	tests := []struct {
+		origin   string
+		expected bool
+	}{
+		{origin: "", expected: true},
+		{origin: "http://api.example.com", expected: true},
+		{origin: "https://desk.example.com", expected: true},
+		{origin: "https://evil.example.com", expected: false},
+		{origin: "https://kiosk.example.com", expected: false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://api.example.com"+v1EventsURL, nil)
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		if got := check(req); got != tt.expected {
+			t.Errorf("checkOrigin() of origin %q returns %v\n expected %v", tt.origin, got, tt.expected)
+		}
+	}
+}
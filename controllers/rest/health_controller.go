@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gorilla/mux"
+
+	"book-management-system/health"
+	"book-management-system/usecases"
+	"book-management-system/usecases/services"
+)
+
+// HealthController will handle liveness and readiness probes
+type HealthController struct {
+	healthService services.HealthService
+	shuttingDown  int32
+}
+
+// NewHealthController returns new HealthController
+func NewHealthController(route *mux.Router, useCase *usecases.UseCase) *HealthController {
+	ctrl := &HealthController{
+		healthService: useCase.Service.HealthService,
+	}
+
+	route.HandleFunc("/healthz", ctrl.Liveness).Methods(http.MethodGet)
+	route.HandleFunc("/readyz", ctrl.Readiness).Methods(http.MethodGet)
+
+	return ctrl
+}
+
+// ShutDown makes readiness fail, so that no new traffic is routed while connections drain
+func (ctrl *HealthController) ShutDown() {
+	atomic.StoreInt32(&ctrl.shuttingDown, 1)
+}
+
+// Liveness handle liveness probe request
+// @Summary Check liveness
+// @Description Check that background workers respond, failing liveness means the service should be restarted
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Report "OK"
+// @Failure 503 {object} health.Report "Service Unavailable"
+// @Router /healthz [get]
+func (ctrl *HealthController) Liveness(w http.ResponseWriter, r *http.Request) {
+	respondWithReport(w, ctrl.healthService.Liveness(r.Context()))
+}
+
+// Readiness handle readiness probe request
+// @Summary Check readiness
+// @Description Check database and search backends, failing readiness means no traffic should be routed to the service
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Report "OK"
+// @Failure 503 {object} health.Report "Service Unavailable"
+// @Router /readyz [get]
+func (ctrl *HealthController) Readiness(w http.ResponseWriter, r *http.Request) {
+	if atomic.LoadInt32(&ctrl.shuttingDown) == 1 {
+		respondWithReport(w, health.Report{
+			Status: health.StatusDown,
+			Checks: map[string]health.Result{
+				"server": {Status: health.StatusDown, Error: health.ErrShuttingDown.Error()},
+			},
+		})
+		return
+	}
+	respondWithReport(w, ctrl.healthService.Readiness(r.Context()))
+}
+
+func respondWithReport(w http.ResponseWriter, report health.Report) {
+	w.Header().Set("Cache-Control", "no-store")
+	if !report.Up() {
+		respondWithJSON(w, http.StatusServiceUnavailable, report)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, report)
+}
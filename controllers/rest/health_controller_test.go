@@ -0,0 +1,148 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+
+	"book-management-system/health"
+	mocks "book-management-system/mocks/services"
+	"book-management-system/repositories"
+	"book-management-system/usecases"
+	"book-management-system/usecases/services"
+)
+
+func TestNewHealthController(t *testing.T) {
+	repo := &repositories.Repository{}
+	healthService := services.NewHealthService(repo)
+	usecase := &usecases.UseCase{
+		Service: &services.Services{
+			HealthService: healthService,
+		},
+	}
+
+	route := mux.NewRouter()
+	got := NewHealthController(route, usecase)
+	expected := &HealthController{
+		healthService: healthService,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewHealthController returns %+v\n expected %+v",
+			got, expected)
+	}
+}
+
+func TestHealthController(t *testing.T) {
+	up := health.Report{
+		Status: health.StatusUp,
+		Checks: map[string]health.Result{"database": {Status: health.StatusUp, Latency: 1.5}},
+	}
+	down := health.Report{
+		Status: health.StatusDown,
+		Checks: map[string]health.Result{"search": {Status: health.StatusDown, Error: "connection refused"}},
+	}
+	shuttingDown := health.Report{
+		Status: health.StatusDown,
+		Checks: map[string]health.Result{"server": {Status: health.StatusDown, Error: "shutting down"}},
+	}
+
+	type output struct {
+		code   int
+		report health.Report
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		shutDown       bool
+		expectedOutput output
+		configureMock  func(*mocks.MockHealthService)
+	}{
+		{
+			name:           "success: alive",
+			path:           "/healthz",
+			expectedOutput: output{code: http.StatusOK, report: up},
+			configureMock: func(mock *mocks.MockHealthService) {
+				mock.EXPECT().Liveness(gomock.Any()).Return(up)
+			},
+		},
+		{
+			name:           "failed: not alive",
+			path:           "/healthz",
+			expectedOutput: output{code: http.StatusServiceUnavailable, report: down},
+			configureMock: func(mock *mocks.MockHealthService) {
+				mock.EXPECT().Liveness(gomock.Any()).Return(down)
+			},
+		},
+		{
+			name:           "success: ready",
+			path:           "/readyz",
+			expectedOutput: output{code: http.StatusOK, report: up},
+			configureMock: func(mock *mocks.MockHealthService) {
+				mock.EXPECT().Readiness(gomock.Any()).Return(up)
+			},
+		},
+		{
+			name:           "failed: backend down",
+			path:           "/readyz",
+			expectedOutput: output{code: http.StatusServiceUnavailable, report: down},
+			configureMock: func(mock *mocks.MockHealthService) {
+				mock.EXPECT().Readiness(gomock.Any()).Return(down)
+			},
+		},
+		{
+			name:           "failed: shutting down",
+			path:           "/readyz",
+			shutDown:       true,
+			expectedOutput: output{code: http.StatusServiceUnavailable, report: shuttingDown},
+			configureMock: func(mock *mocks.MockHealthService) {
+				// backends are not checked while draining
+			},
+		},
+		{
+			name:           "success: alive while shutting down",
+			path:           "/healthz",
+			shutDown:       true,
+			expectedOutput: output{code: http.StatusOK, report: up},
+			configureMock: func(mock *mocks.MockHealthService) {
+				mock.EXPECT().Liveness(gomock.Any()).Return(up)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := mocks.NewMockHealthService(ctrl)
+			tt.configureMock(mock)
+
+			route := mux.NewRouter()
+			healthCtrl := NewHealthController(route, &usecases.UseCase{
+				Service: &services.Services{HealthService: mock},
+			})
+			if tt.shutDown {
+				healthCtrl.ShutDown()
+			}
+
+			rec := httptest.NewRecorder()
+			route.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			var report health.Report
+			if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+				t.Fatalf("response is not JSON: %v", err)
+			}
+			if rec.Code != tt.expectedOutput.code || !reflect.DeepEqual(report, tt.expectedOutput.report) {
+				t.Errorf("GET %s got %d %+v\n expected %d %+v",
+					tt.path, rec.Code, report, tt.expectedOutput.code, tt.expectedOutput.report)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"book-management-system/configs"
+	"book-management-system/entities/models"
+	"book-management-system/health"
+	"book-management-system/logging"
+	"book-management-system/usecases/services"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyPollInterval   = 100 * time.Millisecond
+	idempotencyPurgeInterval  = time.Hour
+	defaultIdempotencyKeyTTL  = 24 * time.Hour
+	defaultIdempotencyWaiting = 5 * time.Second
+)
+
+// idempotencyMiddleware replays stored response of POST request retried with the same Idempotency-Key header.
+// Keys sent with a configured API key are scoped per key, so that a caller reusing the key of another one
+// never receives its response. Keys of anonymous callers are global, as their address changes between retries
+// or is shared behind a proxy, and a key reused with a different request is rejected by its request hash.
+type idempotencyMiddleware struct {
+	idempotencyService services.IdempotencyService
+	callers            callerIdentifier
+	ttl                time.Duration
+	waitTimeout        time.Duration
+	// lockRefresh is how often lock of key is extended while the request runs
+	lockRefresh time.Duration
+}
+
+// callerIdentifier tells authenticated callers apart by configured API key like rate limiting
+type callerIdentifier interface {
+	// principal returns identity of the caller, empty for anonymous ones
+	principal(r *http.Request) string
+}
+
+func newIdempotencyMiddleware(
+	svc services.IdempotencyService,
+	cfg configs.IdempotencyConfig,
+	callers callerIdentifier,
+) *idempotencyMiddleware {
+	middleware := &idempotencyMiddleware{
+		idempotencyService: svc,
+		callers:            callers,
+		ttl:                time.Duration(cfg.TTL) * time.Hour,
+		waitTimeout:        time.Duration(cfg.WaitTimeout) * time.Second,
+		lockRefresh:        services.IdempotencyLockRefresh,
+	}
+	if middleware.ttl <= 0 {
+		middleware.ttl = defaultIdempotencyKeyTTL
+	}
+	if middleware.waitTimeout <= 0 {
+		middleware.waitTimeout = defaultIdempotencyWaiting
+	}
+	return middleware
+}
+
+// Middleware implements mux.MiddlewareFunc
+func (m *idempotencyMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if r.Method != http.MethodPost || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			respondWithError(w, http.StatusBadRequest,
+				fmt.Sprintf("%s header must not exceed %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength))
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			respondWithError(w, bodyErrorStatus(err, http.StatusBadRequest),
+				fmt.Sprintf("Invalid request payload: %s", err.Error()))
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		scopedKey := scopeIdempotencyKey(m.callers.principal(r), key)
+		idempotencyKey, err := m.beginRequest(r.Context(), scopedKey, hashRequest(r, body))
+		switch {
+		case errors.Is(err, models.ErrIdempotencyKeyMismatch):
+			respondWithError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		case errors.Is(err, models.ErrIdempotencyKeyInProgress):
+			respondWithError(w, http.StatusConflict, err.Error())
+			return
+		case err != nil:
+			respondWithError(w, http.StatusInternalServerError,
+				fmt.Sprintf("Failed check idempotency key: %s", err.Error()))
+			return
+		}
+
+		if idempotencyKey.Completed {
+			w.Header().Set(idempotentReplayedHeader, "true")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(idempotencyKey.StatusCode)
+			_, _ = w.Write(idempotencyKey.ResponseBody)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		unlock := m.keepLocked(r, idempotencyKey)
+		next.ServeHTTP(recorder, r)
+		unlock()
+
+		// server errors are not stored so the client can retry the request
+		ctx := context.Background()
+		if recorder.statusCode >= http.StatusInternalServerError {
+			if err := m.idempotencyService.ReleaseRequest(ctx, idempotencyKey); err != nil {
+				logging.FromContext(r.Context(), logging.PackageRest).
+					Error("failed to release idempotency key", zap.String("key", key), zap.Error(err))
+			}
+			return
+		}
+
+		idempotencyKey.StatusCode = recorder.statusCode
+		idempotencyKey.ResponseBody = recorder.body.Bytes()
+		if err := m.idempotencyService.CompleteRequest(ctx, idempotencyKey); err != nil {
+			logging.FromContext(r.Context(), logging.PackageRest).
+				Error("failed to complete idempotency key", zap.String("key", key), zap.Error(err))
+		}
+	})
+}
+
+// beginRequest waits until concurrent request with the same key finishes or wait timeout is reached
+func (m *idempotencyMiddleware) beginRequest(
+	ctx context.Context,
+	key, requestHash string,
+) (*models.IdempotencyKey, error) {
+	deadline := time.Now().Add(m.waitTimeout)
+	for {
+		idempotencyKey, err := m.idempotencyService.BeginRequest(ctx, key, requestHash, m.ttl)
+		if !errors.Is(err, models.ErrIdempotencyKeyInProgress) || time.Now().After(deadline) {
+			return idempotencyKey, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(idempotencyPollInterval):
+		}
+	}
+}
+
+// keepLocked extends lock of key of request r until returned unlock is called,
+// so that a retry of a long running request is not mistaken for a retry of a crashed one
+func (m *idempotencyMiddleware) keepLocked(r *http.Request, key *models.IdempotencyKey) (unlock func()) {
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(m.lockRefresh)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				err := m.idempotencyService.ExtendRequest(context.Background(), key)
+				if err != nil {
+					logging.FromContext(r.Context(), logging.PackageRest).Error("failed to extend idempotency key lock",
+						zap.String("key", r.Header.Get(idempotencyKeyHeader)), zap.Error(err))
+				}
+				if errors.Is(err, models.ErrIdempotencyKeyLost) {
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-stopped
+	}
+}
+
+// purgeExpiredKeys periodically removes expired idempotency keys until ctx is done
+func (m *idempotencyMiddleware) purgeExpiredKeys(ctx context.Context) {
+	ticker := time.NewTicker(idempotencyPurgeInterval)
+	defer ticker.Stop()
+	// purge stuck for longer than an interval fails liveness
+	heartbeat := health.NewHeartbeat("idempotency key purge", 2*idempotencyPurgeInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.idempotencyService.PurgeExpiredKeys(ctx); err != nil {
+				logging.Logger(logging.PackageRest).Error("failed to purge expired idempotency keys", zap.Error(err))
+			}
+			heartbeat.Beat()
+		}
+	}
+}
+
+// scopeIdempotencyKey returns stored key of Idempotency-Key header value sent by principal, empty for anonymous
+// callers, hashed to fit the key column whatever the lengths of both
+func scopeIdempotencyKey(caller, key string) string {
+	sum := sha256.Sum256([]byte(caller + "\n" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashRequest returns digest of request method, route and body
+func hashRequest(r *http.Request, body []byte) string {
+	path := r.URL.Path
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			path = template
+		}
+	}
+
+	hash := sha256.New()
+	_, _ = fmt.Fprintf(hash, "%s %s\n", r.Method, path)
+	_, _ = hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder captures status code and body written by the next handler
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(statusCode int) {
+	rec.statusCode = statusCode
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
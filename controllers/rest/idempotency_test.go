@@ -0,0 +1,380 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"book-management-system/configs"
+	"book-management-system/entities/models"
+	mocks "book-management-system/mocks/services"
+	"book-management-system/ratelimit"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	type input struct {
+		method      string
+		key         string
+		remote      string
+		handlerCode int
+	}
+	type output struct {
+		code         int
+		responseBody string
+		replayed     bool
+		handlerCalls int
+	}
+	type mockConfig struct {
+		given     input
+		storedKey string
+		mock      *mocks.MockIdempotencyService
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "request without idempotency key",
+			givenInput: input{
+				method:      http.MethodPost,
+				handlerCode: http.StatusCreated,
+			},
+			expectedOutput: output{
+				code:         http.StatusCreated,
+				responseBody: `{"name":"C++"}`,
+				handlerCalls: 1,
+			},
+			configureMock: func(mockConfig) {
+				// do nothing
+			},
+		},
+		{
+			name: "non POST request",
+			givenInput: input{
+				method:      http.MethodPut,
+				key:         "key",
+				handlerCode: http.StatusOK,
+			},
+			expectedOutput: output{
+				code:         http.StatusOK,
+				responseBody: `{"name":"C++"}`,
+				handlerCalls: 1,
+			},
+			configureMock: func(mockConfig) {
+				// do nothing
+			},
+		},
+		{
+			name: "first request stores response",
+			givenInput: input{
+				method:      http.MethodPost,
+				key:         "key",
+				handlerCode: http.StatusCreated,
+			},
+			expectedOutput: output{
+				code:         http.StatusCreated,
+				responseBody: `{"name":"C++"}`,
+				handlerCalls: 1,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					BeginRequest(gomock.Any(), conf.storedKey, gomock.Any(), time.Hour).
+					Return(&models.IdempotencyKey{Key: conf.storedKey}, nil)
+				conf.mock.EXPECT().
+					CompleteRequest(gomock.Any(), &models.IdempotencyKey{
+						Key:          conf.storedKey,
+						StatusCode:   http.StatusCreated,
+						ResponseBody: []byte(`{"name":"C++"}`),
+					}).
+					Return(nil)
+			},
+		},
+		{
+			name: "same key of anonymous caller from another address is shared",
+			givenInput: input{
+				method:      http.MethodPost,
+				key:         "key",
+				remote:      "198.51.100.7:41000",
+				handlerCode: http.StatusCreated,
+			},
+			expectedOutput: output{
+				code:         http.StatusCreated,
+				responseBody: `{"name":"C++"}`,
+				handlerCalls: 1,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					BeginRequest(gomock.Any(), conf.storedKey, gomock.Any(), time.Hour).
+					Return(&models.IdempotencyKey{Key: conf.storedKey}, nil)
+				conf.mock.EXPECT().
+					CompleteRequest(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+		},
+		{
+			name: "retried request replays stored response",
+			givenInput: input{
+				method: http.MethodPost,
+				key:    "key",
+			},
+			expectedOutput: output{
+				code:         http.StatusCreated,
+				responseBody: `{"name":"stored"}`,
+				replayed:     true,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					BeginRequest(gomock.Any(), conf.storedKey, gomock.Any(), time.Hour).
+					Return(&models.IdempotencyKey{
+						Key:          conf.storedKey,
+						Completed:    true,
+						StatusCode:   http.StatusCreated,
+						ResponseBody: []byte(`{"name":"stored"}`),
+					}, nil)
+			},
+		},
+		{
+			name: "key reused with different body",
+			givenInput: input{
+				method: http.MethodPost,
+				key:    "key",
+			},
+			expectedOutput: output{
+				code: http.StatusUnprocessableEntity,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					BeginRequest(gomock.Any(), conf.storedKey, gomock.Any(), time.Hour).
+					Return(nil, models.ErrIdempotencyKeyMismatch)
+			},
+		},
+		{
+			name: "concurrent request still in progress",
+			givenInput: input{
+				method: http.MethodPost,
+				key:    "key",
+			},
+			expectedOutput: output{
+				code: http.StatusConflict,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					BeginRequest(gomock.Any(), conf.storedKey, gomock.Any(), time.Hour).
+					Return(nil, models.ErrIdempotencyKeyInProgress).
+					MinTimes(1)
+			},
+		},
+		{
+			name: "server error releases key",
+			givenInput: input{
+				method:      http.MethodPost,
+				key:         "key",
+				handlerCode: http.StatusInternalServerError,
+			},
+			expectedOutput: output{
+				code:         http.StatusInternalServerError,
+				responseBody: `{"name":"C++"}`,
+				handlerCalls: 1,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					BeginRequest(gomock.Any(), conf.storedKey, gomock.Any(), time.Hour).
+					Return(&models.IdempotencyKey{Key: conf.storedKey}, nil)
+				conf.mock.EXPECT().
+					ReleaseRequest(gomock.Any(), &models.IdempotencyKey{Key: conf.storedKey}).
+					Return(nil)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := tt.givenInput.remote
+			if remote == "" {
+				remote = "192.0.2.1:41000"
+			}
+
+			idempotencyServiceMock := mocks.NewMockIdempotencyService(ctrl)
+			tt.configureMock(mockConfig{
+				given:     tt.givenInput,
+				storedKey: scopeIdempotencyKey("", tt.givenInput.key),
+				mock:      idempotencyServiceMock,
+			})
+
+			middleware := &idempotencyMiddleware{
+				idempotencyService: idempotencyServiceMock,
+				callers:            newRateLimiter(ratelimit.NewMemoryStore(), configs.RateLimitConfig{}),
+				ttl:                time.Hour,
+				waitTimeout:        time.Millisecond,
+				lockRefresh:        time.Hour,
+			}
+
+			handlerCalls := 0
+			handler := middleware.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerCalls++
+				respondWithJSON(w, tt.givenInput.handlerCode, map[string]string{"name": "C++"})
+			}))
+
+			req, _ := http.NewRequest(
+				tt.givenInput.method,
+				v1BookURL,
+				strings.NewReader(`{"name":"C++"}`),
+			)
+			req.RemoteAddr = remote
+			if tt.givenInput.key != "" {
+				req.Header.Set(idempotencyKeyHeader, tt.givenInput.key)
+			}
+			resp := httptest.NewRecorder()
+
+			handler.ServeHTTP(resp, req)
+
+			if resp.Code != tt.expectedOutput.code {
+				t.Errorf("Middleware() got status code %d\n expected %d",
+					resp.Code, tt.expectedOutput.code)
+			}
+			if body := tt.expectedOutput.responseBody; body != "" && resp.Body.String() != body {
+				t.Errorf("Middleware() got response body %s\n expected %s",
+					resp.Body.String(), body)
+			}
+			if replayed := resp.Header().Get(idempotentReplayedHeader) == "true"; replayed != tt.expectedOutput.replayed {
+				t.Errorf("Middleware() got replayed %t\n expected %t",
+					replayed, tt.expectedOutput.replayed)
+			}
+			if handlerCalls != tt.expectedOutput.handlerCalls {
+				t.Errorf("Middleware() called handler %d times\n expected %d",
+					handlerCalls, tt.expectedOutput.handlerCalls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddlewareKeepsKeyLocked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key := &models.IdempotencyKey{Key: scopeIdempotencyKey("", "key"), Token: "token"}
+	idempotencyServiceMock := mocks.NewMockIdempotencyService(ctrl)
+	idempotencyServiceMock.EXPECT().
+		BeginRequest(gomock.Any(), key.Key, gomock.Any(), time.Hour).
+		Return(key, nil)
+	extended := make(chan struct{}, 1)
+	idempotencyServiceMock.EXPECT().
+		ExtendRequest(gomock.Any(), key).
+		Do(func(context.Context, *models.IdempotencyKey) {
+			select {
+			case extended <- struct{}{}:
+			default:
+			}
+		}).
+		Return(nil).
+		MinTimes(1)
+	idempotencyServiceMock.EXPECT().
+		CompleteRequest(gomock.Any(), key).
+		Return(nil)
+
+	middleware := &idempotencyMiddleware{
+		idempotencyService: idempotencyServiceMock,
+		callers:            newRateLimiter(ratelimit.NewMemoryStore(), configs.RateLimitConfig{}),
+		ttl:                time.Hour,
+		waitTimeout:        time.Millisecond,
+		lockRefresh:        time.Millisecond,
+	}
+	// long running request returns only after its key lock was extended
+	handler := middleware.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-extended
+		respondWithJSON(w, http.StatusCreated, map[string]string{"name": "C++"})
+	}))
+
+	req, _ := http.NewRequest(http.MethodPost, v1BookURL, strings.NewReader(`{"name":"C++"}`))
+	req.RemoteAddr = "192.0.2.1:41000"
+	req.Header.Set(idempotencyKeyHeader, "key")
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusCreated {
+		t.Errorf("Middleware() got status code %d\n expected %d", resp.Code, http.StatusCreated)
+	}
+}
+
+func TestIdempotencyMiddlewareKeyScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var storedKeys []string
+	idempotencyServiceMock := mocks.NewMockIdempotencyService(ctrl)
+	idempotencyServiceMock.EXPECT().
+		BeginRequest(gomock.Any(), gomock.Any(), gomock.Any(), time.Hour).
+		DoAndReturn(func(_ context.Context, key, _ string, _ time.Duration) (*models.IdempotencyKey, error) {
+			storedKeys = append(storedKeys, key)
+			return nil, models.ErrIdempotencyKeyMismatch
+		}).
+		Times(4)
+
+	middleware := &idempotencyMiddleware{
+		idempotencyService: idempotencyServiceMock,
+		callers: newRateLimiter(ratelimit.NewMemoryStore(), configs.RateLimitConfig{
+			APIKeys: []string{"desk-key", "kiosk-key"},
+		}),
+		ttl:         time.Hour,
+		waitTimeout: time.Millisecond,
+		lockRefresh: time.Hour,
+	}
+	handler := middleware.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Middleware() called handler of rejected request")
+	}))
+
+	callers := []struct {
+		remote string
+		apiKey string
+	}{
+		// anonymous caller retrying from another network
+		{remote: "192.0.2.1:41000"},
+		{remote: "198.51.100.7:41000"},
+		{remote: "192.0.2.1:41000", apiKey: "desk-key"},
+		{remote: "192.0.2.1:41000", apiKey: "kiosk-key"},
+	}
+	for _, caller := range callers {
+		req, _ := http.NewRequest(http.MethodPost, v1BookURL, strings.NewReader(`{"name":"C++"}`))
+		req.RemoteAddr = caller.remote
+		req.Header.Set(idempotencyKeyHeader, "key")
+		if caller.apiKey != "" {
+			req.Header.Set(apiKeyHeader, caller.apiKey)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if len(storedKeys) != len(callers) {
+		t.Fatalf("Middleware() began %d requests\n expected %d", len(storedKeys), len(callers))
+	}
+	if storedKeys[0] != storedKeys[1] {
+		t.Errorf("Middleware() stored key of anonymous caller apart by address: %s and %s", storedKeys[0], storedKeys[1])
+	}
+	if storedKeys[2] == storedKeys[0] || storedKeys[2] == storedKeys[3] {
+		t.Errorf("Middleware() stored key of API key callers %s and %s\n expected apart from %s and each other",
+			storedKeys[2], storedKeys[3], storedKeys[0])
+	}
+}
+
+func TestScopeIdempotencyKey(t *testing.T) {
+	key := scopeIdempotencyKey("key:0123456789abcdef", "key")
+	if len(key) > maxIdempotencyKeyLength {
+		t.Errorf("scopeIdempotencyKey() returned %d characters\n expected at most %d", len(key), maxIdempotencyKeyLength)
+	}
+	if key != scopeIdempotencyKey("key:0123456789abcdef", "key") {
+		t.Errorf("scopeIdempotencyKey() of the same caller and key differ")
+	}
+	if key == scopeIdempotencyKey("", "key") || key == scopeIdempotencyKey("key:0123456789abcdef", "other") {
+		t.Errorf("scopeIdempotencyKey() of another caller or key returned the same key %s", key)
+	}
+}
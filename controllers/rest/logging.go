@@ -0,0 +1,75 @@
+package rest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"book-management-system/logging"
+)
+
+// maxRequestIDLength limits X-Request-ID accepted from clients
+const maxRequestIDLength = 128
+
+// requestID sets X-Request-ID of the request, or a generated one when missing or malformed,
+// on the response and on the request context, so that log lines and error responses carry it
+func requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(logging.RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(logging.RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+// validRequestID accepts IDs of printable ASCII without spaces, such as UUIDs or trace IDs
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// accessLog logs requests at info level and server errors at error level,
+// lines are sampled as configured by log.sampling
+func accessLog(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		h.ServeHTTP(rec, r)
+
+		level := zapcore.InfoLevel
+		if rec.statusCode >= http.StatusInternalServerError {
+			level = zapcore.ErrorLevel
+		}
+		logger := logging.FromContext(r.Context(), logging.PackageAccess)
+		if ce := logger.Check(level, "request"); ce != nil {
+			ce.Write(
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Int("status", rec.statusCode),
+				zap.Int("bytes", rec.size),
+				zap.Duration("duration", time.Since(start)),
+				zap.String("remote", r.RemoteAddr),
+				zap.String("user_agent", r.UserAgent()),
+			)
+		}
+	})
+}
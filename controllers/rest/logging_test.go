@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"book-management-system/logging"
+)
+
+func TestRequestID(t *testing.T) {
+	handler := requestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := logging.RequestID(r.Context()); got != w.Header().Get(logging.RequestIDHeader) {
+			t.Errorf("request context has request ID %q\n expected %q", got, w.Header().Get(logging.RequestIDHeader))
+		}
+		respondWithError(w, http.StatusNotFound, "Book not found")
+	}))
+
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "accepted", header: "3f1c2a-client", expected: "3f1c2a-client"},
+		{name: "generated when missing"},
+		{name: "generated when malformed", header: "id with spaces"},
+		{name: "generated when too long", header: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/book/1", nil)
+			if tt.header != "" {
+				req.Header.Set(logging.RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(logging.RequestIDHeader)
+			if tt.expected != "" && id != tt.expected {
+				t.Errorf("got request ID %q\n expected %q", id, tt.expected)
+			}
+			if tt.expected == "" && (len(id) != 32 || id == tt.header) {
+				t.Errorf("got request ID %q\n expected generated one", id)
+			}
+
+			body := make(map[string]string)
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("error response is not JSON: %v", err)
+			}
+			if body["request_id"] != id || body["error"] != "Book not found" {
+				t.Errorf("got error response %v\n expected request_id %q", body, id)
+			}
+		})
+	}
+}
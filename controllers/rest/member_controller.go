@@ -1,7 +1,6 @@
 package rest
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -29,6 +28,9 @@ func NewMemberController(route *mux.Router, useCase *usecases.UseCase) *MemberCo
 	v1MemberRoute.HandleFunc("", ctrl.CreateMember).Methods(http.MethodPost)
 	v1MemberRoute.HandleFunc("", ctrl.GetMembers).Methods(http.MethodGet)
 	v1MemberRoute.HandleFunc("", ctrl.UpdateMember).Methods(http.MethodPut)
+	v1MemberRoute.HandleFunc("/{id:[0-9]+}", ctrl.GetMember).Methods(http.MethodGet)
+	v1MemberRoute.HandleFunc("/{id:[0-9]+}", ctrl.UpdateMember).Methods(http.MethodPut)
+	v1MemberRoute.HandleFunc("/{id:[0-9]+}", ctrl.PatchMember).Methods(http.MethodPatch)
 
 	return ctrl
 }
@@ -39,16 +41,21 @@ func NewMemberController(route *mux.Router, useCase *usecases.UseCase) *MemberCo
 // @Tags Member
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Unique key to safely retry the request"
 // @Param request body models.Member true "Request Body"
 // @Success 201 {object} models.Member "Created"
 // @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 409 {object} responses.ErrorResponse "Conflict"
+// @Failure 413 {object} responses.ErrorResponse "Request Entity Too Large"
+// @Failure 415 {object} responses.ErrorResponse "Unsupported Media Type"
+// @Failure 422 {object} responses.ErrorResponse "Unprocessable Entity"
 // @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
 // @Router /v1/member [post]
 func (ctrl *MemberController) CreateMember(w http.ResponseWriter, r *http.Request) {
 	var member models.Member
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&member); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+	if err := decodeJSON(r, &member); err != nil {
+		respondWithError(w, err.code,
+			fmt.Sprintf("Invalid request payload: %s", err.Error()))
 		return
 	}
 
@@ -81,30 +88,121 @@ func (ctrl *MemberController) GetMembers(w http.ResponseWriter, r *http.Request)
 	respondWithJSON(w, http.StatusOK, members)
 }
 
+// GetMember handle get member by id request
+// @Summary Get a member
+// @Description Get a member by ID
+// @Tags Member
+// @Accept json
+// @Produce json
+// @Param id path int true "Member ID"
+// @Success 200 {object} models.Member "OK"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 404 {object} responses.ErrorResponse "Not Found"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/member/{id} [get]
+func (ctrl *MemberController) GetMember(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid member ID")
+		return
+	}
+
+	member, err := ctrl.memberService.GetMember(r.Context(), id)
+	if err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed get member: %s", err.Error()))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, member)
+}
+
 // UpdateMember handle update member request
-// @Summary Update a member
-// @Description Update a member
+// @Summary Replace a member
+// @Description Replace all fields of a member, fields missing from request body are cleared
 // @Tags Member
 // @Accept json
 // @Produce json
 // @Param request body models.Member true "Request Body"
 // @Success 200 {object} models.Member "Updated"
 // @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 413 {object} responses.ErrorResponse "Request Entity Too Large"
+// @Failure 415 {object} responses.ErrorResponse "Unsupported Media Type"
+// @Failure 404 {object} responses.ErrorResponse "Not Found"
+// @Failure 422 {object} responses.ErrorResponse "Unprocessable Entity"
 // @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
 // @Router /v1/member [put]
 func (ctrl *MemberController) UpdateMember(w http.ResponseWriter, r *http.Request) {
 	var member models.Member
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&member); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+	if err := decodeJSON(r, &member); err != nil {
+		respondWithError(w, err.code,
+			fmt.Sprintf("Invalid request payload: %s", err.Error()))
 		return
 	}
 
+	if _, ok := mux.Vars(r)["id"]; ok {
+		id, err := getIDFromPath(r)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid member ID")
+			return
+		}
+		member.ID = id
+	}
+
 	if err := ctrl.memberService.UpdateMember(r.Context(), &member); err != nil {
-		respondWithError(w, http.StatusInternalServerError,
+		respondWithError(w, serviceErrorStatus(err),
 			fmt.Sprintf("Failed update member: %s", err.Error()))
 		return
 	}
 
 	respondWithJSON(w, http.StatusOK, member)
 }
+
+// PatchMember handle partial update member request
+// @Summary Patch a member
+// @Description Partially update a member with JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902).
+// @Description A null member in merge patch clears the field.
+// @Tags Member
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path int true "Member ID"
+// @Param request body models.Member true "Patch document"
+// @Success 200 {object} models.Member "Updated"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 404 {object} responses.ErrorResponse "Not Found"
+// @Failure 409 {object} responses.ErrorResponse "Conflict"
+// @Failure 415 {object} responses.ErrorResponse "Unsupported Media Type"
+// @Failure 422 {object} responses.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/member/{id} [patch]
+func (ctrl *MemberController) PatchMember(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid member ID")
+		return
+	}
+
+	original, err := ctrl.memberService.GetMember(r.Context(), id)
+	if err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed patch member: %s", err.Error()))
+		return
+	}
+
+	var member models.Member
+	if err := applyPatch(r, original, &member); err != nil {
+		respondWithError(w, err.code,
+			fmt.Sprintf("Failed patch member: %s", err.Error()))
+		return
+	}
+	member.Model = original.Model
+
+	if err := ctrl.memberService.UpdateMember(r.Context(), &member); err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed patch member: %s", err.Error()))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, member)
+}
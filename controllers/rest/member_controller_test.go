@@ -8,11 +8,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
+	"gorm.io/gorm"
 
+	"book-management-system/controllers/rest/patches"
 	"book-management-system/controllers/rest/responses"
 	"book-management-system/entities/models"
 	mocks "book-management-system/mocks/services"
@@ -23,7 +26,7 @@ import (
 
 func TestNewMemberController(t *testing.T) {
 	repo := &repositories.Repository{}
-	memberService := services.NewMemberService(repo)
+	memberService := services.NewMemberService(repo, nil)
 	usecase := &usecases.UseCase{
 		Service: &services.Services{
 			MemberService: memberService,
@@ -71,7 +74,7 @@ func TestMemberControllerCreateMember(t *testing.T) {
 			name: "failed: invalid request body",
 			givenInput: input{
 				valid: false,
-				ctx:   context.TODO(),
+				ctx:   context.Background(),
 				invalidRequestBody: models.Members{
 					{
 						Name: "",
@@ -80,7 +83,7 @@ func TestMemberControllerCreateMember(t *testing.T) {
 			},
 			expectedOutput: output{
 				responseBody: responses.ErrorResponse{
-					"error": "Invalid request payload",
+					"error": "Invalid request payload: json: cannot unmarshal array into Go value of type models.Member",
 				},
 			},
 			configureMock: func(mockConfig) {
@@ -91,7 +94,7 @@ func TestMemberControllerCreateMember(t *testing.T) {
 			name: "failed: create member service returns error",
 			givenInput: input{
 				valid: true,
-				ctx:   context.TODO(),
+				ctx:   context.Background(),
 				requestBody: &models.Member{
 					Name: "",
 				},
@@ -111,7 +114,7 @@ func TestMemberControllerCreateMember(t *testing.T) {
 			name: "success: create member",
 			givenInput: input{
 				valid: true,
-				ctx:   context.TODO(),
+				ctx:   context.Background(),
 				requestBody: &models.Member{
 					Name: "",
 				},
@@ -193,7 +196,7 @@ func TestMemberControllerGetMember(t *testing.T) {
 		{
 			name: "failed: get members service returns error",
 			givenInput: input{
-				ctx:            context.TODO(),
+				ctx:            context.Background(),
 				httpRequestURL: v1MemberURL,
 			},
 			expectedOutput: output{
@@ -210,7 +213,7 @@ func TestMemberControllerGetMember(t *testing.T) {
 		{
 			name: "success: get members",
 			givenInput: input{
-				ctx:            context.TODO(),
+				ctx:            context.Background(),
 				httpRequestURL: v1MemberURL,
 			},
 			expectedOutput: output{
@@ -288,7 +291,7 @@ func TestMemberControllerUpdateMember(t *testing.T) {
 			name: "failed: invalid request body",
 			givenInput: input{
 				valid: false,
-				ctx:   context.TODO(),
+				ctx:   context.Background(),
 				invalidRequestBody: models.Members{
 					{
 						Name: "",
@@ -297,7 +300,7 @@ func TestMemberControllerUpdateMember(t *testing.T) {
 			},
 			expectedOutput: output{
 				responseBody: responses.ErrorResponse{
-					"error": "Invalid request payload",
+					"error": "Invalid request payload: json: cannot unmarshal array into Go value of type models.Member",
 				},
 			},
 			configureMock: func(confMock) {
@@ -308,7 +311,7 @@ func TestMemberControllerUpdateMember(t *testing.T) {
 			name: "failed: update member service returns error",
 			givenInput: input{
 				valid: true,
-				ctx:   context.TODO(),
+				ctx:   context.Background(),
 				requestBody: &models.Member{
 					Name: "",
 				},
@@ -328,7 +331,7 @@ func TestMemberControllerUpdateMember(t *testing.T) {
 			name: "success: update member",
 			givenInput: input{
 				valid: true,
-				ctx:   context.TODO(),
+				ctx:   context.Background(),
 				requestBody: &models.Member{
 					Name: "",
 				},
@@ -386,3 +389,281 @@ func TestMemberControllerUpdateMember(t *testing.T) {
 		})
 	}
 }
+
+func TestMemberControllerGetMemberByID(t *testing.T) {
+	type input struct {
+		id string
+	}
+	type output struct {
+		code         int
+		responseBody interface{}
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     *mocks.MockMemberService
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "failed: invalid id",
+			givenInput: input{
+				id: "99999999999999999999999",
+			},
+			expectedOutput: output{
+				code: http.StatusBadRequest,
+				responseBody: responses.ErrorResponse{
+					"error": "Invalid member ID",
+				},
+			},
+			configureMock: func(mockConfig) {
+				// do nothing
+			},
+		},
+		{
+			name: "failed: member not found",
+			givenInput: input{
+				id: "1",
+			},
+			expectedOutput: output{
+				code: http.StatusNotFound,
+				responseBody: responses.ErrorResponse{
+					"error": fmt.Sprintf("Failed get member: %s", models.ErrNotFound.Error()),
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetMember(gomock.Any(), uint(1)).
+					Return(nil, models.ErrNotFound)
+			},
+		},
+		{
+			name: "success: get member",
+			givenInput: input{
+				id: "1",
+			},
+			expectedOutput: output{
+				code: http.StatusOK,
+				responseBody: &models.Member{
+					Name: "John Lennon",
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetMember(gomock.Any(), uint(1)).
+					Return(conf.expected.responseBody, nil)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				v1MemberURL+"/"+tt.givenInput.id,
+				nil,
+			)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.givenInput.id})
+			resp := httptest.NewRecorder()
+
+			memberServiceMock := mocks.NewMockMemberService(ctrl)
+			tt.configureMock(mockConfig{
+				given:    tt.givenInput,
+				expected: tt.expectedOutput,
+				mock:     memberServiceMock,
+			})
+
+			memberController := &MemberController{
+				memberService: memberServiceMock,
+			}
+			memberController.GetMember(resp, req)
+
+			if resp.Code != tt.expectedOutput.code {
+				t.Errorf("GetMember() got status code %d\n expected %d",
+					resp.Code, tt.expectedOutput.code)
+			}
+			got := resp.Body.String()
+			expected, _ := json.Marshal(tt.expectedOutput.responseBody)
+			if got != string(expected) {
+				t.Errorf("GetMember() got response body %s\n expected %s",
+					got, string(expected))
+			}
+		})
+	}
+}
+
+func TestMemberControllerPatchMember(t *testing.T) {
+	type input struct {
+		contentType string
+		requestBody string
+	}
+	type output struct {
+		code         int
+		responseBody interface{}
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     *mocks.MockMemberService
+	}
+
+	original := &models.Member{
+		Model: gorm.Model{
+			ID: 1,
+		},
+		Name: "John Lennon",
+	}
+	patched := &models.Member{
+		Model: gorm.Model{
+			ID: 1,
+		},
+		Name: "Paul McCartney",
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "failed: member not found",
+			givenInput: input{
+				contentType: patches.MergePatchContentType,
+				requestBody: `{"name":"Paul McCartney"}`,
+			},
+			expectedOutput: output{
+				code: http.StatusNotFound,
+				responseBody: responses.ErrorResponse{
+					"error": fmt.Sprintf("Failed patch member: %s", models.ErrNotFound.Error()),
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetMember(gomock.Any(), uint(1)).
+					Return(nil, models.ErrNotFound)
+			},
+		},
+		{
+			name: "failed: unsupported content type",
+			givenInput: input{
+				contentType: "application/json",
+				requestBody: `{"name":"Paul McCartney"}`,
+			},
+			expectedOutput: output{
+				code: http.StatusUnsupportedMediaType,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetMember(gomock.Any(), uint(1)).
+					Return(original, nil)
+			},
+		},
+		{
+			name: "failed: invalid patched member",
+			givenInput: input{
+				contentType: patches.MergePatchContentType,
+				requestBody: `{"name":null}`,
+			},
+			expectedOutput: output{
+				code: http.StatusUnprocessableEntity,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetMember(gomock.Any(), uint(1)).
+					Return(original, nil)
+				conf.mock.EXPECT().
+					UpdateMember(gomock.Any(), gomock.Any()).
+					Return(models.ErrInvalid)
+			},
+		},
+		{
+			name: "success: merge patch member",
+			givenInput: input{
+				contentType: patches.MergePatchContentType,
+				requestBody: `{"name":"Paul McCartney"}`,
+			},
+			expectedOutput: output{
+				code:         http.StatusOK,
+				responseBody: patched,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetMember(gomock.Any(), uint(1)).
+					Return(original, nil)
+				conf.mock.EXPECT().
+					UpdateMember(gomock.Any(), patched).
+					Return(nil)
+			},
+		},
+		{
+			name: "success: json patch member",
+			givenInput: input{
+				contentType: patches.JSONPatchContentType,
+				requestBody: `[{"op":"replace","path":"/name","value":"Paul McCartney"}]`,
+			},
+			expectedOutput: output{
+				code:         http.StatusOK,
+				responseBody: patched,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetMember(gomock.Any(), uint(1)).
+					Return(original, nil)
+				conf.mock.EXPECT().
+					UpdateMember(gomock.Any(), patched).
+					Return(nil)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(
+				http.MethodPatch,
+				v1MemberURL+"/1",
+				strings.NewReader(tt.givenInput.requestBody),
+			)
+			req.Header.Set("Content-Type", tt.givenInput.contentType)
+			req = mux.SetURLVars(req, map[string]string{"id": "1"})
+			resp := httptest.NewRecorder()
+
+			memberServiceMock := mocks.NewMockMemberService(ctrl)
+			tt.configureMock(mockConfig{
+				given:    tt.givenInput,
+				expected: tt.expectedOutput,
+				mock:     memberServiceMock,
+			})
+
+			memberController := &MemberController{
+				memberService: memberServiceMock,
+			}
+			memberController.PatchMember(resp, req)
+
+			if resp.Code != tt.expectedOutput.code {
+				t.Errorf("PatchMember() got status code %d\n expected %d",
+					resp.Code, tt.expectedOutput.code)
+			}
+			if tt.expectedOutput.responseBody == nil {
+				return
+			}
+			got := resp.Body.String()
+			expected, _ := json.Marshal(tt.expectedOutput.responseBody)
+			if got != string(expected) {
+				t.Errorf("PatchMember() got response body %s\n expected %s",
+					got, string(expected))
+			}
+		})
+	}
+}
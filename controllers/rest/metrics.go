@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"book-management-system/metrics"
+)
+
+// unmatchedRoute labels requests no route matched
+const unmatchedRoute = "unmatched"
+
+// initMetrics serves /metrics and counts requests of r by route template and status code
+func initMetrics(r *mux.Router) {
+	r.Handle("/metrics", metrics.Handler()).Methods(http.MethodGet)
+	r.Use(metricsMiddleware)
+	r.NotFoundHandler = instrumentRoute(unmatchedRoute, http.NotFoundHandler())
+	r.MethodNotAllowedHandler = instrumentRoute(unmatchedRoute, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+}
+
+// metricsMiddleware instruments matched routes
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := unmatchedRoute
+		if current := mux.CurrentRoute(r); current != nil {
+			if template, err := current.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+		instrumentRoute(route, next).ServeHTTP(w, r)
+	})
+}
+
+func instrumentRoute(route string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		code := strconv.Itoa(rec.statusCode)
+		metrics.HTTPRequests.WithLabelValues(r.Method, route, code).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, route, code).Observe(time.Since(start).Seconds())
+	})
+}
+
+// statusRecorder captures status code and body size written by the next handler
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	size        int
+	wroteHeader bool
+}
+
+func (rec *statusRecorder) WriteHeader(statusCode int) {
+	if !rec.wroteHeader {
+		rec.statusCode = statusCode
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	n, err := rec.ResponseWriter.Write(b)
+	rec.size += n
+	return n, err
+}
+
+// Flush lets streaming handlers flush through the recorder
+func (rec *statusRecorder) Flush() {
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack lets WebSocket handlers take over the connection, it is recorded as switching protocols
+func (rec *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rec.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil && !rec.wroteHeader {
+		rec.statusCode = http.StatusSwitchingProtocols
+		rec.wroteHeader = true
+	}
+	return conn, rw, err
+}
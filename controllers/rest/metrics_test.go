@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"book-management-system/metrics"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	r := mux.NewRouter()
+	v1 := r.PathPrefix("/v1").Subrouter()
+	v1.HandleFunc("/books/{id:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}).Methods(http.MethodGet)
+	initMetrics(r)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		route  string
+		code   string
+	}{
+		{name: "route template", method: http.MethodGet, path: "/v1/books/1", route: "/v1/books/{id:[0-9]+}", code: "418"},
+		{name: "unmatched route", method: http.MethodGet, path: "/v2/books", route: unmatchedRoute, code: "404"},
+		{name: "method not allowed", method: http.MethodPost, path: "/v1/books/1", route: unmatchedRoute, code: "405"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := metrics.HTTPRequests.WithLabelValues(tt.method, tt.route, tt.code)
+			before := testutil.ToFloat64(counter)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("request counted %v times with route %q and code %s\n expected once", got, tt.route, tt.code)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `route="/v1/books/{id:[0-9]+}"`) {
+		t.Errorf("GET /metrics got status %d, body without route template", rec.Code)
+	}
+}
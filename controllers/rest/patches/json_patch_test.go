@@ -0,0 +1,108 @@
+package patches
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestJSONPatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		doc         string
+		patch       string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "replace member",
+			doc:      `{"name":"C++","isbn":"1234"}`,
+			patch:    `[{"op":"replace","path":"/name","value":"Go"}]`,
+			expected: `{"name":"Go","isbn":"1234"}`,
+		},
+		{
+			name:     "remove member",
+			doc:      `{"name":"C++","isbn":"1234"}`,
+			patch:    `[{"op":"remove","path":"/isbn"}]`,
+			expected: `{"name":"C++"}`,
+		},
+		{
+			name:     "add explicit null",
+			doc:      `{"name":"C++"}`,
+			patch:    `[{"op":"add","path":"/isbn","value":null}]`,
+			expected: `{"name":"C++","isbn":null}`,
+		},
+		{
+			name:     "add and remove array elements",
+			doc:      `{"tags":["a","c"]}`,
+			patch:    `[{"op":"add","path":"/tags/1","value":"b"},{"op":"add","path":"/tags/-","value":"d"},{"op":"remove","path":"/tags/0"}]`,
+			expected: `{"tags":["b","c","d"]}`,
+		},
+		{
+			name:     "move and copy",
+			doc:      `{"a":"x","b":{}}`,
+			patch:    `[{"op":"move","from":"/a","path":"/b/a"},{"op":"copy","from":"/b","path":"/c"}]`,
+			expected: `{"b":{"a":"x"},"c":{"a":"x"}}`,
+		},
+		{
+			name:     "escaped pointer",
+			doc:      `{"a/b":1,"m~n":2}`,
+			patch:    `[{"op":"replace","path":"/a~1b","value":3},{"op":"remove","path":"/m~0n"}]`,
+			expected: `{"a/b":3}`,
+		},
+		{
+			name:     "successful test",
+			doc:      `{"name":"C++"}`,
+			patch:    `[{"op":"test","path":"/name","value":"C++"},{"op":"replace","path":"/name","value":"Go"}]`,
+			expected: `{"name":"Go"}`,
+		},
+		{
+			name:        "failed test",
+			doc:         `{"name":"C++"}`,
+			patch:       `[{"op":"test","path":"/name","value":"Go"}]`,
+			expectedErr: ErrTestFailed,
+		},
+		{
+			name:        "replace missing member",
+			doc:         `{"name":"C++"}`,
+			patch:       `[{"op":"replace","path":"/isbn","value":"1234"}]`,
+			expectedErr: ErrInvalidPatch,
+		},
+		{
+			name:        "unknown operation",
+			doc:         `{"name":"C++"}`,
+			patch:       `[{"op":"merge","path":"/name","value":"Go"}]`,
+			expectedErr: ErrInvalidPatch,
+		},
+		{
+			name:        "missing value",
+			doc:         `{"name":"C++"}`,
+			patch:       `[{"op":"add","path":"/isbn"}]`,
+			expectedErr: ErrInvalidPatch,
+		},
+		{
+			name:        "array index out of bounds",
+			doc:         `{"tags":[]}`,
+			patch:       `[{"op":"remove","path":"/tags/0"}]`,
+			expectedErr: ErrInvalidPatch,
+		},
+		{
+			name:        "malformed patch",
+			doc:         `{"name":"C++"}`,
+			patch:       `{"op":"add"}`,
+			expectedErr: ErrInvalidPatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONPatch([]byte(tt.doc), []byte(tt.patch))
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("JSONPatch() got error %+v, expected %+v", err, tt.expectedErr)
+			}
+			if tt.expectedErr != nil {
+				return
+			}
+			assertJSONEqual(t, got, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package patches
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "replace member",
+			doc:      `{"name":"C++","isbn":"1234"}`,
+			patch:    `{"name":"Go"}`,
+			expected: `{"name":"Go","isbn":"1234"}`,
+		},
+		{
+			name:     "null removes member",
+			doc:      `{"name":"C++","isbn":"1234"}`,
+			patch:    `{"isbn":null}`,
+			expected: `{"name":"C++"}`,
+		},
+		{
+			name:     "nested object merged",
+			doc:      `{"a":{"b":"c","d":"e"}}`,
+			patch:    `{"a":{"b":null,"f":"g"}}`,
+			expected: `{"a":{"d":"e","f":"g"}}`,
+		},
+		{
+			name:     "array replaced as a whole",
+			doc:      `{"a":[1,2]}`,
+			patch:    `{"a":[3]}`,
+			expected: `{"a":[3]}`,
+		},
+		{
+			name:     "non object patch replaces document",
+			doc:      `{"a":"b"}`,
+			patch:    `["c"]`,
+			expected: `["c"]`,
+		},
+		{
+			name:    "invalid patch",
+			doc:     `{"a":"b"}`,
+			patch:   `{`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MergePatch() got error %+v, expected error %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			assertJSONEqual(t, got, tt.expected)
+		})
+	}
+}
+
+func assertJSONEqual(t *testing.T, got []byte, expected string) {
+	t.Helper()
+
+	var gotValue, expectedValue interface{}
+	_ = json.Unmarshal(got, &gotValue)
+	_ = json.Unmarshal([]byte(expected), &expectedValue)
+	if !reflect.DeepEqual(gotValue, expectedValue) {
+		t.Errorf("got document %s\n expected %s", got, expected)
+	}
+}
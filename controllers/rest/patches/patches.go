@@ -0,0 +1,41 @@
+// Package patches applies JSON Merge Patch (RFC 7396) and
+// JSON Patch (RFC 6902) documents to JSON encoded resources.
+package patches
+
+import (
+	"errors"
+	"mime"
+)
+
+const (
+	// MergePatchContentType is media type of RFC 7396 JSON Merge Patch document
+	MergePatchContentType = "application/merge-patch+json"
+	// JSONPatchContentType is media type of RFC 6902 JSON Patch document
+	JSONPatchContentType = "application/json-patch+json"
+)
+
+var (
+	// ErrUnsupportedContentType returned when patch media type is neither merge patch nor JSON patch
+	ErrUnsupportedContentType = errors.New("unsupported patch content type")
+	// ErrInvalidPatch returned when patch document is malformed
+	ErrInvalidPatch = errors.New("invalid patch document")
+	// ErrTestFailed returned when JSON patch "test" operation does not match
+	ErrTestFailed = errors.New("patch test operation failed")
+)
+
+// Apply applies patch to doc according to contentType and returns patched document
+func Apply(contentType string, doc, patch []byte) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, ErrUnsupportedContentType
+	}
+
+	switch mediaType {
+	case MergePatchContentType:
+		return MergePatch(doc, patch)
+	case JSONPatchContentType:
+		return JSONPatch(doc, patch)
+	default:
+		return nil, ErrUnsupportedContentType
+	}
+}
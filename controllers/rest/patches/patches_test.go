@@ -0,0 +1,54 @@
+package patches
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		patch       string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:        "merge patch",
+			contentType: MergePatchContentType,
+			patch:       `{"isbn":null}`,
+			expected:    `{"name":"C++"}`,
+		},
+		{
+			name:        "json patch with charset",
+			contentType: JSONPatchContentType + "; charset=utf-8",
+			patch:       `[{"op":"remove","path":"/isbn"}]`,
+			expected:    `{"name":"C++"}`,
+		},
+		{
+			name:        "unsupported content type",
+			contentType: "application/json",
+			patch:       `{"isbn":null}`,
+			expectedErr: ErrUnsupportedContentType,
+		},
+		{
+			name:        "missing content type",
+			contentType: "",
+			patch:       `{"isbn":null}`,
+			expectedErr: ErrUnsupportedContentType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(tt.contentType, []byte(`{"name":"C++","isbn":"1234"}`), []byte(tt.patch))
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Apply() got error %+v, expected %+v", err, tt.expectedErr)
+			}
+			if tt.expectedErr != nil {
+				return
+			}
+			assertJSONEqual(t, got, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package rest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"book-management-system/configs"
+	"book-management-system/logging"
+	"book-management-system/metrics"
+	"book-management-system/ratelimit"
+)
+
+const (
+	apiKeyHeader             = "X-API-Key"
+	forwardedForHeader       = "X-Forwarded-For"
+	realIPHeader             = "X-Real-IP"
+	rateLimitLimitHeader     = "RateLimit-Limit"
+	rateLimitRemainingHeader = "RateLimit-Remaining"
+	rateLimitResetHeader     = "RateLimit-Reset"
+	retryAfterHeader         = "Retry-After"
+)
+
+// Route classes limited by separate rules
+const (
+	routeClassSearch = "search"
+	routeClassRead   = "read"
+	routeClassWrite  = "write"
+)
+
+// searchRoutes are path templates of GET routes limited as search
+var searchRoutes = map[string]bool{
+	"/v1/book":        true,
+	"/v1/book/export": true,
+}
+
+// exemptRoutes are path prefixes of routes used by orchestrators and monitoring, never limited
+var exemptRoutes = []string{"/healthz", "/readyz", "/metrics", "/swagger/"}
+
+// rateLimiter limits requests of each client per route class, client is configured API key or client IP
+type rateLimiter struct {
+	store    ratelimit.Store
+	settings atomic.Value
+}
+
+// rateLimitSettings is config of rateLimiter parsed once per load or reload
+type rateLimitSettings struct {
+	cfg     configs.RateLimitConfig
+	proxies []*net.IPNet
+	apiKeys map[string]bool
+}
+
+func newRateLimiter(store ratelimit.Store, cfg configs.RateLimitConfig) *rateLimiter {
+	limiter := &rateLimiter{store: store}
+	limiter.configure(cfg)
+	return limiter
+}
+
+// configure applies cfg to next requests, buckets of clients are kept
+func (l *rateLimiter) configure(cfg configs.RateLimitConfig) {
+	settings := &rateLimitSettings{cfg: cfg, apiKeys: make(map[string]bool, len(cfg.APIKeys))}
+	for _, proxy := range cfg.TrustedProxies {
+		if network := parseNetwork(proxy); network != nil {
+			settings.proxies = append(settings.proxies, network)
+		}
+	}
+	for _, key := range cfg.APIKeys {
+		settings.apiKeys[key] = true
+	}
+	l.settings.Store(settings)
+}
+
+// principal returns identity of the caller of r by current settings, empty for anonymous callers
+func (l *rateLimiter) principal(r *http.Request) string {
+	return l.settings.Load().(*rateLimitSettings).principal(r)
+}
+
+// Middleware implements mux.MiddlewareFunc
+func (l *rateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		settings := l.settings.Load().(*rateLimitSettings)
+		class := routeClass(r)
+		if !settings.cfg.Enabled || class == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		rule := settings.rule(class)
+		if rule.Rate <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		result, err := l.store.Take(r.Context(), class+":"+settings.client(r),
+			ratelimit.Limit{Rate: rule.Rate, Burst: rule.Burst})
+		if err != nil {
+			// an unavailable store should not take the API down with it
+			logging.FromContext(r.Context(), logging.PackageRest).Error("failed to check rate limit", zap.Error(err))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set(rateLimitLimitHeader, strconv.Itoa(result.Limit))
+		w.Header().Set(rateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+		w.Header().Set(rateLimitResetHeader, strconv.Itoa(ceilSeconds(result.Reset)))
+		if !result.Allowed {
+			metrics.HTTPRateLimited.WithLabelValues(class).Inc()
+			w.Header().Set(retryAfterHeader, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			respondWithError(w, http.StatusTooManyRequests, "Rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// routeClass returns class of matched route of r, empty when the route is not limited
+func routeClass(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+	for _, prefix := range exemptRoutes {
+		if strings.HasPrefix(template, prefix) {
+			return ""
+		}
+	}
+
+	switch {
+	case r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions:
+		return routeClassWrite
+	case searchRoutes[template]:
+		return routeClassSearch
+	default:
+		return routeClassRead
+	}
+}
+
+func (s *rateLimitSettings) rule(class string) configs.RateLimitRule {
+	switch class {
+	case routeClassSearch:
+		return s.cfg.Search
+	case routeClassWrite:
+		return s.cfg.Write
+	default:
+		return s.cfg.Read
+	}
+}
+
+// client returns bucket key of the client, principal or else client IP
+func (s *rateLimitSettings) client(r *http.Request) string {
+	if principal := s.principal(r); principal != "" {
+		return principal
+	}
+	return "ip:" + s.clientIP(r)
+}
+
+// principal returns hash of configured API key sent with r, empty for anonymous callers
+func (s *rateLimitSettings) principal(r *http.Request) string {
+	if key := r.Header.Get(apiKeyHeader); key != "" && s.apiKeys[key] {
+		sum := sha256.Sum256([]byte(key))
+		return "key:" + hex.EncodeToString(sum[:8])
+	}
+	return ""
+}
+
+// clientIP returns remote address of r, or address named by X-Forwarded-For or X-Real-IP
+// when the remote address is a trusted proxy, so that clients cannot spoof their address
+func (s *rateLimitSettings) clientIP(r *http.Request) string {
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remote = r.RemoteAddr
+	}
+	if !s.trusted(remote) {
+		return remote
+	}
+
+	if forwarded := r.Header.Get(forwardedForHeader); forwarded != "" {
+		// the rightmost address not of a trusted proxy is the one the closest trusted proxy saw
+		hops := strings.Split(forwarded, ",")
+		client := ""
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				break
+			}
+			client = hop
+			if !s.trusted(hop) {
+				break
+			}
+		}
+		if client != "" {
+			return client
+		}
+	}
+	if realIP := strings.TrimSpace(r.Header.Get(realIPHeader)); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+	return remote
+}
+
+func (s *rateLimitSettings) trusted(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range s.proxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNetwork parses validated IP or CIDR of trusted proxy, IP is network of the single address
+func parseNetwork(proxy string) *net.IPNet {
+	if _, network, err := net.ParseCIDR(proxy); err == nil {
+		return network
+	}
+	ip := net.ParseIP(proxy)
+	if ip == nil {
+		return nil
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+// ceilSeconds rounds d up to whole seconds as headers count in seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
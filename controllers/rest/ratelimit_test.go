@@ -0,0 +1,262 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"book-management-system/configs"
+	"book-management-system/ratelimit"
+)
+
+// failingStore is ratelimit.Store failing every Take
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store unavailable")
+}
+
+func newRateLimitedRouter(store ratelimit.Store, cfg configs.RateLimitConfig) *mux.Router {
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	r := mux.NewRouter()
+	r.Use(newRateLimiter(store, cfg).Middleware)
+	r.HandleFunc("/healthz", ok).Methods(http.MethodGet)
+	r.HandleFunc("/v1/book", ok).Methods(http.MethodGet, http.MethodPost)
+	r.HandleFunc("/v1/book/{id:[0-9]+}", ok).Methods(http.MethodGet)
+	return r
+}
+
+func TestRateLimiter(t *testing.T) {
+	cfg := configs.RateLimitConfig{
+		Enabled:        true,
+		TrustedProxies: []string{"10.0.0.0/8"},
+		APIKeys:        []string{"partner"},
+		Search:         configs.RateLimitRule{Rate: 1, Burst: 1},
+		Read:           configs.RateLimitRule{Rate: 1, Burst: 2},
+		Write:          configs.RateLimitRule{Rate: 0.5, Burst: 1},
+	}
+
+	type request struct {
+		method  string
+		path    string
+		remote  string
+		headers map[string]string
+	}
+	get := func(path string) request {
+		return request{method: http.MethodGet, path: path, remote: "192.0.2.1:1234"}
+	}
+
+	tests := []struct {
+		name     string
+		cfg      func(*configs.RateLimitConfig)
+		store    ratelimit.Store
+		requests []request
+		// codes of the requests in order
+		codes []int
+	}{
+		{
+			name:     "search limited by search rule",
+			requests: []request{get("/v1/book"), get("/v1/book")},
+			codes:    []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:     "classes have separate buckets",
+			requests: []request{get("/v1/book"), get("/v1/book/1"), get("/v1/book/2"), get("/v1/book/3")},
+			codes:    []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name: "write limited by write rule",
+			requests: []request{
+				{method: http.MethodPost, path: "/v1/book", remote: "192.0.2.1:1234"},
+				{method: http.MethodPost, path: "/v1/book", remote: "192.0.2.1:1234"},
+			},
+			codes: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name: "clients have separate buckets",
+			requests: []request{
+				get("/v1/book"),
+				{method: http.MethodGet, path: "/v1/book", remote: "192.0.2.2:1234"},
+			},
+			codes: []int{http.StatusOK, http.StatusOK},
+		},
+		{
+			name: "API key has its own bucket",
+			requests: []request{
+				get("/v1/book"),
+				{method: http.MethodGet, path: "/v1/book", remote: "192.0.2.1:1234", headers: map[string]string{apiKeyHeader: "partner"}},
+				{method: http.MethodGet, path: "/v1/book", remote: "192.0.2.2:1234", headers: map[string]string{apiKeyHeader: "partner"}},
+			},
+			codes: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name: "unknown API key is limited by IP",
+			requests: []request{
+				get("/v1/book"),
+				{method: http.MethodGet, path: "/v1/book", remote: "192.0.2.1:1234", headers: map[string]string{apiKeyHeader: "guess"}},
+			},
+			codes: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name: "forwarded client behind trusted proxy",
+			requests: []request{
+				{method: http.MethodGet, path: "/v1/book", remote: "10.0.0.1:1234", headers: map[string]string{forwardedForHeader: "192.0.2.1"}},
+				{method: http.MethodGet, path: "/v1/book", remote: "10.0.0.2:1234", headers: map[string]string{forwardedForHeader: "192.0.2.2"}},
+				get("/v1/book"),
+			},
+			codes: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name: "forwarded header of untrusted client ignored",
+			requests: []request{
+				{method: http.MethodGet, path: "/v1/book", remote: "192.0.2.1:1234", headers: map[string]string{forwardedForHeader: "192.0.2.2"}},
+				{method: http.MethodGet, path: "/v1/book", remote: "192.0.2.1:1234", headers: map[string]string{forwardedForHeader: "192.0.2.3"}},
+			},
+			codes: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:     "health checks exempt",
+			requests: []request{get("/healthz"), get("/healthz")},
+			codes:    []int{http.StatusOK, http.StatusOK},
+		},
+		{
+			name:     "disabled",
+			cfg:      func(cfg *configs.RateLimitConfig) { cfg.Enabled = false },
+			requests: []request{get("/v1/book"), get("/v1/book")},
+			codes:    []int{http.StatusOK, http.StatusOK},
+		},
+		{
+			name:     "zero rate is unlimited",
+			cfg:      func(cfg *configs.RateLimitConfig) { cfg.Search = configs.RateLimitRule{} },
+			requests: []request{get("/v1/book"), get("/v1/book")},
+			codes:    []int{http.StatusOK, http.StatusOK},
+		},
+		{
+			name:     "failing store lets requests through",
+			store:    failingStore{},
+			requests: []request{get("/v1/book"), get("/v1/book")},
+			codes:    []int{http.StatusOK, http.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testCfg := cfg
+			if tt.cfg != nil {
+				tt.cfg(&testCfg)
+			}
+			store := tt.store
+			if store == nil {
+				store = ratelimit.NewMemoryStore()
+			}
+			r := newRateLimitedRouter(store, testCfg)
+
+			for i, request := range tt.requests {
+				req := httptest.NewRequest(request.method, request.path, nil)
+				req.RemoteAddr = request.remote
+				for name, value := range request.headers {
+					req.Header.Set(name, value)
+				}
+				rec := httptest.NewRecorder()
+				r.ServeHTTP(rec, req)
+
+				if rec.Code != tt.codes[i] {
+					t.Errorf("request %d %s %s got %d\n expected %d",
+						i+1, request.method, request.path, rec.Code, tt.codes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterHeaders(t *testing.T) {
+	r := newRateLimitedRouter(ratelimit.NewMemoryStore(), configs.RateLimitConfig{
+		Enabled: true,
+		Search:  configs.RateLimitRule{Rate: 0.5, Burst: 2},
+	})
+
+	expected := []map[string]string{
+		{rateLimitLimitHeader: "2", rateLimitRemainingHeader: "1", rateLimitResetHeader: "2", retryAfterHeader: ""},
+		{rateLimitLimitHeader: "2", rateLimitRemainingHeader: "0", rateLimitResetHeader: "4", retryAfterHeader: ""},
+		{rateLimitLimitHeader: "2", rateLimitRemainingHeader: "0", rateLimitResetHeader: "4", retryAfterHeader: "2"},
+	}
+	for i, headers := range expected {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/book", nil))
+		for name, value := range headers {
+			if got := rec.Header().Get(name); got != value {
+				t.Errorf("request %d got %s %q\n expected %q", i+1, name, got, value)
+			}
+		}
+	}
+}
+
+func TestRateLimitClientIP(t *testing.T) {
+	settings := &rateLimitSettings{proxies: []*net.IPNet{
+		parseNetwork("10.0.0.0/8"),
+		parseNetwork("2001:db8::1"),
+	}}
+
+	tests := []struct {
+		name     string
+		remote   string
+		headers  map[string]string
+		expected string
+	}{
+		{name: "direct client", remote: "192.0.2.1:1234", expected: "192.0.2.1"},
+		{
+			name:     "spoofed header of direct client",
+			remote:   "192.0.2.1:1234",
+			headers:  map[string]string{forwardedForHeader: "198.51.100.1", realIPHeader: "198.51.100.2"},
+			expected: "192.0.2.1",
+		},
+		{
+			name:     "forwarded by trusted proxy",
+			remote:   "10.0.0.1:1234",
+			headers:  map[string]string{forwardedForHeader: "198.51.100.1"},
+			expected: "198.51.100.1",
+		},
+		{
+			name:     "chain of trusted proxies",
+			remote:   "10.0.0.1:1234",
+			headers:  map[string]string{forwardedForHeader: "203.0.113.9, 198.51.100.1, 10.0.0.2"},
+			expected: "198.51.100.1",
+		},
+		{
+			name:     "all hops trusted",
+			remote:   "10.0.0.1:1234",
+			headers:  map[string]string{forwardedForHeader: "10.0.0.3, 10.0.0.2"},
+			expected: "10.0.0.3",
+		},
+		{
+			name:     "real IP header",
+			remote:   "[2001:db8::1]:1234",
+			headers:  map[string]string{realIPHeader: "198.51.100.1"},
+			expected: "198.51.100.1",
+		},
+		{
+			name:     "malformed headers",
+			remote:   "10.0.0.1:1234",
+			headers:  map[string]string{forwardedForHeader: "unknown", realIPHeader: "unknown"},
+			expected: "10.0.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/book", nil)
+			req.RemoteAddr = tt.remote
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			if got := settings.clientIP(req); got != tt.expected {
+				t.Errorf("clientIP() got %s\n expected %s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,4 @@
+package responses
+
+// StatusResponse type aliases map[string]string
+type StatusResponse map[string]string
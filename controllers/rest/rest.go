@@ -3,32 +3,59 @@ package rest
 
 import (
 	"context"
-	"flag"
-	"log"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
-	"os"
-	"os/signal"
 	"time"
 
-	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+	"go.uber.org/zap"
 
 	"book-management-system/configs"
 	"book-management-system/docs"
 	"book-management-system/entities/constants"
+	"book-management-system/lifecycle"
+	"book-management-system/logging"
+	"book-management-system/ratelimit"
 	"book-management-system/usecases"
 )
 
-// Init REST controllers
-func Init(useCase *usecases.UseCase) {
+// Init REST controllers and returns components serving them, fail is called when the server stops serving
+func Init(useCase *usecases.UseCase, fail func(error)) []lifecycle.Component {
+	cfg := configs.GetConfig()
 	r := mux.NewRouter()
+	r.Use(otelmux.Middleware(constants.ServiceName))
+
+	limiter := newRateLimiter(ratelimit.NewMemoryStore(), cfg.RateLimit)
+	crossOrigin := newCORS(cfg.CORS)
+	configs.OnChange(func(_, new configs.Configs) {
+		limiter.configure(new.RateLimit)
+		crossOrigin.configure(new.CORS)
+	})
+	r.Use(limiter.Middleware)
+	r.Use(bodyLimit(int64(cfg.Server.MaxBodyBytes), int64(cfg.Server.MaxImportBytes)))
+
+	idempotency := newIdempotencyMiddleware(useCase.Service.IdempotencyService, cfg.Idempotency, limiter)
+	r.Use(idempotency.Middleware)
 
 	NewBookController(r, useCase)
+	NewBookImportController(r, useCase)
+	NewBookExportController(r, useCase)
+	NewBookDuplicateController(r, useCase)
 	NewMemberController(r, useCase)
+	NewWebhookController(r, useCase)
+	eventCtrl := NewEventController(r, useCase, crossOrigin)
+	healthCtrl := NewHealthController(r, useCase)
 
 	initDoc(r)
-	serve(r)
+	initMetrics(r)
+	return []lifecycle.Component{
+		lifecycle.Worker("idempotency key purge", idempotency.purgeExpiredKeys),
+		newServer(securityHeaders(cfg.Server.HSTSMaxAge)(crossOrigin.Handler(r)), healthCtrl, eventCtrl, fail),
+	}
 }
 
 func initDoc(r *mux.Router) {
@@ -37,43 +64,42 @@ func initDoc(r *mux.Router) {
 	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 }
 
-func serve(r http.Handler) {
-	var wait time.Duration
-	flag.DurationVar(
-		&wait,
-		"graceful-timeout",
-		time.Second*15,
-		"the duration for which the server gracefully wait for existing connections to finish - e.g. 15s or 1m",
-	)
-	flag.Parse()
-
+// newServer returns component serving r on configured address,
+// stopping it fails readiness, ends event streams and waits for existing connections to finish
+func newServer(r http.Handler, healthCtrl *HealthController, eventCtrl *EventController, fail func(error)) lifecycle.Component {
 	cfg := configs.GetConfig().Server
-	r = handlers.LoggingHandler(os.Stdout, r)
 	srv := &http.Server{
 		Addr:         cfg.Address,
 		WriteTimeout: time.Duration(cfg.WriteTimeout) * time.Second,
 		ReadTimeout:  time.Duration(cfg.ReadTimeout) * time.Second,
 		IdleTimeout:  time.Duration(cfg.IdleTimeout) * time.Second,
-		Handler:      r,
+		Handler:      requestID(accessLog(r)),
 	}
+	// streams end once listener is closed, so that reconnecting subscribers reach other instances
+	srv.RegisterOnShutdown(eventCtrl.ShutDown)
+	logger := logging.Logger(logging.PackageRest)
 
-	go func() {
-		if err := srv.ListenAndServe(); err != nil {
-			log.Println(err)
-		}
-	}()
+	return lifecycle.Component{
+		Name: "http server",
+		Start: func(context.Context) error {
+			// listening before returning reports address in use as start failure
+			listener, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return err
+			}
+			logger.Info("serving HTTP", zap.String("address", listener.Addr().String()))
 
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
-	<-c
-
-	ctx, cancel := context.WithTimeout(context.Background(), wait)
-	defer cancel()
-
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Printf("Error HTTP server shutdown: %v", err)
+			go func() {
+				if err := srv.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+					fail(fmt.Errorf("HTTP server failed: %w", err))
+				}
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			// readiness fails before draining, so that no new traffic is routed here
+			healthCtrl.ShutDown()
+			return srv.Shutdown(ctx)
+		},
 	}
-
-	log.Println("Shutting down server gracefully")
-	os.Exit(0)
 }
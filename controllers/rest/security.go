@@ -0,0 +1,212 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gorilla/mux"
+
+	"book-management-system/configs"
+	"book-management-system/logging"
+)
+
+// errBodyTooLarge is returned reading request body over limit of bodyLimit
+var errBodyTooLarge = errors.New("request body too large")
+
+// importRoutes is path prefix of routes limited by server.maximportbytes
+const importRoutes = "/v1/book/import"
+
+// contentSecurityPolicy allows nothing since responses are JSON, swagger UI serves its own page and is left out
+const contentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+
+// corsExposedHeaders are response headers browser clients may read besides CORS-safelisted ones
+var corsExposedHeaders = strings.Join([]string{
+	"Location",
+	retryAfterHeader,
+	rateLimitLimitHeader,
+	rateLimitRemainingHeader,
+	rateLimitResetHeader,
+	idempotentReplayedHeader,
+	logging.RequestIDHeader,
+}, ", ")
+
+// securityHeaders sets headers keeping browsers from sniffing, framing or leaking referrer of responses,
+// Strict-Transport-Security is set when hstsMaxAge is positive
+func securityHeaders(hstsMaxAge int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			header.Set("X-Content-Type-Options", "nosniff")
+			header.Set("X-Frame-Options", "DENY")
+			header.Set("Referrer-Policy", "no-referrer")
+			if !strings.HasPrefix(r.URL.Path, "/swagger/") {
+				header.Set("Content-Security-Policy", contentSecurityPolicy)
+			}
+			if hstsMaxAge > 0 {
+				header.Set("Strict-Transport-Security", fmt.Sprintf("max-age=%d; includeSubDomains", hstsMaxAge))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// cors answers preflight requests and allows configured origins to read responses.
+// It wraps the router since preflight OPTIONS requests match no route.
+// Its policy is swapped on config reload, requests in flight keep the policy they started with.
+type cors struct {
+	policy atomic.Value
+}
+
+// corsPolicy is config of cors parsed once per load or reload
+type corsPolicy struct {
+	anyOrigin        bool
+	origins          map[string]bool
+	methods          map[string]bool
+	headers          map[string]bool
+	allowMethods     string
+	allowHeaders     string
+	allowCredentials bool
+	maxAge           string
+}
+
+func newCORS(cfg configs.CORSConfig) *cors {
+	c := &cors{}
+	c.configure(cfg)
+	return c
+}
+
+// configure applies cfg to next requests, empty list of origins disables CORS
+func (c *cors) configure(cfg configs.CORSConfig) {
+	p := &corsPolicy{
+		origins:          make(map[string]bool, len(cfg.AllowedOrigins)),
+		methods:          make(map[string]bool, len(cfg.AllowedMethods)),
+		headers:          make(map[string]bool, len(cfg.AllowedHeaders)),
+		allowMethods:     strings.Join(cfg.AllowedMethods, ", "),
+		allowHeaders:     strings.Join(cfg.AllowedHeaders, ", "),
+		allowCredentials: cfg.AllowCredentials,
+		maxAge:           strconv.Itoa(cfg.MaxAge),
+	}
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			p.anyOrigin = true
+		}
+		p.origins[strings.TrimSuffix(origin, "/")] = true
+	}
+	for _, method := range cfg.AllowedMethods {
+		p.methods[method] = true
+	}
+	for _, header := range cfg.AllowedHeaders {
+		p.headers[http.CanonicalHeaderKey(header)] = true
+	}
+	c.policy.Store(p)
+}
+
+// Handler wraps next, requests are passed through unchanged while no origin is configured
+func (c *cors) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := c.policy.Load().(*corsPolicy)
+		origin := r.Header.Get("Origin")
+		if len(p.origins) == 0 || origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		header := w.Header()
+		header.Add("Vary", "Origin")
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
+		if !p.allowsOrigin(origin) {
+			if preflight {
+				respondWithError(w, http.StatusForbidden, fmt.Sprintf("Origin %s is not allowed", origin))
+				return
+			}
+			// without CORS headers browser does not let the page read the response
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if p.allowCredentials {
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Set("Access-Control-Allow-Credentials", "true")
+		} else if p.anyOrigin {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+
+		if !preflight {
+			header.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if method := r.Header.Get("Access-Control-Request-Method"); !p.methods[method] {
+			respondWithError(w, http.StatusForbidden, fmt.Sprintf("Method %s is not allowed", method))
+			return
+		}
+		for _, name := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+			if name = strings.TrimSpace(name); name != "" && !p.headers[http.CanonicalHeaderKey(name)] {
+				respondWithError(w, http.StatusForbidden, fmt.Sprintf("Header %s is not allowed", name))
+				return
+			}
+		}
+		header.Set("Access-Control-Allow-Methods", p.allowMethods)
+		header.Set("Access-Control-Allow-Headers", p.allowHeaders)
+		header.Set("Access-Control-Max-Age", p.maxAge)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// allowsOrigin tells whether pages of origin may read responses by current policy
+func (c *cors) allowsOrigin(origin string) bool {
+	return c.policy.Load().(*corsPolicy).allowsOrigin(origin)
+}
+
+func (p *corsPolicy) allowsOrigin(origin string) bool {
+	return p.anyOrigin || p.origins[origin]
+}
+
+// bodyLimit limits request bodies of routes, import routes by importLimit and others by limit, in bytes
+func bodyLimit(limit, importLimit int64) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			maxBytes := limit
+			if route := mux.CurrentRoute(r); route != nil {
+				if template, err := route.GetPathTemplate(); err == nil && strings.HasPrefix(template, importRoutes) {
+					maxBytes = importLimit
+				}
+			}
+			if r.ContentLength > maxBytes {
+				respondWithError(w, http.StatusRequestEntityTooLarge,
+					fmt.Sprintf("Request body must not exceed %d bytes", maxBytes))
+				return
+			}
+			r.Body = &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, maxBytes), limit: maxBytes}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// limitedBody reports errBodyTooLarge when http.MaxBytesReader stops reading at limit
+type limitedBody struct {
+	io.ReadCloser
+	limit int64
+	read  int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if err != nil && err != io.EOF && b.read >= b.limit {
+		err = fmt.Errorf("%w, limit is %d bytes", errBodyTooLarge, b.limit)
+	}
+	return n, err
+}
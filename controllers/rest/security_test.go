@@ -0,0 +1,322 @@
+package rest
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"book-management-system/configs"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		hstsMaxAge int
+		expected   map[string]string
+	}{
+		{
+			name: "API route",
+			path: "/v1/book",
+			expected: map[string]string{
+				"X-Content-Type-Options":    "nosniff",
+				"X-Frame-Options":           "DENY",
+				"Referrer-Policy":           "no-referrer",
+				"Content-Security-Policy":   contentSecurityPolicy,
+				"Strict-Transport-Security": "",
+			},
+		},
+		{
+			name: "swagger UI keeps its scripts",
+			path: "/swagger/index.html",
+			expected: map[string]string{
+				"X-Content-Type-Options":  "nosniff",
+				"Content-Security-Policy": "",
+			},
+		},
+		{
+			name:       "HSTS",
+			path:       "/v1/book",
+			hstsMaxAge: 31536000,
+			expected: map[string]string{
+				"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := securityHeaders(tt.hstsMaxAge)(http.NotFoundHandler())
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			for name, value := range tt.expected {
+				if got := rec.Header().Get(name); got != value {
+					t.Errorf("GET %s got %s %q\n expected %q", tt.path, name, got, value)
+				}
+			}
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	cfg := configs.CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders: []string{"Content-Type", "Idempotency-Key"},
+		MaxAge:         600,
+	}
+
+	type request struct {
+		method  string
+		headers map[string]string
+	}
+	type output struct {
+		code    int
+		headers map[string]string
+	}
+
+	tests := []struct {
+		name           string
+		cfg            func(*configs.CORSConfig)
+		givenInput     request
+		expectedOutput output
+	}{
+		{
+			name:       "same origin request",
+			givenInput: request{method: http.MethodGet},
+			expectedOutput: output{
+				code:    http.StatusOK,
+				headers: map[string]string{"Access-Control-Allow-Origin": ""},
+			},
+		},
+		{
+			name: "allowed origin",
+			givenInput: request{
+				method:  http.MethodGet,
+				headers: map[string]string{"Origin": "https://app.example.com"},
+			},
+			expectedOutput: output{
+				code: http.StatusOK,
+				headers: map[string]string{
+					"Access-Control-Allow-Origin":      "https://app.example.com",
+					"Access-Control-Allow-Credentials": "",
+					"Access-Control-Expose-Headers":    corsExposedHeaders,
+					"Vary":                             "Origin",
+				},
+			},
+		},
+		{
+			name: "other origin",
+			givenInput: request{
+				method:  http.MethodGet,
+				headers: map[string]string{"Origin": "https://evil.example.com"},
+			},
+			expectedOutput: output{
+				code:    http.StatusOK,
+				headers: map[string]string{"Access-Control-Allow-Origin": ""},
+			},
+		},
+		{
+			name: "any origin",
+			cfg:  func(cfg *configs.CORSConfig) { cfg.AllowedOrigins = []string{"*"} },
+			givenInput: request{
+				method:  http.MethodGet,
+				headers: map[string]string{"Origin": "https://other.example.com"},
+			},
+			expectedOutput: output{
+				code:    http.StatusOK,
+				headers: map[string]string{"Access-Control-Allow-Origin": "*"},
+			},
+		},
+		{
+			name: "credentials",
+			cfg:  func(cfg *configs.CORSConfig) { cfg.AllowCredentials = true },
+			givenInput: request{
+				method:  http.MethodGet,
+				headers: map[string]string{"Origin": "https://app.example.com"},
+			},
+			expectedOutput: output{
+				code: http.StatusOK,
+				headers: map[string]string{
+					"Access-Control-Allow-Origin":      "https://app.example.com",
+					"Access-Control-Allow-Credentials": "true",
+				},
+			},
+		},
+		{
+			name: "preflight",
+			givenInput: request{
+				method: http.MethodOptions,
+				headers: map[string]string{
+					"Origin":                         "https://app.example.com",
+					"Access-Control-Request-Method":  http.MethodPost,
+					"Access-Control-Request-Headers": "content-type, idempotency-key",
+				},
+			},
+			expectedOutput: output{
+				code: http.StatusNoContent,
+				headers: map[string]string{
+					"Access-Control-Allow-Origin":  "https://app.example.com",
+					"Access-Control-Allow-Methods": "GET, POST",
+					"Access-Control-Allow-Headers": "Content-Type, Idempotency-Key",
+					"Access-Control-Max-Age":       "600",
+				},
+			},
+		},
+		{
+			name: "preflight of other origin",
+			givenInput: request{
+				method: http.MethodOptions,
+				headers: map[string]string{
+					"Origin":                        "https://evil.example.com",
+					"Access-Control-Request-Method": http.MethodPost,
+				},
+			},
+			expectedOutput: output{
+				code:    http.StatusForbidden,
+				headers: map[string]string{"Access-Control-Allow-Origin": ""},
+			},
+		},
+		{
+			name: "preflight of method not allowed",
+			givenInput: request{
+				method: http.MethodOptions,
+				headers: map[string]string{
+					"Origin":                        "https://app.example.com",
+					"Access-Control-Request-Method": http.MethodDelete,
+				},
+			},
+			expectedOutput: output{
+				code:    http.StatusForbidden,
+				headers: map[string]string{"Access-Control-Allow-Methods": ""},
+			},
+		},
+		{
+			name: "preflight of header not allowed",
+			givenInput: request{
+				method: http.MethodOptions,
+				headers: map[string]string{
+					"Origin":                         "https://app.example.com",
+					"Access-Control-Request-Method":  http.MethodPost,
+					"Access-Control-Request-Headers": "X-Custom",
+				},
+			},
+			expectedOutput: output{
+				code:    http.StatusForbidden,
+				headers: map[string]string{"Access-Control-Allow-Headers": ""},
+			},
+		},
+		{
+			name: "disabled",
+			cfg:  func(cfg *configs.CORSConfig) { cfg.AllowedOrigins = nil },
+			givenInput: request{
+				method:  http.MethodGet,
+				headers: map[string]string{"Origin": "https://app.example.com"},
+			},
+			expectedOutput: output{
+				code:    http.StatusOK,
+				headers: map[string]string{"Access-Control-Allow-Origin": "", "Vary": ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testCfg := cfg
+			if tt.cfg != nil {
+				tt.cfg(&testCfg)
+			}
+			handler := newCORS(testCfg).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(tt.givenInput.method, "/v1/book", nil)
+			for name, value := range tt.givenInput.headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedOutput.code {
+				t.Errorf("%s got status %d\n expected %d", tt.givenInput.method, rec.Code, tt.expectedOutput.code)
+			}
+			for name, value := range tt.expectedOutput.headers {
+				if got := rec.Header().Get(name); got != value {
+					t.Errorf("%s got %s %q\n expected %q", tt.givenInput.method, name, got, value)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSConfigure(t *testing.T) {
+	crossOrigin := newCORS(configs.CORSConfig{})
+	handler := crossOrigin.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	allowedOrigin := func() string {
+		req := httptest.NewRequest(http.MethodGet, "/v1/book", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	if got := allowedOrigin(); got != "" {
+		t.Errorf("disabled CORS got Access-Control-Allow-Origin %q\n expected none", got)
+	}
+	// reloaded config applies to the running handler
+	crossOrigin.configure(configs.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}})
+	if got := allowedOrigin(); got != "https://app.example.com" {
+		t.Errorf("reconfigured CORS got Access-Control-Allow-Origin %q\n expected %q", got, "https://app.example.com")
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		body          string
+		unknownLength bool
+		expectedCode  int
+	}{
+		{name: "within limit", path: "/v1/book", body: `{"name":"Go"}`, expectedCode: http.StatusOK},
+		{name: "declared length over limit", path: "/v1/book", body: strings.Repeat("a", 17), expectedCode: http.StatusRequestEntityTooLarge},
+		{name: "streamed body over limit", path: "/v1/book", body: strings.Repeat("a", 17), unknownLength: true, expectedCode: http.StatusRequestEntityTooLarge},
+		{name: "import route limit", path: "/v1/book/import", body: strings.Repeat("a", 17), expectedCode: http.StatusOK},
+	}
+
+	r := mux.NewRouter()
+	r.Use(bodyLimit(16, 32))
+	read := func(w http.ResponseWriter, r *http.Request) {
+		if _, err := ioutil.ReadAll(r.Body); err != nil {
+			if !errors.Is(err, errBodyTooLarge) {
+				t.Errorf("reading body got error %v\n expected %v", err, errBodyTooLarge)
+			}
+			respondWithError(w, bodyErrorStatus(err, http.StatusBadRequest), err.Error())
+		}
+	}
+	r.HandleFunc("/v1/book", read).Methods(http.MethodPost)
+	r.HandleFunc("/v1/book/import", read).Methods(http.MethodPost)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			if tt.unknownLength {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Errorf("POST %s got status %d\n expected %d", tt.path, rec.Code, tt.expectedCode)
+			}
+		})
+	}
+}
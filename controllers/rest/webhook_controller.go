@@ -0,0 +1,275 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"book-management-system/entities/models"
+	"book-management-system/usecases"
+	"book-management-system/usecases/services"
+)
+
+// WebhookController will handle webhook subscription and delivery log requests
+type WebhookController struct {
+	webhookService services.WebhookService
+}
+
+// NewWebhookController returns new WebhookController
+func NewWebhookController(route *mux.Router, useCase *usecases.UseCase) *WebhookController {
+	ctrl := &WebhookController{
+		webhookService: useCase.Service.WebhookService,
+	}
+
+	v1Route := route.PathPrefix("/v1").Subrouter()
+	v1WebhookRoute := v1Route.PathPrefix("/webhooks").Subrouter()
+	v1WebhookRoute.HandleFunc("", ctrl.CreateWebhook).Methods(http.MethodPost)
+	v1WebhookRoute.HandleFunc("", ctrl.GetWebhooks).Methods(http.MethodGet)
+	v1WebhookRoute.HandleFunc("/deliveries", ctrl.GetDeliveries).Methods(http.MethodGet)
+	v1WebhookRoute.HandleFunc("/deliveries/{id:[0-9]+}/redeliver", ctrl.Redeliver).Methods(http.MethodPost)
+	v1WebhookRoute.HandleFunc("/{id:[0-9]+}", ctrl.GetWebhook).Methods(http.MethodGet)
+	v1WebhookRoute.HandleFunc("/{id:[0-9]+}", ctrl.UpdateWebhook).Methods(http.MethodPut)
+	v1WebhookRoute.HandleFunc("/{id:[0-9]+}", ctrl.DeleteWebhook).Methods(http.MethodDelete)
+	v1WebhookRoute.HandleFunc("/{id:[0-9]+}/deliveries", ctrl.GetWebhookDeliveries).Methods(http.MethodGet)
+
+	return ctrl
+}
+
+// CreateWebhook handle create webhook request
+// @Summary Create a webhook
+// @Description Subscribe endpoint to domain events of event_types, all types when empty.
+// @Description Events are POSTed as JSON with headers X-Webhook-Delivery, X-Webhook-Event, X-Webhook-Timestamp
+// @Description and X-Webhook-Signature, which is sha256= followed by hex HMAC-SHA256 of the timestamp, a dot and
+// @Description the body keyed by the secret. The secret is generated unless given and returned only now.
+// @Description Failed deliveries are retried with exponential backoff, then kept as dead letters.
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Unique key to safely retry the request"
+// @Param request body models.Webhook true "Request Body"
+// @Success 201 {object} models.Webhook "Created"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 409 {object} responses.ErrorResponse "Conflict"
+// @Failure 413 {object} responses.ErrorResponse "Request Entity Too Large"
+// @Failure 415 {object} responses.ErrorResponse "Unsupported Media Type"
+// @Failure 422 {object} responses.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/webhooks [post]
+func (ctrl *WebhookController) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var webhook models.Webhook
+	if err := decodeJSON(r, &webhook); err != nil {
+		respondWithError(w, err.code,
+			fmt.Sprintf("Invalid request payload: %s", err.Error()))
+		return
+	}
+
+	if err := ctrl.webhookService.CreateWebhook(r.Context(), &webhook); err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed create webhook: %s", err.Error()))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, webhook)
+}
+
+// GetWebhooks handle get all webhooks request
+// @Summary Get all webhooks
+// @Description Get all webhooks without their secrets
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Webhook "OK"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/webhooks [get]
+func (ctrl *WebhookController) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := ctrl.webhookService.GetWebhooks(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError,
+			fmt.Sprintf("Failed get webhooks: %s", err.Error()))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, webhooks)
+}
+
+// GetWebhook handle get webhook by id request
+// @Summary Get a webhook
+// @Description Get a webhook by ID without its secret
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} models.Webhook "OK"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 404 {object} responses.ErrorResponse "Not Found"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/webhooks/{id} [get]
+func (ctrl *WebhookController) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	webhook, err := ctrl.webhookService.GetWebhook(r.Context(), id)
+	if err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed get webhook: %s", err.Error()))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, webhook)
+}
+
+// UpdateWebhook handle update webhook request
+// @Summary Replace a webhook
+// @Description Replace URL, event types and disabled flag of a webhook.
+// @Description The secret is kept unless a new one is given.
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param request body models.Webhook true "Request Body"
+// @Success 200 {object} models.Webhook "Updated"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 404 {object} responses.ErrorResponse "Not Found"
+// @Failure 413 {object} responses.ErrorResponse "Request Entity Too Large"
+// @Failure 415 {object} responses.ErrorResponse "Unsupported Media Type"
+// @Failure 422 {object} responses.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/webhooks/{id} [put]
+func (ctrl *WebhookController) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	var webhook models.Webhook
+	if err := decodeJSON(r, &webhook); err != nil {
+		respondWithError(w, err.code,
+			fmt.Sprintf("Invalid request payload: %s", err.Error()))
+		return
+	}
+	webhook.ID = id
+
+	if err := ctrl.webhookService.UpdateWebhook(r.Context(), &webhook); err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed update webhook: %s", err.Error()))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, webhook)
+}
+
+// DeleteWebhook handle delete webhook request
+// @Summary Delete a webhook
+// @Description Stop deliveries to a webhook, its delivery log is kept
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 404 {object} responses.ErrorResponse "Not Found"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/webhooks/{id} [delete]
+func (ctrl *WebhookController) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	if err := ctrl.webhookService.DeleteWebhook(r.Context(), id); err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed delete webhook: %s", err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetDeliveries handle get delivery log request of all webhooks
+// @Summary Get deliveries of all webhooks
+// @Description Get the newest 100 deliveries of all webhooks, including deleted ones.
+// @Description Dead deliveries failed all attempts, they are the dead letters to redeliver.
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param status query string false "Delivery status, all when empty" Enums(pending, succeeded, dead)
+// @Success 200 {array} models.WebhookDelivery "OK"
+// @Failure 422 {object} responses.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/webhooks/deliveries [get]
+func (ctrl *WebhookController) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctrl.respondWithDeliveries(w, r, 0)
+}
+
+// GetWebhookDeliveries handle get delivery log request of webhook
+// @Summary Get deliveries of a webhook
+// @Description Get the newest 100 deliveries of a webhook
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param status query string false "Delivery status, all when empty" Enums(pending, succeeded, dead)
+// @Success 200 {array} models.WebhookDelivery "OK"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 404 {object} responses.ErrorResponse "Not Found"
+// @Failure 422 {object} responses.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/webhooks/{id}/deliveries [get]
+func (ctrl *WebhookController) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	ctrl.respondWithDeliveries(w, r, id)
+}
+
+func (ctrl *WebhookController) respondWithDeliveries(w http.ResponseWriter, r *http.Request, webhookID uint) {
+	status := models.WebhookDeliveryStatus(r.URL.Query().Get("status"))
+
+	deliveries, err := ctrl.webhookService.GetDeliveries(r.Context(), webhookID, status)
+	if err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed get webhook deliveries: %s", err.Error()))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, deliveries)
+}
+
+// Redeliver handle manual redelivery request
+// @Summary Redeliver a webhook delivery
+// @Description Send a delivery again as soon as possible with fresh attempts, e.g. a dead letter after
+// @Description the endpoint was fixed. Receivers recognize redeliveries by the same X-Webhook-Delivery header.
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path int true "Delivery ID"
+// @Success 202 {object} models.WebhookDelivery "Accepted"
+// @Failure 400 {object} responses.ErrorResponse "Bad Request"
+// @Failure 404 {object} responses.ErrorResponse "Not Found"
+// @Failure 500 {object} responses.ErrorResponse "Internal Server Error"
+// @Router /v1/webhooks/deliveries/{id}/redeliver [post]
+func (ctrl *WebhookController) Redeliver(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromPath(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid delivery ID")
+		return
+	}
+
+	delivery, err := ctrl.webhookService.Redeliver(r.Context(), id)
+	if err != nil {
+		respondWithError(w, serviceErrorStatus(err),
+			fmt.Sprintf("Failed redeliver webhook delivery: %s", err.Error()))
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, delivery)
+}
@@ -0,0 +1,269 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+
+	"book-management-system/controllers/rest/responses"
+	"book-management-system/entities/models"
+	mocks "book-management-system/mocks/services"
+	"book-management-system/usecases"
+	"book-management-system/usecases/services"
+)
+
+const (
+	v1WebhooksURL = "/v1/webhooks"
+)
+
+func TestWebhookController(t *testing.T) {
+	type input struct {
+		method string
+		url    string
+		body   string
+	}
+	type output struct {
+		code         int
+		responseBody interface{}
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     *mocks.MockWebhookService
+	}
+
+	invalidStatusErr := fmt.Errorf("%w: unknown delivery status %q", models.ErrInvalid, "failed")
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success: create webhook",
+			givenInput: input{
+				method: http.MethodPost,
+				url:    v1WebhooksURL,
+				body:   `{"url":"https://accounting.example.com/hooks/library","event_types":["book.created"]}`,
+			},
+			expectedOutput: output{
+				code: http.StatusCreated,
+				responseBody: models.Webhook{
+					Model:      gorm.Model{ID: 1},
+					URL:        "https://accounting.example.com/hooks/library",
+					Secret:     "0123456789abcdef0123456789abcdef",
+					EventTypes: models.WebhookEventTypes{"book.created"},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					CreateWebhook(gomock.Any(), &models.Webhook{
+						URL:        "https://accounting.example.com/hooks/library",
+						EventTypes: models.WebhookEventTypes{"book.created"},
+					}).
+					DoAndReturn(func(_ interface{}, webhook *models.Webhook) error {
+						*webhook = conf.expected.responseBody.(models.Webhook)
+						return nil
+					})
+			},
+		},
+		{
+			name: "failed: create invalid webhook",
+			givenInput: input{
+				method: http.MethodPost,
+				url:    v1WebhooksURL,
+				body:   `{"url":"/hooks"}`,
+			},
+			expectedOutput: output{
+				code: http.StatusUnprocessableEntity,
+				responseBody: responses.ErrorResponse{
+					"error": "Failed create webhook: invalid model: url must be absolute http or https URL",
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					CreateWebhook(gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("%w: url must be absolute http or https URL", models.ErrInvalid))
+			},
+		},
+		{
+			name: "success: update webhook",
+			givenInput: input{
+				method: http.MethodPut,
+				url:    v1WebhooksURL + "/1",
+				body:   `{"url":"https://accounting.example.com/hooks/v2","disabled":true}`,
+			},
+			expectedOutput: output{
+				code: http.StatusOK,
+				responseBody: models.Webhook{
+					Model:    gorm.Model{ID: 1},
+					URL:      "https://accounting.example.com/hooks/v2",
+					Disabled: true,
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					UpdateWebhook(gomock.Any(), &models.Webhook{
+						Model:    gorm.Model{ID: 1},
+						URL:      "https://accounting.example.com/hooks/v2",
+						Disabled: true,
+					}).
+					Return(nil)
+			},
+		},
+		{
+			name: "success: delete webhook",
+			givenInput: input{
+				method: http.MethodDelete,
+				url:    v1WebhooksURL + "/1",
+			},
+			expectedOutput: output{
+				code: http.StatusNoContent,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					DeleteWebhook(gomock.Any(), uint(1)).
+					Return(nil)
+			},
+		},
+		{
+			name: "failed: delete unknown webhook",
+			givenInput: input{
+				method: http.MethodDelete,
+				url:    v1WebhooksURL + "/2",
+			},
+			expectedOutput: output{
+				code: http.StatusNotFound,
+				responseBody: responses.ErrorResponse{
+					"error": fmt.Sprintf("Failed delete webhook: %s", models.ErrNotFound.Error()),
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					DeleteWebhook(gomock.Any(), uint(2)).
+					Return(models.ErrNotFound)
+			},
+		},
+		{
+			name: "success: get dead letters",
+			givenInput: input{
+				method: http.MethodGet,
+				url:    v1WebhooksURL + "/deliveries?status=dead",
+			},
+			expectedOutput: output{
+				code: http.StatusOK,
+				responseBody: []models.WebhookDelivery{
+					{
+						ID:        3,
+						WebhookID: 1,
+						EventID:   11,
+						EventType: "book.created",
+						Payload:   `{"id":11}`,
+						Status:    models.WebhookDeliveryDead,
+						Attempts:  8,
+						Error:     "webhook is disabled",
+					},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetDeliveries(gomock.Any(), uint(0), models.WebhookDeliveryDead).
+					Return(conf.expected.responseBody, nil)
+			},
+		},
+		{
+			name: "failed: get deliveries of webhook with unknown status",
+			givenInput: input{
+				method: http.MethodGet,
+				url:    v1WebhooksURL + "/1/deliveries?status=failed",
+			},
+			expectedOutput: output{
+				code: http.StatusUnprocessableEntity,
+				responseBody: responses.ErrorResponse{
+					"error": fmt.Sprintf("Failed get webhook deliveries: %s", invalidStatusErr.Error()),
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					GetDeliveries(gomock.Any(), uint(1), models.WebhookDeliveryStatus("failed")).
+					Return(nil, invalidStatusErr)
+			},
+		},
+		{
+			name: "success: redeliver",
+			givenInput: input{
+				method: http.MethodPost,
+				url:    v1WebhooksURL + "/deliveries/3/redeliver",
+			},
+			expectedOutput: output{
+				code: http.StatusAccepted,
+				responseBody: &models.WebhookDelivery{
+					ID:        3,
+					WebhookID: 1,
+					EventID:   11,
+					EventType: "book.created",
+					Payload:   `{"id":11}`,
+					Status:    models.WebhookDeliveryPending,
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.EXPECT().
+					Redeliver(gomock.Any(), uint(3)).
+					Return(conf.expected.responseBody, nil)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(
+				tt.givenInput.method,
+				tt.givenInput.url,
+				strings.NewReader(tt.givenInput.body),
+			)
+			if tt.givenInput.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			resp := httptest.NewRecorder()
+
+			webhookServiceMock := mocks.NewMockWebhookService(ctrl)
+			tt.configureMock(mockConfig{
+				given:    tt.givenInput,
+				expected: tt.expectedOutput,
+				mock:     webhookServiceMock,
+			})
+
+			route := mux.NewRouter()
+			NewWebhookController(route, &usecases.UseCase{
+				Service: &services.Services{WebhookService: webhookServiceMock},
+			})
+			route.ServeHTTP(resp, req)
+
+			if resp.Code != tt.expectedOutput.code {
+				t.Errorf("%s %s got status code %d\n expected %d",
+					tt.givenInput.method, tt.givenInput.url, resp.Code, tt.expectedOutput.code)
+			}
+			got := resp.Body.String()
+			expected := ""
+			if tt.expectedOutput.responseBody != nil {
+				body, _ := json.Marshal(tt.expectedOutput.responseBody)
+				expected = string(body)
+			}
+			if got != expected {
+				t.Errorf("%s %s got response body %s\n expected %s",
+					tt.givenInput.method, tt.givenInput.url, got, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// runDetectDuplicates detects duplicate books for review, meant to run periodically.
+// It returns exit code, non-zero when the detection failed.
+func runDetectDuplicates(args []string) int {
+	flags := flag.NewFlagSet("duplicates", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s duplicates\n", appName)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return flagsExitCode(err)
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	useCase, shutdown := initUseCase()
+	defer shutdown()
+	count, err := useCase.Pipeline.BookDuplicatePipeline.DetectDuplicates(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed detect duplicates: %s\n", err)
+		return exitFailure
+	}
+	fmt.Printf("found %d duplicate candidates\n", count)
+	return exitOK
+}
@@ -1,15 +1,43 @@
 package models
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"gorm.io/gorm"
+
+	"book-management-system/entities/objects"
 )
 
 // Book model
 type Book struct {
 	gorm.Model
-	Name string `gorm:"name" json:"name" example:"The Alchemist"`
-	ISBN string `gorm:"isbn" json:"isbn" example:"9780062315007"`
+	Name string       `gorm:"name" json:"name" example:"The Alchemist"`
+	ISBN objects.ISBN `gorm:"isbn" json:"isbn" swaggertype:"string" example:"9780062315007"`
 }
 
 // Books model is an array of Book
 type Books []Book
+
+// LastModified returns latest update time of books, zero time when there are none
+func (books Books) LastModified() time.Time {
+	var last time.Time
+	for _, book := range books {
+		if book.UpdatedAt.After(last) {
+			last = book.UpdatedAt
+		}
+	}
+	return last
+}
+
+// Validate returns ErrInvalid wrapped error when book is not valid
+func (book *Book) Validate() error {
+	if strings.TrimSpace(book.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalid)
+	}
+	if err := book.ISBN.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"time"
+)
+
+// BookDuplicateStatus is review state of duplicate candidate
+type BookDuplicateStatus string
+
+// BookDuplicateStatus values
+const (
+	BookDuplicatePending   BookDuplicateStatus = "pending"
+	BookDuplicateMerged    BookDuplicateStatus = "merged"
+	BookDuplicateDismissed BookDuplicateStatus = "dismissed"
+)
+
+// BookDuplicateReason tells why two books are considered duplicates
+type BookDuplicateReason string
+
+// BookDuplicateReason values
+const (
+	BookDuplicateSameISBN     BookDuplicateReason = "isbn"
+	BookDuplicateSimilarTitle BookDuplicateReason = "title"
+)
+
+// BookDuplicateReasons are stored as comma separated list
+type BookDuplicateReasons []BookDuplicateReason
+
+// Value implements driver.Valuer
+func (reasons BookDuplicateReasons) Value() (driver.Value, error) {
+	values := make([]string, len(reasons))
+	for i, reason := range reasons {
+		values[i] = string(reason)
+	}
+	return strings.Join(values, ","), nil
+}
+
+// Scan implements sql.Scanner
+func (reasons *BookDuplicateReasons) Scan(value interface{}) error {
+	var joined string
+	switch v := value.(type) {
+	case []byte:
+		joined = string(v)
+	case string:
+		joined = v
+	case nil:
+	default:
+		return errors.New("unsupported book duplicate reasons value")
+	}
+
+	*reasons = BookDuplicateReasons{}
+	for _, reason := range strings.Split(joined, ",") {
+		if reason != "" {
+			*reasons = append(*reasons, BookDuplicateReason(reason))
+		}
+	}
+	return nil
+}
+
+// BookDuplicate is candidate pair of books which may describe the same edition.
+// BookID is the lower ID of the pair, so every pair is stored once.
+type BookDuplicate struct {
+	ID          uint                 `gorm:"primarykey" json:"id" example:"1"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+	BookID      uint                 `gorm:"not null;uniqueIndex:idx_book_duplicate_pair" json:"book_id" example:"1"`
+	DuplicateID uint                 `gorm:"not null;uniqueIndex:idx_book_duplicate_pair" json:"duplicate_id" example:"2"`
+	Score       float64              `gorm:"not null" json:"score" example:"0.93"`
+	Reasons     BookDuplicateReasons `gorm:"size:64;not null" json:"reasons" swaggertype:"array,string" example:"isbn,title"`
+	Status      BookDuplicateStatus  `gorm:"size:16;not null;index" json:"status" example:"pending"`
+	Book        *Book                `gorm:"-" json:"book,omitempty"`
+	Duplicate   *Book                `gorm:"-" json:"duplicate,omitempty"`
+}
+
+// BookRedirect points ID of merged book to the book it was merged into
+type BookRedirect struct {
+	FromID    uint `gorm:"primarykey;autoIncrement:false"`
+	ToID      uint `gorm:"not null;index"`
+	CreatedAt time.Time
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"book-management-system/entities/objects"
+)
+
+// BookImportRowStatus is outcome of importing single row
+type BookImportRowStatus string
+
+// BookImportRowStatus values
+const (
+	BookImportRowCreated   BookImportRowStatus = "created"
+	BookImportRowMerged    BookImportRowStatus = "merged"
+	BookImportRowDuplicate BookImportRowStatus = "duplicate"
+	BookImportRowError     BookImportRowStatus = "error"
+)
+
+// ImportJobStatus is state of asynchronous import job
+type ImportJobStatus string
+
+// ImportJobStatus values
+const (
+	ImportJobPending   ImportJobStatus = "pending"
+	ImportJobRunning   ImportJobStatus = "running"
+	ImportJobCompleted ImportJobStatus = "completed"
+	ImportJobFailed    ImportJobStatus = "failed"
+)
+
+// BookImportRow is report entry of single imported row
+type BookImportRow struct {
+	Row     int                 `json:"row" example:"2"`
+	Status  BookImportRowStatus `json:"status" example:"created"`
+	BookID  uint                `json:"book_id,omitempty" example:"1"`
+	ISBN    objects.ISBN        `json:"isbn,omitempty" swaggertype:"string" example:"9780062315007"`
+	Message string              `json:"message,omitempty"`
+	Record  *CatalogRecord      `json:"record,omitempty"`
+}
+
+// CatalogRecord is book mapped from cataloguing record.
+// Only Book is stored, authors, publisher, year and subjects are not part of the catalogue yet.
+type CatalogRecord struct {
+	Book            Book     `json:"book"`
+	Authors         []string `json:"authors,omitempty" example:"Coelho, Paulo"`
+	Publisher       string   `json:"publisher,omitempty" example:"HarperOne"`
+	PublicationYear int      `json:"publication_year,omitempty" example:"2014"`
+	Subjects        []string `json:"subjects,omitempty" example:"Alchemists -- Fiction"`
+}
+
+// BookImportReport summarizes result of book import.
+// DryRun report shows what the import would do without storing anything.
+type BookImportReport struct {
+	DryRun     bool            `json:"dry_run,omitempty"`
+	Total      int             `json:"total" example:"1"`
+	Created    int             `json:"created" example:"1"`
+	Merged     int             `json:"merged" example:"0"`
+	Duplicates int             `json:"duplicates" example:"0"`
+	Errors     int             `json:"errors" example:"0"`
+	Rows       []BookImportRow `json:"rows"`
+}
+
+// Add appends row to report and updates counters
+func (report *BookImportReport) Add(row BookImportRow) {
+	report.Total++
+	switch row.Status {
+	case BookImportRowCreated:
+		report.Created++
+	case BookImportRowMerged:
+		report.Merged++
+	case BookImportRowDuplicate:
+		report.Duplicates++
+	case BookImportRowError:
+		report.Errors++
+	}
+	report.Rows = append(report.Rows, row)
+}
+
+// Value implements driver.Valuer, report is stored as JSON
+func (report BookImportReport) Value() (driver.Value, error) {
+	return json.Marshal(report)
+}
+
+// GormDBDataType stores report as JSON text, MySQL text is too short for large imports
+func (BookImportReport) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	if db.Dialector.Name() == "mysql" {
+		return "longtext"
+	}
+	return "text"
+}
+
+// Scan implements sql.Scanner
+func (report *BookImportReport) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, report)
+	case string:
+		return json.Unmarshal([]byte(v), report)
+	case nil:
+		return nil
+	default:
+		return errors.New("unsupported book import report value")
+	}
+}
+
+// ImportJob model tracks asynchronous book import
+type ImportJob struct {
+	gorm.Model
+	Status    ImportJobStatus  `gorm:"size:16;not null" json:"status" example:"completed"`
+	Format    string           `gorm:"size:16;not null" json:"format" example:"csv"`
+	Processed int              `gorm:"not null" json:"processed" example:"1"`
+	Error     string           `json:"error,omitempty"`
+	Report    BookImportReport `json:"report"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound returned when requested record does not exist
+	ErrNotFound = errors.New("record not found")
+	// ErrInvalid returned when model fails validation
+	ErrInvalid = errors.New("invalid model")
+)
+
+// MovedError returned when requested record was merged into record with ID.
+// It is ErrNotFound to callers not following the move, e.g. those changing the record.
+type MovedError struct {
+	ID uint
+}
+
+func (e *MovedError) Error() string {
+	return fmt.Sprintf("record moved to %d", e.ID)
+}
+
+// Unwrap returns ErrNotFound, the record itself no longer exists
+func (e *MovedError) Unwrap() error {
+	return ErrNotFound
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrIdempotencyKeyInProgress returned when request with same idempotency key is still being processed
+	ErrIdempotencyKeyInProgress = errors.New("request with the same idempotency key is in progress")
+	// ErrIdempotencyKeyMismatch returned when idempotency key is reused with different request
+	ErrIdempotencyKeyMismatch = errors.New("idempotency key is reused with different request")
+	// ErrIdempotencyKeyLost returned when key of running request was taken over by another request
+	ErrIdempotencyKeyLost = errors.New("idempotency key was taken over by another request")
+)
+
+// IdempotencyKey model stores first response of request sent with Idempotency-Key header
+type IdempotencyKey struct {
+	Key string `gorm:"primaryKey;size:255"`
+	// Token identifies the request which reserved the key, a key taken over from a stale request gets a new one
+	Token        string    `gorm:"size:64;not null"`
+	RequestHash  string    `gorm:"size:64;not null"`
+	Completed    bool      `gorm:"not null"`
+	StatusCode   int       `gorm:"not null"`
+	ResponseBody []byte    // longblob on MySQL, bytea on Postgres
+	CreatedAt    time.Time `gorm:"not null"`
+	// LockedUntil is when key still in progress is considered abandoned, its running request keeps extending it
+	LockedUntil time.Time `gorm:"not null"`
+	ExpiresAt   time.Time `gorm:"index;not null"`
+}
+
+// IsAbandoned returns true when key is still in progress and its request stopped extending the lock
+func (key *IdempotencyKey) IsAbandoned(now time.Time) bool {
+	return !key.Completed && !now.Before(key.LockedUntil)
+}
+
+// IsExpired returns true when key is past its TTL at given time
+func (key *IdempotencyKey) IsExpired(now time.Time) bool {
+	return !now.Before(key.ExpiresAt)
+}
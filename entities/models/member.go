@@ -1,6 +1,9 @@
 package models
 
 import (
+	"fmt"
+	"strings"
+
 	"gorm.io/gorm"
 )
 
@@ -12,3 +15,11 @@ type Member struct {
 
 // Members model is an array of Member
 type Members []Member
+
+// Validate returns ErrInvalid wrapped error when member is not valid
+func (member *Member) Validate() error {
+	if strings.TrimSpace(member.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalid)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// minWebhookSecretLength keeps chosen secrets from being guessed
+const minWebhookSecretLength = 16
+
+// WebhookEventTypes are stored as comma separated list
+type WebhookEventTypes []string
+
+// Value implements driver.Valuer
+func (types WebhookEventTypes) Value() (driver.Value, error) {
+	return strings.Join(types, ","), nil
+}
+
+// Scan implements sql.Scanner
+func (types *WebhookEventTypes) Scan(value interface{}) error {
+	var joined string
+	switch v := value.(type) {
+	case []byte:
+		joined = string(v)
+	case string:
+		joined = v
+	case nil:
+	default:
+		return errors.New("unsupported webhook event types value")
+	}
+
+	*types = WebhookEventTypes{}
+	for _, t := range strings.Split(joined, ",") {
+		if t != "" {
+			*types = append(*types, t)
+		}
+	}
+	return nil
+}
+
+// Webhook is subscription of partner endpoint to domain events, all types when EventTypes is empty.
+// Payloads are signed with Secret, it is returned only when the webhook is created.
+type Webhook struct {
+	gorm.Model
+	URL        string            `gorm:"size:2048;not null" json:"url" example:"https://accounting.example.com/hooks/library"`
+	Secret     string            `gorm:"size:128;not null" json:"secret,omitempty" example:"8f2b0c7e4a9d41f6b3e5c1a7d9f0e2b4"`
+	EventTypes WebhookEventTypes `gorm:"size:255;not null" json:"event_types" swaggertype:"array,string" example:"book.created,member.created"`
+	Disabled   bool              `gorm:"not null" json:"disabled"`
+}
+
+// Validate returns ErrInvalid wrapped error when webhook is not valid, event types are checked by services
+func (webhook *Webhook) Validate() error {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be absolute http or https URL", ErrInvalid)
+	}
+	if len(webhook.Secret) < minWebhookSecretLength {
+		return fmt.Errorf("%w: secret must have at least %d characters", ErrInvalid, minWebhookSecretLength)
+	}
+	return nil
+}
+
+// Matches tells whether events of eventType are delivered to webhook
+func (webhook *Webhook) Matches(eventType string) bool {
+	if webhook.Disabled {
+		return false
+	}
+	if len(webhook.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range webhook.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus is state of webhook delivery
+type WebhookDeliveryStatus string
+
+// WebhookDeliveryStatus values, dead deliveries failed all attempts and wait for manual redelivery
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryDead      WebhookDeliveryStatus = "dead"
+)
+
+// JSONText is JSON document stored in text column, encoded in JSON as the document itself
+type JSONText string
+
+// MarshalJSON implements json.Marshaler
+func (text JSONText) MarshalJSON() ([]byte, error) {
+	if text == "" {
+		return []byte("null"), nil
+	}
+	return []byte(text), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (text *JSONText) UnmarshalJSON(data []byte) error {
+	*text = JSONText(data)
+	return nil
+}
+
+// WebhookDelivery is event sent or to be sent to webhook, it is the delivery log entry of the webhook.
+// Pending delivery is sent at NextAttemptAt.
+type WebhookDelivery struct {
+	ID            uint                  `gorm:"primarykey" json:"id" example:"1"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+	WebhookID     uint                  `gorm:"not null;index" json:"webhook_id" example:"1"`
+	EventID       uint64                `gorm:"not null" json:"event_id" example:"1700000000000001"`
+	EventType     string                `gorm:"size:64;not null" json:"event_type" example:"book.created"`
+	Payload       JSONText              `gorm:"not null" json:"payload" swaggertype:"object"`
+	Status        WebhookDeliveryStatus `gorm:"size:16;not null;index:idx_webhook_deliveries_due" json:"status" example:"pending"`
+	NextAttemptAt time.Time             `gorm:"not null;index:idx_webhook_deliveries_due" json:"next_attempt_at"`
+	Attempts      int                   `gorm:"not null" json:"attempts" example:"1"`
+	ResponseCode  int                   `gorm:"not null" json:"response_code,omitempty" example:"500"`
+	Error         string                `json:"error,omitempty"`
+}
@@ -0,0 +1,215 @@
+// Package objects contains value objects of the domain models.
+package objects
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	isbn13Length = 13
+	isbn10Length = 10
+
+	booklandPrefix = "978"
+)
+
+var (
+	// ErrInvalidISBN returned when value is not valid ISBN-10 or ISBN-13
+	ErrInvalidISBN = errors.New("invalid ISBN")
+	// ErrNoISBN10 returned when ISBN-13 with 979 prefix is converted to ISBN-10
+	ErrNoISBN10 = errors.New("ISBN has no ISBN-10 form")
+)
+
+// ISBN is International Standard Book Number kept in canonical form,
+// 13 digits without hyphens. Empty ISBN is valid and means the book has none.
+type ISBN string
+
+// ParseISBN parses ISBN-10 or ISBN-13 with optional "ISBN" label, hyphens and spaces
+// and returns it in canonical ISBN-13 form
+func ParseISBN(value string) (ISBN, error) {
+	digits := compactISBN(value)
+	switch len(digits) {
+	case 0:
+		return "", nil
+	case isbn10Length:
+		if !validISBN10(digits) {
+			return "", fmt.Errorf("%w: %q has wrong check digit", ErrInvalidISBN, value)
+		}
+		body := booklandPrefix + digits[:9]
+		return ISBN(body + isbn13CheckDigit(body)), nil
+	case isbn13Length:
+		if !validISBN13(digits) {
+			return "", fmt.Errorf("%w: %q has wrong check digit", ErrInvalidISBN, value)
+		}
+		if !strings.HasPrefix(digits, "978") && !strings.HasPrefix(digits, "979") {
+			return "", fmt.Errorf("%w: %q does not start with 978 or 979", ErrInvalidISBN, value)
+		}
+		return ISBN(digits), nil
+	default:
+		return "", fmt.Errorf("%w: %q must have 10 or 13 digits", ErrInvalidISBN, value)
+	}
+}
+
+// NewISBN returns canonical ISBN of value. Invalid value is kept as given,
+// so data entered before validation can still be read and Validate reports it.
+func NewISBN(value string) ISBN {
+	isbn, err := ParseISBN(value)
+	if err != nil {
+		return ISBN(strings.TrimSpace(value))
+	}
+	return isbn
+}
+
+// ISBNFromBody returns ISBN-13 of 12 digits body, prefix, group, publisher and title, completed with check digit
+func ISBNFromBody(body string) (ISBN, error) {
+	if len(body) != isbn13Length-1 {
+		return "", fmt.Errorf("%w: body %q must have 12 digits", ErrInvalidISBN, body)
+	}
+	for _, r := range body {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("%w: body %q must have 12 digits", ErrInvalidISBN, body)
+		}
+	}
+	return ParseISBN(body + isbn13CheckDigit(body))
+}
+
+// String returns canonical form
+func (isbn ISBN) String() string {
+	return string(isbn)
+}
+
+// IsZero reports whether ISBN is empty
+func (isbn ISBN) IsZero() bool {
+	return isbn == ""
+}
+
+// Validate returns ErrInvalidISBN wrapped error when ISBN is neither empty nor canonical
+func (isbn ISBN) Validate() error {
+	parsed, err := ParseISBN(string(isbn))
+	if err != nil {
+		return err
+	}
+	if parsed != isbn {
+		return fmt.Errorf("%w: %q is not in canonical form", ErrInvalidISBN, string(isbn))
+	}
+	return nil
+}
+
+// ISBN13 returns ISBN-13 without hyphens
+func (isbn ISBN) ISBN13() string {
+	return string(isbn)
+}
+
+// ISBN10 returns ISBN-10 without hyphens, only ISBN with 978 prefix has one
+func (isbn ISBN) ISBN10() (string, error) {
+	if err := isbn.Validate(); err != nil {
+		return "", err
+	}
+	if isbn.IsZero() || !strings.HasPrefix(string(isbn), booklandPrefix) {
+		return "", fmt.Errorf("%w: %q", ErrNoISBN10, string(isbn))
+	}
+
+	body := string(isbn)[3:12]
+	return body + isbn10CheckDigit(body), nil
+}
+
+// Value implements driver.Valuer, ISBN is stored in canonical form
+func (isbn ISBN) Value() (driver.Value, error) {
+	return string(isbn), nil
+}
+
+// Scan implements sql.Scanner
+func (isbn *ISBN) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		*isbn = NewISBN(string(v))
+	case string:
+		*isbn = NewISBN(v)
+	case nil:
+		*isbn = ""
+	default:
+		return fmt.Errorf("unsupported ISBN value %T", value)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (isbn ISBN) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(isbn))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, value in any format is normalized
+func (isbn *ISBN) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*isbn = NewISBN(value)
+	return nil
+}
+
+// compactISBN removes label, hyphens and spaces
+func compactISBN(value string) string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	for _, label := range []string{"ISBN-13", "ISBN-10", "ISBN"} {
+		if strings.HasPrefix(value, label) {
+			value = strings.TrimLeft(strings.TrimPrefix(value, label), ": ")
+			break
+		}
+	}
+
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ' ', '‐', '‑':
+			return -1
+		}
+		return r
+	}, value)
+}
+
+func validISBN10(digits string) bool {
+	for _, r := range digits[:9] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return isbn10CheckDigit(digits[:9]) == digits[9:]
+}
+
+func validISBN13(digits string) bool {
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return isbn13CheckDigit(digits[:12]) == digits[12:]
+}
+
+// isbn10CheckDigit returns check digit of first 9 digits, weights 10 to 2 modulo 11
+func isbn10CheckDigit(body string) string {
+	sum := 0
+	for i, r := range body {
+		sum += (10 - i) * int(r-'0')
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return "X"
+	}
+	return string(rune('0' + check))
+}
+
+// isbn13CheckDigit returns check digit of first 12 digits, weights 1 and 3 modulo 10
+func isbn13CheckDigit(body string) string {
+	sum := 0
+	for i, r := range body {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(r-'0')
+	}
+	return string(rune('0' + (10-sum%10)%10))
+}
@@ -0,0 +1,160 @@
+package objects
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// rangeDigits is number of digits ranges are compared on, as in ISBN RangeMessage
+const rangeDigits = 7
+
+var (
+	// ErrUnknownISBNRange returned when ISBN is outside of known range table
+	ErrUnknownISBNRange = errors.New("ISBN range is unknown")
+)
+
+// isbnRange maps 7 digits range to number of digits of the element, 0 means unassigned
+type isbnRange struct {
+	low    string
+	high   string
+	length int
+}
+
+// isbnGroupRanges are registration group ranges of each EAN prefix
+var isbnGroupRanges = map[string][]isbnRange{
+	"978": {
+		{"0000000", "5999999", 1},
+		{"6000000", "6499999", 3},
+		{"6500000", "6599999", 2},
+		{"6600000", "6999999", 0},
+		{"7000000", "7999999", 1},
+		{"8000000", "9499999", 2},
+		{"9500000", "9899999", 3},
+		{"9900000", "9989999", 4},
+		{"9990000", "9999999", 5},
+	},
+	"979": {
+		{"0000000", "0999999", 0},
+		{"1000000", "1299999", 2},
+		{"1300000", "7999999", 0},
+		{"8000000", "8999999", 1},
+		{"9000000", "9999999", 0},
+	},
+}
+
+// isbnRegistrantRanges are registrant ranges of registration groups.
+// Only English language groups are listed, other groups have no known publisher prefix.
+var isbnRegistrantRanges = map[string][]isbnRange{
+	"978-0": {
+		{"0000000", "1999999", 2},
+		{"2000000", "6999999", 3},
+		{"7000000", "8499999", 4},
+		{"8500000", "8999999", 5},
+		{"9000000", "9499999", 6},
+		{"9500000", "9999999", 7},
+	},
+	"978-1": {
+		{"0000000", "0999999", 2},
+		{"1000000", "3999999", 3},
+		{"4000000", "5499999", 4},
+		{"5500000", "8697999", 5},
+		{"8698000", "9989999", 6},
+		{"9990000", "9999999", 7},
+	},
+}
+
+// ISBNParts are elements of ISBN-13
+type ISBNParts struct {
+	Prefix      string
+	Group       string
+	Registrant  string
+	Publication string
+	CheckDigit  string
+}
+
+// String returns hyphenated ISBN-13
+func (parts ISBNParts) String() string {
+	return strings.Join([]string{parts.Prefix, parts.Group, parts.Registrant, parts.Publication, parts.CheckDigit}, "-")
+}
+
+// RegistrationGroup returns EAN prefix and registration group, e.g. "978-0"
+func (isbn ISBN) RegistrationGroup() (string, error) {
+	prefix, group, err := isbn.group()
+	if err != nil {
+		return "", err
+	}
+	return prefix + "-" + group, nil
+}
+
+// PublisherPrefix returns ISBN prefix assigned to registrant, e.g. "978-0-06"
+func (isbn ISBN) PublisherPrefix() (string, error) {
+	parts, err := isbn.Parts()
+	if err != nil {
+		return "", err
+	}
+	return parts.Prefix + "-" + parts.Group + "-" + parts.Registrant, nil
+}
+
+// Parts splits ISBN into its elements
+func (isbn ISBN) Parts() (ISBNParts, error) {
+	prefix, group, err := isbn.group()
+	if err != nil {
+		return ISBNParts{}, err
+	}
+
+	rest := string(isbn)[len(prefix)+len(group) : isbn13Length-1]
+	ranges, ok := isbnRegistrantRanges[prefix+"-"+group]
+	if !ok {
+		return ISBNParts{}, fmt.Errorf("%w: registrants of group %s-%s", ErrUnknownISBNRange, prefix, group)
+	}
+	length := rangeLength(ranges, rest)
+	if length == 0 || length >= len(rest) {
+		return ISBNParts{}, fmt.Errorf("%w: %s", ErrUnknownISBNRange, string(isbn))
+	}
+
+	return ISBNParts{
+		Prefix:      prefix,
+		Group:       group,
+		Registrant:  rest[:length],
+		Publication: rest[length:],
+		CheckDigit:  string(isbn)[isbn13Length-1:],
+	}, nil
+}
+
+// Hyphenate returns ISBN-13 with hyphens between its elements
+func (isbn ISBN) Hyphenate() (string, error) {
+	parts, err := isbn.Parts()
+	if err != nil {
+		return "", err
+	}
+	return parts.String(), nil
+}
+
+func (isbn ISBN) group() (prefix string, group string, err error) {
+	if err := isbn.Validate(); err != nil {
+		return "", "", err
+	}
+	if isbn.IsZero() {
+		return "", "", fmt.Errorf("%w: empty ISBN", ErrInvalidISBN)
+	}
+
+	prefix = string(isbn)[:3]
+	rest := string(isbn)[3 : isbn13Length-1]
+	length := rangeLength(isbnGroupRanges[prefix], rest)
+	if length == 0 {
+		return "", "", fmt.Errorf("%w: %s", ErrUnknownISBNRange, string(isbn))
+	}
+	return prefix, rest[:length], nil
+}
+
+// rangeLength returns element length of range digits falls in, 0 when none matches
+func rangeLength(ranges []isbnRange, digits string) int {
+	key := (digits + strings.Repeat("0", rangeDigits))[:rangeDigits]
+	for _, r := range ranges {
+		if key >= r.low && key <= r.high {
+			return r.length
+		}
+	}
+	return 0
+}
@@ -0,0 +1,222 @@
+package objects
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseISBN(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected ISBN
+		err      error
+	}{
+		{name: "empty", value: "", expected: ""},
+		{name: "isbn-13", value: "9780062315007", expected: "9780062315007"},
+		{name: "hyphenated isbn-13 with label", value: "ISBN-13: 978-0-06-231500-7", expected: "9780062315007"},
+		{name: "isbn-10", value: "0-06-231500-5", expected: "9780062315007"},
+		{name: "isbn-10 with X check digit", value: "0 8044 2957 x", expected: "9780804429573"},
+		{name: "979 isbn-13", value: "979-10-90636-07-1", expected: "9791090636071"},
+		{name: "wrong isbn-10 check digit", value: "0062315006", err: ErrInvalidISBN},
+		{name: "wrong isbn-13 check digit", value: "9780062315008", err: ErrInvalidISBN},
+		{name: "not bookland prefix", value: "4006381333931", err: ErrInvalidISBN},
+		{name: "wrong length", value: "1234", err: ErrInvalidISBN},
+		{name: "letters", value: "97800623150AB", err: ErrInvalidISBN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseISBN(tt.value)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("ParseISBN() got error: %v\nexpected: %v", err, tt.err)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseISBN() got %q\nexpected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewISBN(t *testing.T) {
+	if got := NewISBN("0-06-231500-5"); got != "9780062315007" {
+		t.Errorf("NewISBN() got %q\nexpected %q", got, "9780062315007")
+	}
+
+	invalid := NewISBN(" 1234 ")
+	if invalid != "1234" {
+		t.Errorf("NewISBN() got %q\nexpected %q", invalid, "1234")
+	}
+	if err := invalid.Validate(); !errors.Is(err, ErrInvalidISBN) {
+		t.Errorf("Validate() got error: %v\nexpected: %v", err, ErrInvalidISBN)
+	}
+}
+
+func TestISBNFromBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected ISBN
+		err      error
+	}{
+		{name: "978 body", body: "978006231500", expected: "9780062315007"},
+		{name: "979 body", body: "979109063607", expected: "9791090636071"},
+		{name: "not bookland prefix", body: "400638133393", err: ErrInvalidISBN},
+		{name: "wrong length", body: "97800623150", err: ErrInvalidISBN},
+		{name: "letters", body: "97800623150A", err: ErrInvalidISBN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ISBNFromBody(tt.body)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("ISBNFromBody() got error: %v\nexpected: %v", err, tt.err)
+			}
+			if got != tt.expected {
+				t.Errorf("ISBNFromBody() got %q\nexpected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestISBNValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		isbn ISBN
+		err  error
+	}{
+		{name: "empty", isbn: ""},
+		{name: "canonical", isbn: "9780062315007"},
+		{name: "not canonical", isbn: "0062315005", err: ErrInvalidISBN},
+		{name: "invalid", isbn: "1234", err: ErrInvalidISBN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.isbn.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() got error: %v\nexpected: %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestISBNISBN10(t *testing.T) {
+	tests := []struct {
+		name     string
+		isbn     ISBN
+		expected string
+		err      error
+	}{
+		{name: "978 prefix", isbn: "9780062315007", expected: "0062315005"},
+		{name: "X check digit", isbn: "9780804429573", expected: "080442957X"},
+		{name: "979 prefix", isbn: "9791090636071", err: ErrNoISBN10},
+		{name: "empty", isbn: "", err: ErrNoISBN10},
+		{name: "invalid", isbn: "1234", err: ErrInvalidISBN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.isbn.ISBN10()
+			if !errors.Is(err, tt.err) {
+				t.Errorf("ISBN10() got error: %v\nexpected: %v", err, tt.err)
+			}
+			if got != tt.expected {
+				t.Errorf("ISBN10() got %q\nexpected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestISBNParts(t *testing.T) {
+	tests := []struct {
+		name       string
+		isbn       ISBN
+		group      string
+		publisher  string
+		hyphenated string
+		err        error
+	}{
+		{
+			name:       "english group 0",
+			isbn:       "9780062315007",
+			group:      "978-0",
+			publisher:  "978-0-06",
+			hyphenated: "978-0-06-231500-7",
+		},
+		{
+			name:       "english group 1",
+			isbn:       "9781402894626",
+			group:      "978-1",
+			publisher:  "978-1-4028",
+			hyphenated: "978-1-4028-9462-6",
+		},
+		{
+			name:  "group without registrant ranges",
+			isbn:  "9791090636071",
+			group: "979-10",
+			err:   ErrUnknownISBNRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group, err := tt.isbn.RegistrationGroup()
+			if err != nil || group != tt.group {
+				t.Errorf("RegistrationGroup() got %q, %v\nexpected %q", group, err, tt.group)
+			}
+
+			publisher, err := tt.isbn.PublisherPrefix()
+			if !errors.Is(err, tt.err) {
+				t.Errorf("PublisherPrefix() got error: %v\nexpected: %v", err, tt.err)
+			}
+			if publisher != tt.publisher {
+				t.Errorf("PublisherPrefix() got %q\nexpected %q", publisher, tt.publisher)
+			}
+
+			hyphenated, _ := tt.isbn.Hyphenate()
+			if hyphenated != tt.hyphenated {
+				t.Errorf("Hyphenate() got %q\nexpected %q", hyphenated, tt.hyphenated)
+			}
+		})
+	}
+}
+
+func TestISBNScanAndValue(t *testing.T) {
+	var isbn ISBN
+	if err := isbn.Scan([]byte("978-0-06-231500-7")); err != nil {
+		t.Fatalf("Scan() got error: %v", err)
+	}
+	if isbn != "9780062315007" {
+		t.Errorf("Scan() got %q\nexpected %q", isbn, "9780062315007")
+	}
+
+	value, err := isbn.Value()
+	if err != nil || value != "9780062315007" {
+		t.Errorf("Value() got %v, %v\nexpected %q", value, err, "9780062315007")
+	}
+
+	if err := isbn.Scan(nil); err != nil || isbn != "" {
+		t.Errorf("Scan(nil) got %q, %v\nexpected empty", isbn, err)
+	}
+	if err := isbn.Scan(1); err == nil {
+		t.Errorf("Scan(1) got no error")
+	}
+}
+
+func TestISBNJSON(t *testing.T) {
+	var book struct {
+		ISBN ISBN `json:"isbn"`
+	}
+	if err := json.Unmarshal([]byte(`{"isbn":"0-06-231500-5"}`), &book); err != nil {
+		t.Fatalf("Unmarshal() got error: %v", err)
+	}
+
+	encoded, err := json.Marshal(book)
+	if err != nil {
+		t.Fatalf("Marshal() got error: %v", err)
+	}
+	if expected := `{"isbn":"9780062315007"}`; string(encoded) != expected {
+		t.Errorf("Marshal() got %s\nexpected %s", encoded, expected)
+	}
+}
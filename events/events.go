@@ -0,0 +1,183 @@
+// Package events feeds domain events to subscribers as they are published. Feed keeps last events in a
+// bounded log, so that subscribers reconnecting after a disconnect resume from the last event they received.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	TypeBookCreated   = "book.created"
+	TypeBookUpdated   = "book.updated"
+	TypeBookDeleted   = "book.deleted"
+	TypeMemberCreated = "member.created"
+	TypeMemberUpdated = "member.updated"
+)
+
+// Types are all event types
+var Types = []string{
+	TypeBookCreated,
+	TypeBookUpdated,
+	TypeBookDeleted,
+	TypeMemberCreated,
+	TypeMemberUpdated,
+}
+
+// Event is a change of the domain, Data is JSON of the changed entity
+type Event struct {
+	ID   uint64          `json:"id"`
+	Type string          `json:"type"`
+	Time time.Time       `json:"time"`
+	Data json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// Deletion is data of deleted events, MergedInto is ID of the entity the deleted one was merged into
+type Deletion struct {
+	ID         uint `json:"id"`
+	MergedInto uint `json:"merged_into,omitempty"`
+}
+
+// Feed publishes events to subscribers and keeps last events in a log of limited size
+type Feed struct {
+	mu sync.Mutex
+	// log is ring buffer of kept events, oldest at start
+	log         []Event
+	start       int
+	next        uint64
+	subscribers map[*Subscription]struct{}
+	now         func() time.Time
+}
+
+// NewFeed returns feed keeping last size events.
+// Event IDs continue above IDs of previous runs, so that subscribers resuming from an event of a previous run
+// are reset instead of receiving events they did not miss.
+func NewFeed(size int) *Feed {
+	return &Feed{
+		log:         make([]Event, 0, size),
+		next:        uint64(time.Now().Unix()) * 1000000,
+		subscribers: make(map[*Subscription]struct{}),
+		now:         time.Now,
+	}
+}
+
+// Publish sends event of eventType with data encoded as JSON to subscribers, nil Feed publishes nothing.
+// It does not wait for subscribers, those not keeping up are dropped.
+func (f *Feed) Publish(eventType string, data interface{}) error {
+	if f == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	event := Event{ID: f.next, Type: eventType, Time: f.now().UTC(), Data: encoded}
+	f.next++
+	if len(f.log) < cap(f.log) {
+		f.log = append(f.log, event)
+	} else if len(f.log) > 0 {
+		f.log[f.start] = event
+		f.start = (f.start + 1) % len(f.log)
+	}
+
+	for s := range f.subscribers {
+		if !s.matches(eventType) {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+			s.overflowed = true
+			f.unsubscribe(s)
+		}
+	}
+	return nil
+}
+
+// Subscribe returns subscription to events of types, all types when there are none, buffering up to buffer
+// events not received yet. Subscription resuming after event lastID receives kept events published since
+// first, lastID 0 starts with events published from now on.
+func (f *Feed) Subscribe(types []string, lastID uint64, buffer int) *Subscription {
+	s := &Subscription{feed: f}
+	if len(types) > 0 {
+		s.types = make(map[string]bool, len(types))
+		for _, t := range types {
+			s.types[t] = true
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var replay []Event
+	if lastID != 0 {
+		oldest := f.next - uint64(len(f.log))
+		if lastID+1 < oldest || lastID >= f.next {
+			s.Reset = true
+		} else {
+			for i := range f.log {
+				event := f.log[(f.start+i)%len(f.log)]
+				if event.ID > lastID && s.matches(event.Type) {
+					replay = append(replay, event)
+				}
+			}
+		}
+	}
+
+	s.events = make(chan Event, buffer+len(replay))
+	for _, event := range replay {
+		s.events <- event
+	}
+	f.subscribers[s] = struct{}{}
+	return s
+}
+
+func (f *Feed) unsubscribe(s *Subscription) {
+	if _, ok := f.subscribers[s]; ok {
+		delete(f.subscribers, s)
+		close(s.events)
+	}
+}
+
+// Subscription receives events published to Feed
+type Subscription struct {
+	// Reset tells that events since the resumed event are no longer kept,
+	// the subscriber missed some and should reload the state it follows
+	Reset bool
+
+	feed       *Feed
+	types      map[string]bool
+	events     chan Event
+	overflowed bool
+}
+
+// Events returns channel of events, kept events the subscription resumed with come first.
+// It is closed by Close or when the subscriber fell behind by more events than its buffer, see Overflowed.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Overflowed tells that the subscription was dropped for not keeping up with published events
+func (s *Subscription) Overflowed() bool {
+	s.feed.mu.Lock()
+	defer s.feed.mu.Unlock()
+	return s.overflowed
+}
+
+// Close stops the subscription, closing it again does nothing
+func (s *Subscription) Close() {
+	s.feed.mu.Lock()
+	defer s.feed.mu.Unlock()
+	s.feed.unsubscribe(s)
+}
+
+func (s *Subscription) matches(eventType string) bool {
+	return s.types == nil || s.types[eventType]
+}
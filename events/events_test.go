@@ -0,0 +1,138 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+)
+
+// received returns events buffered by s without waiting
+func received(s *Subscription) []Event {
+	var got []Event
+	for {
+		select {
+		case event, ok := <-s.Events():
+			if !ok {
+				return got
+			}
+			got = append(got, event)
+		default:
+			return got
+		}
+	}
+}
+
+func ids(events []Event) []uint64 {
+	got := make([]uint64, 0, len(events))
+	for _, event := range events {
+		got = append(got, event.ID)
+	}
+	return got
+}
+
+func TestFeedPublish(t *testing.T) {
+	f := NewFeed(10)
+	all := f.Subscribe(nil, 0, 10)
+	members := f.Subscribe([]string{TypeMemberCreated, TypeMemberUpdated}, 0, 10)
+
+	first := f.next
+	_ = f.Publish(TypeBookCreated, map[string]uint{"id": 1})
+	_ = f.Publish(TypeMemberCreated, map[string]uint{"id": 2})
+
+	got := received(all)
+	if expected := []uint64{first, first + 1}; !reflect.DeepEqual(ids(got), expected) {
+		t.Errorf("Subscribe() of all types received %v\n expected %v", ids(got), expected)
+	}
+	if got[0].Type != TypeBookCreated || string(got[0].Data) != `{"id":1}` {
+		t.Errorf("Publish() sent %+v\n expected %s event with data %s", got[0], TypeBookCreated, `{"id":1}`)
+	}
+	if got := received(members); len(got) != 1 || got[0].Type != TypeMemberCreated {
+		t.Errorf("Subscribe() of member types received %+v\n expected one %s event", got, TypeMemberCreated)
+	}
+
+	all.Close()
+	all.Close()
+	if _, ok := <-all.Events(); ok {
+		t.Errorf("Events() of closed subscription is open")
+	}
+	if all.Overflowed() {
+		t.Errorf("Overflowed() of closed subscription is true")
+	}
+}
+
+func TestFeedSubscribeResume(t *testing.T) {
+	f := NewFeed(3)
+	first := f.next
+	for i := 0; i < 5; i++ {
+		_ = f.Publish(TypeBookUpdated, nil)
+	}
+	// log keeps first+2 to first+4
+
+	tests := []struct {
+		name     string
+		lastID   uint64
+		reset    bool
+		expected []uint64
+	}{
+		{name: "new subscriber", lastID: 0, expected: []uint64{}},
+		{name: "last kept event", lastID: first + 4, expected: []uint64{}},
+		{name: "kept events", lastID: first + 2, expected: []uint64{first + 3, first + 4}},
+		{name: "event before oldest kept event", lastID: first + 1, expected: []uint64{first + 2, first + 3, first + 4}},
+		{name: "evicted events", lastID: first, reset: true, expected: []uint64{}},
+		{name: "event of previous run", lastID: 7, reset: true, expected: []uint64{}},
+		{name: "unknown event", lastID: first + 5, reset: true, expected: []uint64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := f.Subscribe(nil, tt.lastID, 1)
+			defer s.Close()
+
+			if s.Reset != tt.reset {
+				t.Errorf("Subscribe() got Reset %v\n expected %v", s.Reset, tt.reset)
+			}
+			if got := ids(received(s)); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Subscribe() replayed %v\n expected %v", got, tt.expected)
+			}
+		})
+	}
+
+	filtered := f.Subscribe([]string{TypeBookCreated}, first+2, 1)
+	if got := received(filtered); len(got) != 0 {
+		t.Errorf("Subscribe() of other types replayed %+v", got)
+	}
+}
+
+func TestFeedSlowSubscriber(t *testing.T) {
+	f := NewFeed(10)
+	slow := f.Subscribe(nil, 0, 2)
+	fast := f.Subscribe(nil, 0, 10)
+
+	for i := 0; i < 3; i++ {
+		if err := f.Publish(TypeBookCreated, nil); err != nil {
+			t.Fatalf("Publish() got error %v", err)
+		}
+	}
+
+	if got := received(slow); len(got) != 2 {
+		t.Errorf("slow subscriber received %d events\n expected %d buffered before it was dropped", len(got), 2)
+	}
+	if _, ok := <-slow.Events(); ok || !slow.Overflowed() {
+		t.Errorf("slow subscriber was not dropped")
+	}
+	if got := received(fast); len(got) != 3 {
+		t.Errorf("fast subscriber received %d events\n expected %d", len(got), 3)
+	}
+	if fast.Overflowed() {
+		t.Errorf("fast subscriber was dropped")
+	}
+}
+
+func TestFeedPublishError(t *testing.T) {
+	var f *Feed
+	if err := f.Publish(TypeBookCreated, nil); err != nil {
+		t.Errorf("Publish() of nil feed got error %v", err)
+	}
+	if err := NewFeed(1).Publish(TypeBookCreated, func() {}); err == nil {
+		t.Errorf("Publish() of data not encodable as JSON got no error")
+	}
+}
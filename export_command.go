@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"book-management-system/usecases/pipelines"
+)
+
+// runExport streams books into file or stdout.
+// It returns exit code, non-zero when the export failed.
+func runExport(args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", "", "export format, csv, jsonl or marc (default from output file extension, else csv)")
+	search := flags.String("search", "", "export only books matching search keyword")
+	output := flags.String("o", "", "output file (default stdout)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s export [flags]\n", appName)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return flagsExitCode(err)
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	if *format == "" {
+		*format = exportFormatFromPath(*output)
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailure
+		}
+		defer file.Close()
+		w = file
+	}
+
+	useCase, shutdown := initUseCase()
+	defer shutdown()
+	err := useCase.Pipeline.BookExportPipeline.ExportBooks(
+		context.Background(),
+		w,
+		pipelines.ExportFormat(*format),
+		pipelines.BookExportFilter{Search: *search},
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed export books: %s\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// exportFormatFromPath returns export format matching file extension
+func exportFormatFromPath(path string) string {
+	switch ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), "."); ext {
+	case "mrc":
+		return string(pipelines.ExportFormatMARC)
+	case "":
+		return string(pipelines.ExportFormatCSV)
+	default:
+		return ext
+	}
+}
@@ -0,0 +1,150 @@
+// Package health checks dependencies and background workers of the service for liveness and readiness probes
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Statuses of checks and reports
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// checkTimeout bounds each check, so that a hanging dependency does not hang the probe
+const checkTimeout = 2 * time.Second
+
+// ErrShuttingDown is reported by readiness while the server drains connections
+var ErrShuttingDown = errors.New("shutting down")
+
+// Check returns error when the checked dependency or worker is unhealthy
+type Check func(ctx context.Context) error
+
+// Result is status of one check
+type Result struct {
+	Status  string  `json:"status"`
+	Latency float64 `json:"latency_ms"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// Report is status of all checks, it is up when every check is up
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Up reports whether every check is up
+func (r Report) Up() bool {
+	return r.Status == StatusUp
+}
+
+// Run runs checks concurrently and reports their results
+func Run(ctx context.Context, checks map[string]Check) Report {
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			result := run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(name, check)
+	}
+	wg.Wait()
+	return report
+}
+
+func run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := Result{
+		Status:  StatusUp,
+		Latency: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+var (
+	heartbeatsMu sync.Mutex
+	heartbeats   = make(map[*Heartbeat]bool)
+)
+
+// Heartbeat tells that a background worker loop is alive
+type Heartbeat struct {
+	name    string
+	timeout time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewHeartbeat registers worker name, Workers reports it dead when it does not beat within timeout.
+// Worker exiting on purpose must call Stop.
+func NewHeartbeat(name string, timeout time.Duration) *Heartbeat {
+	h := &Heartbeat{name: name, timeout: timeout, last: time.Now()}
+
+	heartbeatsMu.Lock()
+	defer heartbeatsMu.Unlock()
+	heartbeats[h] = true
+	return h
+}
+
+// Beat marks the worker alive
+func (h *Heartbeat) Beat() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.last = time.Now()
+}
+
+// Stop unregisters the worker
+func (h *Heartbeat) Stop() {
+	heartbeatsMu.Lock()
+	defer heartbeatsMu.Unlock()
+	delete(heartbeats, h)
+}
+
+func (h *Heartbeat) stale(now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return now.Sub(h.last) > h.timeout
+}
+
+// Workers checks that every registered background worker beats in time
+func Workers(context.Context) error {
+	heartbeatsMu.Lock()
+	defer heartbeatsMu.Unlock()
+
+	now := time.Now()
+	stale := make([]string, 0)
+	for h := range heartbeats {
+		if h.stale(now) {
+			stale = append(stale, h.name)
+		}
+	}
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		return fmt.Errorf("workers not responding: %s", strings.Join(stale, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	errDown := errors.New("connection refused")
+
+	tests := []struct {
+		name     string
+		checks   map[string]Check
+		expected map[string]Result
+		status   string
+	}{
+		{
+			name:     "no checks",
+			checks:   map[string]Check{},
+			expected: map[string]Result{},
+			status:   StatusUp,
+		},
+		{
+			name: "all up",
+			checks: map[string]Check{
+				"database": func(context.Context) error { return nil },
+				"search":   func(context.Context) error { return nil },
+			},
+			expected: map[string]Result{
+				"database": {Status: StatusUp},
+				"search":   {Status: StatusUp},
+			},
+			status: StatusUp,
+		},
+		{
+			name: "one down",
+			checks: map[string]Check{
+				"database": func(context.Context) error { return nil },
+				"search":   func(context.Context) error { return errDown },
+			},
+			expected: map[string]Result{
+				"database": {Status: StatusUp},
+				"search":   {Status: StatusDown, Error: errDown.Error()},
+			},
+			status: StatusDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Run(context.Background(), tt.checks)
+			if got.Status != tt.status || got.Up() != (tt.status == StatusUp) {
+				t.Errorf("Run() got status %q\n expected %q", got.Status, tt.status)
+			}
+			if len(got.Checks) != len(tt.expected) {
+				t.Fatalf("Run() got checks %+v\n expected %+v", got.Checks, tt.expected)
+			}
+			for name, expected := range tt.expected {
+				result := got.Checks[name]
+				if result.Status != expected.Status || result.Error != expected.Error || result.Latency < 0 {
+					t.Errorf("Run() got %s result %+v\n expected %+v", name, result, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	got := Run(context.Background(), map[string]Check{
+		"hanging": func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+	if got.Up() || got.Checks["hanging"].Latency < float64(checkTimeout.Milliseconds()) {
+		t.Errorf("Run() got %+v\n expected hanging check down after timeout", got)
+	}
+}
+
+func TestWorkers(t *testing.T) {
+	alive := NewHeartbeat("alive", time.Hour)
+	defer alive.Stop()
+	if err := Workers(context.Background()); err != nil {
+		t.Fatalf("Workers() got error %v", err)
+	}
+
+	stuck := NewHeartbeat("stuck", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	alive.Beat()
+	if err := Workers(context.Background()); err == nil || err.Error() != "workers not responding: stuck" {
+		t.Errorf("Workers() got error %v\n expected stuck worker", err)
+	}
+
+	stuck.Stop()
+	if err := Workers(context.Background()); err != nil {
+		t.Errorf("Workers() got error %v after stuck worker stopped", err)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"book-management-system/entities/models"
+	"book-management-system/usecases/pipelines"
+)
+
+// runImport imports books from file and prints per row report as JSON.
+// It returns exit code, non-zero when the file cannot be read or any row failed.
+func runImport(args []string) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := flags.String("format", "", "import format, csv, jsonl, marc or marcxml (default from file extension)")
+	columns := flags.String("columns", "", "column mapping of book field to source column, e.g. name=Title,isbn=ISBN")
+	dryRun := flags.Bool("dry-run", false, "only show how marc records would be imported")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s import [flags] FILE\n", appName)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return flagsExitCode(err)
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = importFormatFromPath(path)
+	}
+
+	columnMapping, err := pipelines.ParseColumnMapping(*columns)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+	defer file.Close()
+
+	useCase, shutdown := initUseCase()
+	defer shutdown()
+	var report *models.BookImportReport
+	switch importFormat := pipelines.ImportFormat(*format); importFormat {
+	case pipelines.ImportFormatMARC, pipelines.ImportFormatMARCXML:
+		records, err := pipelines.ReadCatalogRecords(file, importFormat)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed read import file: %s\n", err)
+			return exitFailure
+		}
+		report = useCase.Pipeline.BookImportPipeline.ImportCatalogRecords(context.Background(), records, *dryRun)
+	default:
+		records, err := pipelines.ReadBookRecords(file, importFormat, columnMapping)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed read import file: %s\n", err)
+			return exitFailure
+		}
+		report = useCase.Pipeline.BookImportPipeline.ImportBooks(context.Background(), records)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+	fmt.Fprintf(os.Stderr, "imported %d rows: %d created, %d merged, %d duplicates, %d errors\n",
+		report.Total, report.Created, report.Merged, report.Duplicates, report.Errors)
+
+	if report.Errors > 0 {
+		return exitFailure
+	}
+	return exitOK
+}
+
+// importFormatFromPath returns import format matching file extension
+func importFormatFromPath(path string) string {
+	switch ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), "."); ext {
+	case "mrc":
+		return string(pipelines.ImportFormatMARC)
+	case "xml":
+		return string(pipelines.ImportFormatMARCXML)
+	default:
+		return ext
+	}
+}
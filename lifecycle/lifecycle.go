@@ -0,0 +1,148 @@
+// Package lifecycle starts and stops components of the service in order and tracks background tasks,
+// so that shutdown drains requests and tasks before closing connections they use
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"book-management-system/logging"
+)
+
+// Component is part of the service with its own start and stop, nil Start or Stop means nothing to do
+type Component struct {
+	Name string
+	// Start returns once the component runs, long running work continues in background
+	Start func(ctx context.Context) error
+	// Stop returns once the component stopped or ctx deadline of graceful shutdown is reached
+	Stop func(ctx context.Context) error
+}
+
+// Manager runs components until interrupted
+type Manager struct {
+	components []Component
+	failed     chan error
+}
+
+// NewManager returns Manager without components
+func NewManager() *Manager {
+	return &Manager{failed: make(chan error, 1)}
+}
+
+// Add appends components, they are started in order of adding and stopped in reverse order
+func (m *Manager) Add(components ...Component) {
+	m.components = append(m.components, components...)
+}
+
+// Fail shuts the service down because of err of a running component, e.g. HTTP server failing to serve
+func (m *Manager) Fail(err error) {
+	select {
+	case m.failed <- err:
+	default:
+		// shutdown is under way already
+	}
+}
+
+// Run starts components and waits for SIGINT, SIGTERM, Fail or ctx being done, then stops started components
+// within timeout. Second signal exits at once. It returns error of failed start or Fail, else of failed stop.
+func (m *Manager) Run(ctx context.Context, timeout time.Duration) error {
+	logger := logging.Logger(logging.PackageLifecycle)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	started := 0
+	var err error
+	for _, c := range m.components {
+		if c.Start != nil {
+			if err = c.Start(ctx); err != nil {
+				err = fmt.Errorf("failed to start %s: %w", c.Name, err)
+				break
+			}
+		}
+		started++
+	}
+
+	if err == nil {
+		logger.Info("started", zap.Int("components", started))
+		select {
+		case sig := <-signals:
+			logger.Info("shutting down on signal", zap.Stringer("signal", sig))
+		case <-ctx.Done():
+			logger.Info("shutting down")
+		case err = <-m.failed:
+		}
+	}
+	if err != nil {
+		logger.Error("shutting down on failure", zap.Error(err))
+	}
+
+	go func() {
+		sig := <-signals
+		logger.Error("forced shutdown on second signal", zap.Stringer("signal", sig))
+		os.Exit(1)
+	}()
+
+	stopErr := m.stop(logger, started, timeout)
+	if err == nil {
+		err = stopErr
+	}
+	return err
+}
+
+// stop stops first started components in reverse order, all share one deadline
+func (m *Manager) stop(logger *zap.Logger, started int, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var firstErr error
+	for i := started - 1; i >= 0; i-- {
+		c := m.components[i]
+		if c.Stop == nil {
+			continue
+		}
+
+		start := time.Now()
+		if err := c.Stop(ctx); err != nil {
+			logger.Error("failed to stop", zap.String("component", c.Name), zap.Error(err))
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to stop %s: %w", c.Name, err)
+			}
+			continue
+		}
+		logger.Info("stopped", zap.String("component", c.Name), zap.Duration("duration", time.Since(start)))
+	}
+	return firstErr
+}
+
+// Worker returns component running fn in background, Stop cancels ctx of fn and waits for fn to return
+func Worker(name string, fn func(ctx context.Context)) Component {
+	var cancel context.CancelFunc
+	done := make(chan struct{})
+	return Component{
+		Name: name,
+		Start: func(context.Context) error {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			go func() {
+				defer close(done)
+				fn(ctx)
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
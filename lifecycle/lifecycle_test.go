@@ -0,0 +1,190 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errComponent = errors.New("component error")
+
+// recorder records starts and stops of components
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) component(name string, startErr, stopErr error) Component {
+	return Component{
+		Name: name,
+		Start: func(context.Context) error {
+			r.record("start " + name)
+			return startErr
+		},
+		Stop: func(context.Context) error {
+			r.record("stop " + name)
+			return stopErr
+		},
+	}
+}
+
+func (r *recorder) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func TestManagerRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func(*recorder, *Manager)
+		fail     error
+		expected []string
+		err      error
+	}{
+		{
+			name: "stop in reverse order",
+			build: func(r *recorder, m *Manager) {
+				m.Add(
+					r.component("database", nil, nil),
+					Component{Name: "tasks"},
+					r.component("http", nil, nil),
+				)
+			},
+			expected: []string{"start database", "start http", "stop http", "stop database"},
+		},
+		{
+			name: "failed start stops started components",
+			build: func(r *recorder, m *Manager) {
+				m.Add(
+					r.component("database", nil, nil),
+					r.component("http", errComponent, nil),
+					r.component("grpc", nil, nil),
+				)
+			},
+			expected: []string{"start database", "start http", "stop database"},
+			err:      errComponent,
+		},
+		{
+			name: "failed stop does not stop the rest",
+			build: func(r *recorder, m *Manager) {
+				m.Add(
+					r.component("database", nil, nil),
+					r.component("http", nil, errComponent),
+				)
+			},
+			expected: []string{"start database", "start http", "stop http", "stop database"},
+			err:      errComponent,
+		},
+		{
+			name: "component failure",
+			build: func(r *recorder, m *Manager) {
+				m.Add(r.component("http", nil, nil))
+			},
+			fail:     errComponent,
+			expected: []string{"start http", "stop http"},
+			err:      errComponent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			m := NewManager()
+			tt.build(r, m)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.fail != nil {
+				m.Fail(tt.fail)
+			} else {
+				cancel()
+			}
+			err := m.Run(ctx, time.Second)
+			cancel()
+
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Run() got error %v\n expected %v", err, tt.err)
+			}
+			if !reflect.DeepEqual(r.events, tt.expected) {
+				t.Errorf("Run() got events %v\n expected %v", r.events, tt.expected)
+			}
+		})
+	}
+}
+
+func TestManagerStopTimeout(t *testing.T) {
+	m := NewManager()
+	m.Add(Component{Name: "stuck", Stop: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.Run(ctx, 10*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run() got error %v\n expected %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestWorker(t *testing.T) {
+	stopped := false
+	worker := Worker("purge", func(ctx context.Context) {
+		<-ctx.Done()
+		stopped = true
+	})
+
+	if err := worker.Start(context.Background()); err != nil {
+		t.Fatalf("Start() got error %v", err)
+	}
+	if err := worker.Stop(context.Background()); err != nil || !stopped {
+		t.Errorf("Stop() got error %v, worker stopped %v\n expected worker stopped", err, stopped)
+	}
+}
+
+func TestTaskGroupWait(t *testing.T) {
+	g := newTaskGroup()
+	if err := g.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() without tasks got error %v", err)
+	}
+
+	finished := make(chan struct{})
+	g.Go(context.Background(), func(context.Context) {
+		time.Sleep(10 * time.Millisecond)
+		close(finished)
+	})
+	if err := g.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() got error %v", err)
+	}
+	select {
+	case <-finished:
+	default:
+		t.Error("Wait() returned before task finished")
+	}
+}
+
+func TestTaskGroupWaitTimeout(t *testing.T) {
+	g := newTaskGroup()
+	canceled := make(chan error, 1)
+	g.Go(context.Background(), func(ctx context.Context) {
+		<-ctx.Done()
+		canceled <- ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := g.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() got error %v\n expected %v", err, context.DeadlineExceeded)
+	}
+
+	select {
+	case err := <-canceled:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("task got context error %v\n expected %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Error("task was not canceled after Wait gave up")
+	}
+}
@@ -0,0 +1,91 @@
+package lifecycle
+
+import (
+	"context"
+	"sync"
+)
+
+// tasks tracks background goroutines of requests, e.g. search indexing of stored book
+var tasks = newTaskGroup()
+
+// Go runs fn in background, Wait waits for it. ctx of fn is canceled when Wait gives up.
+func Go(ctx context.Context, fn func(ctx context.Context)) {
+	tasks.Go(ctx, fn)
+}
+
+// Wait waits for background tasks until ctx is done, then cancels the remaining ones and returns ctx error
+func Wait(ctx context.Context) error {
+	return tasks.Wait(ctx)
+}
+
+// Tasks is component waiting for background tasks on stop, it should stop after components starting tasks
+func Tasks() Component {
+	return Component{Name: "background tasks", Stop: Wait}
+}
+
+type taskGroup struct {
+	mu      sync.Mutex
+	running int
+	// idle is closed when running drops to zero
+	idle chan struct{}
+	// abort is closed when Wait gives up
+	abort chan struct{}
+}
+
+func newTaskGroup() *taskGroup {
+	idle := make(chan struct{})
+	close(idle)
+	return &taskGroup{idle: idle, abort: make(chan struct{})}
+}
+
+func (g *taskGroup) Go(ctx context.Context, fn func(ctx context.Context)) {
+	g.mu.Lock()
+	if g.running == 0 {
+		g.idle = make(chan struct{})
+	}
+	g.running++
+	abort := g.abort
+	g.mu.Unlock()
+
+	go func() {
+		defer g.done()
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			select {
+			case <-abort:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		fn(ctx)
+	}()
+}
+
+func (g *taskGroup) done() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.running--
+	if g.running == 0 {
+		close(g.idle)
+	}
+}
+
+func (g *taskGroup) Wait(ctx context.Context) error {
+	g.mu.Lock()
+	idle := g.idle
+	g.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		g.mu.Lock()
+		close(g.abort)
+		g.abort = make(chan struct{})
+		g.mu.Unlock()
+		return ctx.Err()
+	}
+}
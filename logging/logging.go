@@ -0,0 +1,189 @@
+// Package logging provides structured loggers of the service, each package logs through its own named
+// logger whose level is configured by log.packages and reloaded with config
+package logging
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"book-management-system/configs"
+)
+
+// Package loggers
+const (
+	PackageRest         = "rest"
+	PackageServices     = "services"
+	PackagePipelines    = "pipelines"
+	PackageRepositories = "repositories"
+	PackageGorm         = "gorm"
+	PackageAccess       = "access"
+	PackageLifecycle    = "lifecycle"
+	PackageEventBus     = "eventbus"
+	// PackageStd logs lines of standard library log
+	PackageStd = "std"
+)
+
+// RequestIDHeader carries request ID of HTTP requests and responses
+const RequestIDHeader = "X-Request-ID"
+
+var (
+	root *registry
+	once sync.Once
+)
+
+// Init builds loggers of current config and redirects standard library log to them.
+// Loggers are built on first use when Init is not called.
+func Init() {
+	once.Do(func() {
+		cfg := configs.GetConfig()
+		format := cfg.Log.Format
+		if format == "" {
+			format = configs.LogFormatText
+			if cfg.Production {
+				format = configs.LogFormatJSON
+			}
+		}
+
+		root = newRegistry(cfg.Log, format, zapcore.Lock(os.Stdout))
+		configs.OnChange(func(old, new configs.Configs) {
+			if old.Log.Level != new.Log.Level || !reflect.DeepEqual(old.Log.Packages, new.Log.Packages) {
+				root.setLevels(new.Log)
+			}
+		})
+		zap.RedirectStdLog(root.logger(PackageStd))
+	})
+}
+
+// Logger returns logger of package pkg
+func Logger(pkg string) *zap.Logger {
+	Init()
+	return root.logger(pkg)
+}
+
+// FromContext returns logger of package pkg with request ID and trace of ctx
+func FromContext(ctx context.Context, pkg string) *zap.Logger {
+	logger := Logger(pkg)
+	fields := make([]zap.Field, 0, 3)
+	if id := RequestID(ctx); id != "" {
+		fields = append(fields, zap.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		fields = append(fields,
+			zap.String("trace_id", span.TraceID().String()),
+			zap.String("span_id", span.SpanID().String()))
+	}
+	return logger.With(fields...)
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns ctx carrying request ID id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns request ID of ctx, empty when there is none
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// registry builds package loggers sharing one output, level of each package is adjustable
+type registry struct {
+	mu       sync.Mutex
+	core     zapcore.Core
+	sampling configs.LogSamplingConfig
+	levels   map[string]zap.AtomicLevel
+	loggers  map[string]*zap.Logger
+	cfg      configs.LogConfig
+}
+
+func newRegistry(cfg configs.LogConfig, format string, out zapcore.WriteSyncer) *registry {
+	var encoder zapcore.Encoder
+	if format == configs.LogFormatJSON {
+		encoderConfig := zap.NewProductionEncoderConfig()
+		encoderConfig.TimeKey = "time"
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	} else {
+		encoderConfig := zap.NewDevelopmentEncoderConfig()
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	}
+
+	return &registry{
+		// levels are checked by levelCore of each package
+		core:     zapcore.NewCore(encoder, out, zapcore.DebugLevel),
+		sampling: cfg.Sampling,
+		levels:   make(map[string]zap.AtomicLevel),
+		loggers:  make(map[string]*zap.Logger),
+		cfg:      cfg,
+	}
+}
+
+func (r *registry) logger(pkg string) *zap.Logger {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if logger, ok := r.loggers[pkg]; ok {
+		return logger
+	}
+
+	level := zap.NewAtomicLevelAt(parseLevel(r.cfg.LevelOf(pkg)))
+	core := r.core
+	// access log is the only high volume one, sampling others could drop errors worth seeing
+	if pkg == PackageAccess && r.sampling.Initial > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, r.sampling.Initial, r.sampling.Thereafter)
+	}
+	logger := zap.New(&levelCore{Core: core, level: level}, zap.AddCaller()).Named(pkg)
+
+	r.levels[pkg] = level
+	r.loggers[pkg] = logger
+	return logger
+}
+
+// setLevels applies package levels of cfg to loggers built already and to be built
+func (r *registry) setLevels(cfg configs.LogConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cfg.Level = cfg.Level
+	r.cfg.Packages = cfg.Packages
+	for pkg, level := range r.levels {
+		level.SetLevel(parseLevel(cfg.LevelOf(pkg)))
+	}
+}
+
+// parseLevel returns zap level of validated config level, info when empty
+func parseLevel(level string) zapcore.Level {
+	l := zapcore.InfoLevel
+	_ = l.UnmarshalText([]byte(level))
+	return l
+}
+
+// levelCore filters entries of core by adjustable level
+type levelCore struct {
+	zapcore.Core
+	level zap.AtomicLevel
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
@@ -0,0 +1,125 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"book-management-system/configs"
+)
+
+// lines decodes JSON log lines of buf
+func lines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	result := make([]map[string]interface{}, 0)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", line, err)
+		}
+		result = append(result, fields)
+	}
+	buf.Reset()
+	return result
+}
+
+func TestRegistryLevels(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := newRegistry(configs.LogConfig{
+		Level:    configs.LogLevelWarn,
+		Packages: map[string]string{PackageGorm: configs.LogLevelDebug},
+	}, configs.LogFormatJSON, zapcore.AddSync(buf))
+
+	r.logger(PackageRest).Info("hidden")
+	r.logger(PackageRest).Warn("shown", zap.Uint("book_id", 7))
+	r.logger(PackageGorm).Debug("query")
+
+	got := lines(t, buf)
+	if len(got) != 2 {
+		t.Fatalf("got %d log lines %v\n expected 2", len(got), got)
+	}
+	if got[0]["logger"] != PackageRest || got[0]["msg"] != "shown" || got[0]["level"] != "warn" ||
+		got[0]["book_id"] != float64(7) || got[0]["time"] == nil {
+		t.Errorf("got log line %v\n expected warn of rest logger with book_id", got[0])
+	}
+	if got[1]["logger"] != PackageGorm || got[1]["msg"] != "query" {
+		t.Errorf("got log line %v\n expected debug of gorm logger", got[1])
+	}
+
+	r.setLevels(configs.LogConfig{
+		Level:    configs.LogLevelInfo,
+		Packages: map[string]string{PackageGorm: configs.LogLevelError},
+	})
+	r.logger(PackageRest).Info("shown")
+	r.logger(PackageGorm).Warn("hidden")
+	r.logger(PackageServices).Info("shown")
+
+	if got := lines(t, buf); len(got) != 2 || got[0]["logger"] != PackageRest || got[1]["logger"] != PackageServices {
+		t.Errorf("got log lines %v after level change\n expected info of rest and services", got)
+	}
+}
+
+func TestRegistryAccessSampling(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := newRegistry(configs.LogConfig{
+		Sampling: configs.LogSamplingConfig{Initial: 2, Thereafter: 5},
+	}, configs.LogFormatJSON, zapcore.AddSync(buf))
+
+	for i := 0; i < 12; i++ {
+		r.logger(PackageAccess).Info("request")
+		r.logger(PackageRest).Info("request")
+	}
+
+	access, rest := 0, 0
+	for _, line := range lines(t, buf) {
+		switch line["logger"] {
+		case PackageAccess:
+			access++
+		case PackageRest:
+			rest++
+		}
+	}
+	// first 2, then 7th and 12th
+	if access != 4 || rest != 12 {
+		t.Errorf("got %d access and %d rest lines\n expected 4 sampled access and 12 rest lines", access, rest)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	once.Do(func() {})
+	root = newRegistry(configs.LogConfig{}, configs.LogFormatJSON, zapcore.AddSync(buf))
+
+	span := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx := trace.ContextWithSpanContext(WithRequestID(context.Background(), "req-1"), span)
+	if got := RequestID(ctx); got != "req-1" {
+		t.Errorf("RequestID() got %q\n expected %q", got, "req-1")
+	}
+
+	FromContext(ctx, PackageServices).Info("indexed")
+	FromContext(context.Background(), PackageServices).Info("plain")
+
+	got := lines(t, buf)
+	if len(got) != 2 {
+		t.Fatalf("got %d log lines %v\n expected 2", len(got), got)
+	}
+	if got[0]["request_id"] != "req-1" || got[0]["trace_id"] != span.TraceID().String() ||
+		got[0]["span_id"] != span.SpanID().String() {
+		t.Errorf("got log line %v\n expected request_id, trace_id and span_id", got[0])
+	}
+	if _, ok := got[1]["request_id"]; ok {
+		t.Errorf("got log line %v\n expected no request_id", got[1])
+	}
+}
@@ -1,13 +1,9 @@
 package main
 
 import (
-	"book-management-system/configs"
-	"book-management-system/controllers"
-	"book-management-system/repositories"
-	"book-management-system/usecases"
+	"os"
 )
 
 func main() {
-	configs.GetConfig()
-	controllers.Init(usecases.Init(repositories.Init()))
+	os.Exit(run(os.Args[1:]))
 }
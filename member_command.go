@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"book-management-system/entities/models"
+)
+
+// runMember lists, adds and renames library members.
+// It returns exit code, non-zero when the command failed.
+func runMember(args []string) int {
+	flags := flag.NewFlagSet("member", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s member list | add NAME | rename ID NAME\n", appName)
+		flags.PrintDefaults()
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if err := flags.Parse(args); err != nil {
+			return flagsExitCode(err)
+		}
+		flags.Usage()
+		return exitUsage
+	}
+	command := args[0]
+	if err := flags.Parse(args[1:]); err != nil {
+		return flagsExitCode(err)
+	}
+
+	expectedArgs := map[string]int{"list": 0, "add": 1, "rename": 2}
+	if n, ok := expectedArgs[command]; !ok || flags.NArg() != n {
+		flags.Usage()
+		return exitUsage
+	}
+
+	var id uint64
+	if command == "rename" {
+		var err error
+		if id, err = strconv.ParseUint(flags.Arg(0), 10, 64); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid member ID %q\n", flags.Arg(0))
+			return exitUsage
+		}
+	}
+
+	useCase, shutdown := initUseCase()
+	defer shutdown()
+	memberService := useCase.Service.MemberService
+	ctx := context.Background()
+
+	switch command {
+	case "list":
+		members, err := memberService.GetMembers(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed get members: %s\n", err)
+			return exitFailure
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME")
+		for _, member := range members {
+			fmt.Fprintf(w, "%d\t%s\n", member.ID, member.Name)
+		}
+		w.Flush()
+	case "add":
+		member := &models.Member{Name: flags.Arg(0)}
+		if err := memberService.CreateMember(ctx, member); err != nil {
+			fmt.Fprintf(os.Stderr, "failed add member: %s\n", err)
+			return exitFailure
+		}
+		fmt.Printf("added member %d\n", member.ID)
+	case "rename":
+		member, err := memberService.GetMember(ctx, uint(id))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed get member %d: %s\n", id, err)
+			return exitFailure
+		}
+		member.Name = flags.Arg(1)
+		if err := memberService.UpdateMember(ctx, member); err != nil {
+			fmt.Fprintf(os.Stderr, "failed rename member %d: %s\n", id, err)
+			return exitFailure
+		}
+		fmt.Printf("renamed member %d\n", id)
+	}
+	return exitOK
+}
@@ -0,0 +1,208 @@
+// Package metrics contains Prometheus metrics of the service and handler serving them
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes names of all metrics
+const namespace = "bms"
+
+// Background tasks
+const (
+	TaskSearchIndex        = "search_index"
+	TaskBookImport         = "book_import"
+	TaskDuplicateDetection = "duplicate_detection"
+)
+
+// Book sources counted by BooksCreated
+const (
+	SourceAPI    = "api"
+	SourceImport = "import"
+)
+
+var registry = prometheus.NewRegistry()
+
+// HTTP metrics, route is mux path template so that IDs do not create new series
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "code"})
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of HTTP requests by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "code"})
+	HTTPRateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "rate_limited_total",
+		Help:      "Number of HTTP requests rejected by rate limit by route class, search, read or write.",
+	}, []string{"class"})
+)
+
+// Database and search backend metrics
+var (
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of SQL queries by operation, create, query, update, delete, row or raw, and status.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"operation", "status"})
+	ElasticSearchRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "elasticsearch",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of ElasticSearch requests by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+	ElasticSearchRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "elasticsearch",
+		Name:      "request_errors_total",
+		Help:      "Number of ElasticSearch requests failed by connection error or server error status.",
+	}, []string{"method"})
+)
+
+// Background task metrics
+var (
+	BackgroundTasksRunning = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "background",
+		Name:      "tasks_running",
+		Help:      "Number of running background tasks like search indexing, import jobs and duplicate detection.",
+	}, []string{"task"})
+	BackgroundTaskFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "background",
+		Name:      "task_failures_total",
+		Help:      "Number of failed background tasks.",
+	}, []string{"task"})
+)
+
+// Change feed metrics
+var (
+	EventSubscribers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "events",
+		Name:      "subscribers",
+		Help:      "Number of change feed subscribers by transport, sse or websocket.",
+	}, []string{"transport"})
+	EventSubscribersDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "events",
+		Name:      "subscribers_dropped_total",
+		Help:      "Number of change feed subscribers disconnected for falling behind, by transport.",
+	}, []string{"transport"})
+)
+
+// Event bus metrics
+var (
+	EventHandlerFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "eventbus",
+		Name:      "handler_failures_total",
+		Help:      "Number of domain events subscribers failed to handle, by subscriber.",
+	}, []string{"subscriber"})
+)
+
+// Webhook metrics
+var (
+	WebhookDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhooks",
+		Name:      "deliveries_total",
+		Help:      "Number of webhook delivery attempts by result, succeeded, failed or dead.",
+	}, []string{"result"})
+	WebhookEventsMissed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhooks",
+		Name:      "events_missed_total",
+		Help:      "Number of times the webhook dispatcher fell behind the change feed and missed events.",
+	})
+)
+
+// Domain metrics
+var (
+	BooksCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "books_created_total",
+		Help:      "Number of created books by source, api or import.",
+	}, []string{"source"})
+	BooksUpdated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "books_updated_total",
+		Help:      "Number of updated books.",
+	})
+	BooksMerged = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "books_merged_total",
+		Help:      "Number of duplicate books merged into another book.",
+	})
+	MembersCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "members_created_total",
+		Help:      "Number of created members.",
+	})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		HTTPRateLimited,
+		DBQueryDuration,
+		ElasticSearchRequestDuration,
+		ElasticSearchRequestErrors,
+		BackgroundTasksRunning,
+		BackgroundTaskFailures,
+		EventSubscribers,
+		EventSubscribersDropped,
+		EventHandlerFailures,
+		WebhookDeliveries,
+		WebhookEventsMissed,
+		BooksCreated,
+		BooksUpdated,
+		BooksMerged,
+		MembersCreated,
+	)
+}
+
+// Handler serves metrics in Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDB exports connection pool stats of db, registering the same database again has no effect
+func RegisterDB(db *sql.DB, name string) error {
+	err := registry.Register(collectors.NewDBStatsCollector(db, name))
+	if are := (prometheus.AlreadyRegisteredError{}); errors.As(err, &are) {
+		return nil
+	}
+	return err
+}
+
+// StartTask counts background task as running until returned done is called with its result
+func StartTask(task string) (done func(err error)) {
+	BackgroundTasksRunning.WithLabelValues(task).Inc()
+	return func(err error) {
+		BackgroundTasksRunning.WithLabelValues(task).Dec()
+		if err != nil {
+			BackgroundTaskFailures.WithLabelValues(task).Inc()
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestStartTask(t *testing.T) {
+	done := StartTask(TaskBookImport)
+	if got := testutil.ToFloat64(BackgroundTasksRunning.WithLabelValues(TaskBookImport)); got != 1 {
+		t.Errorf("StartTask() got %v running tasks\n expected 1", got)
+	}
+
+	done(errors.New("failed"))
+	if got := testutil.ToFloat64(BackgroundTasksRunning.WithLabelValues(TaskBookImport)); got != 0 {
+		t.Errorf("done() got %v running tasks\n expected 0", got)
+	}
+	if got := testutil.ToFloat64(BackgroundTaskFailures.WithLabelValues(TaskBookImport)); got != 1 {
+		t.Errorf("done() got %v failures\n expected 1", got)
+	}
+}
+
+func TestRegisterDB(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for i := 0; i < 2; i++ {
+		if err := RegisterDB(db, "test"); err != nil {
+			t.Fatalf("RegisterDB() call %d got error %v", i+1, err)
+		}
+	}
+	other, _, _ := sqlmock.New()
+	defer other.Close()
+	if err := RegisterDB(other, "other"); err != nil {
+		t.Fatalf("RegisterDB() of other database got error %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, expected := range []string{
+		`go_sql_open_connections{db_name="test"}`,
+		`go_sql_open_connections{db_name="other"}`,
+		"bms_books_updated_total 0",
+		"go_goroutines",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Handler() got body without %q", expected)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"book-management-system/repositories"
+	"book-management-system/repositories/migrations"
+)
+
+// runMigrate applies, rolls back, lists or creates database migrations.
+// It returns exit code, non-zero when the command failed.
+func runMigrate(args []string) int {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	steps := flags.Int("steps", 1, "number of migrations down rolls back")
+	dir := flags.String("dir", migrations.SourceDir, "migrations directory create writes to")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s migrate up|down|status|create NAME [flags]\n", appName)
+		flags.PrintDefaults()
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if err := flags.Parse(args); err != nil {
+			return flagsExitCode(err)
+		}
+		flags.Usage()
+		return exitUsage
+	}
+	command := args[0]
+	if err := flags.Parse(args[1:]); err != nil {
+		return flagsExitCode(err)
+	}
+
+	if command == "create" {
+		if flags.NArg() != 1 {
+			flags.Usage()
+			return exitUsage
+		}
+		files, err := migrations.Create(*dir, flags.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed create migration: %s\n", err)
+			return exitFailure
+		}
+		for _, file := range files {
+			fmt.Printf("created %s\n", file)
+		}
+		return exitOK
+	}
+	if flags.NArg() != 0 || (command != "up" && command != "down" && command != "status") {
+		flags.Usage()
+		return exitUsage
+	}
+
+	db := repositories.InitDatabase()
+	defer func() {
+		if err := repositories.CloseDatabase(db); err != nil {
+			fmt.Fprintf(os.Stderr, "failed close database: %s\n", err)
+		}
+	}()
+	migrator := migrations.NewMigrator(db, migrations.Embedded())
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %s\n", migration)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed migrate up: %s\n", err)
+			return exitFailure
+		}
+		if len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		if migrations.NeedsReindex(applied) {
+			useCase, shutdown := initUseCase()
+			defer shutdown()
+			count, err := useCase.Pipeline.BookIndexPipeline.ReindexBooks(ctx)
+			fmt.Printf("indexed %d books\n", count)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed reindex books, run reindex command: %s\n", err)
+				return exitFailure
+			}
+		}
+	case "down":
+		rolledBack, err := migrator.Down(ctx, *steps)
+		for _, migration := range rolledBack {
+			fmt.Printf("rolled back %s\n", migration)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed migrate down: %s\n", err)
+			return exitFailure
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed get migration status: %s\n", err)
+			return exitFailure
+		}
+		printMigrationStatuses(statuses)
+	}
+	return exitOK
+}
+
+func printMigrationStatuses(statuses []migrations.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MIGRATION\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		if status.Applied {
+			state, appliedAt = "applied", status.AppliedAt.Format(time.RFC3339)
+		}
+		switch {
+		case status.Missing:
+			state = "applied, file missing"
+		case status.Modified:
+			state = "applied, file modified"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", status.Migration, state, appliedAt)
+	}
+	w.Flush()
+}
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./usecases/pipelines/book_duplicate_pipeline.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "book-management-system/entities/models"
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockBookDuplicatePipeline is a mock of BookDuplicatePipeline interface
+type MockBookDuplicatePipeline struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookDuplicatePipelineMockRecorder
+}
+
+// MockBookDuplicatePipelineMockRecorder is the mock recorder for MockBookDuplicatePipeline
+type MockBookDuplicatePipelineMockRecorder struct {
+	mock *MockBookDuplicatePipeline
+}
+
+// NewMockBookDuplicatePipeline creates a new mock instance
+func NewMockBookDuplicatePipeline(ctrl *gomock.Controller) *MockBookDuplicatePipeline {
+	mock := &MockBookDuplicatePipeline{ctrl: ctrl}
+	mock.recorder = &MockBookDuplicatePipelineMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBookDuplicatePipeline) EXPECT() *MockBookDuplicatePipelineMockRecorder {
+	return m.recorder
+}
+
+// DetectDuplicates mocks base method
+func (m *MockBookDuplicatePipeline) DetectDuplicates(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectDuplicates", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectDuplicates indicates an expected call of DetectDuplicates
+func (mr *MockBookDuplicatePipelineMockRecorder) DetectDuplicates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectDuplicates", reflect.TypeOf((*MockBookDuplicatePipeline)(nil).DetectDuplicates), arg0)
+}
+
+// StartDetection mocks base method
+func (m *MockBookDuplicatePipeline) StartDetection(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartDetection", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartDetection indicates an expected call of StartDetection
+func (mr *MockBookDuplicatePipelineMockRecorder) StartDetection(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartDetection", reflect.TypeOf((*MockBookDuplicatePipeline)(nil).StartDetection), arg0)
+}
+
+// GetDuplicates mocks base method
+func (m *MockBookDuplicatePipeline) GetDuplicates(arg0 context.Context, arg1 models.BookDuplicateStatus) ([]models.BookDuplicate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDuplicates", arg0, arg1)
+	ret0, _ := ret[0].([]models.BookDuplicate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDuplicates indicates an expected call of GetDuplicates
+func (mr *MockBookDuplicatePipelineMockRecorder) GetDuplicates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDuplicates", reflect.TypeOf((*MockBookDuplicatePipeline)(nil).GetDuplicates), arg0, arg1)
+}
+
+// MergeDuplicate mocks base method
+func (m *MockBookDuplicatePipeline) MergeDuplicate(arg0 context.Context, arg1, arg2 uint) (*models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeDuplicate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeDuplicate indicates an expected call of MergeDuplicate
+func (mr *MockBookDuplicatePipelineMockRecorder) MergeDuplicate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeDuplicate", reflect.TypeOf((*MockBookDuplicatePipeline)(nil).MergeDuplicate), arg0, arg1, arg2)
+}
+
+// DismissDuplicate mocks base method
+func (m *MockBookDuplicatePipeline) DismissDuplicate(arg0 context.Context, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DismissDuplicate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DismissDuplicate indicates an expected call of DismissDuplicate
+func (mr *MockBookDuplicatePipelineMockRecorder) DismissDuplicate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DismissDuplicate", reflect.TypeOf((*MockBookDuplicatePipeline)(nil).DismissDuplicate), arg0, arg1)
+}
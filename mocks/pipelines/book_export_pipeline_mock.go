@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./usecases/pipelines/book_export_pipeline.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	pipelines "book-management-system/usecases/pipelines"
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	io "io"
+	reflect "reflect"
+)
+
+// MockBookExportPipeline is a mock of BookExportPipeline interface
+type MockBookExportPipeline struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookExportPipelineMockRecorder
+}
+
+// MockBookExportPipelineMockRecorder is the mock recorder for MockBookExportPipeline
+type MockBookExportPipelineMockRecorder struct {
+	mock *MockBookExportPipeline
+}
+
+// NewMockBookExportPipeline creates a new mock instance
+func NewMockBookExportPipeline(ctrl *gomock.Controller) *MockBookExportPipeline {
+	mock := &MockBookExportPipeline{ctrl: ctrl}
+	mock.recorder = &MockBookExportPipelineMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBookExportPipeline) EXPECT() *MockBookExportPipelineMockRecorder {
+	return m.recorder
+}
+
+// ExportBooks mocks base method
+func (m *MockBookExportPipeline) ExportBooks(arg0 context.Context, arg1 io.Writer, arg2 pipelines.ExportFormat, arg3 pipelines.BookExportFilter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBooks", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportBooks indicates an expected call of ExportBooks
+func (mr *MockBookExportPipelineMockRecorder) ExportBooks(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBooks", reflect.TypeOf((*MockBookExportPipeline)(nil).ExportBooks), arg0, arg1, arg2, arg3)
+}
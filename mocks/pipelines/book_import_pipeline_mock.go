@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./usecases/pipelines/book_import_pipeline.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "book-management-system/entities/models"
+	pipelines "book-management-system/usecases/pipelines"
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockBookImportPipeline is a mock of BookImportPipeline interface
+type MockBookImportPipeline struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookImportPipelineMockRecorder
+}
+
+// MockBookImportPipelineMockRecorder is the mock recorder for MockBookImportPipeline
+type MockBookImportPipelineMockRecorder struct {
+	mock *MockBookImportPipeline
+}
+
+// NewMockBookImportPipeline creates a new mock instance
+func NewMockBookImportPipeline(ctrl *gomock.Controller) *MockBookImportPipeline {
+	mock := &MockBookImportPipeline{ctrl: ctrl}
+	mock.recorder = &MockBookImportPipelineMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBookImportPipeline) EXPECT() *MockBookImportPipelineMockRecorder {
+	return m.recorder
+}
+
+// ImportBooks mocks base method
+func (m *MockBookImportPipeline) ImportBooks(arg0 context.Context, arg1 []pipelines.BookImportRecord) *models.BookImportReport {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportBooks", arg0, arg1)
+	ret0, _ := ret[0].(*models.BookImportReport)
+	return ret0
+}
+
+// ImportBooks indicates an expected call of ImportBooks
+func (mr *MockBookImportPipelineMockRecorder) ImportBooks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportBooks", reflect.TypeOf((*MockBookImportPipeline)(nil).ImportBooks), arg0, arg1)
+}
+
+// ImportCatalogRecords mocks base method
+func (m *MockBookImportPipeline) ImportCatalogRecords(arg0 context.Context, arg1 []pipelines.CatalogImportRecord, arg2 bool) *models.BookImportReport {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportCatalogRecords", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.BookImportReport)
+	return ret0
+}
+
+// ImportCatalogRecords indicates an expected call of ImportCatalogRecords
+func (mr *MockBookImportPipelineMockRecorder) ImportCatalogRecords(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCatalogRecords", reflect.TypeOf((*MockBookImportPipeline)(nil).ImportCatalogRecords), arg0, arg1, arg2)
+}
+
+// StartImportJob mocks base method
+func (m *MockBookImportPipeline) StartImportJob(arg0 context.Context, arg1 pipelines.ImportFormat, arg2 []pipelines.BookImportRecord) (*models.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartImportJob", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartImportJob indicates an expected call of StartImportJob
+func (mr *MockBookImportPipelineMockRecorder) StartImportJob(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartImportJob", reflect.TypeOf((*MockBookImportPipeline)(nil).StartImportJob), arg0, arg1, arg2)
+}
+
+// GetImportJob mocks base method
+func (m *MockBookImportPipeline) GetImportJob(arg0 context.Context, arg1 uint) (*models.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportJob", arg0, arg1)
+	ret0, _ := ret[0].(*models.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportJob indicates an expected call of GetImportJob
+func (mr *MockBookImportPipelineMockRecorder) GetImportJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportJob", reflect.TypeOf((*MockBookImportPipeline)(nil).GetImportJob), arg0, arg1)
+}
+
+// FailStaleImportJobs mocks base method
+func (m *MockBookImportPipeline) FailStaleImportJobs(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailStaleImportJobs", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailStaleImportJobs indicates an expected call of FailStaleImportJobs
+func (mr *MockBookImportPipelineMockRecorder) FailStaleImportJobs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailStaleImportJobs", reflect.TypeOf((*MockBookImportPipeline)(nil).FailStaleImportJobs), arg0)
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./usecases/pipelines/book_index_pipeline.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockBookIndexPipeline is a mock of BookIndexPipeline interface
+type MockBookIndexPipeline struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookIndexPipelineMockRecorder
+}
+
+// MockBookIndexPipelineMockRecorder is the mock recorder for MockBookIndexPipeline
+type MockBookIndexPipelineMockRecorder struct {
+	mock *MockBookIndexPipeline
+}
+
+// NewMockBookIndexPipeline creates a new mock instance
+func NewMockBookIndexPipeline(ctrl *gomock.Controller) *MockBookIndexPipeline {
+	mock := &MockBookIndexPipeline{ctrl: ctrl}
+	mock.recorder = &MockBookIndexPipelineMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBookIndexPipeline) EXPECT() *MockBookIndexPipelineMockRecorder {
+	return m.recorder
+}
+
+// ReindexBooks mocks base method
+func (m *MockBookIndexPipeline) ReindexBooks(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReindexBooks", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReindexBooks indicates an expected call of ReindexBooks
+func (mr *MockBookIndexPipelineMockRecorder) ReindexBooks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReindexBooks", reflect.TypeOf((*MockBookIndexPipeline)(nil).ReindexBooks), arg0)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./usecases/pipelines/seed_pipeline.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	pipelines "book-management-system/usecases/pipelines"
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockSeedPipeline is a mock of SeedPipeline interface
+type MockSeedPipeline struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeedPipelineMockRecorder
+}
+
+// MockSeedPipelineMockRecorder is the mock recorder for MockSeedPipeline
+type MockSeedPipelineMockRecorder struct {
+	mock *MockSeedPipeline
+}
+
+// NewMockSeedPipeline creates a new mock instance
+func NewMockSeedPipeline(ctrl *gomock.Controller) *MockSeedPipeline {
+	mock := &MockSeedPipeline{ctrl: ctrl}
+	mock.recorder = &MockSeedPipelineMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSeedPipeline) EXPECT() *MockSeedPipelineMockRecorder {
+	return m.recorder
+}
+
+// Seed mocks base method
+func (m *MockSeedPipeline) Seed(arg0 context.Context, arg1 pipelines.SeedOptions) (pipelines.SeedReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Seed", arg0, arg1)
+	ret0, _ := ret[0].(pipelines.SeedReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Seed indicates an expected call of Seed
+func (mr *MockSeedPipelineMockRecorder) Seed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Seed", reflect.TypeOf((*MockSeedPipeline)(nil).Seed), arg0, arg1)
+}
@@ -0,0 +1,89 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./usecases/pipelines/webhook_pipeline.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	events "book-management-system/events"
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockWebhookPipeline is a mock of WebhookPipeline interface
+type MockWebhookPipeline struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookPipelineMockRecorder
+}
+
+// MockWebhookPipelineMockRecorder is the mock recorder for MockWebhookPipeline
+type MockWebhookPipelineMockRecorder struct {
+	mock *MockWebhookPipeline
+}
+
+// NewMockWebhookPipeline creates a new mock instance
+func NewMockWebhookPipeline(ctrl *gomock.Controller) *MockWebhookPipeline {
+	mock := &MockWebhookPipeline{ctrl: ctrl}
+	mock.recorder = &MockWebhookPipelineMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockWebhookPipeline) EXPECT() *MockWebhookPipelineMockRecorder {
+	return m.recorder
+}
+
+// DispatchEvents mocks base method
+func (m *MockWebhookPipeline) DispatchEvents(arg0 context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DispatchEvents", arg0)
+}
+
+// DispatchEvents indicates an expected call of DispatchEvents
+func (mr *MockWebhookPipelineMockRecorder) DispatchEvents(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchEvents", reflect.TypeOf((*MockWebhookPipeline)(nil).DispatchEvents), arg0)
+}
+
+// EnqueueDeliveries mocks base method
+func (m *MockWebhookPipeline) EnqueueDeliveries(arg0 context.Context, arg1 []events.Event) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueDeliveries", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueDeliveries indicates an expected call of EnqueueDeliveries
+func (mr *MockWebhookPipelineMockRecorder) EnqueueDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueDeliveries", reflect.TypeOf((*MockWebhookPipeline)(nil).EnqueueDeliveries), arg0, arg1)
+}
+
+// DeliverWebhooks mocks base method
+func (m *MockWebhookPipeline) DeliverWebhooks(arg0 context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeliverWebhooks", arg0)
+}
+
+// DeliverWebhooks indicates an expected call of DeliverWebhooks
+func (mr *MockWebhookPipelineMockRecorder) DeliverWebhooks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverWebhooks", reflect.TypeOf((*MockWebhookPipeline)(nil).DeliverWebhooks), arg0)
+}
+
+// DeliverDue mocks base method
+func (m *MockWebhookPipeline) DeliverDue(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverDue", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeliverDue indicates an expected call of DeliverDue
+func (mr *MockWebhookPipelineMockRecorder) DeliverDue(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverDue", reflect.TypeOf((*MockWebhookPipeline)(nil).DeliverDue), arg0)
+}
@@ -48,6 +48,20 @@ func (mr *MockBookRepositoryMockRecorder) IndexBook(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexBook", reflect.TypeOf((*MockBookRepository)(nil).IndexBook), arg0, arg1)
 }
 
+// BulkIndexBooks mocks base method
+func (m *MockBookRepository) BulkIndexBooks(arg0 context.Context, arg1 models.Books) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkIndexBooks", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BulkIndexBooks indicates an expected call of BulkIndexBooks
+func (mr *MockBookRepositoryMockRecorder) BulkIndexBooks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkIndexBooks", reflect.TypeOf((*MockBookRepository)(nil).BulkIndexBooks), arg0, arg1)
+}
+
 // SearchBook mocks base method
 func (m *MockBookRepository) SearchBook(arg0 context.Context, arg1 string) (models.Books, error) {
 	m.ctrl.T.Helper()
@@ -62,3 +76,47 @@ func (mr *MockBookRepositoryMockRecorder) SearchBook(arg0, arg1 interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBook", reflect.TypeOf((*MockBookRepository)(nil).SearchBook), arg0, arg1)
 }
+
+// SearchBookIDs mocks base method
+func (m *MockBookRepository) SearchBookIDs(arg0 context.Context, arg1 string) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchBookIDs", arg0, arg1)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchBookIDs indicates an expected call of SearchBookIDs
+func (mr *MockBookRepositoryMockRecorder) SearchBookIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBookIDs", reflect.TypeOf((*MockBookRepository)(nil).SearchBookIDs), arg0, arg1)
+}
+
+// SearchSimilarBooks mocks base method
+func (m *MockBookRepository) SearchSimilarBooks(arg0 context.Context, arg1 *models.Book, arg2 int) (models.Books, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchSimilarBooks", arg0, arg1, arg2)
+	ret0, _ := ret[0].(models.Books)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchSimilarBooks indicates an expected call of SearchSimilarBooks
+func (mr *MockBookRepositoryMockRecorder) SearchSimilarBooks(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchSimilarBooks", reflect.TypeOf((*MockBookRepository)(nil).SearchSimilarBooks), arg0, arg1, arg2)
+}
+
+// DeleteBook mocks base method
+func (m *MockBookRepository) DeleteBook(arg0 context.Context, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBook", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBook indicates an expected call of DeleteBook
+func (mr *MockBookRepositoryMockRecorder) DeleteBook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockBookRepository)(nil).DeleteBook), arg0, arg1)
+}
@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repositories/mysql/mysql_book_duplicate_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "book-management-system/entities/models"
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockBookDuplicateRepository is a mock of BookDuplicateRepository interface
+type MockBookDuplicateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookDuplicateRepositoryMockRecorder
+}
+
+// MockBookDuplicateRepositoryMockRecorder is the mock recorder for MockBookDuplicateRepository
+type MockBookDuplicateRepositoryMockRecorder struct {
+	mock *MockBookDuplicateRepository
+}
+
+// NewMockBookDuplicateRepository creates a new mock instance
+func NewMockBookDuplicateRepository(ctrl *gomock.Controller) *MockBookDuplicateRepository {
+	mock := &MockBookDuplicateRepository{ctrl: ctrl}
+	mock.recorder = &MockBookDuplicateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBookDuplicateRepository) EXPECT() *MockBookDuplicateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetDuplicates mocks base method
+func (m *MockBookDuplicateRepository) GetDuplicates(arg0 context.Context, arg1 models.BookDuplicateStatus) ([]models.BookDuplicate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDuplicates", arg0, arg1)
+	ret0, _ := ret[0].([]models.BookDuplicate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDuplicates indicates an expected call of GetDuplicates
+func (mr *MockBookDuplicateRepositoryMockRecorder) GetDuplicates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDuplicates", reflect.TypeOf((*MockBookDuplicateRepository)(nil).GetDuplicates), arg0, arg1)
+}
+
+// GetDuplicateByID mocks base method
+func (m *MockBookDuplicateRepository) GetDuplicateByID(arg0 context.Context, arg1 uint) (*models.BookDuplicate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDuplicateByID", arg0, arg1)
+	ret0, _ := ret[0].(*models.BookDuplicate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDuplicateByID indicates an expected call of GetDuplicateByID
+func (mr *MockBookDuplicateRepositoryMockRecorder) GetDuplicateByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDuplicateByID", reflect.TypeOf((*MockBookDuplicateRepository)(nil).GetDuplicateByID), arg0, arg1)
+}
+
+// SaveDuplicates mocks base method
+func (m *MockBookDuplicateRepository) SaveDuplicates(arg0 context.Context, arg1 []models.BookDuplicate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDuplicates", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDuplicates indicates an expected call of SaveDuplicates
+func (mr *MockBookDuplicateRepositoryMockRecorder) SaveDuplicates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDuplicates", reflect.TypeOf((*MockBookDuplicateRepository)(nil).SaveDuplicates), arg0, arg1)
+}
+
+// UpdateDuplicateStatus mocks base method
+func (m *MockBookDuplicateRepository) UpdateDuplicateStatus(arg0 context.Context, arg1 uint, arg2 models.BookDuplicateStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDuplicateStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDuplicateStatus indicates an expected call of UpdateDuplicateStatus
+func (mr *MockBookDuplicateRepositoryMockRecorder) UpdateDuplicateStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDuplicateStatus", reflect.TypeOf((*MockBookDuplicateRepository)(nil).UpdateDuplicateStatus), arg0, arg1, arg2)
+}
+
+// MergeBooks mocks base method
+func (m *MockBookDuplicateRepository) MergeBooks(arg0 context.Context, arg1, arg2 *models.Book) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeBooks", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeBooks indicates an expected call of MergeBooks
+func (mr *MockBookDuplicateRepositoryMockRecorder) MergeBooks(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeBooks", reflect.TypeOf((*MockBookDuplicateRepository)(nil).MergeBooks), arg0, arg1, arg2)
+}
+
+// GetRedirect mocks base method
+func (m *MockBookDuplicateRepository) GetRedirect(arg0 context.Context, arg1 uint) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRedirect", arg0, arg1)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRedirect indicates an expected call of GetRedirect
+func (mr *MockBookDuplicateRepositoryMockRecorder) GetRedirect(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRedirect", reflect.TypeOf((*MockBookDuplicateRepository)(nil).GetRedirect), arg0, arg1)
+}
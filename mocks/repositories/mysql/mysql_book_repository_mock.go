@@ -6,6 +6,7 @@ package mocks
 
 import (
 	models "book-management-system/entities/models"
+	objects "book-management-system/entities/objects"
 	context "context"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
@@ -49,6 +50,66 @@ func (mr *MockBookRepositoryMockRecorder) GetAll(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockBookRepository)(nil).GetAll), arg0)
 }
 
+// GetBookByID mocks base method
+func (m *MockBookRepository) GetBookByID(arg0 context.Context, arg1 uint) (*models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByID", arg0, arg1)
+	ret0, _ := ret[0].(*models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookByID indicates an expected call of GetBookByID
+func (mr *MockBookRepositoryMockRecorder) GetBookByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByID", reflect.TypeOf((*MockBookRepository)(nil).GetBookByID), arg0, arg1)
+}
+
+// GetBookByISBN mocks base method
+func (m *MockBookRepository) GetBookByISBN(arg0 context.Context, arg1 objects.ISBN) (*models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByISBN", arg0, arg1)
+	ret0, _ := ret[0].(*models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookByISBN indicates an expected call of GetBookByISBN
+func (mr *MockBookRepositoryMockRecorder) GetBookByISBN(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByISBN", reflect.TypeOf((*MockBookRepository)(nil).GetBookByISBN), arg0, arg1)
+}
+
+// GetBooksByISBNs mocks base method
+func (m *MockBookRepository) GetBooksByISBNs(arg0 context.Context, arg1 []objects.ISBN) (models.Books, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBooksByISBNs", arg0, arg1)
+	ret0, _ := ret[0].(models.Books)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBooksByISBNs indicates an expected call of GetBooksByISBNs
+func (mr *MockBookRepositoryMockRecorder) GetBooksByISBNs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksByISBNs", reflect.TypeOf((*MockBookRepository)(nil).GetBooksByISBNs), arg0, arg1)
+}
+
+// GetBooksAfterID mocks base method
+func (m *MockBookRepository) GetBooksAfterID(arg0 context.Context, arg1 uint, arg2 int, arg3 []uint) (models.Books, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBooksAfterID", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(models.Books)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBooksAfterID indicates an expected call of GetBooksAfterID
+func (mr *MockBookRepositoryMockRecorder) GetBooksAfterID(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksAfterID", reflect.TypeOf((*MockBookRepository)(nil).GetBooksAfterID), arg0, arg1, arg2, arg3)
+}
+
 // CreateBook mocks base method
 func (m *MockBookRepository) CreateBook(arg0 context.Context, arg1 *models.Book) error {
 	m.ctrl.T.Helper()
@@ -63,6 +124,20 @@ func (mr *MockBookRepositoryMockRecorder) CreateBook(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBook", reflect.TypeOf((*MockBookRepository)(nil).CreateBook), arg0, arg1)
 }
 
+// CreateBooks mocks base method
+func (m *MockBookRepository) CreateBooks(arg0 context.Context, arg1 models.Books) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBooks", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBooks indicates an expected call of CreateBooks
+func (mr *MockBookRepositoryMockRecorder) CreateBooks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBooks", reflect.TypeOf((*MockBookRepository)(nil).CreateBooks), arg0, arg1)
+}
+
 // UpdateBook mocks base method
 func (m *MockBookRepository) UpdateBook(arg0 context.Context, arg1 *models.Book) error {
 	m.ctrl.T.Helper()
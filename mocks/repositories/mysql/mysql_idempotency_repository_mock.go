@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repositories/mysql/mysql_idempotency_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "book-management-system/entities/models"
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockIdempotencyRepository is a mock of IdempotencyRepository interface
+type MockIdempotencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRepositoryMockRecorder
+}
+
+// MockIdempotencyRepositoryMockRecorder is the mock recorder for MockIdempotencyRepository
+type MockIdempotencyRepositoryMockRecorder struct {
+	mock *MockIdempotencyRepository
+}
+
+// NewMockIdempotencyRepository creates a new mock instance
+func NewMockIdempotencyRepository(ctrl *gomock.Controller) *MockIdempotencyRepository {
+	mock := &MockIdempotencyRepository{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIdempotencyRepository) EXPECT() *MockIdempotencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateKey mocks base method
+func (m *MockIdempotencyRepository) CreateKey(arg0 context.Context, arg1 *models.IdempotencyKey) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKey", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateKey indicates an expected call of CreateKey
+func (mr *MockIdempotencyRepositoryMockRecorder) CreateKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).CreateKey), arg0, arg1)
+}
+
+// GetKey mocks base method
+func (m *MockIdempotencyRepository) GetKey(arg0 context.Context, arg1 string) (*models.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKey", arg0, arg1)
+	ret0, _ := ret[0].(*models.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKey indicates an expected call of GetKey
+func (mr *MockIdempotencyRepositoryMockRecorder) GetKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).GetKey), arg0, arg1)
+}
+
+// ExtendKey mocks base method
+func (m *MockIdempotencyRepository) ExtendKey(arg0 context.Context, arg1 *models.IdempotencyKey) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendKey", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExtendKey indicates an expected call of ExtendKey
+func (mr *MockIdempotencyRepositoryMockRecorder) ExtendKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).ExtendKey), arg0, arg1)
+}
+
+// CompleteKey mocks base method
+func (m *MockIdempotencyRepository) CompleteKey(arg0 context.Context, arg1 *models.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteKey indicates an expected call of CompleteKey
+func (mr *MockIdempotencyRepositoryMockRecorder) CompleteKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).CompleteKey), arg0, arg1)
+}
+
+// DeleteKey mocks base method
+func (m *MockIdempotencyRepository) DeleteKey(arg0 context.Context, arg1 *models.IdempotencyKey) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteKey", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteKey indicates an expected call of DeleteKey
+func (mr *MockIdempotencyRepositoryMockRecorder) DeleteKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).DeleteKey), arg0, arg1)
+}
+
+// DeleteExpiredKeys mocks base method
+func (m *MockIdempotencyRepository) DeleteExpiredKeys(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredKeys", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredKeys indicates an expected call of DeleteExpiredKeys
+func (mr *MockIdempotencyRepositoryMockRecorder) DeleteExpiredKeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredKeys", reflect.TypeOf((*MockIdempotencyRepository)(nil).DeleteExpiredKeys), arg0, arg1)
+}
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./repositories/mysql/mysql_import_job_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "book-management-system/entities/models"
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockImportJobRepository is a mock of ImportJobRepository interface
+type MockImportJobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportJobRepositoryMockRecorder
+}
+
+// MockImportJobRepositoryMockRecorder is the mock recorder for MockImportJobRepository
+type MockImportJobRepositoryMockRecorder struct {
+	mock *MockImportJobRepository
+}
+
+// NewMockImportJobRepository creates a new mock instance
+func NewMockImportJobRepository(ctrl *gomock.Controller) *MockImportJobRepository {
+	mock := &MockImportJobRepository{ctrl: ctrl}
+	mock.recorder = &MockImportJobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockImportJobRepository) EXPECT() *MockImportJobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetJobByID mocks base method
+func (m *MockImportJobRepository) GetJobByID(arg0 context.Context, arg1 uint) (*models.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobByID", arg0, arg1)
+	ret0, _ := ret[0].(*models.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobByID indicates an expected call of GetJobByID
+func (mr *MockImportJobRepositoryMockRecorder) GetJobByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobByID", reflect.TypeOf((*MockImportJobRepository)(nil).GetJobByID), arg0, arg1)
+}
+
+// CreateJob mocks base method
+func (m *MockImportJobRepository) CreateJob(arg0 context.Context, arg1 *models.ImportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateJob indicates an expected call of CreateJob
+func (mr *MockImportJobRepositoryMockRecorder) CreateJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockImportJobRepository)(nil).CreateJob), arg0, arg1)
+}
+
+// UpdateJob mocks base method
+func (m *MockImportJobRepository) UpdateJob(arg0 context.Context, arg1 *models.ImportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateJob indicates an expected call of UpdateJob
+func (mr *MockImportJobRepositoryMockRecorder) UpdateJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockImportJobRepository)(nil).UpdateJob), arg0, arg1)
+}
+
+// FailStaleJobs mocks base method
+func (m *MockImportJobRepository) FailStaleJobs(ctx context.Context, updatedBefore time.Time, message string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailStaleJobs", ctx, updatedBefore, message)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailStaleJobs indicates an expected call of FailStaleJobs
+func (mr *MockImportJobRepositoryMockRecorder) FailStaleJobs(ctx, updatedBefore, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailStaleJobs", reflect.TypeOf((*MockImportJobRepository)(nil).FailStaleJobs), ctx, updatedBefore, message)
+}
@@ -49,6 +49,21 @@ func (mr *MockMemberRepositoryMockRecorder) GetAll(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockMemberRepository)(nil).GetAll), arg0)
 }
 
+// GetMemberByID mocks base method
+func (m *MockMemberRepository) GetMemberByID(arg0 context.Context, arg1 uint) (*models.Member, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberByID", arg0, arg1)
+	ret0, _ := ret[0].(*models.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberByID indicates an expected call of GetMemberByID
+func (mr *MockMemberRepositoryMockRecorder) GetMemberByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberByID", reflect.TypeOf((*MockMemberRepository)(nil).GetMemberByID), arg0, arg1)
+}
+
 // CreateMember mocks base method
 func (m *MockMemberRepository) CreateMember(arg0 context.Context, arg1 *models.Member) error {
 	m.ctrl.T.Helper()
@@ -49,6 +49,36 @@ func (mr *MockBookServiceMockRecorder) GetBooks(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockBookService)(nil).GetBooks), arg0)
 }
 
+// GetBook mocks base method
+func (m *MockBookService) GetBook(arg0 context.Context, arg1 uint) (*models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBook", arg0, arg1)
+	ret0, _ := ret[0].(*models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBook indicates an expected call of GetBook
+func (mr *MockBookServiceMockRecorder) GetBook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBook", reflect.TypeOf((*MockBookService)(nil).GetBook), arg0, arg1)
+}
+
+// GetBookByISBN mocks base method
+func (m *MockBookService) GetBookByISBN(arg0 context.Context, arg1 string) (*models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByISBN", arg0, arg1)
+	ret0, _ := ret[0].(*models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookByISBN indicates an expected call of GetBookByISBN
+func (mr *MockBookServiceMockRecorder) GetBookByISBN(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByISBN", reflect.TypeOf((*MockBookService)(nil).GetBookByISBN), arg0, arg1)
+}
+
 // CreateBook mocks base method
 func (m *MockBookService) CreateBook(arg0 context.Context, arg1 *models.Book) error {
 	m.ctrl.T.Helper()
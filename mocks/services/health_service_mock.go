@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./usecases/services/health_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	health "book-management-system/health"
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockHealthService is a mock of HealthService interface
+type MockHealthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthServiceMockRecorder
+}
+
+// MockHealthServiceMockRecorder is the mock recorder for MockHealthService
+type MockHealthServiceMockRecorder struct {
+	mock *MockHealthService
+}
+
+// NewMockHealthService creates a new mock instance
+func NewMockHealthService(ctrl *gomock.Controller) *MockHealthService {
+	mock := &MockHealthService{ctrl: ctrl}
+	mock.recorder = &MockHealthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockHealthService) EXPECT() *MockHealthServiceMockRecorder {
+	return m.recorder
+}
+
+// Liveness mocks base method
+func (m *MockHealthService) Liveness(arg0 context.Context) health.Report {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Liveness", arg0)
+	ret0, _ := ret[0].(health.Report)
+	return ret0
+}
+
+// Liveness indicates an expected call of Liveness
+func (mr *MockHealthServiceMockRecorder) Liveness(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Liveness", reflect.TypeOf((*MockHealthService)(nil).Liveness), arg0)
+}
+
+// Readiness mocks base method
+func (m *MockHealthService) Readiness(arg0 context.Context) health.Report {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Readiness", arg0)
+	ret0, _ := ret[0].(health.Report)
+	return ret0
+}
+
+// Readiness indicates an expected call of Readiness
+func (mr *MockHealthServiceMockRecorder) Readiness(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Readiness", reflect.TypeOf((*MockHealthService)(nil).Readiness), arg0)
+}
@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./usecases/services/idempotency_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "book-management-system/entities/models"
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockIdempotencyService is a mock of IdempotencyService interface
+type MockIdempotencyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyServiceMockRecorder
+}
+
+// MockIdempotencyServiceMockRecorder is the mock recorder for MockIdempotencyService
+type MockIdempotencyServiceMockRecorder struct {
+	mock *MockIdempotencyService
+}
+
+// NewMockIdempotencyService creates a new mock instance
+func NewMockIdempotencyService(ctrl *gomock.Controller) *MockIdempotencyService {
+	mock := &MockIdempotencyService{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIdempotencyService) EXPECT() *MockIdempotencyServiceMockRecorder {
+	return m.recorder
+}
+
+// BeginRequest mocks base method
+func (m *MockIdempotencyService) BeginRequest(ctx context.Context, key, requestHash string, ttl time.Duration) (*models.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginRequest", ctx, key, requestHash, ttl)
+	ret0, _ := ret[0].(*models.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginRequest indicates an expected call of BeginRequest
+func (mr *MockIdempotencyServiceMockRecorder) BeginRequest(ctx, key, requestHash, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginRequest", reflect.TypeOf((*MockIdempotencyService)(nil).BeginRequest), ctx, key, requestHash, ttl)
+}
+
+// ExtendRequest mocks base method
+func (m *MockIdempotencyService) ExtendRequest(arg0 context.Context, arg1 *models.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendRequest", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExtendRequest indicates an expected call of ExtendRequest
+func (mr *MockIdempotencyServiceMockRecorder) ExtendRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendRequest", reflect.TypeOf((*MockIdempotencyService)(nil).ExtendRequest), arg0, arg1)
+}
+
+// CompleteRequest mocks base method
+func (m *MockIdempotencyService) CompleteRequest(arg0 context.Context, arg1 *models.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteRequest", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteRequest indicates an expected call of CompleteRequest
+func (mr *MockIdempotencyServiceMockRecorder) CompleteRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteRequest", reflect.TypeOf((*MockIdempotencyService)(nil).CompleteRequest), arg0, arg1)
+}
+
+// ReleaseRequest mocks base method
+func (m *MockIdempotencyService) ReleaseRequest(arg0 context.Context, arg1 *models.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseRequest", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseRequest indicates an expected call of ReleaseRequest
+func (mr *MockIdempotencyServiceMockRecorder) ReleaseRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseRequest", reflect.TypeOf((*MockIdempotencyService)(nil).ReleaseRequest), arg0, arg1)
+}
+
+// PurgeExpiredKeys mocks base method
+func (m *MockIdempotencyService) PurgeExpiredKeys(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredKeys", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeExpiredKeys indicates an expected call of PurgeExpiredKeys
+func (mr *MockIdempotencyServiceMockRecorder) PurgeExpiredKeys(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredKeys", reflect.TypeOf((*MockIdempotencyService)(nil).PurgeExpiredKeys), arg0)
+}
@@ -49,6 +49,21 @@ func (mr *MockMemberServiceMockRecorder) GetMembers(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembers", reflect.TypeOf((*MockMemberService)(nil).GetMembers), arg0)
 }
 
+// GetMember mocks base method
+func (m *MockMemberService) GetMember(arg0 context.Context, arg1 uint) (*models.Member, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMember", arg0, arg1)
+	ret0, _ := ret[0].(*models.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMember indicates an expected call of GetMember
+func (mr *MockMemberServiceMockRecorder) GetMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMember", reflect.TypeOf((*MockMemberService)(nil).GetMember), arg0, arg1)
+}
+
 // CreateMember mocks base method
 func (m *MockMemberService) CreateMember(arg0 context.Context, arg1 *models.Member) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,108 @@
+// Package ratelimit limits request rates of clients by token buckets kept in a Store,
+// MemoryStore keeps buckets of a single instance and other stores may share them between instances
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore drops buckets refilled to full, they equal new buckets
+const sweepInterval = time.Minute
+
+// Limit allows Burst requests at once refilled by Rate requests per second
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result is outcome of taking a token from bucket
+type Result struct {
+	Allowed bool
+	// Limit is bucket size
+	Limit int
+	// Remaining is number of tokens left in bucket
+	Remaining int
+	// RetryAfter is time until next token when request is not allowed
+	RetryAfter time.Duration
+	// Reset is time until bucket is full again
+	Reset time.Duration
+}
+
+// Store takes tokens from buckets of keys
+type Store interface {
+	// Take takes one token from bucket of key refilled by limit, request is allowed when there was one
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// MemoryStore is Store keeping buckets in memory of the process
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+// NewMemoryStore returns MemoryStore without buckets
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Take implements Store
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	burst := float64(limit.Burst)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, updated: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+	b.updated = now
+	b.limit = limit
+
+	result := Result{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / limit.Rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = seconds((burst - b.tokens) / limit.Rate)
+	return result, nil
+}
+
+// sweep drops buckets full by now at most once per sweepInterval
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*b.limit.Rate >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// clock is adjustable time of MemoryStore
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newTestStore() (*MemoryStore, *clock) {
+	c := &clock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewMemoryStore()
+	s.now = c.Now
+	s.lastSweep = c.now
+	return s, c
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	limit := Limit{Rate: 2, Burst: 3}
+
+	tests := []struct {
+		name     string
+		elapsed  time.Duration
+		expected Result
+	}{
+		{
+			name:     "first request",
+			expected: Result{Allowed: true, Limit: 3, Remaining: 2, Reset: 500 * time.Millisecond},
+		},
+		{
+			name:     "second request",
+			expected: Result{Allowed: true, Limit: 3, Remaining: 1, Reset: time.Second},
+		},
+		{
+			name:     "last token",
+			expected: Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 1500 * time.Millisecond},
+		},
+		{
+			name:     "bucket empty",
+			expected: Result{Allowed: false, Limit: 3, Remaining: 0, RetryAfter: 500 * time.Millisecond, Reset: 1500 * time.Millisecond},
+		},
+		{
+			name:     "partly refilled",
+			elapsed:  250 * time.Millisecond,
+			expected: Result{Allowed: false, Limit: 3, Remaining: 0, RetryAfter: 250 * time.Millisecond, Reset: 1250 * time.Millisecond},
+		},
+		{
+			name:     "refilled token",
+			elapsed:  250 * time.Millisecond,
+			expected: Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 1500 * time.Millisecond},
+		},
+		{
+			name:     "refill is capped by burst",
+			elapsed:  time.Hour,
+			expected: Result{Allowed: true, Limit: 3, Remaining: 2, Reset: 500 * time.Millisecond},
+		},
+	}
+
+	s, c := newTestStore()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.now = c.now.Add(tt.elapsed)
+			got, err := s.Take(context.Background(), "client", limit)
+			if err != nil {
+				t.Fatalf("Take() got error %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Take() got %+v\n expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreKeys(t *testing.T) {
+	s, _ := newTestStore()
+	limit := Limit{Rate: 1, Burst: 1}
+
+	if got, _ := s.Take(context.Background(), "a", limit); !got.Allowed {
+		t.Fatal("Take() of a got not allowed")
+	}
+	if got, _ := s.Take(context.Background(), "a", limit); got.Allowed {
+		t.Error("Take() of a got allowed over burst")
+	}
+	if got, _ := s.Take(context.Background(), "b", limit); !got.Allowed {
+		t.Error("Take() of b got not allowed, buckets of keys are not separate")
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s, c := newTestStore()
+	ctx := context.Background()
+
+	_, _ = s.Take(ctx, "slow", Limit{Rate: 0.001, Burst: 5})
+	_, _ = s.Take(ctx, "fast", Limit{Rate: 1, Burst: 5})
+
+	c.now = c.now.Add(sweepInterval)
+	_, _ = s.Take(ctx, "new", Limit{Rate: 1, Burst: 5})
+
+	if _, ok := s.buckets["fast"]; ok {
+		t.Error("sweep kept full bucket")
+	}
+	if _, ok := s.buckets["slow"]; !ok {
+		t.Error("sweep dropped bucket not refilled yet")
+	}
+}
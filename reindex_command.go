@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+
+	"book-management-system/logging"
+	"book-management-system/repositories"
+	"book-management-system/usecases"
+)
+
+// runReindex indexes all stored books into search backend, e.g. after search index was lost.
+// It returns exit code, non-zero when indexing failed.
+func runReindex(args []string) int {
+	flags := flag.NewFlagSet("reindex", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s reindex\n", appName)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return flagsExitCode(err)
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	useCase, shutdown := initUseCase()
+	defer shutdown()
+	count, err := useCase.Pipeline.BookIndexPipeline.ReindexBooks(context.Background())
+	fmt.Printf("indexed %d books\n", count)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed reindex books: %s\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// reindexStaleSearch indexes all stored books when migrations applied on start changed indexed book columns
+func reindexStaleSearch(repo *repositories.Repository, useCase *usecases.UseCase) {
+	if !repo.SearchIndexStale {
+		return
+	}
+	count, err := useCase.Pipeline.BookIndexPipeline.ReindexBooks(context.Background())
+	if err != nil {
+		logging.Logger(logging.PackagePipelines).Error("failed to reindex books after migrations",
+			zap.Int("books", count), zap.Error(err))
+		return
+	}
+	repo.SearchIndexStale = false
+	logging.Logger(logging.PackagePipelines).Info("reindexed books after migrations", zap.Int("books", count))
+}
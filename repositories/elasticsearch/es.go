@@ -1,18 +1,30 @@
 package elasticsearch
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/elastic/go-elasticsearch/v8"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.uber.org/zap"
 
 	"book-management-system/configs"
+	"book-management-system/logging"
+	"book-management-system/metrics"
 )
 
 var (
 	es   *elasticsearch.Client
 	once sync.Once
+	// transport holds connections of es
+	transport = http.DefaultTransport.(*http.Transport).Clone()
 )
 
 // Init returns elastic search client
@@ -24,6 +36,11 @@ func Init() *elasticsearch.Client {
 			Addresses: []string{
 				cfg.Address,
 			},
+			Transport: &metricsTransport{next: otelhttp.NewTransport(transport,
+				otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+					return "elasticsearch " + r.Method + " " + r.URL.Path
+				}),
+			)},
 		}
 		if cfg.IsAuth {
 			esCfg.Username = cfg.Username
@@ -36,21 +53,65 @@ func Init() *elasticsearch.Client {
 			log.Fatal(err)
 		}
 
-		res, err := es.Info()
-		if err != nil {
-			log.Fatalf("Error getting elasticsearch info: %s", err)
-		}
-		defer res.Body.Close()
-
-		if res.IsError() {
-			log.Fatalf("Error elasticsearch info response: %s", res.String())
-		}
-
-		var r map[string]interface{}
-		if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
-			log.Fatalf("Error parsing the response body: %s", err)
+		// search being down is reported by readiness, the service still starts
+		if err := ping(context.Background()); err != nil {
+			logging.Logger(logging.PackageRepositories).Warn("elasticsearch is not available", zap.Error(err))
 		}
 	})
 
 	return es
 }
+
+// Close closes idle connections to elasticsearch, requests in flight finish first
+func Close() error {
+	transport.CloseIdleConnections()
+	return nil
+}
+
+// HealthCheck reports elasticsearch down when it is unreachable or its cluster status is red
+func HealthCheck(ctx context.Context) error {
+	return ping(ctx)
+}
+
+func ping(ctx context.Context) error {
+	res, err := es.Cluster.Health(es.Cluster.Health.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("cluster health response: %s", res.Status())
+	}
+
+	var body struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return fmt.Errorf("failed to parse cluster health: %w", err)
+	}
+	if body.Status == "red" {
+		return errors.New("cluster status is red")
+	}
+	return nil
+}
+
+// metricsTransport observes latency and errors of ElasticSearch requests
+type metricsTransport struct {
+	next http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(res.StatusCode)
+	}
+	metrics.ElasticSearchRequestDuration.WithLabelValues(req.Method, code).Observe(time.Since(start).Seconds())
+	if err != nil || res.StatusCode >= http.StatusInternalServerError {
+		metrics.ElasticSearchRequestErrors.WithLabelValues(req.Method).Inc()
+	}
+	return res, err
+}
@@ -1,22 +1,35 @@
 package elasticsearch
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/elastic/go-elasticsearch/v8"
 
 	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
 )
 
 // BookRepository interface
 type BookRepository interface {
 	IndexBook(context.Context, *models.Book) error
+	BulkIndexBooks(context.Context, models.Books) error
 	SearchBook(context.Context, string) (models.Books, error)
+	SearchBookIDs(context.Context, string) ([]uint, error)
+	SearchSimilarBooks(context.Context, *models.Book, int) (models.Books, error)
+	DeleteBook(context.Context, uint) error
 }
 
+// searchPageSize is number of hits SearchBookIDs reads at once
+const searchPageSize = 1000
+
 type bookRepository struct {
 	es    *elasticsearch.Client
 	index string
@@ -51,11 +64,60 @@ func (repo *bookRepository) IndexBook(ctx context.Context, book *models.Book) er
 	return nil
 }
 
+// BulkIndexBooks indexes books with a single bulk request
+func (repo *bookRepository) BulkIndexBooks(ctx context.Context, books models.Books) error {
+	if len(books) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for i := range books {
+		action := map[string]interface{}{
+			"index": map[string]interface{}{
+				"_index": repo.index,
+				"_id":    strconv.Itoa(int(books[i].ID)),
+			},
+		}
+		if err := encoder.Encode(action); err != nil {
+			return err
+		}
+		if err := encoder.Encode(&books[i]); err != nil {
+			return err
+		}
+	}
+
+	res, err := repo.es.Bulk(
+		&body,
+		repo.es.Bulk.WithContext(ctx),
+		repo.es.Bulk.WithIndex(repo.index),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("bulk index books: %s", res.String())
+	}
+
+	var decodedRes struct {
+		Errors bool `json:"errors"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&decodedRes); err != nil {
+		return err
+	}
+	if decodedRes.Errors {
+		return errors.New("bulk index books: some documents failed to index")
+	}
+	return nil
+}
+
 func (repo *bookRepository) SearchBook(ctx context.Context, keyword string) (models.Books, error) {
 	res, err := repo.es.Search(
 		es.Search.WithContext(ctx),
 		es.Search.WithIndex(repo.index),
-		es.Search.WithQuery(keyword),
+		es.Search.WithQuery(queryString(keyword)),
 		es.Search.WithPretty(),
 	)
 	if err != nil {
@@ -63,8 +125,148 @@ func (repo *bookRepository) SearchBook(ctx context.Context, keyword string) (mod
 	}
 	defer res.Body.Close()
 
-	decodedRes := make(map[string]interface{})
+	return decodeBooks(res.Body)
+}
+
+// SearchBookIDs returns IDs of all books matching keyword in ascending order. Unlike SearchBook it is not
+// limited to the first page of hits, pages are read one after another with search_after.
+func (repo *bookRepository) SearchBookIDs(ctx context.Context, keyword string) ([]uint, error) {
+	ids := make([]uint, 0)
+	var after []json.RawMessage
+	for {
+		query := map[string]interface{}{
+			"size":    searchPageSize,
+			"_source": false,
+			"query": map[string]interface{}{
+				"query_string": map[string]interface{}{"query": queryString(keyword)},
+			},
+			"sort": []map[string]interface{}{{"ID": "asc"}},
+		}
+		if after != nil {
+			query["search_after"] = after
+		}
+		queryBytes, err := json.Marshal(query)
+		if err != nil {
+			return nil, err
+		}
+
+		hits, err := repo.searchHits(ctx, queryBytes)
+		if err != nil {
+			return nil, err
+		}
+		for _, hit := range hits {
+			id, err := strconv.ParseUint(hit.ID, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("search book IDs: invalid document ID %q", hit.ID)
+			}
+			ids = append(ids, uint(id))
+		}
+
+		if len(hits) < searchPageSize {
+			return ids, nil
+		}
+		after = hits[len(hits)-1].Sort
+	}
+}
+
+type searchHit struct {
+	ID   string            `json:"_id"`
+	Sort []json.RawMessage `json:"sort"`
+}
+
+func (repo *bookRepository) searchHits(ctx context.Context, query []byte) ([]searchHit, error) {
+	res, err := repo.es.Search(
+		repo.es.Search.WithContext(ctx),
+		repo.es.Search.WithIndex(repo.index),
+		repo.es.Search.WithBody(bytes.NewReader(query)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, fmt.Errorf("search book IDs: %s", res.String())
+	}
+
+	var decodedRes struct {
+		Hits struct {
+			Hits []searchHit `json:"hits"`
+		} `json:"hits"`
+	}
 	if err := json.NewDecoder(res.Body).Decode(&decodedRes); err != nil {
+		return nil, err
+	}
+	return decodedRes.Hits.Hits, nil
+}
+
+// SearchSimilarBooks returns up to size books with title like the book title, the book itself excluded
+func (repo *bookRepository) SearchSimilarBooks(ctx context.Context, book *models.Book, size int) (models.Books, error) {
+	query := map[string]interface{}{
+		"size": size,
+		"query": map[string]interface{}{
+			"more_like_this": map[string]interface{}{
+				"fields": []string{"name"},
+				"like": []map[string]interface{}{
+					{"_index": repo.index, "_id": strconv.Itoa(int(book.ID))},
+				},
+				"min_term_freq":        1,
+				"min_doc_freq":         1,
+				"minimum_should_match": "50%",
+			},
+		},
+	}
+	queryBytes, err := json.Marshal(query)
+	if err != nil {
+		return models.Books{}, err
+	}
+
+	res, err := repo.es.Search(
+		es.Search.WithContext(ctx),
+		es.Search.WithIndex(repo.index),
+		es.Search.WithBody(bytes.NewReader(queryBytes)),
+	)
+	if err != nil {
+		return models.Books{}, err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return models.Books{}, fmt.Errorf("search similar books: %s", res.String())
+	}
+	return decodeBooks(res.Body)
+}
+
+// DeleteBook removes book from index, missing book is not an error
+func (repo *bookRepository) DeleteBook(ctx context.Context, id uint) error {
+	res, err := repo.es.Delete(
+		repo.index,
+		strconv.Itoa(int(id)),
+		repo.es.Delete.WithContext(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("delete book: %s", res.String())
+	}
+	return nil
+}
+
+// queryString returns query string of keyword, ISBN in any format is looked up by its canonical form
+func queryString(keyword string) string {
+	if isbn, err := objects.ParseISBN(keyword); err == nil && !isbn.IsZero() {
+		return fmt.Sprintf("isbn:%s", isbn)
+	}
+	return keyword
+}
+
+// decodeBooks decodes books of search response hits
+func decodeBooks(body io.Reader) (models.Books, error) {
+	decodedRes := make(map[string]interface{})
+	if err := json.NewDecoder(body).Decode(&decodedRes); err != nil {
 		return models.Books{}, err
 	}
 
@@ -72,7 +274,7 @@ func (repo *bookRepository) SearchBook(ctx context.Context, keyword string) (mod
 	for _, hit := range decodedRes["hits"].(map[string]interface{})["hits"].([]interface{}) {
 		source, _ := json.Marshal(hit.(map[string]interface{})["_source"])
 		var book models.Book
-		err = json.Unmarshal(source, &book)
+		err := json.Unmarshal(source, &book)
 		if err != nil {
 			return models.Books{}, err
 		}
@@ -0,0 +1,62 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+func TestBookRepositorySearchBookIDs(t *testing.T) {
+	// more books than a page of hits match, ElasticSearch returns 10 hits unless size is given
+	const matching = searchPageSize + 500
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Size  int `json:"size"`
+			Query struct {
+				QueryString struct {
+					Query string `json:"query"`
+				} `json:"query_string"`
+			} `json:"query"`
+			SearchAfter []uint `json:"search_after"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode search request: %v", err)
+		}
+		queries = append(queries, req.Query.QueryString.Query)
+
+		var after uint
+		if len(req.SearchAfter) > 0 {
+			after = req.SearchAfter[0]
+		}
+		hits := make([]map[string]interface{}, 0, req.Size)
+		for id := after + 1; id <= matching && len(hits) < req.Size; id++ {
+			hits = append(hits, map[string]interface{}{"_id": strconv.Itoa(int(id)), "sort": []uint{id}})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"hits": map[string]interface{}{"hits": hits}})
+	}))
+	defer server.Close()
+
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{server.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := NewBookRepository(es).SearchBookIDs(context.TODO(), "978-0-06-231500-7")
+	if err != nil {
+		t.Fatalf("SearchBookIDs() got error %v", err)
+	}
+	if len(ids) != matching || ids[0] != 1 || ids[matching-1] != matching {
+		t.Errorf("SearchBookIDs() got %d IDs from %v\n expected %d IDs from 1", len(ids), ids[:1], matching)
+	}
+	if expected := []string{"isbn:9780062315007", "isbn:9780062315007"}; !reflect.DeepEqual(queries, expected) {
+		t.Errorf("SearchBookIDs() queried %v\n expected %v", queries, expected)
+	}
+}
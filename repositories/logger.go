@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"book-management-system/logging"
+)
+
+// slowQueryThreshold is duration above which queries are logged at warn level
+const slowQueryThreshold = 200 * time.Millisecond
+
+// gormLogger writes gorm logs to gorm package logger, SQL statements at debug level,
+// slow ones at warn and failed ones at error level. Levels follow log.packages of reloaded config.
+type gormLogger struct{}
+
+// LogMode has no effect, level is set by gorm package logger
+func (l gormLogger) LogMode(logger.LogLevel) logger.Interface {
+	return l
+}
+
+func (gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	logging.FromContext(ctx, logging.PackageGorm).Sugar().Infof(msg, data...)
+}
+
+func (gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	logging.FromContext(ctx, logging.PackageGorm).Sugar().Warnf(msg, data...)
+}
+
+func (gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	logging.FromContext(ctx, logging.PackageGorm).Sugar().Errorf(msg, data...)
+}
+
+func (gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	level, msg := zapcore.DebugLevel, "query"
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		level, msg = zapcore.ErrorLevel, "query failed"
+	case elapsed > slowQueryThreshold:
+		level, msg = zapcore.WarnLevel, "slow query"
+	}
+
+	// statement is built only when it is logged
+	ce := logging.FromContext(ctx, logging.PackageGorm).Check(level, msg)
+	if ce == nil {
+		return
+	}
+	sql, rows := fc()
+	fields := []zap.Field{zap.String("sql", sql), zap.Int64("rows", rows), zap.Duration("duration", elapsed)}
+	if level == zapcore.ErrorLevel {
+		fields = append(fields, zap.Error(err))
+	}
+	ce.Write(fields...)
+}
@@ -0,0 +1,61 @@
+// Package memory contains repositories keeping records in process memory.
+// Records are lost on exit, it is meant for local development and tests.
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"book-management-system/entities/models"
+)
+
+// DB is in-memory database shared by memory repositories,
+// so operations spanning several tables such as merging books are atomic
+type DB struct {
+	mu sync.RWMutex
+
+	books           map[uint]models.Book
+	members         map[uint]models.Member
+	idempotencyKeys map[string]models.IdempotencyKey
+	importJobs      map[uint]models.ImportJob
+	bookDuplicates  map[uint]models.BookDuplicate
+	bookRedirects   map[uint]models.BookRedirect
+	webhooks        map[uint]models.Webhook
+	deliveries      map[uint]models.WebhookDelivery
+
+	lastBookID          uint
+	lastMemberID        uint
+	lastImportJobID     uint
+	lastBookDuplicateID uint
+	lastWebhookID       uint
+	lastDeliveryID      uint
+
+	// now returns current time, replaced in tests
+	now func() time.Time
+}
+
+// NewDB returns empty DB
+func NewDB() *DB {
+	return &DB{
+		books:           make(map[uint]models.Book),
+		members:         make(map[uint]models.Member),
+		idempotencyKeys: make(map[string]models.IdempotencyKey),
+		importJobs:      make(map[uint]models.ImportJob),
+		bookDuplicates:  make(map[uint]models.BookDuplicate),
+		bookRedirects:   make(map[uint]models.BookRedirect),
+		webhooks:        make(map[uint]models.Webhook),
+		deliveries:      make(map[uint]models.WebhookDelivery),
+		now:             time.Now,
+	}
+}
+
+// nextID returns ID of new record, explicit ID is kept like auto increment column does
+func nextID(last *uint, id uint) uint {
+	if id == 0 {
+		id = *last + 1
+	}
+	if id > *last {
+		*last = id
+	}
+	return id
+}
@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+	"book-management-system/repositories/mysql"
+)
+
+type bookDuplicateRepository struct {
+	db *DB
+}
+
+// NewBookDuplicateRepository returns new mysql.BookDuplicateRepository keeping candidates and redirects in db
+func NewBookDuplicateRepository(db *DB) mysql.BookDuplicateRepository {
+	return &bookDuplicateRepository{
+		db: db,
+	}
+}
+
+// GetDuplicates returns candidates with status, the most likely duplicates first
+func (repo *bookDuplicateRepository) GetDuplicates(
+	ctx context.Context,
+	status models.BookDuplicateStatus,
+) ([]models.BookDuplicate, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	duplicates := make([]models.BookDuplicate, 0)
+	for _, duplicate := range repo.db.bookDuplicates {
+		if duplicate.Status == status {
+			duplicates = append(duplicates, duplicate)
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].Score != duplicates[j].Score {
+			return duplicates[i].Score > duplicates[j].Score
+		}
+		return duplicates[i].ID < duplicates[j].ID
+	})
+	return duplicates, nil
+}
+
+func (repo *bookDuplicateRepository) GetDuplicateByID(ctx context.Context, id uint) (*models.BookDuplicate, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	duplicate, ok := repo.db.bookDuplicates[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return &duplicate, nil
+}
+
+// SaveDuplicates inserts new candidates and refreshes score of known ones.
+// Status of known candidates is kept, so dismissed pairs are not reviewed again.
+func (repo *bookDuplicateRepository) SaveDuplicates(ctx context.Context, duplicates []models.BookDuplicate) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	byPair := make(map[[2]uint]uint, len(repo.db.bookDuplicates))
+	for id, duplicate := range repo.db.bookDuplicates {
+		byPair[[2]uint{duplicate.BookID, duplicate.DuplicateID}] = id
+	}
+
+	now := repo.db.now()
+	for i := range duplicates {
+		duplicate := &duplicates[i]
+		if id, ok := byPair[[2]uint{duplicate.BookID, duplicate.DuplicateID}]; ok {
+			stored := repo.db.bookDuplicates[id]
+			stored.Score = duplicate.Score
+			stored.Reasons = duplicate.Reasons
+			stored.UpdatedAt = now
+			repo.db.bookDuplicates[id] = stored
+			continue
+		}
+
+		duplicate.ID = nextID(&repo.db.lastBookDuplicateID, duplicate.ID)
+		duplicate.CreatedAt = now
+		duplicate.UpdatedAt = now
+		repo.db.bookDuplicates[duplicate.ID] = *duplicate
+		byPair[[2]uint{duplicate.BookID, duplicate.DuplicateID}] = duplicate.ID
+	}
+	return nil
+}
+
+func (repo *bookDuplicateRepository) UpdateDuplicateStatus(
+	ctx context.Context,
+	id uint,
+	status models.BookDuplicateStatus,
+) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	duplicate, ok := repo.db.bookDuplicates[id]
+	if !ok {
+		return models.ErrNotFound
+	}
+	duplicate.Status = status
+	duplicate.UpdatedAt = repo.db.now()
+	repo.db.bookDuplicates[id] = duplicate
+	return nil
+}
+
+// MergeBooks stores survivor, soft deletes loser and redirects loser ID to survivor at once
+func (repo *bookDuplicateRepository) MergeBooks(ctx context.Context, survivor *models.Book, loser *models.Book) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	storedLoser, ok := repo.db.books[loser.ID]
+	if !ok || storedLoser.DeletedAt.Valid {
+		return models.ErrNotFound
+	}
+
+	now := repo.db.now()
+	if storedSurvivor, ok := repo.db.books[survivor.ID]; ok {
+		storedSurvivor.Name = survivor.Name
+		storedSurvivor.ISBN = survivor.ISBN
+		storedSurvivor.UpdatedAt = now
+		repo.db.books[survivor.ID] = storedSurvivor
+		survivor.UpdatedAt = now
+	}
+
+	storedLoser.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	repo.db.books[loser.ID] = storedLoser
+
+	// books merged into loser before now resolve to survivor directly
+	for id, redirect := range repo.db.bookRedirects {
+		if redirect.ToID == loser.ID {
+			redirect.ToID = survivor.ID
+			repo.db.bookRedirects[id] = redirect
+		}
+	}
+	repo.db.bookRedirects[loser.ID] = models.BookRedirect{FromID: loser.ID, ToID: survivor.ID, CreatedAt: now}
+
+	for id, duplicate := range repo.db.bookDuplicates {
+		pair := map[uint]bool{duplicate.BookID: true, duplicate.DuplicateID: true}
+		switch {
+		case pair[survivor.ID] && pair[loser.ID]:
+			duplicate.Status = models.BookDuplicateMerged
+			duplicate.UpdatedAt = now
+			repo.db.bookDuplicates[id] = duplicate
+		case pair[loser.ID] && duplicate.Status == models.BookDuplicatePending:
+			// other pending pairs of loser are found again against survivor by next detection
+			delete(repo.db.bookDuplicates, id)
+		}
+	}
+	return nil
+}
+
+// GetRedirect returns ID of the book merged book was merged into
+func (repo *bookDuplicateRepository) GetRedirect(ctx context.Context, id uint) (uint, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	redirect, ok := repo.db.bookRedirects[id]
+	if !ok {
+		return 0, models.ErrNotFound
+	}
+	return redirect.ToID, nil
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"book-management-system/entities/models"
+)
+
+func TestMergeBooks(t *testing.T) {
+	ctx := context.TODO()
+	db := NewDB()
+	bookRepo := NewBookRepository(db)
+	repo := NewBookDuplicateRepository(db)
+
+	books := models.Books{
+		{Name: "The Alchemist", ISBN: "9780062315007"},
+		{Name: "The Alchemist."},
+		{Name: "Alchemist, The"},
+	}
+	if err := bookRepo.CreateBooks(ctx, books); err != nil {
+		t.Fatalf("CreateBooks() got error %v", err)
+	}
+	if err := repo.SaveDuplicates(ctx, []models.BookDuplicate{
+		{BookID: 1, DuplicateID: 2, Score: 0.9, Status: models.BookDuplicatePending},
+		{BookID: 2, DuplicateID: 3, Score: 0.8, Status: models.BookDuplicatePending},
+	}); err != nil {
+		t.Fatalf("SaveDuplicates() got error %v", err)
+	}
+
+	if err := repo.MergeBooks(ctx, &books[0], &books[1]); err != nil {
+		t.Fatalf("MergeBooks() got error %v", err)
+	}
+	if err := repo.MergeBooks(ctx, &books[0], &books[1]); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("MergeBooks() of merged book got error %v\n expected %v", err, models.ErrNotFound)
+	}
+	if err := repo.MergeBooks(ctx, &books[2], &books[0]); err != nil {
+		t.Fatalf("MergeBooks() into third book got error %v", err)
+	}
+
+	for _, id := range []uint{books[0].ID, books[1].ID} {
+		toID, err := repo.GetRedirect(ctx, id)
+		if err != nil || toID != books[2].ID {
+			t.Errorf("GetRedirect(%d) got %d, error %v\n expected %d", id, toID, err, books[2].ID)
+		}
+		if _, err := bookRepo.GetBookByID(ctx, id); !errors.Is(err, models.ErrNotFound) {
+			t.Errorf("GetBookByID(%d) of merged book got error %v\n expected %v", id, err, models.ErrNotFound)
+		}
+	}
+
+	merged, _ := repo.GetDuplicates(ctx, models.BookDuplicateMerged)
+	if len(merged) != 1 || merged[0].BookID != 1 || merged[0].DuplicateID != 2 {
+		t.Errorf("GetDuplicates() got merged %+v\n expected pair 1-2", merged)
+	}
+	pending, _ := repo.GetDuplicates(ctx, models.BookDuplicatePending)
+	if len(pending) != 0 {
+		t.Errorf("GetDuplicates() got pending %+v\n expected none", pending)
+	}
+}
@@ -0,0 +1,210 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+	"book-management-system/repositories/elasticsearch"
+)
+
+// bookIndex is inverted index of book names, standing in for ElasticSearch
+type bookIndex struct {
+	mu    sync.RWMutex
+	books map[uint]models.Book
+	// terms maps name term to IDs of books having it
+	terms map[string]map[uint]bool
+}
+
+// NewBookIndex returns new elasticsearch.BookRepository searching books in memory
+func NewBookIndex() elasticsearch.BookRepository {
+	return &bookIndex{
+		books: make(map[uint]models.Book),
+		terms: make(map[string]map[uint]bool),
+	}
+}
+
+func (index *bookIndex) IndexBook(ctx context.Context, book *models.Book) error {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	index.add(book)
+	return nil
+}
+
+// BulkIndexBooks indexes books at once
+func (index *bookIndex) BulkIndexBooks(ctx context.Context, books models.Books) error {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	for i := range books {
+		index.add(&books[i])
+	}
+	return nil
+}
+
+// SearchBook returns books matching any term of keyword, books matching more terms first.
+// Term may be restricted to field as "name:alchemist" or "isbn:9780062315007",
+// keyword which is an ISBN matches the ISBN only.
+func (index *bookIndex) SearchBook(ctx context.Context, keyword string) (models.Books, error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	scores := index.search(keyword)
+	return index.ranked(scores, len(scores)), nil
+}
+
+// SearchBookIDs returns IDs of all books SearchBook matches in ascending order
+func (index *bookIndex) SearchBookIDs(ctx context.Context, keyword string) ([]uint, error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	ids := make([]uint, 0)
+	for id := range index.search(keyword) {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// search returns number of keyword terms matched by each matching book, caller holds the lock
+func (index *bookIndex) search(keyword string) map[uint]int {
+	if isbn, err := objects.ParseISBN(keyword); err == nil && !isbn.IsZero() {
+		keyword = "isbn:" + isbn.String()
+	}
+
+	scores := make(map[uint]int)
+	for _, field := range strings.Fields(keyword) {
+		name, value := "", field
+		if i := strings.Index(field, ":"); i > 0 {
+			name, value = strings.ToLower(field[:i]), field[i+1:]
+		}
+
+		if name == "" || name == "isbn" {
+			isbn := objects.NewISBN(value)
+			for id, book := range index.books {
+				if !isbn.IsZero() && book.ISBN == isbn {
+					scores[id]++
+				}
+			}
+		}
+		if name == "" || name == "name" {
+			for _, term := range tokenize(value) {
+				for id := range index.terms[term] {
+					scores[id]++
+				}
+			}
+		}
+	}
+	return scores
+}
+
+// SearchSimilarBooks returns up to size books sharing at least half of the book name terms,
+// the book itself excluded
+func (index *bookIndex) SearchSimilarBooks(ctx context.Context, book *models.Book, size int) (models.Books, error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	name := book.Name
+	if indexed, ok := index.books[book.ID]; ok {
+		name = indexed.Name
+	}
+	terms := uniqueTerms(name)
+
+	scores := make(map[uint]int)
+	for _, term := range terms {
+		for id := range index.terms[term] {
+			if id != book.ID {
+				scores[id]++
+			}
+		}
+	}
+	for id, score := range scores {
+		if 2*score < len(terms) {
+			delete(scores, id)
+		}
+	}
+
+	return index.ranked(scores, size), nil
+}
+
+// DeleteBook removes book from index, missing book is not an error
+func (index *bookIndex) DeleteBook(ctx context.Context, id uint) error {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	index.remove(id)
+	return nil
+}
+
+// add replaces indexed book, caller holds the lock
+func (index *bookIndex) add(book *models.Book) {
+	index.remove(book.ID)
+
+	index.books[book.ID] = *book
+	for _, term := range uniqueTerms(book.Name) {
+		if index.terms[term] == nil {
+			index.terms[term] = make(map[uint]bool)
+		}
+		index.terms[term][book.ID] = true
+	}
+}
+
+// remove deletes book and its terms, caller holds the lock
+func (index *bookIndex) remove(id uint) {
+	book, ok := index.books[id]
+	if !ok {
+		return
+	}
+
+	for _, term := range uniqueTerms(book.Name) {
+		delete(index.terms[term], id)
+		if len(index.terms[term]) == 0 {
+			delete(index.terms, term)
+		}
+	}
+	delete(index.books, id)
+}
+
+// ranked returns up to size books of scores, highest score then lowest ID first
+func (index *bookIndex) ranked(scores map[uint]int, size int) models.Books {
+	ids := make([]uint, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+
+	books := make(models.Books, 0, len(ids))
+	for i := 0; i < len(ids) && i < size; i++ {
+		books = append(books, index.books[ids[i]])
+	}
+	return books
+}
+
+// tokenize splits text into lowercase words like ElasticSearch standard analyzer
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func uniqueTerms(text string) []string {
+	seen := make(map[string]bool)
+	terms := make([]string, 0)
+	for _, term := range tokenize(text) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"book-management-system/entities/models"
+)
+
+func bookIDs(books models.Books) []uint {
+	ids := make([]uint, 0, len(books))
+	for _, book := range books {
+		ids = append(ids, book.ID)
+	}
+	return ids
+}
+
+func newTestIndex(t *testing.T) *bookIndex {
+	index := NewBookIndex().(*bookIndex)
+	books := models.Books{
+		{Name: "The Alchemist", ISBN: "9780062315007"},
+		{Name: "The Alchemist: A Fable"},
+		{Name: "The Hobbit", ISBN: "9780261103283"},
+		{Name: "The Republic"},
+	}
+	for i := range books {
+		books[i].ID = uint(i + 1)
+	}
+	if err := index.BulkIndexBooks(context.TODO(), books); err != nil {
+		t.Fatal(err)
+	}
+	return index
+}
+
+func TestSearchBook(t *testing.T) {
+	index := newTestIndex(t)
+
+	tests := []struct {
+		name    string
+		keyword string
+		output  []uint
+	}{
+		{name: "single term", keyword: "alchemist", output: []uint{1, 2}},
+		{name: "more terms matched first", keyword: "fable alchemist", output: []uint{2, 1}},
+		{name: "isbn keyword", keyword: "978-0-261-10328-3", output: []uint{3}},
+		{name: "name field", keyword: "name:republic", output: []uint{4}},
+		{name: "isbn field", keyword: "isbn:9780062315007", output: []uint{1}},
+		{name: "no match", keyword: "dune", output: []uint{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			books, err := index.SearchBook(context.TODO(), test.keyword)
+			if ids := bookIDs(books); err != nil || !reflect.DeepEqual(ids, test.output) {
+				t.Errorf("SearchBook() got %v, error %v\n expected %v", ids, err, test.output)
+			}
+		})
+	}
+}
+
+func TestSearchSimilarBooks(t *testing.T) {
+	ctx := context.TODO()
+	index := newTestIndex(t)
+
+	books, err := index.SearchSimilarBooks(ctx, &models.Book{Name: "Alchemist"}, 10)
+	if ids := bookIDs(books); err != nil || !reflect.DeepEqual(ids, []uint{1, 2}) {
+		t.Errorf("SearchSimilarBooks() got %v, error %v\n expected [1 2]", ids, err)
+	}
+
+	book := models.Book{Name: "The Alchemist"}
+	book.ID = 1
+	books, err = index.SearchSimilarBooks(ctx, &book, 10)
+	if ids := bookIDs(books); err != nil || !reflect.DeepEqual(ids, []uint{2, 3, 4}) {
+		t.Errorf("SearchSimilarBooks() got %v, error %v\n expected [2 3 4]", ids, err)
+	}
+
+	if err := index.DeleteBook(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	books, _ = index.SearchBook(ctx, "alchemist")
+	if ids := bookIDs(books); !reflect.DeepEqual(ids, []uint{1}) {
+		t.Errorf("SearchBook() after DeleteBook() got %v\n expected [1]", ids)
+	}
+	if _, ok := index.terms["fable"]; ok {
+		t.Errorf("DeleteBook() left term of deleted book in index")
+	}
+}
+
+func TestSearchBookIDs(t *testing.T) {
+	index := NewBookIndex()
+	books := make(models.Books, 0, 25)
+	expected := make([]uint, 0, 25)
+	for id := uint(1); id <= 25; id++ {
+		book := models.Book{Name: "The Alchemist"}
+		book.ID = id
+		books = append(books, book)
+		expected = append(expected, id)
+	}
+	books = append(books, models.Book{Name: "The Hobbit"})
+	books[len(books)-1].ID = 26
+	if err := index.BulkIndexBooks(context.TODO(), books); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := index.SearchBookIDs(context.TODO(), "alchemist")
+	if err != nil || !reflect.DeepEqual(ids, expected) {
+		t.Errorf("SearchBookIDs() got %v, error %v\n expected %v", ids, err, expected)
+	}
+}
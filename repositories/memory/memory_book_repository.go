@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+	"book-management-system/repositories/mysql"
+)
+
+type bookRepository struct {
+	db *DB
+}
+
+// NewBookRepository returns new mysql.BookRepository keeping books in db
+func NewBookRepository(db *DB) mysql.BookRepository {
+	return &bookRepository{
+		db: db,
+	}
+}
+
+func (repo *bookRepository) GetAll(ctx context.Context) (models.Books, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	return repo.findBooks(func(*models.Book) bool { return true }), nil
+}
+
+func (repo *bookRepository) GetBookByID(ctx context.Context, id uint) (*models.Book, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	book, ok := repo.db.books[id]
+	if !ok || book.DeletedAt.Valid {
+		return nil, models.ErrNotFound
+	}
+	return &book, nil
+}
+
+func (repo *bookRepository) GetBookByISBN(ctx context.Context, isbn objects.ISBN) (*models.Book, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	books := repo.findBooks(func(book *models.Book) bool { return book.ISBN == isbn })
+	if len(books) == 0 {
+		return nil, models.ErrNotFound
+	}
+	return &books[0], nil
+}
+
+func (repo *bookRepository) GetBooksByISBNs(ctx context.Context, isbns []objects.ISBN) (models.Books, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	wanted := make(map[objects.ISBN]bool, len(isbns))
+	for _, isbn := range isbns {
+		wanted[isbn] = true
+	}
+	return repo.findBooks(func(book *models.Book) bool { return wanted[book.ISBN] }), nil
+}
+
+// GetBooksAfterID returns up to limit books with ID greater than afterID ordered by ID,
+// restricted to ids when ids is not nil
+func (repo *bookRepository) GetBooksAfterID(ctx context.Context, afterID uint, limit int, ids []uint) (models.Books, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	var wanted map[uint]bool
+	if ids != nil {
+		wanted = make(map[uint]bool, len(ids))
+		for _, id := range ids {
+			wanted[id] = true
+		}
+	}
+
+	books := repo.findBooks(func(book *models.Book) bool {
+		return book.ID > afterID && (wanted == nil || wanted[book.ID])
+	})
+	if len(books) > limit {
+		books = books[:limit]
+	}
+	return books, nil
+}
+
+func (repo *bookRepository) CreateBook(ctx context.Context, book *models.Book) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	repo.insertBook(book)
+	return nil
+}
+
+// CreateBooks inserts all books at once
+func (repo *bookRepository) CreateBooks(ctx context.Context, books models.Books) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	for i := range books {
+		repo.insertBook(&books[i])
+	}
+	return nil
+}
+
+// UpdateBook replaces all fields of book, including zero values
+func (repo *bookRepository) UpdateBook(ctx context.Context, book *models.Book) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	stored, ok := repo.db.books[book.ID]
+	if !ok || stored.DeletedAt.Valid {
+		return models.ErrNotFound
+	}
+
+	book.CreatedAt = stored.CreatedAt
+	book.UpdatedAt = repo.db.now()
+	book.DeletedAt = stored.DeletedAt
+	repo.db.books[book.ID] = *book
+	return nil
+}
+
+func (repo *bookRepository) insertBook(book *models.Book) {
+	now := repo.db.now()
+	book.ID = nextID(&repo.db.lastBookID, book.ID)
+	if book.CreatedAt.IsZero() {
+		book.CreatedAt = now
+	}
+	if book.UpdatedAt.IsZero() {
+		book.UpdatedAt = now
+	}
+	repo.db.books[book.ID] = *book
+}
+
+// findBooks returns books not deleted which match ordered by ID, caller holds the lock
+func (repo *bookRepository) findBooks(match func(*models.Book) bool) models.Books {
+	books := make(models.Books, 0)
+	for _, book := range repo.db.books {
+		book := book
+		if !book.DeletedAt.Valid && match(&book) {
+			books = append(books, book)
+		}
+	}
+	sort.Slice(books, func(i, j int) bool { return books[i].ID < books[j].ID })
+	return books
+}
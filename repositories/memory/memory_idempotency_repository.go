@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"book-management-system/entities/models"
+	"book-management-system/repositories/mysql"
+)
+
+type idempotencyRepository struct {
+	db *DB
+}
+
+// NewIdempotencyRepository returns new mysql.IdempotencyRepository keeping keys in db
+func NewIdempotencyRepository(db *DB) mysql.IdempotencyRepository {
+	return &idempotencyRepository{
+		db: db,
+	}
+}
+
+// CreateKey inserts key and reports false when the key already exists
+func (repo *idempotencyRepository) CreateKey(ctx context.Context, key *models.IdempotencyKey) (bool, error) {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	if _, ok := repo.db.idempotencyKeys[key.Key]; ok {
+		return false, nil
+	}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = repo.db.now()
+	}
+	repo.db.idempotencyKeys[key.Key] = *key
+	return true, nil
+}
+
+func (repo *idempotencyRepository) GetKey(ctx context.Context, key string) (*models.IdempotencyKey, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	idempotencyKey, ok := repo.db.idempotencyKeys[key]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return &idempotencyKey, nil
+}
+
+// ExtendKey stores lock of key still in progress and reports false when key is no longer reserved by its token
+func (repo *idempotencyRepository) ExtendKey(ctx context.Context, key *models.IdempotencyKey) (bool, error) {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	stored, ok := repo.db.idempotencyKeys[key.Key]
+	if !ok || stored.Token != key.Token || stored.Completed {
+		return false, nil
+	}
+	stored.LockedUntil = key.LockedUntil
+	repo.db.idempotencyKeys[key.Key] = stored
+	return true, nil
+}
+
+// CompleteKey stores response of key unless the key is no longer reserved by its token
+func (repo *idempotencyRepository) CompleteKey(ctx context.Context, key *models.IdempotencyKey) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	stored, ok := repo.db.idempotencyKeys[key.Key]
+	if !ok || stored.Token != key.Token {
+		return nil
+	}
+	stored.Completed = key.Completed
+	stored.StatusCode = key.StatusCode
+	stored.ResponseBody = key.ResponseBody
+	repo.db.idempotencyKeys[key.Key] = stored
+	return nil
+}
+
+// DeleteKey deletes key unless it was completed or reserved by another token since it was read
+func (repo *idempotencyRepository) DeleteKey(ctx context.Context, key *models.IdempotencyKey) (bool, error) {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	stored, ok := repo.db.idempotencyKeys[key.Key]
+	if !ok || stored.Token != key.Token || stored.Completed != key.Completed {
+		return false, nil
+	}
+	delete(repo.db.idempotencyKeys, key.Key)
+	return true, nil
+}
+
+func (repo *idempotencyRepository) DeleteExpiredKeys(ctx context.Context, now time.Time) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	for key, idempotencyKey := range repo.db.idempotencyKeys {
+		if !idempotencyKey.ExpiresAt.After(now) {
+			delete(repo.db.idempotencyKeys, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"book-management-system/entities/models"
+	"book-management-system/repositories/mysql"
+)
+
+type importJobRepository struct {
+	db *DB
+}
+
+// NewImportJobRepository returns new mysql.ImportJobRepository keeping jobs in db
+func NewImportJobRepository(db *DB) mysql.ImportJobRepository {
+	return &importJobRepository{
+		db: db,
+	}
+}
+
+func (repo *importJobRepository) GetJobByID(ctx context.Context, id uint) (*models.ImportJob, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	job, ok := repo.db.importJobs[id]
+	if !ok || job.DeletedAt.Valid {
+		return nil, models.ErrNotFound
+	}
+	return &job, nil
+}
+
+func (repo *importJobRepository) CreateJob(ctx context.Context, job *models.ImportJob) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	now := repo.db.now()
+	job.ID = nextID(&repo.db.lastImportJobID, job.ID)
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	repo.db.importJobs[job.ID] = *job
+	return nil
+}
+
+func (repo *importJobRepository) UpdateJob(ctx context.Context, job *models.ImportJob) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	stored, ok := repo.db.importJobs[job.ID]
+	if !ok {
+		return nil
+	}
+	stored.Status = job.Status
+	stored.Processed = job.Processed
+	stored.Error = job.Error
+	stored.Report = job.Report
+	stored.UpdatedAt = repo.db.now()
+	job.UpdatedAt = stored.UpdatedAt
+	repo.db.importJobs[job.ID] = stored
+	return nil
+}
+
+func (repo *importJobRepository) FailStaleJobs(
+	ctx context.Context,
+	updatedBefore time.Time,
+	message string,
+) (int64, error) {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	var failed int64
+	now := repo.db.now()
+	for id, job := range repo.db.importJobs {
+		if job.DeletedAt.Valid || !job.UpdatedAt.Before(updatedBefore) ||
+			(job.Status != models.ImportJobPending && job.Status != models.ImportJobRunning) {
+			continue
+		}
+		job.Status = models.ImportJobFailed
+		job.Error = message
+		job.UpdatedAt = now
+		repo.db.importJobs[id] = job
+		failed++
+	}
+	return failed, nil
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"book-management-system/entities/models"
+	"book-management-system/repositories/mysql"
+)
+
+type memberRepository struct {
+	db *DB
+}
+
+// NewMemberRepository returns new mysql.MemberRepository keeping members in db
+func NewMemberRepository(db *DB) mysql.MemberRepository {
+	return &memberRepository{
+		db: db,
+	}
+}
+
+func (repo *memberRepository) GetAll(ctx context.Context) (models.Members, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	members := make(models.Members, 0, len(repo.db.members))
+	for _, member := range repo.db.members {
+		if !member.DeletedAt.Valid {
+			members = append(members, member)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].ID < members[j].ID })
+	return members, nil
+}
+
+func (repo *memberRepository) GetMemberByID(ctx context.Context, id uint) (*models.Member, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	member, ok := repo.db.members[id]
+	if !ok || member.DeletedAt.Valid {
+		return nil, models.ErrNotFound
+	}
+	return &member, nil
+}
+
+func (repo *memberRepository) CreateMember(ctx context.Context, member *models.Member) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	now := repo.db.now()
+	member.ID = nextID(&repo.db.lastMemberID, member.ID)
+	if member.CreatedAt.IsZero() {
+		member.CreatedAt = now
+	}
+	if member.UpdatedAt.IsZero() {
+		member.UpdatedAt = now
+	}
+	repo.db.members[member.ID] = *member
+	return nil
+}
+
+// UpdateMember replaces all fields of member, including zero values
+func (repo *memberRepository) UpdateMember(ctx context.Context, member *models.Member) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	stored, ok := repo.db.members[member.ID]
+	if !ok || stored.DeletedAt.Valid {
+		return models.ErrNotFound
+	}
+
+	member.CreatedAt = stored.CreatedAt
+	member.UpdatedAt = repo.db.now()
+	member.DeletedAt = stored.DeletedAt
+	repo.db.members[member.ID] = *member
+	return nil
+}
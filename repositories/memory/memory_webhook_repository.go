@@ -0,0 +1,215 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+	"book-management-system/repositories/mysql"
+)
+
+type webhookRepository struct {
+	db *DB
+}
+
+// NewWebhookRepository returns new mysql.WebhookRepository keeping webhooks and deliveries in db
+func NewWebhookRepository(db *DB) mysql.WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (repo *webhookRepository) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	webhooks := make([]models.Webhook, 0, len(repo.db.webhooks))
+	for _, webhook := range repo.db.webhooks {
+		if !webhook.DeletedAt.Valid {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].ID < webhooks[j].ID
+	})
+	return webhooks, nil
+}
+
+func (repo *webhookRepository) GetWebhookByID(ctx context.Context, id uint) (*models.Webhook, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	webhook, ok := repo.db.webhooks[id]
+	if !ok || webhook.DeletedAt.Valid {
+		return nil, models.ErrNotFound
+	}
+	return &webhook, nil
+}
+
+func (repo *webhookRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	now := repo.db.now()
+	webhook.ID = nextID(&repo.db.lastWebhookID, webhook.ID)
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+	repo.db.webhooks[webhook.ID] = *webhook
+	return nil
+}
+
+func (repo *webhookRepository) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	stored, ok := repo.db.webhooks[webhook.ID]
+	if !ok || stored.DeletedAt.Valid {
+		return nil
+	}
+	stored.URL = webhook.URL
+	stored.Secret = webhook.Secret
+	stored.EventTypes = webhook.EventTypes
+	stored.Disabled = webhook.Disabled
+	stored.UpdatedAt = repo.db.now()
+	webhook.UpdatedAt = stored.UpdatedAt
+	repo.db.webhooks[webhook.ID] = stored
+	return nil
+}
+
+// DeleteWebhook soft deletes webhook, its deliveries are kept in the log
+func (repo *webhookRepository) DeleteWebhook(ctx context.Context, id uint) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	webhook, ok := repo.db.webhooks[id]
+	if !ok || webhook.DeletedAt.Valid {
+		return models.ErrNotFound
+	}
+	webhook.DeletedAt = gorm.DeletedAt{Time: repo.db.now(), Valid: true}
+	repo.db.webhooks[id] = webhook
+	return nil
+}
+
+// GetDeliveries returns up to limit deliveries newest first, of all webhooks when webhookID is 0
+// and of all statuses when status is empty
+func (repo *webhookRepository) GetDeliveries(
+	ctx context.Context,
+	webhookID uint,
+	status models.WebhookDeliveryStatus,
+	limit int,
+) ([]models.WebhookDelivery, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for _, delivery := range repo.db.deliveries {
+		if (webhookID == 0 || delivery.WebhookID == webhookID) && (status == "" || delivery.Status == status) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].ID > deliveries[j].ID
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (repo *webhookRepository) GetDeliveryByID(ctx context.Context, id uint) (*models.WebhookDelivery, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	delivery, ok := repo.db.deliveries[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return &delivery, nil
+}
+
+// GetDueDeliveries returns up to limit pending deliveries due at now, the longest waiting first
+func (repo *webhookRepository) GetDueDeliveries(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]models.WebhookDelivery, error) {
+	repo.db.mu.RLock()
+	defer repo.db.mu.RUnlock()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for _, delivery := range repo.db.deliveries {
+		if delivery.Status == models.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].NextAttemptAt.Equal(deliveries[j].NextAttemptAt) {
+			return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt)
+		}
+		return deliveries[i].ID < deliveries[j].ID
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (repo *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	now := repo.db.now()
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		delivery.ID = nextID(&repo.db.lastDeliveryID, delivery.ID)
+		delivery.CreatedAt = now
+		delivery.UpdatedAt = now
+		repo.db.deliveries[delivery.ID] = *delivery
+	}
+	return nil
+}
+
+// ClaimDelivery counts new attempt of pending delivery and postpones its next attempt until.
+// It returns false when the delivery was claimed or changed since it was read.
+func (repo *webhookRepository) ClaimDelivery(
+	ctx context.Context,
+	delivery *models.WebhookDelivery,
+	until time.Time,
+) (bool, error) {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	stored, ok := repo.db.deliveries[delivery.ID]
+	if !ok || stored.Status != models.WebhookDeliveryPending || stored.Attempts != delivery.Attempts {
+		return false, nil
+	}
+	stored.Attempts++
+	stored.NextAttemptAt = until
+	stored.UpdatedAt = repo.db.now()
+	repo.db.deliveries[delivery.ID] = stored
+	delivery.Attempts = stored.Attempts
+	delivery.NextAttemptAt = until
+	return true, nil
+}
+
+func (repo *webhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	repo.db.mu.Lock()
+	defer repo.db.mu.Unlock()
+
+	stored, ok := repo.db.deliveries[delivery.ID]
+	if !ok {
+		return nil
+	}
+	stored.Status = delivery.Status
+	stored.NextAttemptAt = delivery.NextAttemptAt
+	stored.Attempts = delivery.Attempts
+	stored.ResponseCode = delivery.ResponseCode
+	stored.Error = delivery.Error
+	stored.UpdatedAt = repo.db.now()
+	delivery.UpdatedAt = stored.UpdatedAt
+	repo.db.deliveries[delivery.ID] = stored
+	return nil
+}
@@ -0,0 +1,217 @@
+// Package migrations applies versioned SQL migrations embedded in the binary.
+// Migrations of every database live in sql/<dialect> as NNNN_name.up.sql and NNNN_name.down.sql files,
+// every statement ends with semicolon at end of line. Up script changing indexed book columns has
+// "-- +reindex" line, search index is rebuilt after it.
+package migrations
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// reindexDirective marks up script after which search index is rebuilt
+const reindexDirective = "-- +reindex"
+
+// SourceDir is directory of migration files relative to repository root, Create writes there by default
+const SourceDir = "repositories/migrations/sql"
+
+//go:embed sql
+var embedded embed.FS
+
+var (
+	fileNameRgx      = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+	migrationNameRgx = regexp.MustCompile(`^[a-z0-9_]+$`)
+)
+
+var (
+	// ErrChecksumMismatch returned when applied migration file was modified afterwards
+	ErrChecksumMismatch = errors.New("applied migration was modified")
+	// ErrMissingMigration returned when applied migration has no file
+	ErrMissingMigration = errors.New("applied migration file is missing")
+	// ErrLocked returned when another process keeps migrating longer than lock timeout
+	ErrLocked = errors.New("migrations are locked by another process")
+	// ErrUnsupportedDialect returned for database without migrations
+	ErrUnsupportedDialect = errors.New("database dialect has no migrations")
+)
+
+// Migration is pair of up and down SQL scripts
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+	// Reindex tells that up script changes indexed book columns
+	Reindex bool
+}
+
+// String returns migration file name without direction and extension
+func (migration Migration) String() string {
+	return fmt.Sprintf("%04d_%s", migration.Version, migration.Name)
+}
+
+// Embedded returns migrations of all dialects embedded in the binary
+func Embedded() fs.FS {
+	source, _ := fs.Sub(embedded, "sql")
+	return source
+}
+
+// Load returns migrations of dialect directory of source ordered by version
+func Load(source fs.FS, dialect string) ([]Migration, error) {
+	entries, err := fs.ReadDir(source, dialect)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDialect, dialect)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		matches := fileNameRgx.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, _ := strconv.ParseInt(matches[1], 10, 64)
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration %d has files %s and %s", version, migration, entry.Name())
+		}
+
+		content, err := fs.ReadFile(source, path.Join(dialect, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if matches[3] == "up" {
+			migration.Up = string(content)
+			migration.Reindex = hasDirective(migration.Up, reindexDirective)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %s needs both up and down file", migration)
+		}
+		sum := sha256.Sum256([]byte(migration.Up))
+		migration.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// NeedsReindex reports whether search index is rebuilt after migrations
+func NeedsReindex(migrations []Migration) bool {
+	for _, migration := range migrations {
+		if migration.Reindex {
+			return true
+		}
+	}
+	return false
+}
+
+// Create writes empty up and down files of new migration to every dialect directory of dir.
+// New migration gets the next version of all dialects, so dialects keep the same versions.
+func Create(dir string, name string) ([]string, error) {
+	if !migrationNameRgx.MatchString(name) {
+		return nil, fmt.Errorf("invalid migration name %q, use lowercase letters, digits and underscores", name)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var dialects []string
+	var lastVersion int64
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dialects = append(dialects, entry.Name())
+
+		migrations, err := Load(os.DirFS(dir), entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if n := len(migrations); n > 0 && migrations[n-1].Version > lastVersion {
+			lastVersion = migrations[n-1].Version
+		}
+	}
+	if len(dialects) == 0 {
+		return nil, fmt.Errorf("no dialect directory in %s", dir)
+	}
+
+	migration := Migration{Version: lastVersion + 1, Name: name}
+	files := make([]string, 0, 2*len(dialects))
+	for _, dialect := range dialects {
+		for _, direction := range []string{"up", "down"} {
+			file := filepath.Join(dir, dialect, fmt.Sprintf("%s.%s.sql", migration, direction))
+			content := fmt.Sprintf("-- %s %s migration, end every statement with semicolon at end of line\n",
+				migration.Name, direction)
+			if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+				return files, err
+			}
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// hasDirective reports whether script has directive line
+func hasDirective(script string, directive string) bool {
+	for _, line := range strings.Split(script, "\n") {
+		if strings.TrimSpace(line) == directive {
+			return true
+		}
+	}
+	return false
+}
+
+// splitStatements splits script into statements ending with semicolon at end of line.
+// Lines starting with -- are comments, drivers run single statement at a time.
+func splitStatements(script string) []string {
+	var statements []string
+	var statement strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		statement.WriteString(line)
+		statement.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(statement.String()))
+			statement.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(statement.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
@@ -0,0 +1,159 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadEmbedded(t *testing.T) {
+	var expected []string
+	for _, dialect := range []string{"mysql", "postgres", "sqlite"} {
+		migrations, err := Load(Embedded(), dialect)
+		if err != nil {
+			t.Fatalf("Load(%s) got error %v", dialect, err)
+		}
+
+		names := make([]string, 0, len(migrations))
+		for _, migration := range migrations {
+			names = append(names, migration.String())
+		}
+		if expected == nil {
+			expected = names
+		}
+		if len(names) == 0 || !reflect.DeepEqual(names, expected) {
+			t.Errorf("Load(%s) got migrations %v\n expected %v, the same in every dialect", dialect, names, expected)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   fstest.MapFS
+		expected []Migration
+		err      string
+	}{
+		{
+			name: "success ordered by version",
+			source: fstest.MapFS{
+				"sqlite/0002_add_index.up.sql":      {Data: []byte("-- +reindex\nCREATE INDEX;")},
+				"sqlite/0002_add_index.down.sql":    {Data: []byte("DROP INDEX;")},
+				"sqlite/0001_create_table.up.sql":   {Data: []byte("CREATE TABLE;")},
+				"sqlite/0001_create_table.down.sql": {Data: []byte("DROP TABLE;")},
+				"sqlite/README.md":                  {Data: []byte("not a migration")},
+			},
+			expected: []Migration{
+				{Version: 1, Name: "create_table", Up: "CREATE TABLE;", Down: "DROP TABLE;"},
+				{Version: 2, Name: "add_index", Up: "-- +reindex\nCREATE INDEX;", Down: "DROP INDEX;", Reindex: true},
+			},
+		},
+		{
+			name:   "error unsupported dialect",
+			source: fstest.MapFS{"mysql/0001_create_table.up.sql": {Data: []byte("CREATE TABLE;")}},
+			err:    ErrUnsupportedDialect.Error(),
+		},
+		{
+			name:   "error invalid file name",
+			source: fstest.MapFS{"sqlite/create_table.sql": {Data: []byte("CREATE TABLE;")}},
+			err:    `invalid migration file name "create_table.sql"`,
+		},
+		{
+			name:   "error missing down file",
+			source: fstest.MapFS{"sqlite/0001_create_table.up.sql": {Data: []byte("CREATE TABLE;")}},
+			err:    "migration 0001_create_table needs both up and down file",
+		},
+		{
+			name: "error different names of version",
+			source: fstest.MapFS{
+				"sqlite/0001_create_table.up.sql":   {Data: []byte("CREATE TABLE;")},
+				"sqlite/0001_create_books.down.sql": {Data: []byte("DROP TABLE;")},
+			},
+			err: "migration 1 has files",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			migrations, err := Load(test.source, "sqlite")
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("Load() got error %v\n expected %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() got error %v", err)
+			}
+
+			for i := range migrations {
+				if len(migrations[i].Checksum) != 64 {
+					t.Errorf("Load() got checksum %q\n expected sha256 hex", migrations[i].Checksum)
+				}
+				migrations[i].Checksum = ""
+			}
+			if !reflect.DeepEqual(migrations, test.expected) {
+				t.Errorf("Load() got %+v\n expected %+v", migrations, test.expected)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	for _, dialect := range []string{"mysql", "sqlite"} {
+		if err := os.Mkdir(filepath.Join(dir, dialect), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{"0001_create_table.up.sql", "0001_create_table.down.sql"} {
+		if err := os.WriteFile(filepath.Join(dir, "mysql", file), []byte("SELECT 1;"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := Create(dir, "add_index")
+	if err != nil {
+		t.Fatalf("Create() got error %v", err)
+	}
+	expected := []string{
+		filepath.Join(dir, "mysql", "0002_add_index.up.sql"),
+		filepath.Join(dir, "mysql", "0002_add_index.down.sql"),
+		filepath.Join(dir, "sqlite", "0002_add_index.up.sql"),
+		filepath.Join(dir, "sqlite", "0002_add_index.down.sql"),
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Create() got files %v\n expected %v", files, expected)
+	}
+	if migrations, err := Load(os.DirFS(dir), "sqlite"); err != nil || len(migrations) != 1 {
+		t.Errorf("Load() of created migration got %+v, error %v", migrations, err)
+	}
+
+	if _, err := Create(dir, "Add Index"); err == nil {
+		t.Errorf("Create() of invalid name got no error")
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- comment
+CREATE TABLE books (
+  id integer
+);
+
+CREATE INDEX idx ON books (id);
+-- trailing comment
+SELECT 1`
+
+	got := splitStatements(script)
+	expected := []string{
+		"CREATE TABLE books (\n  id integer\n);",
+		"CREATE INDEX idx ON books (id);",
+		"SELECT 1",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("splitStatements() got %q\n expected %q", got, expected)
+	}
+}
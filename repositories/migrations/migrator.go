@@ -0,0 +1,311 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// lockName names the lock held while migrating, so replicas can't migrate concurrently
+	lockName = "schema_migrations"
+	// lockTimeout is how long to wait for another process to finish migrating
+	lockTimeout       = time.Minute
+	lockRetryInterval = time.Second
+)
+
+// schemaMigration records applied migration
+type schemaMigration struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// TableName of schemaMigration
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// dialect is database specific part of migrating
+type dialect struct {
+	createTable string
+	// tryLock returns false when another connection holds the lock
+	tryLock func(conn *gorm.DB) (bool, error)
+	unlock  func(conn *gorm.DB) error
+}
+
+var dialects = map[string]dialect{
+	"mysql": {
+		createTable: "CREATE TABLE IF NOT EXISTS `schema_migrations` (" +
+			"`version` bigint NOT NULL PRIMARY KEY, `name` varchar(255) NOT NULL, " +
+			"`checksum` char(64) NOT NULL, `applied_at` datetime(3) NOT NULL) ENGINE=InnoDB",
+		tryLock: func(conn *gorm.DB) (bool, error) {
+			var locked int
+			err := conn.Raw("SELECT COALESCE(GET_LOCK(?, 0), 0)", lockName).Scan(&locked).Error
+			return locked == 1, err
+		},
+		unlock: func(conn *gorm.DB) error {
+			return conn.Exec("SELECT RELEASE_LOCK(?)", lockName).Error
+		},
+	},
+	"postgres": {
+		createTable: `CREATE TABLE IF NOT EXISTS "schema_migrations" (` +
+			`"version" bigint NOT NULL PRIMARY KEY, "name" varchar(255) NOT NULL, ` +
+			`"checksum" char(64) NOT NULL, "applied_at" timestamptz NOT NULL)`,
+		tryLock: func(conn *gorm.DB) (bool, error) {
+			var locked bool
+			err := conn.Raw("SELECT pg_try_advisory_lock(hashtext(?))", lockName).Scan(&locked).Error
+			return locked, err
+		},
+		unlock: func(conn *gorm.DB) error {
+			return conn.Exec("SELECT pg_advisory_unlock(hashtext(?))", lockName).Error
+		},
+	},
+	// SQLite allows single writer and migrations run in write transactions, no lock needed
+	"sqlite": {
+		createTable: "CREATE TABLE IF NOT EXISTS `schema_migrations` (" +
+			"`version` integer NOT NULL PRIMARY KEY, `name` text NOT NULL, " +
+			"`checksum` text NOT NULL, `applied_at` datetime NOT NULL)",
+		tryLock: func(conn *gorm.DB) (bool, error) {
+			return true, nil
+		},
+		unlock: func(conn *gorm.DB) error {
+			return nil
+		},
+	},
+}
+
+// MigrationStatus is migration with its state in database
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	// Modified is true when applied migration file differs from the applied one
+	Modified bool
+	// Missing is true when applied migration has no file
+	Missing bool
+}
+
+// Migrator interface
+type Migrator interface {
+	Up(context.Context) ([]Migration, error)
+	Down(context.Context, int) ([]Migration, error)
+	Status(context.Context) ([]MigrationStatus, error)
+}
+
+type migrator struct {
+	db     *gorm.DB
+	source fs.FS
+}
+
+// NewMigrator returns new Migrator applying migrations of db dialect from source
+func NewMigrator(db *gorm.DB, source fs.FS) Migrator {
+	return &migrator{
+		db:     db,
+		source: source,
+	}
+}
+
+// Up applies pending migrations in version order, each in its own transaction.
+// It fails without applying anything when an applied migration was modified or removed.
+func (m *migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied := make([]Migration, 0)
+	err := m.locked(ctx, func(conn *gorm.DB, migrations []Migration, records map[int64]schemaMigration) error {
+		if err := verify(migrations, records); err != nil {
+			return err
+		}
+
+		for _, migration := range migrations {
+			if _, ok := records[migration.Version]; ok {
+				continue
+			}
+
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := execScript(tx, migration.Up); err != nil {
+					return err
+				}
+				return tx.Create(&schemaMigration{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					Checksum:  migration.Checksum,
+					AppliedAt: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("failed apply migration %s: %w", migration, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back up to steps most recently applied migrations
+func (m *migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	rolledBack := make([]Migration, 0)
+	err := m.locked(ctx, func(conn *gorm.DB, migrations []Migration, records map[int64]schemaMigration) error {
+		if err := verify(migrations, records); err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			migration := migrations[i]
+			if _, ok := records[migration.Version]; !ok {
+				continue
+			}
+
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := execScript(tx, migration.Down); err != nil {
+					return err
+				}
+				return tx.Delete(&schemaMigration{}, migration.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("failed roll back migration %s: %w", migration, err)
+			}
+			rolledBack = append(rolledBack, migration)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Status returns migrations of source and applied migrations without file, in version order
+func (m *migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := Load(m.source, m.db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	db := m.db.WithContext(ctx)
+	records := make(map[int64]schemaMigration)
+	if db.Migrator().HasTable(&schemaMigration{}) {
+		if records, err = getRecords(db); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Migration: migration}
+		if record, ok := records[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = record.AppliedAt
+			status.Modified = record.Checksum != migration.Checksum
+			delete(records, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, record := range records {
+		statuses = append(statuses, MigrationStatus{
+			Migration: Migration{Version: record.Version, Name: record.Name, Checksum: record.Checksum},
+			Applied:   true,
+			AppliedAt: record.AppliedAt,
+			Missing:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+// locked runs fn on single connection holding the migration lock
+func (m *migrator) locked(
+	ctx context.Context,
+	fn func(conn *gorm.DB, migrations []Migration, records map[int64]schemaMigration) error,
+) error {
+	dialect, ok := dialects[m.db.Dialector.Name()]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedDialect, m.db.Dialector.Name())
+	}
+	migrations, err := Load(m.source, m.db.Dialector.Name())
+	if err != nil {
+		return err
+	}
+
+	// locks of MySQL and Postgres belong to connection, so everything runs on the same one
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec(dialect.createTable).Error; err != nil {
+			return err
+		}
+
+		if err := acquireLock(ctx, conn, dialect); err != nil {
+			return err
+		}
+		defer func() {
+			_ = dialect.unlock(conn)
+		}()
+
+		records, err := getRecords(conn)
+		if err != nil {
+			return err
+		}
+		return fn(conn, migrations, records)
+	})
+}
+
+// acquireLock waits for the lock up to lockTimeout
+func acquireLock(ctx context.Context, conn *gorm.DB, dialect dialect) error {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		locked, err := dialect.tryLock(conn)
+		if err != nil || locked {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return ErrLocked
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+func getRecords(db *gorm.DB) (map[int64]schemaMigration, error) {
+	var applied []schemaMigration
+	if err := db.Order("version").Find(&applied).Error; err != nil {
+		return nil, err
+	}
+
+	records := make(map[int64]schemaMigration, len(applied))
+	for _, record := range applied {
+		records[record.Version] = record
+	}
+	return records, nil
+}
+
+// verify returns error when applied migration was modified or has no file
+func verify(migrations []Migration, records map[int64]schemaMigration) error {
+	known := make(map[int64]bool, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		if record, ok := records[migration.Version]; ok && record.Checksum != migration.Checksum {
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, migration)
+		}
+	}
+	for version, record := range records {
+		if !known[version] {
+			return fmt.Errorf("%w: %s", ErrMissingMigration, Migration{Version: version, Name: record.Name})
+		}
+	}
+	return nil
+}
+
+func execScript(tx *gorm.DB, script string) error {
+	for _, statement := range splitStatements(script) {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+	"book-management-system/repositories/mysql"
+	"book-management-system/repositories/sqlite"
+)
+
+func testSource() fstest.MapFS {
+	return fstest.MapFS{
+		"sqlite/0001_create_books.up.sql":   {Data: []byte("CREATE TABLE books (\n  id integer PRIMARY KEY\n);\n")},
+		"sqlite/0001_create_books.down.sql": {Data: []byte("DROP TABLE books;\n")},
+		"sqlite/0002_add_name.up.sql": {Data: []byte(
+			"-- name of book\nALTER TABLE books ADD COLUMN name text;\nCREATE INDEX idx_books_name ON books (name);\n")},
+		"sqlite/0002_add_name.down.sql": {Data: []byte("DROP INDEX idx_books_name;\nALTER TABLE books DROP COLUMN name;\n")},
+	}
+}
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := sqlite.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+	return db
+}
+
+func migrationNames(migrations []Migration) []string {
+	names := make([]string, 0, len(migrations))
+	for _, migration := range migrations {
+		names = append(names, migration.String())
+	}
+	return names
+}
+
+func statusStates(statuses []MigrationStatus) []string {
+	states := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		state := status.String()
+		switch {
+		case status.Missing:
+			state += " missing"
+		case status.Modified:
+			state += " modified"
+		case status.Applied:
+			state += " applied"
+		default:
+			state += " pending"
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.TODO()
+	db := openTestDB(t)
+	source := testSource()
+	migrator := NewMigrator(db, source)
+
+	statuses, err := migrator.Status(ctx)
+	if expected := []string{"0001_create_books pending", "0002_add_name pending"}; err != nil ||
+		!reflect.DeepEqual(statusStates(statuses), expected) {
+		t.Errorf("Status() before Up() got %v, error %v\n expected %v", statusStates(statuses), err, expected)
+	}
+
+	applied, err := migrator.Up(ctx)
+	if expected := []string{"0001_create_books", "0002_add_name"}; err != nil ||
+		!reflect.DeepEqual(migrationNames(applied), expected) {
+		t.Errorf("Up() got %v, error %v\n expected %v", migrationNames(applied), err, expected)
+	}
+	if err := db.Exec("INSERT INTO books (id, name) VALUES (1, 'The Alchemist')").Error; err != nil {
+		t.Errorf("migrated table got error %v", err)
+	}
+
+	applied, err = migrator.Up(ctx)
+	if err != nil || len(applied) != 0 {
+		t.Errorf("Up() again got %v, error %v\n expected nothing applied", migrationNames(applied), err)
+	}
+
+	rolledBack, err := migrator.Down(ctx, 1)
+	if expected := []string{"0002_add_name"}; err != nil || !reflect.DeepEqual(migrationNames(rolledBack), expected) {
+		t.Errorf("Down() got %v, error %v\n expected %v", migrationNames(rolledBack), err, expected)
+	}
+	statuses, err = migrator.Status(ctx)
+	if expected := []string{"0001_create_books applied", "0002_add_name pending"}; err != nil ||
+		!reflect.DeepEqual(statusStates(statuses), expected) {
+		t.Errorf("Status() after Down() got %v, error %v\n expected %v", statusStates(statuses), err, expected)
+	}
+
+	modified := testSource()
+	modified["sqlite/0001_create_books.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE books (id integer);\n")}
+	if _, err := NewMigrator(db, modified).Up(ctx); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Up() of modified migration got error %v\n expected %v", err, ErrChecksumMismatch)
+	}
+	statuses, _ = NewMigrator(db, modified).Status(ctx)
+	if expected := []string{"0001_create_books modified", "0002_add_name pending"}; !reflect.DeepEqual(statusStates(statuses), expected) {
+		t.Errorf("Status() of modified migration got %v\n expected %v", statusStates(statuses), expected)
+	}
+
+	missing := fstest.MapFS{"sqlite/0002_add_name.up.sql": source["sqlite/0002_add_name.up.sql"],
+		"sqlite/0002_add_name.down.sql": source["sqlite/0002_add_name.down.sql"]}
+	if _, err := NewMigrator(db, missing).Down(ctx, 1); !errors.Is(err, ErrMissingMigration) {
+		t.Errorf("Down() of missing migration got error %v\n expected %v", err, ErrMissingMigration)
+	}
+
+	rolledBack, err = migrator.Down(ctx, 5)
+	if expected := []string{"0001_create_books"}; err != nil || !reflect.DeepEqual(migrationNames(rolledBack), expected) {
+		t.Errorf("Down() of all got %v, error %v\n expected %v", migrationNames(rolledBack), err, expected)
+	}
+	if db.Migrator().HasTable("books") {
+		t.Errorf("Down() of all left table books")
+	}
+}
+
+func TestMigratorFailedMigrationRollsBack(t *testing.T) {
+	ctx := context.TODO()
+	db := openTestDB(t)
+	source := testSource()
+	source["sqlite/0002_add_name.up.sql"] = &fstest.MapFile{Data: []byte(
+		"ALTER TABLE books ADD COLUMN name text;\nCREATE INDEX idx ON missing (name);\n")}
+
+	applied, err := NewMigrator(db, source).Up(ctx)
+	if err == nil || !reflect.DeepEqual(migrationNames(applied), []string{"0001_create_books"}) {
+		t.Errorf("Up() got %v, error %v\n expected only first migration applied and error", migrationNames(applied), err)
+	}
+	if db.Migrator().HasColumn("books", "name") {
+		t.Errorf("Up() kept statement of failed migration")
+	}
+}
+
+func TestMigratorUnsupportedDialect(t *testing.T) {
+	migrator := NewMigrator(openTestDB(t), fstest.MapFS{})
+	if _, err := migrator.Up(context.TODO()); !errors.Is(err, ErrUnsupportedDialect) {
+		t.Errorf("Up() got error %v\n expected %v", err, ErrUnsupportedDialect)
+	}
+}
+
+func TestCanonicalizeBookISBNsMigration(t *testing.T) {
+	ctx := context.TODO()
+	db := openTestDB(t)
+	migrator := NewMigrator(db, Embedded())
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// books stored before ISBNs were canonicalized, the backfill runs again after rollback
+	legacy := []struct {
+		stored   string
+		expected string
+	}{
+		{stored: "978-0-06-231500-7", expected: "9780062315007"},
+		{stored: "ISBN 0-261-10328-8", expected: "9780261103283"},
+		{stored: "080442957x", expected: "9780804429573"},
+		{stored: "9780140449136", expected: "9780140449136"},
+		{stored: "0-261-10328-9", expected: "0261103289"},
+		{stored: "unknown", expected: "unknown"},
+	}
+	for i, book := range legacy {
+		if err := db.Exec("INSERT INTO books (id, name, isbn) VALUES (?, 'Book', ?)", i+1, book.stored).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	books := mysql.NewBookRepository(db)
+	if _, err := books.GetBookByISBN(ctx, "9780261103283"); !errors.Is(err, models.ErrNotFound) {
+		t.Fatalf("GetBookByISBN() of legacy ISBN before migration got error %v\n expected %v", err, models.ErrNotFound)
+	}
+
+	rolledBack, err := migrator.Down(ctx, 2)
+	if expected := []string{"0004_add_idempotency_key_locks", "0003_canonicalize_book_isbns"}; err != nil ||
+		!reflect.DeepEqual(migrationNames(rolledBack), expected) {
+		t.Fatalf("Down() got %v, error %v\n expected %v", migrationNames(rolledBack), err, expected)
+	}
+	applied, err := migrator.Up(ctx)
+	if err != nil || !NeedsReindex(applied) {
+		t.Fatalf("Up() got %v, error %v\n expected migration needing reindex", migrationNames(applied), err)
+	}
+
+	for i, book := range legacy {
+		var stored string
+		if err := db.Raw("SELECT isbn FROM books WHERE id = ?", i+1).Scan(&stored).Error; err != nil {
+			t.Fatal(err)
+		}
+		if stored != book.expected {
+			t.Errorf("migration stored ISBN %q as %q\n expected %q", book.stored, stored, book.expected)
+		}
+	}
+	book, err := books.GetBookByISBN(ctx, "9780261103283")
+	if err != nil || book.ID != 2 {
+		t.Errorf("GetBookByISBN() of migrated legacy ISBN got %+v, error %v\n expected book 2", book, err)
+	}
+	found, err := books.GetBooksByISBNs(ctx, []objects.ISBN{"9780062315007", "9780804429573"})
+	if err != nil || len(found) != 2 {
+		t.Errorf("GetBooksByISBNs() of migrated legacy ISBNs got %+v, error %v\n expected books 1 and 3", found, err)
+	}
+}
@@ -0,0 +1,70 @@
+-- Tables as created by gorm AutoMigrate before versioned migrations,
+-- IF NOT EXISTS lets existing databases adopt this migration
+CREATE TABLE IF NOT EXISTS `books` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `name` longtext,
+  `isbn` longtext,
+  PRIMARY KEY (`id`),
+  INDEX `idx_books_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB;
+
+CREATE TABLE IF NOT EXISTS `members` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `name` longtext,
+  PRIMARY KEY (`id`),
+  INDEX `idx_members_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB;
+
+CREATE TABLE IF NOT EXISTS `idempotency_keys` (
+  `key` varchar(255) NOT NULL,
+  `request_hash` varchar(64) NOT NULL,
+  `completed` boolean NOT NULL,
+  `status_code` bigint NOT NULL,
+  `response_body` longblob,
+  `created_at` datetime(3) NOT NULL,
+  `expires_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`key`),
+  INDEX `idx_idempotency_keys_expires_at` (`expires_at`)
+) ENGINE=InnoDB;
+
+CREATE TABLE IF NOT EXISTS `import_jobs` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `status` varchar(16) NOT NULL,
+  `format` varchar(16) NOT NULL,
+  `processed` bigint NOT NULL,
+  `error` longtext,
+  `report` longtext,
+  PRIMARY KEY (`id`),
+  INDEX `idx_import_jobs_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB;
+
+CREATE TABLE IF NOT EXISTS `book_duplicates` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `book_id` bigint unsigned NOT NULL,
+  `duplicate_id` bigint unsigned NOT NULL,
+  `score` double NOT NULL,
+  `reasons` varchar(64) NOT NULL,
+  `status` varchar(16) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_book_duplicate_pair` (`book_id`, `duplicate_id`),
+  INDEX `idx_book_duplicates_status` (`status`)
+) ENGINE=InnoDB;
+
+CREATE TABLE IF NOT EXISTS `book_redirects` (
+  `from_id` bigint unsigned NOT NULL,
+  `to_id` bigint unsigned NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`from_id`),
+  INDEX `idx_book_redirects_to_id` (`to_id`)
+) ENGINE=InnoDB;
@@ -0,0 +1,30 @@
+CREATE TABLE `webhooks` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `url` varchar(2048) NOT NULL,
+  `secret` varchar(128) NOT NULL,
+  `event_types` varchar(255) NOT NULL,
+  `disabled` boolean NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_webhooks_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB;
+
+CREATE TABLE `webhook_deliveries` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `webhook_id` bigint unsigned NOT NULL,
+  `event_id` bigint unsigned NOT NULL,
+  `event_type` varchar(64) NOT NULL,
+  `payload` longtext NOT NULL,
+  `status` varchar(16) NOT NULL,
+  `next_attempt_at` datetime(3) NOT NULL,
+  `attempts` bigint NOT NULL,
+  `response_code` bigint NOT NULL,
+  `error` longtext,
+  PRIMARY KEY (`id`),
+  INDEX `idx_webhook_deliveries_webhook_id` (`webhook_id`),
+  INDEX `idx_webhook_deliveries_due` (`status`, `next_attempt_at`)
+) ENGINE=InnoDB;
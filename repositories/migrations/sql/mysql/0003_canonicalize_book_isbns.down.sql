@@ -0,0 +1 @@
+-- canonical ISBNs are read the same as their legacy forms, so they are kept on rollback
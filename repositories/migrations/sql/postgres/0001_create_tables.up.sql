@@ -0,0 +1,67 @@
+CREATE TABLE IF NOT EXISTS "books" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "name" text,
+  "isbn" text
+);
+CREATE INDEX IF NOT EXISTS "idx_books_deleted_at" ON "books" ("deleted_at");
+
+-- full-text search of book name and ISBN, generated columns need PostgreSQL 12 or newer
+ALTER TABLE "books" ADD COLUMN IF NOT EXISTS "search_vector" tsvector
+  GENERATED ALWAYS AS (to_tsvector('simple', coalesce("name", '') || ' ' || coalesce("isbn", ''))) STORED;
+CREATE INDEX IF NOT EXISTS "idx_books_search_vector" ON "books" USING GIN ("search_vector");
+
+CREATE TABLE IF NOT EXISTS "members" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "name" text
+);
+CREATE INDEX IF NOT EXISTS "idx_members_deleted_at" ON "members" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "idempotency_keys" (
+  "key" varchar(255) PRIMARY KEY,
+  "request_hash" varchar(64) NOT NULL,
+  "completed" boolean NOT NULL,
+  "status_code" bigint NOT NULL,
+  "response_body" bytea,
+  "created_at" timestamptz NOT NULL,
+  "expires_at" timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS "idx_idempotency_keys_expires_at" ON "idempotency_keys" ("expires_at");
+
+CREATE TABLE IF NOT EXISTS "import_jobs" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "status" varchar(16) NOT NULL,
+  "format" varchar(16) NOT NULL,
+  "processed" bigint NOT NULL,
+  "error" text,
+  "report" text
+);
+CREATE INDEX IF NOT EXISTS "idx_import_jobs_deleted_at" ON "import_jobs" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "book_duplicates" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "book_id" bigint NOT NULL,
+  "duplicate_id" bigint NOT NULL,
+  "score" double precision NOT NULL,
+  "reasons" varchar(64) NOT NULL,
+  "status" varchar(16) NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_book_duplicate_pair" ON "book_duplicates" ("book_id", "duplicate_id");
+CREATE INDEX IF NOT EXISTS "idx_book_duplicates_status" ON "book_duplicates" ("status");
+
+CREATE TABLE IF NOT EXISTS "book_redirects" (
+  "from_id" bigint PRIMARY KEY,
+  "to_id" bigint NOT NULL,
+  "created_at" timestamptz
+);
+CREATE INDEX IF NOT EXISTS "idx_book_redirects_to_id" ON "book_redirects" ("to_id");
@@ -0,0 +1,28 @@
+CREATE TABLE "webhooks" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "url" varchar(2048) NOT NULL,
+  "secret" varchar(128) NOT NULL,
+  "event_types" varchar(255) NOT NULL,
+  "disabled" boolean NOT NULL
+);
+CREATE INDEX "idx_webhooks_deleted_at" ON "webhooks" ("deleted_at");
+
+CREATE TABLE "webhook_deliveries" (
+  "id" bigserial PRIMARY KEY,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "webhook_id" bigint NOT NULL,
+  "event_id" bigint NOT NULL,
+  "event_type" varchar(64) NOT NULL,
+  "payload" text NOT NULL,
+  "status" varchar(16) NOT NULL,
+  "next_attempt_at" timestamptz NOT NULL,
+  "attempts" bigint NOT NULL,
+  "response_code" bigint NOT NULL,
+  "error" text
+);
+CREATE INDEX "idx_webhook_deliveries_webhook_id" ON "webhook_deliveries" ("webhook_id");
+CREATE INDEX "idx_webhook_deliveries_due" ON "webhook_deliveries" ("status", "next_attempt_at");
@@ -0,0 +1,6 @@
+-- token tells apart requests reserving the same key, so a stale key is taken over by one request only.
+-- Keys stored before are locked until their creation, so those still in progress count as abandoned.
+ALTER TABLE "idempotency_keys" ADD COLUMN "token" varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE "idempotency_keys" ADD COLUMN "locked_until" timestamptz;
+UPDATE "idempotency_keys" SET "locked_until" = "created_at";
+ALTER TABLE "idempotency_keys" ALTER COLUMN "locked_until" SET NOT NULL;
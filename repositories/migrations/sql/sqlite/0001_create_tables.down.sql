@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS `book_redirects`;
+DROP TABLE IF EXISTS `book_duplicates`;
+DROP TABLE IF EXISTS `import_jobs`;
+DROP TABLE IF EXISTS `idempotency_keys`;
+DROP TABLE IF EXISTS `members`;
+DROP TABLE IF EXISTS `books`;
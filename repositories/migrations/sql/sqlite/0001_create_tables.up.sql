@@ -0,0 +1,62 @@
+CREATE TABLE IF NOT EXISTS `books` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `name` text,
+  `isbn` text
+);
+CREATE INDEX IF NOT EXISTS `idx_books_deleted_at` ON `books` (`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `members` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `name` text
+);
+CREATE INDEX IF NOT EXISTS `idx_members_deleted_at` ON `members` (`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `idempotency_keys` (
+  `key` text PRIMARY KEY,
+  `request_hash` text NOT NULL,
+  `completed` numeric NOT NULL,
+  `status_code` integer NOT NULL,
+  `response_body` blob,
+  `created_at` datetime NOT NULL,
+  `expires_at` datetime NOT NULL
+);
+CREATE INDEX IF NOT EXISTS `idx_idempotency_keys_expires_at` ON `idempotency_keys` (`expires_at`);
+
+CREATE TABLE IF NOT EXISTS `import_jobs` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `status` text NOT NULL,
+  `format` text NOT NULL,
+  `processed` integer NOT NULL,
+  `error` text,
+  `report` text
+);
+CREATE INDEX IF NOT EXISTS `idx_import_jobs_deleted_at` ON `import_jobs` (`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `book_duplicates` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `book_id` integer NOT NULL,
+  `duplicate_id` integer NOT NULL,
+  `score` real NOT NULL,
+  `reasons` text NOT NULL,
+  `status` text NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_book_duplicate_pair` ON `book_duplicates` (`book_id`, `duplicate_id`);
+CREATE INDEX IF NOT EXISTS `idx_book_duplicates_status` ON `book_duplicates` (`status`);
+
+CREATE TABLE IF NOT EXISTS `book_redirects` (
+  `from_id` integer PRIMARY KEY,
+  `to_id` integer NOT NULL,
+  `created_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_book_redirects_to_id` ON `book_redirects` (`to_id`);
@@ -0,0 +1,28 @@
+CREATE TABLE `webhooks` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `url` text NOT NULL,
+  `secret` text NOT NULL,
+  `event_types` text NOT NULL,
+  `disabled` numeric NOT NULL
+);
+CREATE INDEX `idx_webhooks_deleted_at` ON `webhooks` (`deleted_at`);
+
+CREATE TABLE `webhook_deliveries` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `webhook_id` integer NOT NULL,
+  `event_id` integer NOT NULL,
+  `event_type` text NOT NULL,
+  `payload` text NOT NULL,
+  `status` text NOT NULL,
+  `next_attempt_at` datetime NOT NULL,
+  `attempts` integer NOT NULL,
+  `response_code` integer NOT NULL,
+  `error` text
+);
+CREATE INDEX `idx_webhook_deliveries_webhook_id` ON `webhook_deliveries` (`webhook_id`);
+CREATE INDEX `idx_webhook_deliveries_due` ON `webhook_deliveries` (`status`, `next_attempt_at`);
@@ -0,0 +1,32 @@
+-- +reindex
+-- books stored before ISBNs were canonicalized keep hyphens, spaces, "ISBN" label or ISBN-10 form,
+-- while lookups by ISBN compare the canonical ISBN-13 form. Values which do not look like an ISBN are kept as they are.
+
+-- values of 10 or 13 ISBN characters lose label, hyphens and spaces
+UPDATE `books` SET `isbn` = REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(UPPER(TRIM(`isbn`)), 'ISBN-13', ''), 'ISBN-10', ''), 'ISBN', ''), ':', ''), '-', ''), ' ', ''), '‐', ''), '‑', '')
+  WHERE `isbn` <> REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(UPPER(TRIM(`isbn`)), 'ISBN-13', ''), 'ISBN-10', ''), 'ISBN', ''), ':', ''), '-', ''), ' ', ''), '‐', ''), '‑', '')
+  AND (REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(UPPER(TRIM(`isbn`)), 'ISBN-13', ''), 'ISBN-10', ''), 'ISBN', ''), ':', ''), '-', ''), ' ', ''), '‐', ''), '‑', '') GLOB '97[89][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9]'
+    OR REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(UPPER(TRIM(`isbn`)), 'ISBN-13', ''), 'ISBN-10', ''), 'ISBN', ''), ':', ''), '-', ''), ' ', ''), '‐', ''), '‑', '') GLOB '[0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9X]');
+
+-- valid ISBN-10 gets 978 prefix and ISBN-13 check digit, 38 is weighted sum of the prefix
+UPDATE `books` SET `isbn` = '978' || SUBSTR(`isbn`, 1, 9) || CAST((10 - (38
+    + 3 * CAST(SUBSTR(`isbn`, 1, 1) AS INTEGER)
+    + CAST(SUBSTR(`isbn`, 2, 1) AS INTEGER)
+    + 3 * CAST(SUBSTR(`isbn`, 3, 1) AS INTEGER)
+    + CAST(SUBSTR(`isbn`, 4, 1) AS INTEGER)
+    + 3 * CAST(SUBSTR(`isbn`, 5, 1) AS INTEGER)
+    + CAST(SUBSTR(`isbn`, 6, 1) AS INTEGER)
+    + 3 * CAST(SUBSTR(`isbn`, 7, 1) AS INTEGER)
+    + CAST(SUBSTR(`isbn`, 8, 1) AS INTEGER)
+    + 3 * CAST(SUBSTR(`isbn`, 9, 1) AS INTEGER)) % 10) % 10 AS TEXT)
+  WHERE `isbn` GLOB '[0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9X]'
+  AND (10 * CAST(SUBSTR(`isbn`, 1, 1) AS INTEGER)
+    + 9 * CAST(SUBSTR(`isbn`, 2, 1) AS INTEGER)
+    + 8 * CAST(SUBSTR(`isbn`, 3, 1) AS INTEGER)
+    + 7 * CAST(SUBSTR(`isbn`, 4, 1) AS INTEGER)
+    + 6 * CAST(SUBSTR(`isbn`, 5, 1) AS INTEGER)
+    + 5 * CAST(SUBSTR(`isbn`, 6, 1) AS INTEGER)
+    + 4 * CAST(SUBSTR(`isbn`, 7, 1) AS INTEGER)
+    + 3 * CAST(SUBSTR(`isbn`, 8, 1) AS INTEGER)
+    + 2 * CAST(SUBSTR(`isbn`, 9, 1) AS INTEGER)
+    + CASE WHEN SUBSTR(`isbn`, 10, 1) = 'X' THEN 10 ELSE CAST(SUBSTR(`isbn`, 10, 1) AS INTEGER) END) % 11 = 0;
@@ -0,0 +1,6 @@
+-- token tells apart requests reserving the same key, so a stale key is taken over by one request only.
+-- Keys stored before are locked until their creation, so those still in progress count as abandoned.
+-- SQLite cannot add NOT NULL column without constant default, so locked_until stays nullable.
+ALTER TABLE `idempotency_keys` ADD COLUMN `token` text NOT NULL DEFAULT '';
+ALTER TABLE `idempotency_keys` ADD COLUMN `locked_until` datetime;
+UPDATE `idempotency_keys` SET `locked_until` = `created_at`;
@@ -11,7 +11,6 @@ import (
 	"gorm.io/gorm"
 
 	"book-management-system/configs"
-	"book-management-system/entities/models"
 )
 
 var (
@@ -31,17 +30,12 @@ func Init() *gorm.DB {
 			log.Fatalf("failed to connect to mysql database: %s", err)
 		}
 
-		if !cfg.Production {
-			if err = mysqlDB.Set("gorm:table_options", "ENGINE=InnoDB").
-				AutoMigrate(
-					&models.Book{},
-					&models.Member{},
-				); err != nil {
-				log.Fatalf("failed to migrate new model to mysql database: %s", err)
-			}
-		}
-
 		configMySQLConn(cfg.Mysql)
+		configs.OnChange(func(old, new configs.Configs) {
+			if old.Mysql != new.Mysql {
+				configMySQLConn(new.Mysql)
+			}
+		})
 	})
 
 	return mysqlDB
@@ -53,7 +47,7 @@ func getMySQLConnString(cfg configs.MySQLConfig) string {
 		cfg.User, cfg.Pass, cfg.Host, cfg.Port, cfg.Name)
 }
 
-// configMySQLConn configure MySQLConnection settings
+// configMySQLConn configure MySQLConnection settings, pool sizes change on config reload
 func configMySQLConn(cfg configs.MySQLConfig) {
 	db, _ := mysqlDB.DB()
 	db.SetMaxIdleConns(cfg.MaxIdleConn)
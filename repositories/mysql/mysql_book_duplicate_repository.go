@@ -0,0 +1,138 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"book-management-system/entities/models"
+)
+
+// BookDuplicateRepository handle sql query to book_duplicates and book_redirects tables
+type BookDuplicateRepository interface {
+	GetDuplicates(context.Context, models.BookDuplicateStatus) ([]models.BookDuplicate, error)
+	GetDuplicateByID(context.Context, uint) (*models.BookDuplicate, error)
+	SaveDuplicates(context.Context, []models.BookDuplicate) error
+	UpdateDuplicateStatus(context.Context, uint, models.BookDuplicateStatus) error
+	MergeBooks(context.Context, *models.Book, *models.Book) error
+	GetRedirect(context.Context, uint) (uint, error)
+}
+
+type bookDuplicateRepository struct {
+	db *gorm.DB
+}
+
+// NewBookDuplicateRepository returns new BookDuplicateRepository
+func NewBookDuplicateRepository(db *gorm.DB) BookDuplicateRepository {
+	return &bookDuplicateRepository{
+		db: db,
+	}
+}
+
+// GetDuplicates returns candidates with status, the most likely duplicates first
+func (repo *bookDuplicateRepository) GetDuplicates(
+	ctx context.Context,
+	status models.BookDuplicateStatus,
+) ([]models.BookDuplicate, error) {
+	var duplicates []models.BookDuplicate
+
+	query := repo.db.WithContext(ctx).
+		Where("status = ?", status).
+		Order("score DESC").
+		Order("id").
+		Find(&duplicates)
+	return duplicates, query.Error
+}
+
+func (repo *bookDuplicateRepository) GetDuplicateByID(ctx context.Context, id uint) (*models.BookDuplicate, error) {
+	var duplicate models.BookDuplicate
+
+	query := repo.db.WithContext(ctx).
+		First(&duplicate, id)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		return nil, models.ErrNotFound
+	}
+	return &duplicate, query.Error
+}
+
+// SaveDuplicates inserts new candidates and refreshes score of known ones.
+// Status of known candidates is kept, so dismissed pairs are not reviewed again.
+func (repo *bookDuplicateRepository) SaveDuplicates(ctx context.Context, duplicates []models.BookDuplicate) error {
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	query := repo.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "book_id"}, {Name: "duplicate_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"score", "reasons", "updated_at"}),
+		}).
+		Create(&duplicates)
+	return query.Error
+}
+
+func (repo *bookDuplicateRepository) UpdateDuplicateStatus(
+	ctx context.Context,
+	id uint,
+	status models.BookDuplicateStatus,
+) error {
+	query := repo.db.WithContext(ctx).
+		Model(&models.BookDuplicate{ID: id}).
+		Update("status", status)
+	if query.Error == nil && query.RowsAffected == 0 {
+		return models.ErrNotFound
+	}
+	return query.Error
+}
+
+// MergeBooks stores survivor, soft deletes loser and redirects loser ID to survivor in one transaction
+func (repo *bookDuplicateRepository) MergeBooks(ctx context.Context, survivor *models.Book, loser *models.Book) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(survivor).Select("name", "isbn").Updates(survivor).Error; err != nil {
+			return err
+		}
+
+		query := tx.Delete(loser)
+		if query.Error != nil {
+			return query.Error
+		}
+		if query.RowsAffected == 0 {
+			return models.ErrNotFound
+		}
+
+		// books merged into loser before now resolve to survivor directly
+		if err := tx.Model(&models.BookRedirect{}).
+			Where("to_id = ?", loser.ID).
+			Update("to_id", survivor.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.BookRedirect{FromID: loser.ID, ToID: survivor.ID}).Error; err != nil {
+			return err
+		}
+
+		ids := []uint{survivor.ID, loser.ID}
+		if err := tx.Model(&models.BookDuplicate{}).
+			Where("book_id IN ? AND duplicate_id IN ?", ids, ids).
+			Update("status", models.BookDuplicateMerged).Error; err != nil {
+			return err
+		}
+		// other pending pairs of loser are found again against survivor by next detection
+		return tx.
+			Where("status = ? AND (book_id = ? OR duplicate_id = ?)", models.BookDuplicatePending, loser.ID, loser.ID).
+			Delete(&models.BookDuplicate{}).Error
+	})
+}
+
+// GetRedirect returns ID of the book merged book was merged into
+func (repo *bookDuplicateRepository) GetRedirect(ctx context.Context, id uint) (uint, error) {
+	var redirect models.BookRedirect
+
+	query := repo.db.WithContext(ctx).
+		Take(&redirect, "from_id = ?", id)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		return 0, models.ErrNotFound
+	}
+	return redirect.ToID, query.Error
+}
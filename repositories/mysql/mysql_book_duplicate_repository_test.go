@@ -0,0 +1,325 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+)
+
+func TestNewBookDuplicateRepository(t *testing.T) {
+	db := &gorm.DB{}
+
+	got := NewBookDuplicateRepository(db)
+	expected := &bookDuplicateRepository{
+		db: db,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewBookDuplicateRepository returns %+v\n expected %+v",
+			got, expected)
+	}
+}
+
+func TestBookDuplicateRepositoryGetDuplicates(t *testing.T) {
+	type input struct {
+		ctx    context.Context
+		status models.BookDuplicateStatus
+	}
+	type output struct {
+		duplicates []models.BookDuplicate
+		err        error
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     sqlmock.Sqlmock
+	}
+
+	queryRgx := regexp.QuoteMeta("SELECT * FROM `book_duplicates` WHERE status = ? ORDER BY score DESC,id")
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success get pending duplicates",
+			givenInput: input{
+				ctx:    context.TODO(),
+				status: models.BookDuplicatePending,
+			},
+			expectedOutput: output{
+				duplicates: []models.BookDuplicate{
+					{
+						ID:          1,
+						BookID:      1,
+						DuplicateID: 2,
+						Score:       1,
+						Reasons:     models.BookDuplicateReasons{models.BookDuplicateSameISBN, models.BookDuplicateSimilarTitle},
+						Status:      models.BookDuplicatePending,
+					},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.status).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "duplicate_id", "score", "reasons", "status"}).
+						AddRow(1, 1, 2, 1.0, "isbn,title", "pending"))
+			},
+		},
+		{
+			name: "error database",
+			givenInput: input{
+				ctx:    context.TODO(),
+				status: models.BookDuplicatePending,
+			},
+			expectedOutput: output{
+				err: errDatabase,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.status).
+					WillReturnError(conf.expected.err)
+			},
+		},
+	}
+
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	for _, tt := range tests {
+		tt.configureMock(mockConfig{
+			given:    tt.givenInput,
+			expected: tt.expectedOutput,
+			mock:     mock,
+		})
+
+		repo := bookDuplicateRepository{
+			db: dbMock,
+		}
+
+		duplicates, err := repo.GetDuplicates(tt.givenInput.ctx, tt.givenInput.status)
+		if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+			t.Errorf("GetDuplicates() got error: %v\nexpected: %v",
+				err, expectedError)
+		}
+		if expected := tt.expectedOutput.duplicates; expected != nil && !reflect.DeepEqual(duplicates, expected) {
+			t.Errorf("GetDuplicates() got duplicates: %+v\nexpected: %+v",
+				duplicates, expected)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func TestBookDuplicateRepositoryMergeBooks(t *testing.T) {
+	type input struct {
+		ctx      context.Context
+		survivor *models.Book
+		loser    *models.Book
+	}
+	type output struct {
+		err error
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     sqlmock.Sqlmock
+	}
+
+	updateSurvivorRgx := regexp.QuoteMeta("UPDATE `books` SET `updated_at`=?,`name`=?,`isbn`=? " +
+		"WHERE `books`.`deleted_at` IS NULL AND `id` = ?")
+	deleteLoserRgx := regexp.QuoteMeta("UPDATE `books` SET `deleted_at`=? " +
+		"WHERE `books`.`id` = ? AND `books`.`deleted_at` IS NULL")
+	updateRedirectsRgx := regexp.QuoteMeta("UPDATE `book_redirects` SET `to_id`=? WHERE to_id = ?")
+	createRedirectRgx := regexp.QuoteMeta("INSERT INTO `book_redirects` (`from_id`,`to_id`,`created_at`) VALUES (?,?,?)")
+	updateDuplicatesRgx := regexp.QuoteMeta("UPDATE `book_duplicates` SET `status`=?,`updated_at`=? " +
+		"WHERE book_id IN (?,?) AND duplicate_id IN (?,?)")
+	deleteDuplicatesRgx := regexp.QuoteMeta("DELETE FROM `book_duplicates` " +
+		"WHERE status = ? AND (book_id = ? OR duplicate_id = ?)")
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success merge books",
+			givenInput: input{
+				ctx:      context.TODO(),
+				survivor: &models.Book{Model: gorm.Model{ID: 1}, Name: "C++", ISBN: "9780062315007"},
+				loser:    &models.Book{Model: gorm.Model{ID: 2}, Name: "C ++", ISBN: "9780062315007"},
+			},
+			configureMock: func(conf mockConfig) {
+				survivor, loser := conf.given.survivor, conf.given.loser
+				conf.mock.ExpectBegin()
+				conf.mock.ExpectExec(updateSurvivorRgx).
+					WithArgs(AnyTime{}, survivor.Name, survivor.ISBN, survivor.ID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				conf.mock.ExpectExec(deleteLoserRgx).
+					WithArgs(AnyTime{}, loser.ID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				conf.mock.ExpectExec(updateRedirectsRgx).
+					WithArgs(survivor.ID, loser.ID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				conf.mock.ExpectExec(createRedirectRgx).
+					WithArgs(loser.ID, survivor.ID, AnyTime{}).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				conf.mock.ExpectExec(updateDuplicatesRgx).
+					WithArgs(models.BookDuplicateMerged, AnyTime{}, survivor.ID, loser.ID, survivor.ID, loser.ID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				conf.mock.ExpectExec(deleteDuplicatesRgx).
+					WithArgs(models.BookDuplicatePending, loser.ID, loser.ID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				conf.mock.ExpectCommit()
+			},
+		},
+		{
+			name: "loser already deleted",
+			givenInput: input{
+				ctx:      context.TODO(),
+				survivor: &models.Book{Model: gorm.Model{ID: 1}, Name: "C++"},
+				loser:    &models.Book{Model: gorm.Model{ID: 2}, Name: "C ++"},
+			},
+			expectedOutput: output{
+				err: models.ErrNotFound,
+			},
+			configureMock: func(conf mockConfig) {
+				survivor, loser := conf.given.survivor, conf.given.loser
+				conf.mock.ExpectBegin()
+				conf.mock.ExpectExec(updateSurvivorRgx).
+					WithArgs(AnyTime{}, survivor.Name, survivor.ISBN, survivor.ID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				conf.mock.ExpectExec(deleteLoserRgx).
+					WithArgs(AnyTime{}, loser.ID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				conf.mock.ExpectRollback()
+			},
+		},
+	}
+
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	for _, tt := range tests {
+		tt.configureMock(mockConfig{
+			given:    tt.givenInput,
+			expected: tt.expectedOutput,
+			mock:     mock,
+		})
+
+		repo := bookDuplicateRepository{
+			db: dbMock,
+		}
+
+		err := repo.MergeBooks(tt.givenInput.ctx, tt.givenInput.survivor, tt.givenInput.loser)
+		if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+			t.Errorf("MergeBooks() got error: %v\nexpected: %v",
+				err, expectedError)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func TestBookDuplicateRepositoryGetRedirect(t *testing.T) {
+	type input struct {
+		ctx context.Context
+		id  uint
+	}
+	type output struct {
+		toID uint
+		err  error
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     sqlmock.Sqlmock
+	}
+
+	queryRgx := regexp.QuoteMeta("SELECT * FROM `book_redirects` WHERE from_id = ? LIMIT ?")
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success get redirect",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  2,
+			},
+			expectedOutput: output{
+				toID: 1,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.id, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"from_id", "to_id"}).
+						AddRow(conf.given.id, conf.expected.toID))
+			},
+		},
+		{
+			name: "redirect not found",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  2,
+			},
+			expectedOutput: output{
+				err: models.ErrNotFound,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.id, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"from_id", "to_id"}))
+			},
+		},
+	}
+
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	for _, tt := range tests {
+		tt.configureMock(mockConfig{
+			given:    tt.givenInput,
+			expected: tt.expectedOutput,
+			mock:     mock,
+		})
+
+		repo := bookDuplicateRepository{
+			db: dbMock,
+		}
+
+		toID, err := repo.GetRedirect(tt.givenInput.ctx, tt.givenInput.id)
+		if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+			t.Errorf("GetRedirect() got error: %v\nexpected: %v",
+				err, expectedError)
+		}
+		if toID != tt.expectedOutput.toID {
+			t.Errorf("GetRedirect() got ID: %d\nexpected: %d",
+				toID, tt.expectedOutput.toID)
+		}
+	}
+}
@@ -2,16 +2,23 @@ package mysql
 
 import (
 	"context"
+	"errors"
 
 	"gorm.io/gorm"
 
 	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
 )
 
 // BookRepository handle sql query to books table
 type BookRepository interface {
 	GetAll(context.Context) (models.Books, error)
+	GetBookByID(context.Context, uint) (*models.Book, error)
+	GetBookByISBN(context.Context, objects.ISBN) (*models.Book, error)
+	GetBooksByISBNs(context.Context, []objects.ISBN) (models.Books, error)
+	GetBooksAfterID(context.Context, uint, int, []uint) (models.Books, error)
 	CreateBook(context.Context, *models.Book) error
+	CreateBooks(context.Context, models.Books) error
 	UpdateBook(context.Context, *models.Book) error
 }
 
@@ -34,14 +41,81 @@ func (repo *bookRepository) GetAll(ctx context.Context) (models.Books, error) {
 	return books, query.Error
 }
 
+func (repo *bookRepository) GetBookByID(ctx context.Context, id uint) (*models.Book, error) {
+	var book models.Book
+
+	query := repo.db.WithContext(ctx).
+		First(&book, id)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		return nil, models.ErrNotFound
+	}
+	return &book, query.Error
+}
+
+func (repo *bookRepository) GetBookByISBN(ctx context.Context, isbn objects.ISBN) (*models.Book, error) {
+	var book models.Book
+
+	query := repo.db.WithContext(ctx).
+		Where("isbn = ?", isbn).
+		First(&book)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		return nil, models.ErrNotFound
+	}
+	return &book, query.Error
+}
+
+func (repo *bookRepository) GetBooksByISBNs(ctx context.Context, isbns []objects.ISBN) (models.Books, error) {
+	var books models.Books
+
+	query := repo.db.WithContext(ctx).
+		Where("isbn IN ?", isbns).
+		Find(&books)
+	return books, query.Error
+}
+
+// GetBooksAfterID returns up to limit books with ID greater than afterID ordered by ID,
+// restricted to ids when ids is not nil. It allows reading whole table in batches.
+func (repo *bookRepository) GetBooksAfterID(ctx context.Context, afterID uint, limit int, ids []uint) (models.Books, error) {
+	var books models.Books
+
+	query := repo.db.WithContext(ctx).
+		Where("id > ?", afterID)
+	if ids != nil {
+		query = query.Where("id IN ?", ids)
+	}
+	query = query.
+		Order("id").
+		Limit(limit).
+		Find(&books)
+	return books, query.Error
+}
+
 func (repo *bookRepository) CreateBook(ctx context.Context, book *models.Book) error {
 	query := repo.db.WithContext(ctx).
 		Create(book)
 	return query.Error
 }
 
+// CreateBooks inserts books in a single transaction
+func (repo *bookRepository) CreateBooks(ctx context.Context, books models.Books) error {
+	return repo.db.WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			return tx.Create(&books).Error
+		})
+}
+
+// UpdateBook replaces all columns of book, including zero values
 func (repo *bookRepository) UpdateBook(ctx context.Context, book *models.Book) error {
 	query := repo.db.WithContext(ctx).
+		Model(book).
+		Select("*").
+		Omit("id", "created_at", "deleted_at").
 		Updates(book)
-	return query.Error
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return models.ErrNotFound
+	}
+	return nil
 }
@@ -11,6 +11,7 @@ import (
 	"gorm.io/gorm"
 
 	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
 )
 
 func TestNewBookRepository(t *testing.T) {
@@ -131,6 +132,337 @@ func TestBookRepositoryGetAll(t *testing.T) {
 	}
 }
 
+func TestBookRepositoryGetBookByID(t *testing.T) {
+	type input struct {
+		ctx context.Context
+		id  uint
+	}
+	type output struct {
+		book *models.Book
+		err  error
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     sqlmock.Sqlmock
+	}
+
+	queryRgx := regexp.QuoteMeta("SELECT * FROM `books` WHERE `books`.`id` = ? AND `books`.`deleted_at` IS NULL ORDER BY `books`.`id` LIMIT ?")
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success get book by id",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  1,
+			},
+			expectedOutput: output{
+				book: &models.Book{
+					Model: gorm.Model{
+						ID: 1,
+					},
+					Name: "Book",
+					ISBN: "1234",
+				},
+				err: nil,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.id, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "isbn"}).
+						AddRow(conf.expected.book.ID, conf.expected.book.Name, conf.expected.book.ISBN))
+			},
+		},
+		{
+			name: "book not found",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  1,
+			},
+			expectedOutput: output{
+				err: models.ErrNotFound,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.id, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "isbn"}))
+			},
+		},
+		{
+			name: "error database",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  1,
+			},
+			expectedOutput: output{
+				err: errDatabase,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.id, 1).
+					WillReturnError(conf.expected.err)
+			},
+		},
+	}
+
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	for _, tt := range tests {
+		tt.configureMock(mockConfig{
+			given:    tt.givenInput,
+			expected: tt.expectedOutput,
+			mock:     mock,
+		})
+
+		repo := bookRepository{
+			db: dbMock,
+		}
+
+		book, err := repo.GetBookByID(tt.givenInput.ctx, tt.givenInput.id)
+		if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+			t.Errorf("GetBookByID() got error: %v\nexpected: %v",
+				err, expectedError)
+		}
+		if expectedBook := tt.expectedOutput.book; expectedBook != nil && !reflect.DeepEqual(book, expectedBook) {
+			t.Errorf("GetBookByID() got book: %+v\nexpected: %+v",
+				book, expectedBook)
+		}
+	}
+}
+
+func TestBookRepositoryGetBookByISBN(t *testing.T) {
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	queryRgx := regexp.QuoteMeta("SELECT * FROM `books` WHERE isbn = ? AND `books`.`deleted_at` IS NULL ORDER BY `books`.`id` LIMIT ?")
+	mock.ExpectQuery(queryRgx).
+		WithArgs("9780062315007", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "isbn"}).
+			AddRow(1, "Book", "978-0-06-231500-7"))
+	mock.ExpectQuery(queryRgx).
+		WithArgs("9780261103283", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "isbn"}))
+
+	repo := bookRepository{
+		db: dbMock,
+	}
+
+	book, err := repo.GetBookByISBN(context.TODO(), "9780062315007")
+	if err != nil {
+		t.Errorf("GetBookByISBN() got error: %v", err)
+	}
+	if expected := (&models.Book{Model: gorm.Model{ID: 1}, Name: "Book", ISBN: "9780062315007"}); !reflect.DeepEqual(book, expected) {
+		t.Errorf("GetBookByISBN() got book: %+v\nexpected: %+v", book, expected)
+	}
+
+	if _, err := repo.GetBookByISBN(context.TODO(), "9780261103283"); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("GetBookByISBN() got error: %v\nexpected: %v", err, models.ErrNotFound)
+	}
+}
+
+func TestBookRepositoryGetBooksByISBNs(t *testing.T) {
+	type input struct {
+		ctx   context.Context
+		isbns []objects.ISBN
+	}
+	type output struct {
+		books models.Books
+		err   error
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     sqlmock.Sqlmock
+	}
+
+	queryRgx := regexp.QuoteMeta("SELECT * FROM `books` WHERE isbn IN (?,?) AND `books`.`deleted_at` IS NULL")
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success get books by isbns",
+			givenInput: input{
+				ctx:   context.TODO(),
+				isbns: []objects.ISBN{"1234", "5678"},
+			},
+			expectedOutput: output{
+				books: models.Books{
+					{
+						Model: gorm.Model{
+							ID: 1,
+						},
+						Name: "Book",
+						ISBN: "1234",
+					},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				book := conf.expected.books[0]
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.isbns[0], conf.given.isbns[1]).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "isbn"}).
+						AddRow(book.ID, book.Name, book.ISBN))
+			},
+		},
+		{
+			name: "error database",
+			givenInput: input{
+				ctx:   context.TODO(),
+				isbns: []objects.ISBN{"1234", "5678"},
+			},
+			expectedOutput: output{
+				err: errDatabase,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.isbns[0], conf.given.isbns[1]).
+					WillReturnError(conf.expected.err)
+			},
+		},
+	}
+
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	for _, tt := range tests {
+		tt.configureMock(mockConfig{
+			given:    tt.givenInput,
+			expected: tt.expectedOutput,
+			mock:     mock,
+		})
+
+		repo := bookRepository{
+			db: dbMock,
+		}
+
+		books, err := repo.GetBooksByISBNs(tt.givenInput.ctx, tt.givenInput.isbns)
+		if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+			t.Errorf("GetBooksByISBNs() got error: %v\nexpected: %v",
+				err, expectedError)
+		}
+		if expectedBooks := tt.expectedOutput.books; expectedBooks != nil && !reflect.DeepEqual(books, expectedBooks) {
+			t.Errorf("GetBooksByISBNs() got books: %+v\nexpected: %+v",
+				books, expectedBooks)
+		}
+	}
+}
+
+func TestBookRepositoryGetBooksAfterID(t *testing.T) {
+	type input struct {
+		ctx     context.Context
+		afterID uint
+		limit   int
+		ids     []uint
+	}
+	type output struct {
+		books models.Books
+		err   error
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     sqlmock.Sqlmock
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success get books after id",
+			givenInput: input{
+				ctx:     context.TODO(),
+				afterID: 1,
+				limit:   2,
+			},
+			expectedOutput: output{
+				books: models.Books{
+					{
+						Model: gorm.Model{
+							ID: 2,
+						},
+						Name: "Book",
+						ISBN: "1234",
+					},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				book := conf.expected.books[0]
+				conf.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `books` WHERE id > ? AND `books`.`deleted_at` IS NULL ORDER BY id LIMIT ?")).
+					WithArgs(conf.given.afterID, conf.given.limit).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "isbn"}).
+						AddRow(book.ID, book.Name, book.ISBN))
+			},
+		},
+		{
+			name: "error database with ids",
+			givenInput: input{
+				ctx:     context.TODO(),
+				afterID: 0,
+				limit:   2,
+				ids:     []uint{3, 4},
+			},
+			expectedOutput: output{
+				err: errDatabase,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `books` WHERE id > ? AND id IN (?,?) AND `books`.`deleted_at` IS NULL ORDER BY id LIMIT ?")).
+					WithArgs(conf.given.afterID, conf.given.ids[0], conf.given.ids[1], conf.given.limit).
+					WillReturnError(conf.expected.err)
+			},
+		},
+	}
+
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	for _, tt := range tests {
+		tt.configureMock(mockConfig{
+			given:    tt.givenInput,
+			expected: tt.expectedOutput,
+			mock:     mock,
+		})
+
+		repo := bookRepository{
+			db: dbMock,
+		}
+
+		books, err := repo.GetBooksAfterID(tt.givenInput.ctx, tt.givenInput.afterID, tt.givenInput.limit, tt.givenInput.ids)
+		if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+			t.Errorf("GetBooksAfterID() got error: %v\nexpected: %v",
+				err, expectedError)
+		}
+		if expectedBooks := tt.expectedOutput.books; expectedBooks != nil && !reflect.DeepEqual(books, expectedBooks) {
+			t.Errorf("GetBooksAfterID() got books: %+v\nexpected: %+v",
+				books, expectedBooks)
+		}
+	}
+}
+
 func TestBookRepositoryCreateBook(t *testing.T) {
 	type input struct {
 		ctx  context.Context
@@ -227,6 +559,97 @@ func TestBookRepositoryCreateBook(t *testing.T) {
 	}
 }
 
+func TestBookRepositoryCreateBooks(t *testing.T) {
+	type input struct {
+		ctx   context.Context
+		books models.Books
+	}
+	type output struct {
+		err error
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     sqlmock.Sqlmock
+	}
+
+	queryRgx := regexp.QuoteMeta("INSERT INTO `books` (`created_at`,`updated_at`,`deleted_at`,`name`,`isbn`) " +
+		"VALUES (?,?,?,?,?),(?,?,?,?,?)")
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success create books",
+			givenInput: input{
+				ctx: context.TODO(),
+				books: models.Books{
+					{Name: "Book", ISBN: "1234"},
+					{Name: "Other Book", ISBN: "5678"},
+				},
+			},
+			expectedOutput: output{
+				err: nil,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectBegin()
+				conf.mock.ExpectExec(queryRgx).
+					WithArgs(
+						AnyTime{}, AnyTime{}, nil, conf.given.books[0].Name, conf.given.books[0].ISBN,
+						AnyTime{}, AnyTime{}, nil, conf.given.books[1].Name, conf.given.books[1].ISBN,
+					).WillReturnResult(sqlmock.NewResult(1, 2))
+				conf.mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error create books",
+			givenInput: input{
+				ctx: context.TODO(),
+				books: models.Books{
+					{Name: "Book", ISBN: "1234"},
+					{Name: "Other Book", ISBN: "5678"},
+				},
+			},
+			expectedOutput: output{
+				err: errDatabase,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectBegin()
+				conf.mock.ExpectExec(queryRgx).
+					WillReturnError(conf.expected.err)
+				conf.mock.ExpectRollback()
+			},
+		},
+	}
+
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	for _, tt := range tests {
+		tt.configureMock(mockConfig{
+			given:    tt.givenInput,
+			expected: tt.expectedOutput,
+			mock:     mock,
+		})
+
+		repo := bookRepository{
+			db: dbMock,
+		}
+
+		err := repo.CreateBooks(tt.givenInput.ctx, tt.givenInput.books)
+		if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+			t.Errorf("CreateBooks() got error: %v\nexpected: %v",
+				err, expectedError)
+		}
+	}
+}
+
 func TestBookRepositoryUpdateBook(t *testing.T) {
 	type input struct {
 		ctx  context.Context
@@ -241,7 +664,8 @@ func TestBookRepositoryUpdateBook(t *testing.T) {
 		mock     sqlmock.Sqlmock
 	}
 
-	queryRgx := regexp.QuoteMeta("UPDATE `books` SET `updated_at`=?,`name`=?,`isbn`=? WHERE `id` = ?")
+	queryRgx := regexp.QuoteMeta("UPDATE `books` SET `updated_at`=?,`name`=?,`isbn`=? " +
+		"WHERE `books`.`deleted_at` IS NULL AND `id` = ?")
 	errDatabase := errors.New("error")
 
 	tests := []struct {
@@ -304,6 +728,33 @@ func TestBookRepositoryUpdateBook(t *testing.T) {
 				conf.mock.ExpectRollback()
 			},
 		},
+		{
+			name: "book not found",
+			givenInput: input{
+				ctx: context.TODO(),
+				book: &models.Book{
+					Model: gorm.Model{
+						ID: 1,
+					},
+					Name: "Book",
+					ISBN: "1234",
+				},
+			},
+			expectedOutput: output{
+				err: models.ErrNotFound,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectBegin()
+				conf.mock.ExpectExec(queryRgx).
+					WithArgs(
+						AnyTime{},
+						conf.given.book.Name,
+						conf.given.book.ISBN,
+						conf.given.book.ID,
+					).WillReturnResult(sqlmock.NewResult(0, 0))
+				conf.mock.ExpectCommit()
+			},
+		},
 	}
 
 	dbMock, mock, err := setupTestSuite()
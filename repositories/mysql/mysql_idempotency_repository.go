@@ -0,0 +1,98 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"book-management-system/entities/models"
+)
+
+// IdempotencyRepository handle sql query to idempotency_keys table
+type IdempotencyRepository interface {
+	CreateKey(context.Context, *models.IdempotencyKey) (bool, error)
+	GetKey(context.Context, string) (*models.IdempotencyKey, error)
+	ExtendKey(context.Context, *models.IdempotencyKey) (bool, error)
+	CompleteKey(context.Context, *models.IdempotencyKey) error
+	DeleteKey(context.Context, *models.IdempotencyKey) (bool, error)
+	DeleteExpiredKeys(context.Context, time.Time) error
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository returns new IdempotencyRepository
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{
+		db: db,
+	}
+}
+
+// CreateKey inserts key and reports false when the key already exists
+func (repo *idempotencyRepository) CreateKey(ctx context.Context, key *models.IdempotencyKey) (bool, error) {
+	query := repo.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(key)
+	return query.RowsAffected > 0, query.Error
+}
+
+func (repo *idempotencyRepository) GetKey(ctx context.Context, key string) (*models.IdempotencyKey, error) {
+	var idempotencyKey models.IdempotencyKey
+
+	query := repo.db.WithContext(ctx).
+		Where(clause.Eq{Column: clause.Column{Name: "key"}, Value: key}).
+		Take(&idempotencyKey)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		return nil, models.ErrNotFound
+	}
+	return &idempotencyKey, query.Error
+}
+
+// ExtendKey stores lock of key still in progress and reports false when key is no longer reserved by its token
+func (repo *idempotencyRepository) ExtendKey(ctx context.Context, key *models.IdempotencyKey) (bool, error) {
+	query := repo.db.WithContext(ctx).
+		Model(&models.IdempotencyKey{}).
+		Where(reservedBy(key)).
+		Where(clause.Eq{Column: clause.Column{Name: "completed"}, Value: false}).
+		Update("locked_until", key.LockedUntil)
+	return query.RowsAffected > 0, query.Error
+}
+
+// CompleteKey stores response of key unless the key is no longer reserved by its token
+func (repo *idempotencyRepository) CompleteKey(ctx context.Context, key *models.IdempotencyKey) error {
+	query := repo.db.WithContext(ctx).
+		Model(&models.IdempotencyKey{}).
+		Where(reservedBy(key)).
+		Select("completed", "status_code", "response_body").
+		Updates(key)
+	return query.Error
+}
+
+// DeleteKey deletes key unless it was completed or reserved by another token since it was read,
+// and reports false when it was, so that of requests taking over the same stale key only one removes it
+func (repo *idempotencyRepository) DeleteKey(ctx context.Context, key *models.IdempotencyKey) (bool, error) {
+	query := repo.db.WithContext(ctx).
+		Where(reservedBy(key)).
+		Where(clause.Eq{Column: clause.Column{Name: "completed"}, Value: key.Completed}).
+		Delete(&models.IdempotencyKey{})
+	return query.RowsAffected > 0, query.Error
+}
+
+func (repo *idempotencyRepository) DeleteExpiredKeys(ctx context.Context, now time.Time) error {
+	query := repo.db.WithContext(ctx).
+		Where("expires_at <= ?", now).
+		Delete(&models.IdempotencyKey{})
+	return query.Error
+}
+
+// reservedBy matches key reserved by the token of key
+func reservedBy(key *models.IdempotencyKey) clause.Expression {
+	return clause.And(
+		clause.Eq{Column: clause.Column{Name: "key"}, Value: key.Key},
+		clause.Eq{Column: clause.Column{Name: "token"}, Value: key.Token},
+	)
+}
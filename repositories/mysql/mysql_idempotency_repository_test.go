@@ -0,0 +1,333 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+)
+
+func TestNewIdempotencyRepository(t *testing.T) {
+	db := &gorm.DB{}
+
+	got := NewIdempotencyRepository(db)
+	expected := &idempotencyRepository{
+		db: db,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewIdempotencyRepository returns %+v\n expected %+v",
+			got, expected)
+	}
+}
+
+func TestIdempotencyRepositoryCreateKey(t *testing.T) {
+	type input struct {
+		ctx context.Context
+		key *models.IdempotencyKey
+	}
+	type output struct {
+		created bool
+		err     error
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     sqlmock.Sqlmock
+	}
+
+	queryRgx := regexp.QuoteMeta("INSERT INTO `idempotency_keys` " +
+		"(`key`,`token`,`request_hash`,`completed`,`status_code`,`response_body`,`created_at`,`locked_until`,`expires_at`) " +
+		"VALUES (?,?,?,?,?,?,?,?,?) ON DUPLICATE KEY UPDATE `key`=`key`")
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success create key",
+			givenInput: input{
+				ctx: context.TODO(),
+				key: &models.IdempotencyKey{
+					Key:         "key",
+					RequestHash: "hash",
+				},
+			},
+			expectedOutput: output{
+				created: true,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectBegin()
+				conf.mock.ExpectExec(queryRgx).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				conf.mock.ExpectCommit()
+			},
+		},
+		{
+			name: "key already exists",
+			givenInput: input{
+				ctx: context.TODO(),
+				key: &models.IdempotencyKey{
+					Key:         "key",
+					RequestHash: "hash",
+				},
+			},
+			expectedOutput: output{
+				created: false,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectBegin()
+				conf.mock.ExpectExec(queryRgx).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				conf.mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error database",
+			givenInput: input{
+				ctx: context.TODO(),
+				key: &models.IdempotencyKey{
+					Key:         "key",
+					RequestHash: "hash",
+				},
+			},
+			expectedOutput: output{
+				err: errDatabase,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectBegin()
+				conf.mock.ExpectExec(queryRgx).
+					WillReturnError(conf.expected.err)
+				conf.mock.ExpectRollback()
+			},
+		},
+	}
+
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	for _, tt := range tests {
+		tt.configureMock(mockConfig{
+			given:    tt.givenInput,
+			expected: tt.expectedOutput,
+			mock:     mock,
+		})
+
+		repo := idempotencyRepository{
+			db: dbMock,
+		}
+
+		created, err := repo.CreateKey(tt.givenInput.ctx, tt.givenInput.key)
+		if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+			t.Errorf("CreateKey() got error: %v\nexpected: %v",
+				err, expectedError)
+		}
+		if created != tt.expectedOutput.created {
+			t.Errorf("CreateKey() got created: %t\nexpected: %t",
+				created, tt.expectedOutput.created)
+		}
+	}
+}
+
+func TestIdempotencyRepositoryGetKey(t *testing.T) {
+	type input struct {
+		ctx context.Context
+		key string
+	}
+	type output struct {
+		key *models.IdempotencyKey
+		err error
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     sqlmock.Sqlmock
+	}
+
+	queryRgx := regexp.QuoteMeta("SELECT * FROM `idempotency_keys` WHERE `key` = ? LIMIT ?")
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success get key",
+			givenInput: input{
+				ctx: context.TODO(),
+				key: "key",
+			},
+			expectedOutput: output{
+				key: &models.IdempotencyKey{
+					Key:          "key",
+					RequestHash:  "hash",
+					Completed:    true,
+					StatusCode:   201,
+					ResponseBody: []byte(`{"name":"C++"}`),
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				key := conf.expected.key
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.key, 1).
+					WillReturnRows(sqlmock.NewRows([]string{
+						"key", "request_hash", "completed", "status_code", "response_body",
+					}).AddRow(key.Key, key.RequestHash, key.Completed, key.StatusCode, key.ResponseBody))
+			},
+		},
+		{
+			name: "key not found",
+			givenInput: input{
+				ctx: context.TODO(),
+				key: "key",
+			},
+			expectedOutput: output{
+				err: models.ErrNotFound,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.key, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"key"}))
+			},
+		},
+	}
+
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	for _, tt := range tests {
+		tt.configureMock(mockConfig{
+			given:    tt.givenInput,
+			expected: tt.expectedOutput,
+			mock:     mock,
+		})
+
+		repo := idempotencyRepository{
+			db: dbMock,
+		}
+
+		key, err := repo.GetKey(tt.givenInput.ctx, tt.givenInput.key)
+		if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+			t.Errorf("GetKey() got error: %v\nexpected: %v",
+				err, expectedError)
+		}
+		if expectedKey := tt.expectedOutput.key; expectedKey != nil && !reflect.DeepEqual(key, expectedKey) {
+			t.Errorf("GetKey() got key: %+v\nexpected: %+v",
+				key, expectedKey)
+		}
+	}
+}
+
+func TestIdempotencyRepositoryCompleteKey(t *testing.T) {
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	key := &models.IdempotencyKey{
+		Key:          "key",
+		Token:        "token",
+		Completed:    true,
+		StatusCode:   201,
+		ResponseBody: []byte(`{"name":"C++"}`),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `idempotency_keys` SET `completed`=?,`status_code`=?,`response_body`=? "+
+		"WHERE `key` = ? AND `token` = ?")).
+		WithArgs(key.Completed, key.StatusCode, key.ResponseBody, key.Key, key.Token).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := idempotencyRepository{
+		db: dbMock,
+	}
+	if err := repo.CompleteKey(context.TODO(), key); err != nil {
+		t.Errorf("CompleteKey() got error: %v", err)
+	}
+}
+
+func TestIdempotencyRepositoryExtendKey(t *testing.T) {
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	key := &models.IdempotencyKey{Key: "key", Token: "token", LockedUntil: time.Now()}
+	queryRgx := regexp.QuoteMeta("UPDATE `idempotency_keys` SET `locked_until`=? " +
+		"WHERE (`key` = ? AND `token` = ?) AND `completed` = ?")
+	for _, rows := range []int64{1, 0} {
+		mock.ExpectBegin()
+		mock.ExpectExec(queryRgx).
+			WithArgs(key.LockedUntil, key.Key, key.Token, false).
+			WillReturnResult(sqlmock.NewResult(0, rows))
+		mock.ExpectCommit()
+	}
+
+	repo := idempotencyRepository{
+		db: dbMock,
+	}
+	if extended, err := repo.ExtendKey(context.TODO(), key); err != nil || !extended {
+		t.Errorf("ExtendKey() got %t, error: %v\nexpected: true", extended, err)
+	}
+	if extended, err := repo.ExtendKey(context.TODO(), key); err != nil || extended {
+		t.Errorf("ExtendKey() of key taken over got %t, error: %v\nexpected: false", extended, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestIdempotencyRepositoryDeleteKeys(t *testing.T) {
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	key := &models.IdempotencyKey{Key: "key", Token: "token"}
+	queryRgx := regexp.QuoteMeta("DELETE FROM `idempotency_keys` WHERE (`key` = ? AND `token` = ?) AND `completed` = ?")
+	for _, rows := range []int64{1, 0} {
+		mock.ExpectBegin()
+		mock.ExpectExec(queryRgx).
+			WithArgs(key.Key, key.Token, false).
+			WillReturnResult(sqlmock.NewResult(0, rows))
+		mock.ExpectCommit()
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `idempotency_keys` WHERE expires_at <= ?")).
+		WithArgs(AnyTime{}).
+		WillReturnError(errDatabase)
+	mock.ExpectRollback()
+
+	repo := idempotencyRepository{
+		db: dbMock,
+	}
+	if deleted, err := repo.DeleteKey(context.TODO(), key); err != nil || !deleted {
+		t.Errorf("DeleteKey() got %t, error: %v\nexpected: true", deleted, err)
+	}
+	if deleted, err := repo.DeleteKey(context.TODO(), key); err != nil || deleted {
+		t.Errorf("DeleteKey() of key taken over got %t, error: %v\nexpected: false", deleted, err)
+	}
+	if err := repo.DeleteExpiredKeys(context.TODO(), time.Now()); !errors.Is(err, errDatabase) {
+		t.Errorf("DeleteExpiredKeys() got error: %v\nexpected: %v", err, errDatabase)
+	}
+}
@@ -0,0 +1,70 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+)
+
+// ImportJobRepository handle sql query to import_jobs table
+type ImportJobRepository interface {
+	GetJobByID(context.Context, uint) (*models.ImportJob, error)
+	CreateJob(context.Context, *models.ImportJob) error
+	UpdateJob(context.Context, *models.ImportJob) error
+	FailStaleJobs(ctx context.Context, updatedBefore time.Time, message string) (int64, error)
+}
+
+type importJobRepository struct {
+	db *gorm.DB
+}
+
+// NewImportJobRepository returns new ImportJobRepository
+func NewImportJobRepository(db *gorm.DB) ImportJobRepository {
+	return &importJobRepository{
+		db: db,
+	}
+}
+
+func (repo *importJobRepository) GetJobByID(ctx context.Context, id uint) (*models.ImportJob, error) {
+	var job models.ImportJob
+
+	query := repo.db.WithContext(ctx).
+		First(&job, id)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		return nil, models.ErrNotFound
+	}
+	return &job, query.Error
+}
+
+func (repo *importJobRepository) CreateJob(ctx context.Context, job *models.ImportJob) error {
+	query := repo.db.WithContext(ctx).
+		Create(job)
+	return query.Error
+}
+
+func (repo *importJobRepository) UpdateJob(ctx context.Context, job *models.ImportJob) error {
+	query := repo.db.WithContext(ctx).
+		Model(job).
+		Select("status", "processed", "error", "report").
+		Updates(job)
+	return query.Error
+}
+
+// FailStaleJobs marks pending and running jobs not updated since updatedBefore as failed with message
+// and returns number of them
+func (repo *importJobRepository) FailStaleJobs(
+	ctx context.Context,
+	updatedBefore time.Time,
+	message string,
+) (int64, error) {
+	query := repo.db.WithContext(ctx).
+		Model(&models.ImportJob{}).
+		Where("status IN ? AND updated_at < ?",
+			[]models.ImportJobStatus{models.ImportJobPending, models.ImportJobRunning}, updatedBefore).
+		Updates(map[string]interface{}{"status": models.ImportJobFailed, "error": message})
+	return query.RowsAffected, query.Error
+}
@@ -0,0 +1,246 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+)
+
+func TestNewImportJobRepository(t *testing.T) {
+	db := &gorm.DB{}
+
+	got := NewImportJobRepository(db)
+	expected := &importJobRepository{
+		db: db,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewImportJobRepository returns %+v\n expected %+v",
+			got, expected)
+	}
+}
+
+func TestImportJobRepositoryGetJobByID(t *testing.T) {
+	type input struct {
+		ctx context.Context
+		id  uint
+	}
+	type output struct {
+		job *models.ImportJob
+		err error
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     sqlmock.Sqlmock
+	}
+
+	queryRgx := regexp.QuoteMeta("SELECT * FROM `import_jobs` WHERE `import_jobs`.`id` = ? " +
+		"AND `import_jobs`.`deleted_at` IS NULL ORDER BY `import_jobs`.`id` LIMIT ?")
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success get job by id",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  1,
+			},
+			expectedOutput: output{
+				job: &models.ImportJob{
+					Model: gorm.Model{
+						ID: 1,
+					},
+					Status:    models.ImportJobCompleted,
+					Format:    "csv",
+					Processed: 1,
+					Report: models.BookImportReport{
+						Total:   1,
+						Created: 1,
+						Rows: []models.BookImportRow{
+							{Row: 2, Status: models.BookImportRowCreated, BookID: 1},
+						},
+					},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				job := conf.expected.job
+				report, _ := job.Report.Value()
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.id, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status", "format", "processed", "report"}).
+						AddRow(job.ID, job.Status, job.Format, job.Processed, report))
+			},
+		},
+		{
+			name: "job not found",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  1,
+			},
+			expectedOutput: output{
+				err: models.ErrNotFound,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.id, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status"}))
+			},
+		},
+		{
+			name: "error database",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  1,
+			},
+			expectedOutput: output{
+				err: errDatabase,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.id, 1).
+					WillReturnError(conf.expected.err)
+			},
+		},
+	}
+
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	for _, tt := range tests {
+		tt.configureMock(mockConfig{
+			given:    tt.givenInput,
+			expected: tt.expectedOutput,
+			mock:     mock,
+		})
+
+		repo := importJobRepository{
+			db: dbMock,
+		}
+
+		job, err := repo.GetJobByID(tt.givenInput.ctx, tt.givenInput.id)
+		if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+			t.Errorf("GetJobByID() got error: %v\nexpected: %v",
+				err, expectedError)
+		}
+		if expectedJob := tt.expectedOutput.job; expectedJob != nil && !reflect.DeepEqual(job, expectedJob) {
+			t.Errorf("GetJobByID() got job: %+v\nexpected: %+v",
+				job, expectedJob)
+		}
+	}
+}
+
+func TestImportJobRepositoryCreateAndUpdateJob(t *testing.T) {
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	job := &models.ImportJob{
+		Status: models.ImportJobPending,
+		Format: "jsonl",
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `import_jobs` " +
+		"(`created_at`,`updated_at`,`deleted_at`,`status`,`format`,`processed`,`error`,`report`) " +
+		"VALUES (?,?,?,?,?,?,?,?)")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `import_jobs` SET `updated_at`=?,`status`=?,`processed`=?,`error`=?,`report`=? " +
+		"WHERE `import_jobs`.`deleted_at` IS NULL AND `id` = ?")).
+		WillReturnError(errDatabase)
+	mock.ExpectRollback()
+
+	repo := importJobRepository{
+		db: dbMock,
+	}
+	if err := repo.CreateJob(context.TODO(), job); err != nil {
+		t.Errorf("CreateJob() got error: %v", err)
+	}
+	if job.ID != 1 {
+		t.Errorf("CreateJob() got job ID %d\nexpected: 1", job.ID)
+	}
+
+	job.Status = models.ImportJobRunning
+	if err := repo.UpdateJob(context.TODO(), job); !errors.Is(err, errDatabase) {
+		t.Errorf("UpdateJob() got error: %v\nexpected: %v", err, errDatabase)
+	}
+}
+
+func TestImportJobRepositoryFailStaleJobs(t *testing.T) {
+	updatedBefore := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		expectedFailed int64
+		expectedErr    error
+		configureMock  func(sqlmock.Sqlmock)
+	}{
+		{
+			name:           "stale jobs are failed",
+			expectedFailed: 2,
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `import_jobs` SET `error`=?,`status`=?,`updated_at`=? "+
+					"WHERE (status IN (?,?) AND updated_at < ?) AND `import_jobs`.`deleted_at` IS NULL")).
+					WithArgs("interrupted", models.ImportJobFailed, AnyTime{},
+						models.ImportJobPending, models.ImportJobRunning, updatedBefore).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:        "database error",
+			expectedErr: errDatabase,
+			configureMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `import_jobs`")).
+					WillReturnError(errDatabase)
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbMock, mock, err := setupTestSuite()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer closeDB(dbMock)
+			tt.configureMock(mock)
+
+			repo := importJobRepository{
+				db: dbMock,
+			}
+			failed, err := repo.FailStaleJobs(context.TODO(), updatedBefore, "interrupted")
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("FailStaleJobs() got error: %v\nexpected: %v", err, tt.expectedErr)
+			}
+			if failed != tt.expectedFailed {
+				t.Errorf("FailStaleJobs() got %d failed jobs\nexpected: %d", failed, tt.expectedFailed)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"context"
+	"errors"
 
 	"gorm.io/gorm"
 
@@ -11,6 +12,7 @@ import (
 // MemberRepository handle sql query to members table
 type MemberRepository interface {
 	GetAll(context.Context) (models.Members, error)
+	GetMemberByID(context.Context, uint) (*models.Member, error)
 	CreateMember(context.Context, *models.Member) error
 	UpdateMember(context.Context, *models.Member) error
 }
@@ -34,14 +36,35 @@ func (repo *memberRepository) GetAll(ctx context.Context) (models.Members, error
 	return members, query.Error
 }
 
+func (repo *memberRepository) GetMemberByID(ctx context.Context, id uint) (*models.Member, error) {
+	var member models.Member
+
+	query := repo.db.WithContext(ctx).
+		First(&member, id)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		return nil, models.ErrNotFound
+	}
+	return &member, query.Error
+}
+
 func (repo *memberRepository) CreateMember(ctx context.Context, member *models.Member) error {
 	query := repo.db.WithContext(ctx).
 		Create(member)
 	return query.Error
 }
 
+// UpdateMember replaces all columns of member, including zero values
 func (repo *memberRepository) UpdateMember(ctx context.Context, member *models.Member) error {
 	query := repo.db.WithContext(ctx).
+		Model(member).
+		Select("*").
+		Omit("id", "created_at", "deleted_at").
 		Updates(member)
-	return query.Error
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return models.ErrNotFound
+	}
+	return nil
 }
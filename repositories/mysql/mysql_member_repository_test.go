@@ -129,6 +129,112 @@ func TestMemberRepositoryGetAll(t *testing.T) {
 	}
 }
 
+func TestMemberRepositoryGetMemberByID(t *testing.T) {
+	type input struct {
+		ctx context.Context
+		id  uint
+	}
+	type output struct {
+		member *models.Member
+		err    error
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     sqlmock.Sqlmock
+	}
+
+	queryRgx := regexp.QuoteMeta("SELECT * FROM `members` WHERE `members`.`id` = ? AND `members`.`deleted_at` IS NULL ORDER BY `members`.`id` LIMIT ?")
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success get member by id",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  1,
+			},
+			expectedOutput: output{
+				member: &models.Member{
+					Model: gorm.Model{
+						ID: 1,
+					},
+					Name: "Member",
+				},
+				err: nil,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.id, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+						AddRow(conf.expected.member.ID, conf.expected.member.Name))
+			},
+		},
+		{
+			name: "member not found",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  1,
+			},
+			expectedOutput: output{
+				err: models.ErrNotFound,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.id, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+			},
+		},
+		{
+			name: "error database",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  1,
+			},
+			expectedOutput: output{
+				err: errDatabase,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(conf.given.id, 1).
+					WillReturnError(conf.expected.err)
+			},
+		},
+	}
+
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	for _, tt := range tests {
+		tt.configureMock(mockConfig{
+			given:    tt.givenInput,
+			expected: tt.expectedOutput,
+			mock:     mock,
+		})
+
+		repo := memberRepository{
+			db: dbMock,
+		}
+
+		member, err := repo.GetMemberByID(tt.givenInput.ctx, tt.givenInput.id)
+		if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+			t.Errorf("GetMemberByID() got error: %v\nexpected: %v",
+				err, expectedError)
+		}
+		if expectedMember := tt.expectedOutput.member; expectedMember != nil && !reflect.DeepEqual(member, expectedMember) {
+			t.Errorf("GetMemberByID() got member: %+v\nexpected: %+v",
+				member, expectedMember)
+		}
+	}
+}
+
 func TestMemberRepositoryCreateMember(t *testing.T) {
 	type input struct {
 		ctx    context.Context
@@ -234,7 +340,8 @@ func TestMemberRepositoryUpdateMember(t *testing.T) {
 		mock     sqlmock.Sqlmock
 	}
 
-	queryRgx := regexp.QuoteMeta("UPDATE `members` SET `updated_at`=?,`name`=? WHERE `id` = ?")
+	queryRgx := regexp.QuoteMeta("UPDATE `members` SET `updated_at`=?,`name`=? " +
+		"WHERE `members`.`deleted_at` IS NULL AND `id` = ?")
 
 	tests := []struct {
 		name           string
@@ -292,6 +399,31 @@ func TestMemberRepositoryUpdateMember(t *testing.T) {
 				conf.mock.ExpectRollback()
 			},
 		},
+		{
+			name: "member not found",
+			givenInput: input{
+				ctx: context.TODO(),
+				member: &models.Member{
+					Model: gorm.Model{
+						ID: 1,
+					},
+					Name: "Updated Member",
+				},
+			},
+			expectedOutput: output{
+				err: models.ErrNotFound,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectBegin()
+				conf.mock.ExpectExec(queryRgx).
+					WithArgs(
+						AnyTime{},
+						conf.given.member.Name,
+						conf.given.member.ID,
+					).WillReturnResult(sqlmock.NewResult(0, 0))
+				conf.mock.ExpectCommit()
+			},
+		},
 	}
 
 	dbMock, mock, err := setupTestSuite()
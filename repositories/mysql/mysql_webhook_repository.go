@@ -0,0 +1,174 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+)
+
+// WebhookRepository handle sql query to webhooks and webhook_deliveries tables
+type WebhookRepository interface {
+	GetWebhooks(context.Context) ([]models.Webhook, error)
+	GetWebhookByID(context.Context, uint) (*models.Webhook, error)
+	CreateWebhook(context.Context, *models.Webhook) error
+	UpdateWebhook(context.Context, *models.Webhook) error
+	DeleteWebhook(context.Context, uint) error
+	GetDeliveries(context.Context, uint, models.WebhookDeliveryStatus, int) ([]models.WebhookDelivery, error)
+	GetDeliveryByID(context.Context, uint) (*models.WebhookDelivery, error)
+	GetDueDeliveries(context.Context, time.Time, int) ([]models.WebhookDelivery, error)
+	CreateDeliveries(context.Context, []models.WebhookDelivery) error
+	ClaimDelivery(context.Context, *models.WebhookDelivery, time.Time) (bool, error)
+	UpdateDelivery(context.Context, *models.WebhookDelivery) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository returns new WebhookRepository
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (repo *webhookRepository) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+
+	query := repo.db.WithContext(ctx).
+		Order("id").
+		Find(&webhooks)
+	return webhooks, query.Error
+}
+
+func (repo *webhookRepository) GetWebhookByID(ctx context.Context, id uint) (*models.Webhook, error) {
+	var webhook models.Webhook
+
+	query := repo.db.WithContext(ctx).
+		First(&webhook, id)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		return nil, models.ErrNotFound
+	}
+	return &webhook, query.Error
+}
+
+func (repo *webhookRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	query := repo.db.WithContext(ctx).
+		Create(webhook)
+	return query.Error
+}
+
+func (repo *webhookRepository) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	query := repo.db.WithContext(ctx).
+		Model(webhook).
+		Select("url", "secret", "event_types", "disabled").
+		Updates(webhook)
+	return query.Error
+}
+
+// DeleteWebhook soft deletes webhook, its deliveries are kept in the log
+func (repo *webhookRepository) DeleteWebhook(ctx context.Context, id uint) error {
+	query := repo.db.WithContext(ctx).
+		Delete(&models.Webhook{}, id)
+	if query.Error == nil && query.RowsAffected == 0 {
+		return models.ErrNotFound
+	}
+	return query.Error
+}
+
+// GetDeliveries returns up to limit deliveries newest first, of all webhooks when webhookID is 0
+// and of all statuses when status is empty
+func (repo *webhookRepository) GetDeliveries(
+	ctx context.Context,
+	webhookID uint,
+	status models.WebhookDeliveryStatus,
+	limit int,
+) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	query := repo.db.WithContext(ctx)
+	if webhookID != 0 {
+		query = query.Where("webhook_id = ?", webhookID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	query = query.
+		Order("id DESC").
+		Limit(limit).
+		Find(&deliveries)
+	return deliveries, query.Error
+}
+
+func (repo *webhookRepository) GetDeliveryByID(ctx context.Context, id uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+
+	query := repo.db.WithContext(ctx).
+		First(&delivery, id)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		return nil, models.ErrNotFound
+	}
+	return &delivery, query.Error
+}
+
+// GetDueDeliveries returns up to limit pending deliveries due at now, the longest waiting first
+func (repo *webhookRepository) GetDueDeliveries(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	query := repo.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("next_attempt_at").
+		Order("id").
+		Limit(limit).
+		Find(&deliveries)
+	return deliveries, query.Error
+}
+
+func (repo *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	query := repo.db.WithContext(ctx).
+		Create(&deliveries)
+	return query.Error
+}
+
+// ClaimDelivery counts new attempt of pending delivery and postpones its next attempt until,
+// so that other instances do not send it meanwhile. It returns false when the delivery was claimed
+// or changed since it was read.
+func (repo *webhookRepository) ClaimDelivery(
+	ctx context.Context,
+	delivery *models.WebhookDelivery,
+	until time.Time,
+) (bool, error) {
+	query := repo.db.WithContext(ctx).
+		Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND attempts = ?", delivery.ID, models.WebhookDeliveryPending, delivery.Attempts).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": until,
+		})
+	if query.Error != nil || query.RowsAffected == 0 {
+		return false, query.Error
+	}
+	delivery.Attempts++
+	delivery.NextAttemptAt = until
+	return true, nil
+}
+
+func (repo *webhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := repo.db.WithContext(ctx).
+		Model(delivery).
+		Select("status", "next_attempt_at", "attempts", "response_code", "error").
+		Updates(delivery)
+	return query.Error
+}
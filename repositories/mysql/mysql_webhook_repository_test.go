@@ -0,0 +1,356 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+)
+
+func TestNewWebhookRepository(t *testing.T) {
+	db := &gorm.DB{}
+
+	got := NewWebhookRepository(db)
+	expected := &webhookRepository{
+		db: db,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewWebhookRepository returns %+v\n expected %+v",
+			got, expected)
+	}
+}
+
+func TestWebhookRepositoryDeleteWebhook(t *testing.T) {
+	type input struct {
+		ctx context.Context
+		id  uint
+	}
+	type output struct {
+		err error
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     sqlmock.Sqlmock
+	}
+
+	queryRgx := regexp.QuoteMeta("UPDATE `webhooks` SET `deleted_at`=? " +
+		"WHERE `webhooks`.`id` = ? AND `webhooks`.`deleted_at` IS NULL")
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success delete webhook",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  1,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectBegin()
+				conf.mock.ExpectExec(queryRgx).
+					WithArgs(AnyTime{}, conf.given.id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				conf.mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error not found",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  2,
+			},
+			expectedOutput: output{
+				err: models.ErrNotFound,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectBegin()
+				conf.mock.ExpectExec(queryRgx).
+					WithArgs(AnyTime{}, conf.given.id).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				conf.mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error database",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  1,
+			},
+			expectedOutput: output{
+				err: errDatabase,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectBegin()
+				conf.mock.ExpectExec(queryRgx).
+					WithArgs(AnyTime{}, conf.given.id).
+					WillReturnError(conf.expected.err)
+				conf.mock.ExpectRollback()
+			},
+		},
+	}
+
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	for _, tt := range tests {
+		tt.configureMock(mockConfig{
+			given:    tt.givenInput,
+			expected: tt.expectedOutput,
+			mock:     mock,
+		})
+
+		repo := webhookRepository{
+			db: dbMock,
+		}
+
+		err := repo.DeleteWebhook(tt.givenInput.ctx, tt.givenInput.id)
+		if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+			t.Errorf("DeleteWebhook() got error: %v\nexpected: %v",
+				err, expectedError)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func TestWebhookRepositoryGetDueDeliveries(t *testing.T) {
+	type input struct {
+		ctx   context.Context
+		now   time.Time
+		limit int
+	}
+	type output struct {
+		deliveries []models.WebhookDelivery
+		err        error
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     sqlmock.Sqlmock
+	}
+
+	queryRgx := regexp.QuoteMeta("SELECT * FROM `webhook_deliveries` WHERE status = ? AND next_attempt_at <= ? " +
+		"ORDER BY next_attempt_at,id LIMIT ?")
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success get due deliveries",
+			givenInput: input{
+				ctx:   context.TODO(),
+				now:   now,
+				limit: 50,
+			},
+			expectedOutput: output{
+				deliveries: []models.WebhookDelivery{
+					{
+						ID:            1,
+						WebhookID:     2,
+						EventID:       1700000000000001,
+						EventType:     "book.created",
+						Payload:       `{"id":1700000000000001}`,
+						Status:        models.WebhookDeliveryPending,
+						NextAttemptAt: now,
+						Attempts:      1,
+						ResponseCode:  500,
+						Error:         "unexpected response status 500 Internal Server Error",
+					},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				delivery := conf.expected.deliveries[0]
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(models.WebhookDeliveryPending, conf.given.now, conf.given.limit).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "webhook_id", "event_id", "event_type", "payload",
+						"status", "next_attempt_at", "attempts", "response_code", "error"}).
+						AddRow(delivery.ID, delivery.WebhookID, delivery.EventID, delivery.EventType, delivery.Payload,
+							delivery.Status, delivery.NextAttemptAt, delivery.Attempts, delivery.ResponseCode, delivery.Error))
+			},
+		},
+		{
+			name: "error database",
+			givenInput: input{
+				ctx:   context.TODO(),
+				now:   now,
+				limit: 50,
+			},
+			expectedOutput: output{
+				err: errDatabase,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(queryRgx).
+					WithArgs(models.WebhookDeliveryPending, conf.given.now, conf.given.limit).
+					WillReturnError(conf.expected.err)
+			},
+		},
+	}
+
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	for _, tt := range tests {
+		tt.configureMock(mockConfig{
+			given:    tt.givenInput,
+			expected: tt.expectedOutput,
+			mock:     mock,
+		})
+
+		repo := webhookRepository{
+			db: dbMock,
+		}
+
+		deliveries, err := repo.GetDueDeliveries(tt.givenInput.ctx, tt.givenInput.now, tt.givenInput.limit)
+		if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+			t.Errorf("GetDueDeliveries() got error: %v\nexpected: %v",
+				err, expectedError)
+		}
+		if expected := tt.expectedOutput.deliveries; expected != nil && !reflect.DeepEqual(deliveries, expected) {
+			t.Errorf("GetDueDeliveries() got deliveries: %+v\nexpected: %+v",
+				deliveries, expected)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
+
+func TestWebhookRepositoryClaimDelivery(t *testing.T) {
+	type input struct {
+		ctx      context.Context
+		delivery *models.WebhookDelivery
+		until    time.Time
+	}
+	type output struct {
+		claimed  bool
+		attempts int
+		err      error
+	}
+	type mockConfig struct {
+		given    input
+		expected output
+		mock     sqlmock.Sqlmock
+	}
+
+	queryRgx := regexp.QuoteMeta("UPDATE `webhook_deliveries` SET `attempts`=attempts + 1,`next_attempt_at`=?," +
+		"`updated_at`=? WHERE id = ? AND status = ? AND attempts = ?")
+	until := time.Date(2026, 10, 19, 12, 0, 40, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success claim delivery",
+			givenInput: input{
+				ctx:      context.TODO(),
+				delivery: &models.WebhookDelivery{ID: 1, Attempts: 2},
+				until:    until,
+			},
+			expectedOutput: output{
+				claimed:  true,
+				attempts: 3,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectBegin()
+				conf.mock.ExpectExec(queryRgx).
+					WithArgs(conf.given.until, AnyTime{}, conf.given.delivery.ID, models.WebhookDeliveryPending, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				conf.mock.ExpectCommit()
+			},
+		},
+		{
+			name: "success delivery claimed by other instance",
+			givenInput: input{
+				ctx:      context.TODO(),
+				delivery: &models.WebhookDelivery{ID: 1, Attempts: 2},
+				until:    until,
+			},
+			expectedOutput: output{
+				attempts: 2,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectBegin()
+				conf.mock.ExpectExec(queryRgx).
+					WithArgs(conf.given.until, AnyTime{}, conf.given.delivery.ID, models.WebhookDeliveryPending, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				conf.mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error database",
+			givenInput: input{
+				ctx:      context.TODO(),
+				delivery: &models.WebhookDelivery{ID: 1, Attempts: 2},
+				until:    until,
+			},
+			expectedOutput: output{
+				attempts: 2,
+				err:      errDatabase,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectBegin()
+				conf.mock.ExpectExec(queryRgx).
+					WithArgs(conf.given.until, AnyTime{}, conf.given.delivery.ID, models.WebhookDeliveryPending, 2).
+					WillReturnError(conf.expected.err)
+				conf.mock.ExpectRollback()
+			},
+		},
+	}
+
+	dbMock, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(dbMock)
+
+	for _, tt := range tests {
+		tt.configureMock(mockConfig{
+			given:    tt.givenInput,
+			expected: tt.expectedOutput,
+			mock:     mock,
+		})
+
+		repo := webhookRepository{
+			db: dbMock,
+		}
+
+		claimed, err := repo.ClaimDelivery(tt.givenInput.ctx, tt.givenInput.delivery, tt.givenInput.until)
+		if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+			t.Errorf("ClaimDelivery() got error: %v\nexpected: %v",
+				err, expectedError)
+		}
+		if claimed != tt.expectedOutput.claimed || tt.givenInput.delivery.Attempts != tt.expectedOutput.attempts {
+			t.Errorf("ClaimDelivery() got claimed %v with %d attempts\nexpected: %v with %d attempts",
+				claimed, tt.givenInput.delivery.Attempts, tt.expectedOutput.claimed, tt.expectedOutput.attempts)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	}
+}
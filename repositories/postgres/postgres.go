@@ -0,0 +1,67 @@
+// Package postgres opens PostgreSQL database, stores books and members in it
+// and searches books with PostgreSQL full-text search.
+//
+// Book and member repositories bind lists of IDs and ISBNs as one array parameter and insert books
+// in batches, so that exports and imports of any size stay within 65535 bind parameters of a statement.
+// Other records, e.g. idempotency keys, import jobs and webhooks, are stored by the repositories of mysql package,
+// whose queries built by gorm are quoted and numbered in PostgreSQL dialect by the connection.
+package postgres
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"book-management-system/configs"
+)
+
+var (
+	postgresDB *gorm.DB
+	once       sync.Once
+)
+
+// Init returns postgresDB connection instance
+func Init() *gorm.DB {
+	once.Do(func() {
+		cfg := configs.GetConfig()
+		dsn := getPostgresConnString(cfg.Postgres)
+
+		var err error
+		postgresDB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		if err != nil {
+			log.Fatalf("failed to connect to postgres database: %s", err)
+		}
+
+		configPostgresConn(cfg.Postgres)
+		configs.OnChange(func(old, new configs.Configs) {
+			if old.Postgres != new.Postgres {
+				configPostgresConn(new.Postgres)
+			}
+		})
+	})
+
+	return postgresDB
+}
+
+// getPostgresConnString return connection string from config
+func getPostgresConnString(cfg configs.PostgresConfig) string {
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s TimeZone=UTC",
+		cfg.Host, cfg.Port, cfg.User, cfg.Pass, cfg.Name, sslMode)
+}
+
+// configPostgresConn configure PostgresConnection settings
+func configPostgresConn(cfg configs.PostgresConfig) {
+	db, _ := postgresDB.DB()
+	db.SetMaxIdleConns(cfg.MaxIdleConn)
+	db.SetMaxOpenConns(cfg.MaxOpenConn)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Minute)
+}
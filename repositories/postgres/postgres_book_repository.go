@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+	"book-management-system/repositories/mysql"
+)
+
+// createBatchSize is number of books inserted by one statement,
+// their columns stay well within 65535 bind parameters of a PostgreSQL statement
+const createBatchSize = 1000
+
+type bookRepository struct {
+	db *gorm.DB
+}
+
+// NewBookRepository returns new mysql.BookRepository storing books in PostgreSQL books table
+func NewBookRepository(db *gorm.DB) mysql.BookRepository {
+	return &bookRepository{
+		db: db,
+	}
+}
+
+// GetAll returns books ordered by ID, PostgreSQL returns rows in no particular order without ORDER BY
+func (repo *bookRepository) GetAll(ctx context.Context) (models.Books, error) {
+	var books models.Books
+
+	query := repo.db.WithContext(ctx).
+		Order("id").
+		Find(&books)
+	return books, query.Error
+}
+
+func (repo *bookRepository) GetBookByID(ctx context.Context, id uint) (*models.Book, error) {
+	var book models.Book
+
+	query := repo.db.WithContext(ctx).
+		First(&book, id)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		return nil, models.ErrNotFound
+	}
+	return &book, query.Error
+}
+
+func (repo *bookRepository) GetBookByISBN(ctx context.Context, isbn objects.ISBN) (*models.Book, error) {
+	var book models.Book
+
+	query := repo.db.WithContext(ctx).
+		Where(clause.Eq{Column: clause.Column{Name: "isbn"}, Value: isbn}).
+		First(&book)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		return nil, models.ErrNotFound
+	}
+	return &book, query.Error
+}
+
+// GetBooksByISBNs binds isbns as one array, so that any number of them fits a statement
+func (repo *bookRepository) GetBooksByISBNs(ctx context.Context, isbns []objects.ISBN) (models.Books, error) {
+	var books models.Books
+
+	query := repo.db.WithContext(ctx).
+		Where("isbn = ANY(?)", isbnArray(isbns)).
+		Find(&books)
+	return books, query.Error
+}
+
+// GetBooksAfterID returns up to limit books with ID greater than afterID ordered by ID,
+// restricted to ids when ids is not nil. It allows reading whole table in batches.
+// ids are bound as one array, an export of all books matching a search may restrict it to any number of them.
+func (repo *bookRepository) GetBooksAfterID(ctx context.Context, afterID uint, limit int, ids []uint) (models.Books, error) {
+	var books models.Books
+
+	query := repo.db.WithContext(ctx).
+		Where("id > ?", afterID)
+	if ids != nil {
+		query = query.Where("id = ANY(?)", idArray(ids))
+	}
+	query = query.
+		Order("id").
+		Limit(limit).
+		Find(&books)
+	return books, query.Error
+}
+
+func (repo *bookRepository) CreateBook(ctx context.Context, book *models.Book) error {
+	query := repo.db.WithContext(ctx).
+		Create(book)
+	return query.Error
+}
+
+// CreateBooks inserts books in batches of createBatchSize, gorm runs the batches in a single transaction
+func (repo *bookRepository) CreateBooks(ctx context.Context, books models.Books) error {
+	query := repo.db.WithContext(ctx).
+		CreateInBatches(&books, createBatchSize)
+	return query.Error
+}
+
+// UpdateBook replaces all columns of book, including zero values
+func (repo *bookRepository) UpdateBook(ctx context.Context, book *models.Book) error {
+	query := repo.db.WithContext(ctx).
+		Model(book).
+		Select("*").
+		Omit("id", "created_at", "deleted_at").
+		Updates(book)
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return models.ErrNotFound
+	}
+	return nil
+}
+
+// idArray binds IDs as one PostgreSQL array in text form, e.g. {1,2,3}
+type idArray []uint
+
+// Value implements driver.Valuer
+func (ids idArray) Value() (driver.Value, error) {
+	elements := make([]string, len(ids))
+	for i, id := range ids {
+		elements[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return "{" + strings.Join(elements, ",") + "}", nil
+}
+
+// isbnArray binds ISBNs as one PostgreSQL array in text form, e.g. {"9780062315007","9780261103283"}
+type isbnArray []objects.ISBN
+
+// Value implements driver.Valuer, elements are quoted so that any text is read as it is
+func (isbns isbnArray) Value() (driver.Value, error) {
+	quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	elements := make([]string, len(isbns))
+	for i, isbn := range isbns {
+		elements[i] = `"` + quote.Replace(string(isbn)) + `"`
+	}
+	return "{" + strings.Join(elements, ",") + "}", nil
+}
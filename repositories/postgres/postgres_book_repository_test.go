@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+)
+
+type anyTime struct{}
+
+func (anyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+func TestBookRepository(t *testing.T) {
+	db, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(db)
+	ctx := context.TODO()
+	repo := NewBookRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "books" WHERE "isbn" = $1 AND "books"."deleted_at" IS NULL `+
+		`ORDER BY "books"."id" LIMIT $2`)).
+		WithArgs("9780062315007", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "isbn"}).AddRow(1, "The Alchemist", "9780062315007"))
+	book, err := repo.GetBookByISBN(ctx, "9780062315007")
+	expected := &models.Book{Model: gorm.Model{ID: 1}, Name: "The Alchemist", ISBN: "9780062315007"}
+	if err != nil || !reflect.DeepEqual(book, expected) {
+		t.Errorf("GetBookByISBN() got %+v, error %v\n expected %+v", book, err, expected)
+	}
+
+	// any number of ISBNs and IDs is bound as one array
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "books" WHERE isbn = ANY($1) AND "books"."deleted_at" IS NULL`)).
+		WithArgs(`{"9780062315007","9780261103283"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	if _, err := repo.GetBooksByISBNs(ctx, []objects.ISBN{"9780062315007", "9780261103283"}); err != nil {
+		t.Errorf("GetBooksByISBNs() got error %v", err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "books" WHERE id > $1 AND id = ANY($2) `+
+		`AND "books"."deleted_at" IS NULL ORDER BY id LIMIT $3`)).
+		WithArgs(0, "{1,2}", 500).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	books, err := repo.GetBooksAfterID(ctx, 0, 500, []uint{1, 2})
+	if err != nil || len(books) != 2 {
+		t.Errorf("GetBooksAfterID() got %+v, error %v\n expected books 1 and 2", books, err)
+	}
+
+	// PostgreSQL returns ID of inserted row instead of last insert ID
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "books" ("created_at","updated_at","deleted_at","name","isbn") `+
+		`VALUES ($1,$2,$3,$4,$5) RETURNING "id"`)).
+		WithArgs(anyTime{}, anyTime{}, nil, "The Hobbit", "9780261103283").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectCommit()
+	created := &models.Book{Name: "The Hobbit", ISBN: "9780261103283"}
+	if err := repo.CreateBook(ctx, created); err != nil || created.ID != 2 {
+		t.Errorf("CreateBook() got ID %d, error %v\n expected ID 2", created.ID, err)
+	}
+
+	// books are inserted in batches within bind parameters of a statement
+	books = make(models.Books, createBatchSize+1)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "books"`)).
+		WillReturnRows(idRows(1, createBatchSize))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "books"`)).
+		WillReturnRows(idRows(createBatchSize+1, 1))
+	mock.ExpectCommit()
+	if err := repo.CreateBooks(ctx, books); err != nil || books[createBatchSize].ID != createBatchSize+1 {
+		t.Errorf("CreateBooks() got last ID %d, error %v\n expected ID %d", books[createBatchSize].ID, err, createBatchSize+1)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "books" SET "updated_at"=$1,"name"=$2,"isbn"=$3 `+
+		`WHERE "books"."deleted_at" IS NULL AND "id" = $4`)).
+		WithArgs(anyTime{}, "The Hobbit", "", 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	missing := &models.Book{Model: gorm.Model{ID: 3}, Name: "The Hobbit"}
+	if err := repo.UpdateBook(ctx, missing); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("UpdateBook() of missing book got error %v\n expected %v", err, models.ErrNotFound)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func idRows(first, count int) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id"})
+	for id := first; id < first+count; id++ {
+		rows.AddRow(id)
+	}
+	return rows
+}
+
+func TestArrayValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		array    driver.Valuer
+		expected string
+	}{
+		{name: "no IDs", array: idArray{}, expected: "{}"},
+		{name: "IDs", array: idArray{1, 20, 300}, expected: "{1,20,300}"},
+		{name: "ISBNs", array: isbnArray{"9780062315007", "080442957X"}, expected: `{"9780062315007","080442957X"}`},
+		{name: "quoted text", array: isbnArray{`a"b\c`, "d,e"}, expected: `{"a\"b\\c","d,e"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.array.Value()
+			if err != nil || got != tt.expected {
+				t.Errorf("Value() got %v, error %v\n expected %s", got, err, tt.expected)
+			}
+		})
+	}
+}
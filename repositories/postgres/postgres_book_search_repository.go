@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+	"book-management-system/repositories/elasticsearch"
+)
+
+// searchSize is number of books SearchBook returns, as many as ElasticSearch hits by default
+const searchSize = 10
+
+// anyTermQuery converts text to tsquery matching any of its terms,
+// ElasticSearch query string matches any term by default too
+const anyTermQuery = "replace(plainto_tsquery('simple', ?)::text, ' & ', ' | ')::tsquery"
+
+type bookSearchRepository struct {
+	db *gorm.DB
+}
+
+// NewBookSearchRepository returns new elasticsearch.BookRepository searching books table with full-text search.
+// Search column is generated from book columns, so books need no indexing.
+func NewBookSearchRepository(db *gorm.DB) elasticsearch.BookRepository {
+	return &bookSearchRepository{
+		db: db,
+	}
+}
+
+func (repo *bookSearchRepository) IndexBook(ctx context.Context, book *models.Book) error {
+	return nil
+}
+
+func (repo *bookSearchRepository) BulkIndexBooks(ctx context.Context, books models.Books) error {
+	return nil
+}
+
+// SearchBook returns books matching any term of keyword, the best ranked first.
+// Keyword which is an ISBN matches the ISBN only.
+func (repo *bookSearchRepository) SearchBook(ctx context.Context, keyword string) (models.Books, error) {
+	var books models.Books
+
+	query := repo.db.WithContext(ctx)
+	if isbn, err := objects.ParseISBN(keyword); err == nil && !isbn.IsZero() {
+		query = query.Where("isbn = ?", isbn).Order("id")
+	} else {
+		query = query.
+			Where("search_vector @@ "+anyTermQuery, keyword).
+			Order(rankOrder(keyword))
+	}
+
+	query = query.
+		Limit(searchSize).
+		Find(&books)
+	return books, query.Error
+}
+
+// SearchBookIDs returns IDs of all books SearchBook matches in ascending order, without its limit
+func (repo *bookSearchRepository) SearchBookIDs(ctx context.Context, keyword string) ([]uint, error) {
+	ids := make([]uint, 0)
+
+	query := repo.db.WithContext(ctx).
+		Model(&models.Book{})
+	if isbn, err := objects.ParseISBN(keyword); err == nil && !isbn.IsZero() {
+		query = query.Where("isbn = ?", isbn)
+	} else {
+		query = query.Where("search_vector @@ "+anyTermQuery, keyword)
+	}
+
+	query = query.
+		Order("id").
+		Pluck("id", &ids)
+	return ids, query.Error
+}
+
+// SearchSimilarBooks returns up to size books sharing terms of the book name, the best ranked first,
+// the book itself excluded
+func (repo *bookSearchRepository) SearchSimilarBooks(
+	ctx context.Context,
+	book *models.Book,
+	size int,
+) (models.Books, error) {
+	var books models.Books
+
+	query := repo.db.WithContext(ctx).
+		Where("id <> ?", book.ID).
+		Where("search_vector @@ "+anyTermQuery, book.Name).
+		Order(rankOrder(book.Name)).
+		Limit(size).
+		Find(&books)
+	return books, query.Error
+}
+
+// DeleteBook does nothing, deleted books are excluded from search by their deleted_at
+func (repo *bookSearchRepository) DeleteBook(ctx context.Context, id uint) error {
+	return nil
+}
+
+// rankOrder orders books by relevance to text, then by ID.
+// Gorm does not merge expression with column orders, so ID is part of the expression.
+func rankOrder(text string) clause.OrderBy {
+	return clause.OrderBy{
+		Expression: clause.Expr{
+			SQL:                "ts_rank(search_vector, " + anyTermQuery + ") DESC, id",
+			Vars:               []interface{}{text},
+			WithoutParentheses: true,
+		},
+	}
+}
@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+)
+
+func TestNewBookSearchRepository(t *testing.T) {
+	db := &gorm.DB{}
+
+	got := NewBookSearchRepository(db)
+	expected := &bookSearchRepository{
+		db: db,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewBookSearchRepository returns %+v\n expected %+v",
+			got, expected)
+	}
+}
+
+func TestBookSearchRepositorySearchBook(t *testing.T) {
+	type input struct {
+		ctx     context.Context
+		keyword string
+	}
+	type output struct {
+		books models.Books
+		err   error
+	}
+	type mockConfig struct {
+		expected output
+		mock     sqlmock.Sqlmock
+	}
+
+	termsRgx := regexp.QuoteMeta(`SELECT * FROM "books" WHERE search_vector @@ ` +
+		`replace(plainto_tsquery('simple', $1)::text, ' & ', ' | ')::tsquery AND "books"."deleted_at" IS NULL ` +
+		`ORDER BY ts_rank(search_vector, replace(plainto_tsquery('simple', $2)::text, ' & ', ' | ')::tsquery) DESC, id ` +
+		`LIMIT $3`)
+	isbnRgx := regexp.QuoteMeta(`SELECT * FROM "books" WHERE isbn = $1 AND "books"."deleted_at" IS NULL ORDER BY id LIMIT $2`)
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success search by terms",
+			givenInput: input{
+				ctx:     context.TODO(),
+				keyword: "alchemist fable",
+			},
+			expectedOutput: output{
+				books: models.Books{
+					{Model: gorm.Model{ID: 2}, Name: "The Alchemist: A Fable"},
+					{Model: gorm.Model{ID: 1}, Name: "The Alchemist", ISBN: "9780062315007"},
+				},
+				err: nil,
+			},
+			configureMock: func(conf mockConfig) {
+				rows := sqlmock.NewRows([]string{"id", "name", "isbn"})
+				for _, book := range conf.expected.books {
+					rows.AddRow(book.ID, book.Name, book.ISBN)
+				}
+
+				conf.mock.ExpectQuery(termsRgx).
+					WithArgs("alchemist fable", "alchemist fable", searchSize).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "success search by isbn",
+			givenInput: input{
+				ctx:     context.TODO(),
+				keyword: "978-0-06-231500-7",
+			},
+			expectedOutput: output{
+				books: models.Books{
+					{Model: gorm.Model{ID: 1}, Name: "The Alchemist", ISBN: "9780062315007"},
+				},
+				err: nil,
+			},
+			configureMock: func(conf mockConfig) {
+				rows := sqlmock.NewRows([]string{"id", "name", "isbn"})
+				for _, book := range conf.expected.books {
+					rows.AddRow(book.ID, book.Name, book.ISBN)
+				}
+
+				conf.mock.ExpectQuery(isbnRgx).
+					WithArgs("9780062315007", searchSize).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "error database",
+			givenInput: input{
+				ctx:     context.TODO(),
+				keyword: "alchemist",
+			},
+			expectedOutput: output{
+				books: nil,
+				err:   errDatabase,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mock.ExpectQuery(termsRgx).
+					WillReturnError(errDatabase)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock, err := setupTestSuite()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer closeDB(db)
+
+			test.configureMock(mockConfig{
+				expected: test.expectedOutput,
+				mock:     mock,
+			})
+
+			repo := NewBookSearchRepository(db)
+			books, err := repo.SearchBook(test.givenInput.ctx, test.givenInput.keyword)
+			if err != test.expectedOutput.err {
+				t.Errorf("SearchBook() got error %v\n expected %v", err, test.expectedOutput.err)
+			}
+			if err == nil && !reflect.DeepEqual(books, test.expectedOutput.books) {
+				t.Errorf("SearchBook() got books %+v\n expected %+v", books, test.expectedOutput.books)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestBookSearchRepositorySearchSimilarBooks(t *testing.T) {
+	db, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(db)
+
+	book := &models.Book{Model: gorm.Model{ID: 1}, Name: "The Alchemist"}
+	expected := models.Books{{Model: gorm.Model{ID: 2}, Name: "The Alchemist: A Fable"}}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "books" WHERE id <> $1 AND search_vector @@ `)).
+		WithArgs(book.ID, book.Name, book.Name, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "The Alchemist: A Fable"))
+
+	books, err := NewBookSearchRepository(db).SearchSimilarBooks(context.TODO(), book, 5)
+	if err != nil || !reflect.DeepEqual(books, expected) {
+		t.Errorf("SearchSimilarBooks() got books %+v, error %v\n expected %+v", books, err, expected)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBookSearchRepositorySearchBookIDs(t *testing.T) {
+	db, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(db)
+
+	// all matches are returned, not only searchSize of them
+	rows := sqlmock.NewRows([]string{"id"})
+	expected := make([]uint, 0, 3*searchSize)
+	for id := uint(1); id <= 3*searchSize; id++ {
+		rows.AddRow(id)
+		expected = append(expected, id)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "books" WHERE search_vector @@ ` +
+		`replace(plainto_tsquery('simple', $1)::text, ' & ', ' | ')::tsquery AND "books"."deleted_at" IS NULL ` +
+		`ORDER BY id`)).
+		WithArgs("alchemist").
+		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "books" WHERE isbn = $1 AND "books"."deleted_at" IS NULL ORDER BY id`)).
+		WithArgs("9780062315007").
+		WillReturnError(errDatabase)
+
+	repo := NewBookSearchRepository(db)
+	ids, err := repo.SearchBookIDs(context.TODO(), "alchemist")
+	if err != nil || !reflect.DeepEqual(ids, expected) {
+		t.Errorf("SearchBookIDs() got %v, error %v\n expected %v", ids, err, expected)
+	}
+	if _, err := repo.SearchBookIDs(context.TODO(), "978-0-06-231500-7"); err != errDatabase {
+		t.Errorf("SearchBookIDs() got error %v\n expected %v", err, errDatabase)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
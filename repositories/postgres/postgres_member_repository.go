@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+	"book-management-system/repositories/mysql"
+)
+
+type memberRepository struct {
+	db *gorm.DB
+}
+
+// NewMemberRepository returns new mysql.MemberRepository storing members in PostgreSQL members table
+func NewMemberRepository(db *gorm.DB) mysql.MemberRepository {
+	return &memberRepository{
+		db: db,
+	}
+}
+
+// GetAll returns members ordered by ID, PostgreSQL returns rows in no particular order without ORDER BY
+func (repo *memberRepository) GetAll(ctx context.Context) (models.Members, error) {
+	var members models.Members
+
+	query := repo.db.WithContext(ctx).
+		Order("id").
+		Find(&members)
+	return members, query.Error
+}
+
+func (repo *memberRepository) GetMemberByID(ctx context.Context, id uint) (*models.Member, error) {
+	var member models.Member
+
+	query := repo.db.WithContext(ctx).
+		First(&member, id)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		return nil, models.ErrNotFound
+	}
+	return &member, query.Error
+}
+
+func (repo *memberRepository) CreateMember(ctx context.Context, member *models.Member) error {
+	query := repo.db.WithContext(ctx).
+		Create(member)
+	return query.Error
+}
+
+// UpdateMember replaces all columns of member, including zero values
+func (repo *memberRepository) UpdateMember(ctx context.Context, member *models.Member) error {
+	query := repo.db.WithContext(ctx).
+		Model(member).
+		Select("*").
+		Omit("id", "created_at", "deleted_at").
+		Updates(member)
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return models.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"book-management-system/entities/models"
+)
+
+func TestMemberRepository(t *testing.T) {
+	db, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(db)
+	ctx := context.TODO()
+	repo := NewMemberRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "members" WHERE "members"."deleted_at" IS NULL ORDER BY id`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	if members, err := repo.GetAll(ctx); err != nil || len(members) != 2 {
+		t.Errorf("GetAll() got %+v, error %v\n expected members 1 and 2", members, err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "members" WHERE "members"."id" = $1 `+
+		`AND "members"."deleted_at" IS NULL ORDER BY "members"."id" LIMIT $2`)).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	if _, err := repo.GetMemberByID(ctx, 1); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("GetMemberByID() of missing member got error %v\n expected %v", err, models.ErrNotFound)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "members"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectCommit()
+	member := &models.Member{Name: "John Lennon"}
+	if err := repo.CreateMember(ctx, member); err != nil || member.ID != 5 {
+		t.Errorf("CreateMember() got ID %d, error %v\n expected ID 5", member.ID, err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "members" SET "updated_at"=$1,"name"=$2`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	if err := repo.UpdateMember(ctx, member); err != nil {
+		t.Errorf("UpdateMember() got error %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"book-management-system/configs"
+	"book-management-system/entities/models"
+	"book-management-system/repositories/mysql"
+)
+
+func setupTestSuite() (*gorm.DB, sqlmock.Sqlmock, error) {
+	dbMock, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gormDBMock, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: dbMock,
+	}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return gormDBMock, mock, nil
+}
+
+func closeDB(gormDBMock *gorm.DB) {
+	dbMock, _ := gormDBMock.DB()
+	dbMock.Close()
+}
+
+var errDatabase = errors.New("error")
+
+func TestGetPostgresConnString(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      configs.PostgresConfig
+		expected string
+	}{
+		{
+			name: "ssl mode disabled by default",
+			cfg: configs.PostgresConfig{
+				Host: "localhost", Port: "5432", User: "user", Pass: "password", Name: "db",
+			},
+			expected: "host=localhost port=5432 user=user password=password dbname=db sslmode=disable TimeZone=UTC",
+		},
+		{
+			name: "configured ssl mode",
+			cfg: configs.PostgresConfig{
+				Host: "db.local", Port: "5433", User: "user", Pass: "password", Name: "db", SSLMode: "require",
+			},
+			expected: "host=db.local port=5433 user=user password=password dbname=db sslmode=require TimeZone=UTC",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := getPostgresConnString(test.cfg); got != test.expected {
+				t.Errorf("getPostgresConnString() got %q\n expected %q", got, test.expected)
+			}
+		})
+	}
+}
+
+// TestIdempotencyRepositoryOnPostgres checks repositories of mysql package quote columns for Postgres
+func TestIdempotencyRepositoryOnPostgres(t *testing.T) {
+	db, mock, err := setupTestSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "idempotency_keys" WHERE "key" = $1 LIMIT $2`)).
+		WithArgs("key", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"key", "request_hash"}).AddRow("key", "hash"))
+
+	got, err := mysql.NewIdempotencyRepository(db).GetKey(context.TODO(), "key")
+	if err != nil || got.RequestHash != "hash" {
+		t.Errorf("GetKey() got %+v, error %v\n expected key with hash %q", got, err, "hash")
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "idempotency_keys" WHERE ("key" = $1 AND "token" = $2) AND "completed" = $3`)).
+		WithArgs("key", "token", false).
+		WillReturnError(errDatabase)
+	mock.ExpectRollback()
+
+	key := &models.IdempotencyKey{Key: "key", Token: "token"}
+	if _, err := mysql.NewIdempotencyRepository(db).DeleteKey(context.TODO(), key); !errors.Is(err, errDatabase) {
+		t.Errorf("DeleteKey() got error %v\n expected %v", err, errDatabase)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -2,24 +2,207 @@
 package repositories
 
 import (
+	"context"
+	"log"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"book-management-system/cache"
+	"book-management-system/configs"
+	"book-management-system/events"
+	"book-management-system/health"
+	"book-management-system/logging"
+	"book-management-system/metrics"
 	"book-management-system/repositories/elasticsearch"
+	"book-management-system/repositories/memory"
+	"book-management-system/repositories/migrations"
 	"book-management-system/repositories/mysql"
+	"book-management-system/repositories/postgres"
+	"book-management-system/repositories/sqlite"
 )
 
-// Repository contains repositories
+// Repository contains repositories.
+// MySQL repositories are served by the database backend and ES repository by the search backend
+// selected in configs.BackendConfig. Their interfaces are of mysql and elasticsearch packages whatever the backend,
+// e.g. Postgres database backend serves books and members by repositories of postgres package.
 type Repository struct {
-	MySQLBookRepository   mysql.BookRepository
-	ESBookRepository      elasticsearch.BookRepository
-	MySQLMemberRepository mysql.MemberRepository
+	MySQLBookRepository          mysql.BookRepository
+	ESBookRepository             elasticsearch.BookRepository
+	MySQLMemberRepository        mysql.MemberRepository
+	MySQLIdempotencyRepository   mysql.IdempotencyRepository
+	MySQLImportJobRepository     mysql.ImportJobRepository
+	MySQLBookDuplicateRepository mysql.BookDuplicateRepository
+	MySQLWebhookRepository       mysql.WebhookRepository
+	// Cache caches reads of the service, nil when caching is disabled
+	Cache cache.Cache
+	// Events feeds changes published by services and pipelines to subscribers of the process
+	Events *events.Feed
+	// HealthChecks checks backends by name, database and search, memory backends have none
+	HealthChecks map[string]health.Check
+	// SearchIndexStale is set when migrations applied by Init changed indexed book columns,
+	// books are reindexed before use
+	SearchIndexStale bool
+	// closers release connections of backends
+	closers []func() error
 }
 
-// Init returns Repository
+// Init returns Repository of configured backends.
+// SQL database is migrated unless in production, where migrations are applied by migrate command.
+// SQLite is always migrated as it is meant for development.
 func Init() *Repository {
-	mysqlDB := mysql.Init()
-	es := elasticsearch.Init()
-	return &Repository{
-		MySQLBookRepository:   mysql.NewBookRepository(mysqlDB),
-		ESBookRepository:      elasticsearch.NewBookRepository(es),
-		MySQLMemberRepository: mysql.NewMemberRepository(mysqlDB),
+	cfg := configs.GetConfig().Backend
+	repo := &Repository{
+		Events:       events.NewFeed(configs.GetConfig().Events.LogSize),
+		HealthChecks: make(map[string]health.Check),
+	}
+	if cacheCfg := configs.GetConfig().Cache; cacheCfg.Enabled {
+		repo.Cache = cache.NewLRU(cacheCfg.Size)
+	}
+
+	if cfg.Database == configs.DatabaseMemory {
+		repo.initMemoryRepositories(memory.NewDB())
+	} else {
+		db := InitDatabase()
+		if !configs.GetConfig().Production || cfg.Database == configs.DatabaseSQLite {
+			repo.SearchIndexStale = migrations.NeedsReindex(migrate(db))
+		}
+		repo.initGormRepositories(db)
+		if cfg.Database == configs.DatabasePostgres {
+			repo.initPostgresRepositories(db)
+		}
+		repo.HealthChecks["database"] = databaseHealthCheck(db)
+		repo.closers = append(repo.closers, databaseCloser(db))
+	}
+
+	switch cfg.Search {
+	case "", configs.SearchElasticSearch:
+		repo.ESBookRepository = elasticsearch.NewBookRepository(elasticsearch.Init())
+		repo.HealthChecks["search"] = elasticsearch.HealthCheck
+		repo.closers = append(repo.closers, elasticsearch.Close)
+	case configs.SearchPostgres:
+		if cfg.Database != configs.DatabasePostgres {
+			log.Fatalf("postgres search backend needs postgres database backend, got %q", cfg.Database)
+		}
+		repo.ESBookRepository = postgres.NewBookSearchRepository(postgres.Init())
+	case configs.SearchMemory:
+		repo.ESBookRepository = memory.NewBookIndex()
+	default:
+		log.Fatalf("unknown search backend %q", cfg.Search)
+	}
+
+	return repo
+}
+
+// Close closes connections of backends, repositories must not be used afterwards
+func (repo *Repository) Close() error {
+	var firstErr error
+	for i := len(repo.closers) - 1; i >= 0; i-- {
+		if err := repo.closers[i](); err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
+	repo.closers = nil
+	return firstErr
+}
+
+// InitDatabase returns connection of configured SQL database backend
+func InitDatabase() *gorm.DB {
+	cfg := configs.GetConfig()
+
+	var db *gorm.DB
+	switch cfg.Backend.Database {
+	case "", configs.DatabaseMySQL:
+		db = mysql.Init()
+	case configs.DatabasePostgres:
+		db = postgres.Init()
+	case configs.DatabaseSQLite:
+		db = sqlite.Init()
+	default:
+		log.Fatalf("database backend %q is not SQL database", cfg.Backend.Database)
+	}
+
+	// database connection is shared, so are its logger, metrics and tracing
+	if _, ok := db.Logger.(gormLogger); !ok {
+		db.Logger = gormLogger{}
+		if err := db.Use(metricsPlugin{}); err != nil {
+			log.Fatalf("failed to register database metrics: %s", err)
+		}
+		if err := db.Use(tracingPlugin{}); err != nil {
+			log.Fatalf("failed to register database tracing: %s", err)
+		}
+		sqlDB, err := db.DB()
+		if err == nil {
+			err = metrics.RegisterDB(sqlDB, db.Dialector.Name())
+		}
+		if err != nil {
+			log.Fatalf("failed to register database metrics: %s", err)
+		}
+	}
+	return db
+}
+
+// CloseDatabase closes connection pool of db returned by InitDatabase, repositories sharing it included
+func CloseDatabase(db *gorm.DB) error {
+	return databaseCloser(db)()
+}
+
+// databaseHealthCheck pings a connection of db pool
+func databaseHealthCheck(db *gorm.DB) health.Check {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// databaseCloser closes db pool, waiting for running queries to finish
+func databaseCloser(db *gorm.DB) func() error {
+	return func() error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.Close()
+	}
+}
+
+// migrate applies pending migrations of db and returns them
+func migrate(db *gorm.DB) []migrations.Migration {
+	applied, err := migrations.NewMigrator(db, migrations.Embedded()).Up(context.Background())
+	if err != nil {
+		log.Fatalf("failed to migrate database: %s", err)
+	}
+	for _, migration := range applied {
+		logging.Logger(logging.PackageRepositories).Info("applied migration",
+			zap.Int64("version", migration.Version), zap.String("name", migration.Name))
+	}
+	return applied
+}
+
+// initGormRepositories sets SQL repositories of MySQL, Postgres or SQLite database
+func (repo *Repository) initGormRepositories(db *gorm.DB) {
+	repo.MySQLBookRepository = mysql.NewBookRepository(db)
+	repo.MySQLMemberRepository = mysql.NewMemberRepository(db)
+	repo.MySQLIdempotencyRepository = mysql.NewIdempotencyRepository(db)
+	repo.MySQLImportJobRepository = mysql.NewImportJobRepository(db)
+	repo.MySQLBookDuplicateRepository = mysql.NewBookDuplicateRepository(db)
+	repo.MySQLWebhookRepository = mysql.NewWebhookRepository(db)
+}
+
+// initPostgresRepositories replaces SQL repositories of Postgres database which have Postgres implementations
+func (repo *Repository) initPostgresRepositories(db *gorm.DB) {
+	repo.MySQLBookRepository = postgres.NewBookRepository(db)
+	repo.MySQLMemberRepository = postgres.NewMemberRepository(db)
+}
+
+func (repo *Repository) initMemoryRepositories(db *memory.DB) {
+	repo.MySQLBookRepository = memory.NewBookRepository(db)
+	repo.MySQLMemberRepository = memory.NewMemberRepository(db)
+	repo.MySQLIdempotencyRepository = memory.NewIdempotencyRepository(db)
+	repo.MySQLImportJobRepository = memory.NewImportJobRepository(db)
+	repo.MySQLBookDuplicateRepository = memory.NewBookDuplicateRepository(db)
+	repo.MySQLWebhookRepository = memory.NewWebhookRepository(db)
 }
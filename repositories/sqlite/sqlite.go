@@ -0,0 +1,55 @@
+// Package sqlite opens SQLite database served by the repositories of mysql package
+package sqlite
+
+import (
+	"log"
+	"sync"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"book-management-system/configs"
+)
+
+// memoryDSN is private in-memory database, it lives as long as its only connection
+const memoryDSN = ":memory:"
+
+var (
+	sqliteDB *gorm.DB
+	once     sync.Once
+)
+
+// Init returns sqliteDB connection instance
+func Init() *gorm.DB {
+	once.Do(func() {
+		var err error
+		sqliteDB, err = Open(configs.GetConfig().SQLite.Path)
+		if err != nil {
+			log.Fatalf("failed to open sqlite database: %s", err)
+		}
+	})
+
+	return sqliteDB
+}
+
+// Open opens SQLite database file, in-memory database when path is empty
+func Open(path string) (*gorm.DB, error) {
+	dsn := path
+	if dsn == "" {
+		dsn = memoryDSN
+	}
+
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite has single writer, one connection avoids "database is locked" errors
+	// and keeps in-memory database alive
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+	return db, nil
+}
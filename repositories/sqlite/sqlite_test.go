@@ -0,0 +1,179 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+	"book-management-system/repositories/migrations"
+	"book-management-system/repositories/mysql"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrations.NewMigrator(db, migrations.Embedded()).Up(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+	return db
+}
+
+func TestBookRepository(t *testing.T) {
+	ctx := context.TODO()
+	repo := mysql.NewBookRepository(openTestDB(t))
+
+	books := models.Books{
+		{Name: "The Alchemist", ISBN: "9780062315007"},
+		{Name: "The Hobbit", ISBN: "9780261103283"},
+		{Name: "The Republic"},
+	}
+	if err := repo.CreateBooks(ctx, books); err != nil {
+		t.Fatalf("CreateBooks() got error %v", err)
+	}
+
+	book, err := repo.GetBookByISBN(ctx, "9780261103283")
+	if err != nil || book.ID != books[1].ID {
+		t.Errorf("GetBookByISBN() got book %+v, error %v\n expected book %d", book, err, books[1].ID)
+	}
+
+	got, err := repo.GetBooksAfterID(ctx, books[0].ID, 1, nil)
+	if err != nil || len(got) != 1 || got[0].ID != books[1].ID {
+		t.Errorf("GetBooksAfterID() got books %+v, error %v\n expected book %d", got, err, books[1].ID)
+	}
+
+	got, err = repo.GetBooksByISBNs(ctx, []objects.ISBN{"9780062315007", "9780140449136"})
+	if err != nil || len(got) != 1 || got[0].ID != books[0].ID {
+		t.Errorf("GetBooksByISBNs() got books %+v, error %v\n expected book %d", got, err, books[0].ID)
+	}
+
+	books[2].ISBN = "9780140449136"
+	if err := repo.UpdateBook(ctx, &books[2]); err != nil {
+		t.Errorf("UpdateBook() got error %v", err)
+	}
+	book, err = repo.GetBookByID(ctx, books[2].ID)
+	if err != nil || book.ISBN != books[2].ISBN {
+		t.Errorf("GetBookByID() got book %+v, error %v\n expected ISBN %s", book, err, books[2].ISBN)
+	}
+
+	if err := repo.UpdateBook(ctx, &models.Book{Model: gorm.Model{ID: 99}, Name: "Missing"}); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("UpdateBook() of missing book got error %v\n expected %v", err, models.ErrNotFound)
+	}
+}
+
+func TestIdempotencyRepository(t *testing.T) {
+	ctx := context.TODO()
+	repo := mysql.NewIdempotencyRepository(openTestDB(t))
+
+	now := time.Now()
+	key := &models.IdempotencyKey{
+		Key: "key", Token: "token", RequestHash: "hash", CreatedAt: now, LockedUntil: now, ExpiresAt: now.Add(time.Hour),
+	}
+	for i, expected := range []bool{true, false} {
+		created, err := repo.CreateKey(ctx, key)
+		if err != nil || created != expected {
+			t.Errorf("CreateKey() call %d got %v, error %v\n expected %v", i+1, created, err, expected)
+		}
+	}
+
+	key.LockedUntil = now.Add(time.Minute)
+	if extended, err := repo.ExtendKey(ctx, key); err != nil || !extended {
+		t.Errorf("ExtendKey() got %v, error %v\n expected true", extended, err)
+	}
+	if stored, err := repo.GetKey(ctx, "key"); err != nil || !stored.LockedUntil.Equal(key.LockedUntil) {
+		t.Errorf("GetKey() after ExtendKey() got %+v, error %v\n expected locked until %v", stored, err, key.LockedUntil)
+	}
+	// key of another token, e.g. taken over by another request, is kept
+	if deleted, err := repo.DeleteKey(ctx, &models.IdempotencyKey{Key: "key", Token: "other"}); err != nil || deleted {
+		t.Errorf("DeleteKey() of another token got %v, error %v\n expected false", deleted, err)
+	}
+
+	if err := repo.DeleteExpiredKeys(ctx, now.Add(2*time.Hour)); err != nil {
+		t.Errorf("DeleteExpiredKeys() got error %v", err)
+	}
+	if _, err := repo.GetKey(ctx, "key"); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("GetKey() of expired key got error %v\n expected %v", err, models.ErrNotFound)
+	}
+}
+
+func TestBookDuplicateRepository(t *testing.T) {
+	ctx := context.TODO()
+	db := openTestDB(t)
+	bookRepo := mysql.NewBookRepository(db)
+	repo := mysql.NewBookDuplicateRepository(db)
+
+	books := models.Books{
+		{Name: "The Alchemist", ISBN: "9780062315007"},
+		{Name: "The Alchemist."},
+		{Name: "Alchemist, The"},
+	}
+	if err := bookRepo.CreateBooks(ctx, books); err != nil {
+		t.Fatalf("CreateBooks() got error %v", err)
+	}
+
+	duplicates := []models.BookDuplicate{
+		{BookID: 1, DuplicateID: 2, Score: 0.9, Reasons: models.BookDuplicateReasons{models.BookDuplicateSimilarTitle},
+			Status: models.BookDuplicatePending},
+		{BookID: 2, DuplicateID: 3, Score: 0.8, Reasons: models.BookDuplicateReasons{models.BookDuplicateSimilarTitle},
+			Status: models.BookDuplicatePending},
+	}
+	if err := repo.SaveDuplicates(ctx, duplicates); err != nil {
+		t.Fatalf("SaveDuplicates() got error %v", err)
+	}
+	// saving again refreshes score and keeps dismissed status
+	if err := repo.UpdateDuplicateStatus(ctx, duplicates[1].ID, models.BookDuplicateDismissed); err != nil {
+		t.Fatalf("UpdateDuplicateStatus() got error %v", err)
+	}
+	if err := repo.SaveDuplicates(ctx, []models.BookDuplicate{
+		{BookID: 1, DuplicateID: 2, Score: 1, Reasons: models.BookDuplicateReasons{models.BookDuplicateSimilarTitle},
+			Status: models.BookDuplicatePending},
+		{BookID: 2, DuplicateID: 3, Score: 0.85, Reasons: models.BookDuplicateReasons{models.BookDuplicateSimilarTitle},
+			Status: models.BookDuplicatePending},
+	}); err != nil {
+		t.Fatalf("SaveDuplicates() again got error %v", err)
+	}
+	dismissed, err := repo.GetDuplicates(ctx, models.BookDuplicateDismissed)
+	if err != nil || len(dismissed) != 1 || dismissed[0].Score != 0.85 {
+		t.Errorf("GetDuplicates() got %+v, error %v\n expected one dismissed pair with score 0.85", dismissed, err)
+	}
+
+	if err := repo.MergeBooks(ctx, &books[0], &books[1]); err != nil {
+		t.Fatalf("MergeBooks() got error %v", err)
+	}
+	if err := repo.MergeBooks(ctx, &books[2], &books[0]); err != nil {
+		t.Fatalf("MergeBooks() into third book got error %v", err)
+	}
+
+	for _, id := range []uint{books[0].ID, books[1].ID} {
+		toID, err := repo.GetRedirect(ctx, id)
+		if err != nil || toID != books[2].ID {
+			t.Errorf("GetRedirect(%d) got %d, error %v\n expected %d", id, toID, err, books[2].ID)
+		}
+	}
+	if _, err := bookRepo.GetBookByID(ctx, books[1].ID); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("GetBookByID() of merged book got error %v\n expected %v", err, models.ErrNotFound)
+	}
+
+	merged, err := repo.GetDuplicates(ctx, models.BookDuplicateMerged)
+	if err != nil {
+		t.Fatalf("GetDuplicates() got error %v", err)
+	}
+	mergedPairs := make([][2]uint, 0, len(merged))
+	for _, duplicate := range merged {
+		mergedPairs = append(mergedPairs, [2]uint{duplicate.BookID, duplicate.DuplicateID})
+	}
+	if expected := [][2]uint{{1, 2}}; !reflect.DeepEqual(mergedPairs, expected) {
+		t.Errorf("GetDuplicates() got merged pairs %v\n expected %v", mergedPairs, expected)
+	}
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"book-management-system/entities/models"
+	"book-management-system/repositories/sqlite"
+)
+
+func TestTracingPlugin(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	db, err := sqlite.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(tracingPlugin{}); err != nil {
+		t.Fatalf("Use() got error %v", err)
+	}
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+	var count int64
+	if err := db.WithContext(ctx).Model(&models.Book{}).Count(&count).Error; err == nil {
+		t.Fatal("Count() of missing table got no error")
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans\n expected query and request span", len(spans))
+	}
+	query := spans[0]
+	if query.Name() != "gorm.query" || query.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("got span %q with parent %s\n expected gorm.query child of request span",
+			query.Name(), query.Parent().SpanID())
+	}
+	if len(query.Events()) != 1 || query.Status().Description == "" {
+		t.Errorf("got span events %+v, status %+v\n expected recorded error", query.Events(), query.Status())
+	}
+}
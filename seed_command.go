@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"book-management-system/usecases/pipelines"
+)
+
+// runSeed generates demo books and members and indexes the books into search backend.
+// It returns exit code, non-zero when seeding failed.
+func runSeed(args []string) int {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	books := flags.Int("books", 1000, "number of books generated")
+	members := flags.Int("members", 100, "number of members generated")
+	seed := flags.Int64("seed", 1, "random seed, the same seed generates the same data")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s seed [flags]\n", appName)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return flagsExitCode(err)
+	}
+	if flags.NArg() != 0 || *books < 0 || *members < 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	useCase, shutdown := initUseCase()
+	defer shutdown()
+	report, err := useCase.Pipeline.SeedPipeline.Seed(context.Background(), pipelines.SeedOptions{
+		Books:   *books,
+		Members: *members,
+		Seed:    *seed,
+	})
+	fmt.Printf("created %d books, %d members, indexed %d books, skipped %d books already stored\n",
+		report.Books, report.Members, report.Indexed, report.Skipped)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed seed: %s\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
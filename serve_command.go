@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"book-management-system/configs"
+	"book-management-system/controllers"
+	"book-management-system/lifecycle"
+	"book-management-system/logging"
+	"book-management-system/repositories"
+	"book-management-system/tracing"
+	"book-management-system/usecases"
+)
+
+// runServe serves REST API until SIGINT or SIGTERM, reloading config on its change.
+// It returns exit code, zero after graceful shutdown.
+func runServe(args []string) int {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	gracefulTimeout := flags.Duration(
+		"graceful-timeout",
+		15*time.Second,
+		"the duration for which the server gracefully wait for existing connections and background tasks to finish - e.g. 15s or 1m",
+	)
+	reloadInterval := flags.Duration("config-check-interval", 5*time.Second,
+		"how often config file is checked for changes to reload, it is also reloaded on SIGHUP")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s serve [flags]\n", appName)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return flagsExitCode(err)
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	logging.Init()
+	shutdownTracing, err := tracing.Init(configs.GetConfig().Tracing)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed init tracing: %s\n", err)
+		return exitFailure
+	}
+	repo := repositories.Init()
+
+	// components stop in reverse order: HTTP server drains requests, then background tasks and workers finish,
+	// then connections to backends are closed and traces flushed
+	manager := lifecycle.NewManager()
+	manager.Add(
+		lifecycle.Component{Name: "tracing", Stop: shutdownTracing},
+		lifecycle.Component{Name: "repositories", Stop: func(context.Context) error { return repo.Close() }},
+		lifecycle.Tasks(),
+		lifecycle.Worker("config watcher", func(ctx context.Context) { configs.Watch(ctx, *reloadInterval) }),
+	)
+	useCase := usecases.Init(repo)
+	reindexStaleSearch(repo, useCase)
+	// jobs left running by a previous run never finish, clients polling them are told they failed
+	if err := useCase.Pipeline.BookImportPipeline.FailStaleImportJobs(context.Background()); err != nil {
+		logging.Logger(logging.PackagePipelines).Error("failed to fail stale import jobs", zap.Error(err))
+	}
+	// deliveries stop before the dispatcher, which enqueues events published while requests drained
+	manager.Add(
+		lifecycle.Worker("webhook dispatcher", useCase.Pipeline.WebhookPipeline.DispatchEvents),
+		lifecycle.Worker("webhook delivery", useCase.Pipeline.WebhookPipeline.DeliverWebhooks),
+	)
+	manager.Add(controllers.Init(useCase, manager.Fail)...)
+
+	if err := manager.Run(context.Background(), *gracefulTimeout); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"book-management-system/configs"
+)
+
+func TestInitFileExporter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "traces.json")
+	shutdown, err := Init(configs.TracingConfig{Exporter: configs.TracingExporterFile, File: file, SampleRatio: 1})
+	if err != nil {
+		t.Fatalf("Init() got error %v", err)
+	}
+
+	_, span := Tracer().Start(context.Background(), "test span")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown() got error %v", err)
+	}
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), `"Name":"test span"`) {
+		t.Errorf("Init() file exporter wrote %s\n expected test span", content)
+	}
+}
+
+func TestInitNone(t *testing.T) {
+	shutdown, err := Init(configs.TracingConfig{Exporter: configs.TracingExporterNone})
+	if err != nil {
+		t.Fatalf("Init() got error %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() got error %v", err)
+	}
+}
+
+func TestDetach(t *testing.T) {
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx, cancel := context.WithCancel(trace.ContextWithSpanContext(context.Background(), spanContext))
+	cancel()
+
+	detached := Detach(ctx)
+	if detached.Err() != nil {
+		t.Errorf("Detach() got canceled context")
+	}
+	if got := trace.SpanContextFromContext(detached); !got.Equal(spanContext) {
+		t.Errorf("Detach() got span context %+v\n expected %+v", got, spanContext)
+	}
+}
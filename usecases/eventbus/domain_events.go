@@ -0,0 +1,75 @@
+package eventbus
+
+import (
+	"fmt"
+
+	"book-management-system/entities/models"
+	"book-management-system/events"
+)
+
+// BookCreated is published after a book was stored.
+// Indexed tells that the publisher indexed the book for search already, e.g. in bulk with its import batch.
+type BookCreated struct {
+	Book    models.Book
+	Indexed bool
+}
+
+// Name returns events.TypeBookCreated
+func (e BookCreated) Name() string { return events.TypeBookCreated }
+
+// AggregateID returns ID of the book
+func (e BookCreated) AggregateID() string { return bookAggregateID(e.Book.ID) }
+
+// BookUpdated is published after changes of a book were stored, Indexed as in BookCreated
+type BookUpdated struct {
+	Book    models.Book
+	Indexed bool
+}
+
+// Name returns events.TypeBookUpdated
+func (e BookUpdated) Name() string { return events.TypeBookUpdated }
+
+// AggregateID returns ID of the book
+func (e BookUpdated) AggregateID() string { return bookAggregateID(e.Book.ID) }
+
+// BookDeleted is published after a book was deleted, MergedInto is ID of the book it was merged into
+type BookDeleted struct {
+	ID         uint
+	MergedInto uint
+}
+
+// Name returns events.TypeBookDeleted
+func (e BookDeleted) Name() string { return events.TypeBookDeleted }
+
+// AggregateID returns ID of the book
+func (e BookDeleted) AggregateID() string { return bookAggregateID(e.ID) }
+
+// MemberCreated is published after a member was stored
+type MemberCreated struct {
+	Member models.Member
+}
+
+// Name returns events.TypeMemberCreated
+func (e MemberCreated) Name() string { return events.TypeMemberCreated }
+
+// AggregateID returns ID of the member
+func (e MemberCreated) AggregateID() string { return memberAggregateID(e.Member.ID) }
+
+// MemberUpdated is published after changes of a member were stored
+type MemberUpdated struct {
+	Member models.Member
+}
+
+// Name returns events.TypeMemberUpdated
+func (e MemberUpdated) Name() string { return events.TypeMemberUpdated }
+
+// AggregateID returns ID of the member
+func (e MemberUpdated) AggregateID() string { return memberAggregateID(e.Member.ID) }
+
+func bookAggregateID(id uint) string {
+	return fmt.Sprintf("book:%d", id)
+}
+
+func memberAggregateID(id uint) string {
+	return fmt.Sprintf("member:%d", id)
+}
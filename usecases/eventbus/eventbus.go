@@ -0,0 +1,170 @@
+// Package eventbus dispatches domain events published by services and pipelines to subscribers of the process,
+// so that reactions to a change like search indexing or cache invalidation are not part of the code changing it.
+// Failing subscribers are logged and counted, they never fail the publisher.
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"book-management-system/lifecycle"
+	"book-management-system/logging"
+	"book-management-system/metrics"
+	"book-management-system/tracing"
+)
+
+// Event is a change of the domain
+type Event interface {
+	// Name is type of the event, the same as in the change feed, e.g. events.TypeBookCreated
+	Name() string
+	// AggregateID identifies the changed entity, e.g. book:1
+	AggregateID() string
+}
+
+// Handler reacts to event, its error is logged and not returned to the publisher
+type Handler func(ctx context.Context, event Event) error
+
+// Bus dispatches published events to subscribers
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions []*subscription
+}
+
+// New returns Bus without subscribers
+func New() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers handler of events of names, all events when there are none.
+// Handler runs before Publish returns, in order of subscribing, with ctx of the publisher.
+// It suits quick reactions the publisher relies on, e.g. dropping cached reads of a changed book.
+func (b *Bus) Subscribe(name string, handler Handler, names ...string) {
+	b.subscribe(&subscription{name: name, handler: handler, names: nameSet(names)})
+}
+
+// SubscribeAsync registers handler of events of names, all events when there are none, running in background.
+// Events of one aggregate are handled one at a time in order of publishing, events of different aggregates
+// concurrently. Handling is a background task named name, shutdown waits for it.
+func (b *Bus) SubscribeAsync(name string, handler Handler, names ...string) {
+	b.subscribe(&subscription{
+		name:    name,
+		handler: handler,
+		names:   nameSet(names),
+		async:   true,
+		last:    make(map[string]chan struct{}),
+	})
+}
+
+func (b *Bus) subscribe(s *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions = append(b.subscriptions, s)
+}
+
+// Publish dispatches event to subscribers, nil Bus publishes nothing.
+// Publishers call it after the change was stored, in order of changes of the aggregate.
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	subscriptions := b.subscriptions
+	b.mu.RUnlock()
+
+	for _, s := range subscriptions {
+		if !s.matches(event.Name()) {
+			continue
+		}
+		if s.async {
+			s.enqueue(ctx, event)
+		} else {
+			s.handle(ctx, event)
+		}
+	}
+}
+
+type subscription struct {
+	name    string
+	handler Handler
+	names   map[string]bool
+	async   bool
+
+	mu sync.Mutex
+	// last is closed when the last queued event of aggregate is handled, aggregates without queued events
+	// have none
+	last map[string]chan struct{}
+}
+
+func nameSet(names []string) map[string]bool {
+	if len(names) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+func (s *subscription) matches(name string) bool {
+	return s.names == nil || s.names[name]
+}
+
+// enqueue handles event in background after previous events of its aggregate
+func (s *subscription) enqueue(ctx context.Context, event Event) {
+	key := event.AggregateID()
+	done := make(chan struct{})
+	s.mu.Lock()
+	previous := s.last[key]
+	s.last[key] = done
+	s.mu.Unlock()
+
+	taskDone := metrics.StartTask(s.name)
+	lifecycle.Go(tracing.Detach(ctx), func(ctx context.Context) {
+		defer func() {
+			s.mu.Lock()
+			if s.last[key] == done {
+				delete(s.last, key)
+			}
+			s.mu.Unlock()
+			close(done)
+		}()
+
+		if previous != nil {
+			select {
+			case <-previous:
+			case <-ctx.Done():
+				// shutdown gave up waiting, handling now would overtake the previous event
+				s.fail(ctx, event, ctx.Err())
+				taskDone(ctx.Err())
+				return
+			}
+		}
+		taskDone(s.handle(ctx, event))
+	})
+}
+
+// handle runs handler, its error or panic is logged and counted instead of reaching the publisher
+func (s *subscription) handle(ctx context.Context, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+		if err != nil {
+			s.fail(ctx, event, err)
+		}
+	}()
+	return s.handler(ctx, event)
+}
+
+func (s *subscription) fail(ctx context.Context, event Event, err error) {
+	metrics.EventHandlerFailures.WithLabelValues(s.name).Inc()
+	logging.FromContext(ctx, logging.PackageEventBus).Error("failed to handle event",
+		zap.String("subscriber", s.name),
+		zap.String("event", event.Name()),
+		zap.String("aggregate_id", event.AggregateID()),
+		zap.Error(err))
+}
@@ -0,0 +1,92 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+	"book-management-system/events"
+	"book-management-system/lifecycle"
+)
+
+func TestBusPublish(t *testing.T) {
+	bus := New()
+	var got []string
+	record := func(name string) Handler {
+		return func(_ context.Context, event Event) error {
+			got = append(got, name+" "+event.AggregateID())
+			return nil
+		}
+	}
+	bus.Subscribe("first", record("first"))
+	bus.Subscribe("failing", func(context.Context, Event) error {
+		return errors.New("handler error")
+	})
+	bus.Subscribe("panicking", func(context.Context, Event) error {
+		panic("handler panic")
+	})
+	bus.Subscribe("members", record("members"), events.TypeMemberCreated)
+	bus.Subscribe("last", record("last"))
+
+	bus.Publish(context.TODO(), BookCreated{Book: models.Book{Model: gorm.Model{ID: 1}}})
+	bus.Publish(context.TODO(), MemberCreated{Member: models.Member{Model: gorm.Model{ID: 2}}})
+
+	expected := []string{"first book:1", "last book:1", "first member:2", "members member:2", "last member:2"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Publish() handled %v\n expected %v", got, expected)
+	}
+
+	var nilBus *Bus
+	nilBus.Publish(context.TODO(), BookDeleted{ID: 1})
+}
+
+// bookVersion is event of version of a book
+type bookVersion struct {
+	bookID  uint
+	version int
+}
+
+func (e bookVersion) Name() string        { return events.TypeBookUpdated }
+func (e bookVersion) AggregateID() string { return bookAggregateID(e.bookID) }
+
+func TestBusSubscribeAsync(t *testing.T) {
+	bus := New()
+	var mu sync.Mutex
+	got := make(map[string][]int)
+	bus.SubscribeAsync("books", func(_ context.Context, event Event) error {
+		e := event.(bookVersion)
+		// earlier versions take longer, handling them concurrently would reorder them
+		time.Sleep(time.Duration(10-e.version) * time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		got[e.AggregateID()] = append(got[e.AggregateID()], e.version)
+		if e.version == 2 {
+			return errors.New("handler error")
+		}
+		return nil
+	}, events.TypeBookUpdated)
+
+	for _, e := range []bookVersion{{1, 1}, {1, 2}, {2, 1}, {1, 3}, {2, 2}, {1, 4}} {
+		bus.Publish(context.TODO(), e)
+	}
+	bus.Publish(context.TODO(), BookCreated{Book: models.Book{Model: gorm.Model{ID: 1}}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := lifecycle.Wait(ctx); err != nil {
+		t.Fatalf("Wait() got error %v", err)
+	}
+	expected := map[string][]int{"book:1": {1, 2, 3, 4}, "book:2": {1, 2}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("SubscribeAsync() handled %v\n expected %v", got, expected)
+	}
+	if len(bus.subscriptions[0].last) != 0 {
+		t.Errorf("SubscribeAsync() kept queues of %d aggregates after handling", len(bus.subscriptions[0].last))
+	}
+}
@@ -0,0 +1,320 @@
+package pipelines
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+	"book-management-system/lifecycle"
+	"book-management-system/logging"
+	"book-management-system/metrics"
+	"book-management-system/repositories"
+	"book-management-system/repositories/elasticsearch"
+	"book-management-system/repositories/mysql"
+	"book-management-system/tracing"
+	"book-management-system/usecases/eventbus"
+)
+
+const (
+	// duplicateBatchSize is number of books read and candidates saved at once
+	duplicateBatchSize = 500
+	// similarBooksSize is number of similar titles compared with every book
+	similarBooksSize = 10
+	// titleSimilarityThreshold is the lowest title similarity reported as duplicate
+	titleSimilarityThreshold = 0.8
+)
+
+var (
+	// ErrDetectionRunning returned when duplicate detection is started twice
+	ErrDetectionRunning = errors.New("duplicate detection is already running")
+)
+
+// BookDuplicatePipeline finds duplicate book records and merges them.
+// Books are compared by ISBN and title, authors are not part of the catalogue yet.
+type BookDuplicatePipeline interface {
+	DetectDuplicates(context.Context) (int, error)
+	StartDetection(context.Context) error
+	GetDuplicates(context.Context, models.BookDuplicateStatus) ([]models.BookDuplicate, error)
+	MergeDuplicate(context.Context, uint, uint) (*models.Book, error)
+	DismissDuplicate(context.Context, uint) error
+}
+
+type bookDuplicatePipeline struct {
+	MySQLBookRepository          mysql.BookRepository
+	MySQLBookDuplicateRepository mysql.BookDuplicateRepository
+	ESBookRepository             elasticsearch.BookRepository
+	bus                          *eventbus.Bus
+	detecting                    int32
+}
+
+// NewBookDuplicatePipeline returns BookDuplicatePipeline publishing merged books to bus
+func NewBookDuplicatePipeline(repo *repositories.Repository, bus *eventbus.Bus) BookDuplicatePipeline {
+	return &bookDuplicatePipeline{
+		MySQLBookRepository:          repo.MySQLBookRepository,
+		MySQLBookDuplicateRepository: repo.MySQLBookDuplicateRepository,
+		ESBookRepository:             repo.ESBookRepository,
+		bus:                          bus,
+	}
+}
+
+// bookPair is key of candidate, lower ID first
+type bookPair struct {
+	bookID      uint
+	duplicateID uint
+}
+
+// DetectDuplicates scores candidate pairs of whole catalogue and saves them for review.
+// It returns number of candidates found.
+func (p *bookDuplicatePipeline) DetectDuplicates(ctx context.Context) (int, error) {
+	candidates := make(map[bookPair]models.BookDuplicate)
+	addCandidate := func(a, b *models.Book) {
+		duplicate, ok := scoreDuplicate(a, b)
+		if !ok {
+			return
+		}
+		key := bookPair{bookID: duplicate.BookID, duplicateID: duplicate.DuplicateID}
+		if _, found := candidates[key]; !found {
+			candidates[key] = duplicate
+		}
+	}
+
+	firstByISBN := make(map[objects.ISBN]models.Book)
+	var afterID uint
+	for {
+		books, err := p.detectBatch(ctx, afterID, firstByISBN, addCandidate)
+		if err != nil {
+			return 0, err
+		}
+		if len(books) == 0 {
+			break
+		}
+		afterID = books[len(books)-1].ID
+	}
+
+	duplicates := make([]models.BookDuplicate, 0, len(candidates))
+	for _, duplicate := range candidates {
+		duplicates = append(duplicates, duplicate)
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].BookID != duplicates[j].BookID {
+			return duplicates[i].BookID < duplicates[j].BookID
+		}
+		return duplicates[i].DuplicateID < duplicates[j].DuplicateID
+	})
+
+	for start := 0; start < len(duplicates); start += duplicateBatchSize {
+		end := start + duplicateBatchSize
+		if end > len(duplicates) {
+			end = len(duplicates)
+		}
+		if err := p.saveDuplicates(ctx, duplicates[start:end]); err != nil {
+			return 0, err
+		}
+	}
+	return len(duplicates), nil
+}
+
+// detectBatch compares next batch of books with books of the same ISBN and similar titles
+func (p *bookDuplicatePipeline) detectBatch(
+	ctx context.Context,
+	afterID uint,
+	firstByISBN map[objects.ISBN]models.Book,
+	addCandidate func(a, b *models.Book),
+) (models.Books, error) {
+	ctx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	books, err := p.MySQLBookRepository.GetBooksAfterID(ctx, afterID, duplicateBatchSize, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range books {
+		book := &books[i]
+		if !book.ISBN.IsZero() {
+			if first, ok := firstByISBN[book.ISBN]; ok {
+				addCandidate(&first, book)
+			} else {
+				firstByISBN[book.ISBN] = *book
+			}
+		}
+
+		similar, err := p.ESBookRepository.SearchSimilarBooks(ctx, book, similarBooksSize)
+		if err != nil {
+			return nil, fmt.Errorf("search books similar to book %d: %w", book.ID, err)
+		}
+		for j := range similar {
+			if similar[j].ID != book.ID {
+				addCandidate(book, &similar[j])
+			}
+		}
+	}
+	return books, nil
+}
+
+func (p *bookDuplicatePipeline) saveDuplicates(ctx context.Context, duplicates []models.BookDuplicate) error {
+	ctx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	return p.MySQLBookDuplicateRepository.SaveDuplicates(ctx, duplicates)
+}
+
+// StartDetection detects duplicates in background, only one detection runs at a time
+func (p *bookDuplicatePipeline) StartDetection(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&p.detecting, 0, 1) {
+		return ErrDetectionRunning
+	}
+
+	done := metrics.StartTask(metrics.TaskDuplicateDetection)
+	lifecycle.Go(tracing.Detach(ctx), func(ctx context.Context) {
+		defer atomic.StoreInt32(&p.detecting, 0)
+
+		count, err := p.DetectDuplicates(ctx)
+		done(err)
+		logger := logging.FromContext(ctx, logging.PackagePipelines)
+		if err != nil {
+			logger.Error("failed to detect duplicate books", zap.Error(err))
+			return
+		}
+		logger.Info("detected duplicate books", zap.Int("candidates", count))
+	})
+	return nil
+}
+
+// GetDuplicates returns candidates with status together with both books
+func (p *bookDuplicatePipeline) GetDuplicates(
+	ctx context.Context,
+	status models.BookDuplicateStatus,
+) ([]models.BookDuplicate, error) {
+	switch status {
+	case models.BookDuplicatePending, models.BookDuplicateMerged, models.BookDuplicateDismissed:
+	default:
+		return nil, fmt.Errorf("%w: unknown duplicate status %q", models.ErrInvalid, status)
+	}
+
+	ctx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	duplicates, err := p.MySQLBookDuplicateRepository.GetDuplicates(ctx, status)
+	if err != nil || len(duplicates) == 0 {
+		return duplicates, err
+	}
+
+	ids := make([]uint, 0, 2*len(duplicates))
+	for _, duplicate := range duplicates {
+		ids = append(ids, duplicate.BookID, duplicate.DuplicateID)
+	}
+	books, err := p.MySQLBookRepository.GetBooksAfterID(ctx, 0, len(ids), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// merged books are deleted and stay nil
+	byID := make(map[uint]*models.Book, len(books))
+	for i := range books {
+		byID[books[i].ID] = &books[i]
+	}
+	for i := range duplicates {
+		duplicates[i].Book = byID[duplicates[i].BookID]
+		duplicates[i].Duplicate = byID[duplicates[i].DuplicateID]
+	}
+	return duplicates, nil
+}
+
+// MergeDuplicate merges the other book of candidate into survivor and returns survivor.
+// Zero survivorID keeps the older book. Survivor takes ISBN of the other book when it has none.
+func (p *bookDuplicatePipeline) MergeDuplicate(ctx context.Context, id uint, survivorID uint) (*models.Book, error) {
+	ctx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	duplicate, err := p.MySQLBookDuplicateRepository.GetDuplicateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate.Status != models.BookDuplicatePending {
+		return nil, fmt.Errorf("%w: duplicate is already %s", models.ErrInvalid, duplicate.Status)
+	}
+
+	var loserID uint
+	switch survivorID {
+	case 0, duplicate.BookID:
+		survivorID, loserID = duplicate.BookID, duplicate.DuplicateID
+	case duplicate.DuplicateID:
+		loserID = duplicate.BookID
+	default:
+		return nil, fmt.Errorf("%w: survivor must be book %d or %d",
+			models.ErrInvalid, duplicate.BookID, duplicate.DuplicateID)
+	}
+
+	survivor, err := p.MySQLBookRepository.GetBookByID(ctx, survivorID)
+	if err != nil {
+		return nil, err
+	}
+	loser, err := p.MySQLBookRepository.GetBookByID(ctx, loserID)
+	if err != nil {
+		return nil, err
+	}
+	if survivor.ISBN.IsZero() {
+		survivor.ISBN = loser.ISBN
+	}
+
+	if err := p.MySQLBookDuplicateRepository.MergeBooks(ctx, survivor, loser); err != nil {
+		return nil, err
+	}
+	metrics.BooksMerged.Inc()
+
+	p.bus.Publish(ctx, eventbus.BookUpdated{Book: *survivor})
+	p.bus.Publish(ctx, eventbus.BookDeleted{ID: loser.ID, MergedInto: survivor.ID})
+	return survivor, nil
+}
+
+// DismissDuplicate marks candidate as not duplicate, it is kept so detection does not report it again
+func (p *bookDuplicatePipeline) DismissDuplicate(ctx context.Context, id uint) error {
+	ctx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	duplicate, err := p.MySQLBookDuplicateRepository.GetDuplicateByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if duplicate.Status != models.BookDuplicatePending {
+		return fmt.Errorf("%w: duplicate is already %s", models.ErrInvalid, duplicate.Status)
+	}
+	return p.MySQLBookDuplicateRepository.UpdateDuplicateStatus(ctx, id, models.BookDuplicateDismissed)
+}
+
+// scoreDuplicate scores pair of books, score is 1 for the same ISBN, else similarity of titles.
+// Books with different ISBNs are different editions and never duplicates.
+func scoreDuplicate(a, b *models.Book) (models.BookDuplicate, bool) {
+	if a.ID > b.ID {
+		a, b = b, a
+	}
+	duplicate := models.BookDuplicate{
+		BookID:      a.ID,
+		DuplicateID: b.ID,
+		Reasons:     models.BookDuplicateReasons{},
+		Status:      models.BookDuplicatePending,
+	}
+
+	switch {
+	case a.ISBN.IsZero() || b.ISBN.IsZero():
+	case a.ISBN == b.ISBN:
+		duplicate.Score = 1
+		duplicate.Reasons = append(duplicate.Reasons, models.BookDuplicateSameISBN)
+	default:
+		return models.BookDuplicate{}, false
+	}
+
+	if similarity := titleSimilarity(a.Name, b.Name); similarity >= titleSimilarityThreshold {
+		duplicate.Score = math.Max(duplicate.Score, math.Round(similarity*100)/100)
+		duplicate.Reasons = append(duplicate.Reasons, models.BookDuplicateSimilarTitle)
+	}
+	return duplicate, len(duplicate.Reasons) > 0
+}
@@ -0,0 +1,302 @@
+package pipelines
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+	esMocks "book-management-system/mocks/repositories/elasticsearch"
+	mySqlMocks "book-management-system/mocks/repositories/mysql"
+	"book-management-system/repositories"
+	"book-management-system/repositories/elasticsearch"
+	"book-management-system/repositories/mysql"
+	"book-management-system/usecases/eventbus"
+)
+
+func TestNewBookDuplicatePipeline(t *testing.T) {
+	mySQLBookRepo := mysql.NewBookRepository(nil)
+	mySQLBookDuplicateRepo := mysql.NewBookDuplicateRepository(nil)
+	esBookRepo := elasticsearch.NewBookRepository(nil)
+	repo := &repositories.Repository{
+		MySQLBookRepository:          mySQLBookRepo,
+		MySQLBookDuplicateRepository: mySQLBookDuplicateRepo,
+		ESBookRepository:             esBookRepo,
+	}
+
+	bus := eventbus.New()
+
+	got := NewBookDuplicatePipeline(repo, bus)
+	expected := &bookDuplicatePipeline{
+		MySQLBookRepository:          mySQLBookRepo,
+		MySQLBookDuplicateRepository: mySQLBookDuplicateRepo,
+		ESBookRepository:             esBookRepo,
+		bus:                          bus,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewBookDuplicatePipeline returns %+v\n expected %+v",
+			got, expected)
+	}
+}
+
+func TestBookDuplicatePipelineDetectDuplicates(t *testing.T) {
+	books := models.Books{
+		{Model: gorm.Model{ID: 1}, Name: "The Alchemist", ISBN: "9780062315007"},
+		{Model: gorm.Model{ID: 2}, Name: "The Alchemist.", ISBN: ""},
+		{Model: gorm.Model{ID: 3}, Name: "Alchemist, The", ISBN: "9780062315007"},
+		{Model: gorm.Model{ID: 4}, Name: "The Alchemist", ISBN: "9780261103283"},
+		{Model: gorm.Model{ID: 5}, Name: "The Hobbit", ISBN: "9780140449136"},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mySQLBookRepoMock := mySqlMocks.NewMockBookRepository(ctrl)
+	mySQLBookDuplicateRepoMock := mySqlMocks.NewMockBookDuplicateRepository(ctrl)
+	esBookRepoMock := esMocks.NewMockBookRepository(ctrl)
+
+	mySQLBookRepoMock.EXPECT().
+		GetBooksAfterID(gomock.Any(), uint(0), duplicateBatchSize, nil).
+		Return(books, nil)
+	mySQLBookRepoMock.EXPECT().
+		GetBooksAfterID(gomock.Any(), uint(5), duplicateBatchSize, nil).
+		Return(models.Books{}, nil)
+
+	similar := map[uint]models.Books{
+		1: {books[1], books[3]},
+		2: {books[0]},
+		3: {books[0]},
+		4: {books[0], books[1]},
+		5: {},
+	}
+	esBookRepoMock.EXPECT().
+		SearchSimilarBooks(gomock.Any(), gomock.Any(), similarBooksSize).
+		DoAndReturn(func(_ context.Context, book *models.Book, _ int) (models.Books, error) {
+			return similar[book.ID], nil
+		}).
+		Times(len(books))
+
+	mySQLBookDuplicateRepoMock.EXPECT().
+		SaveDuplicates(gomock.Any(), []models.BookDuplicate{
+			{
+				BookID:      1,
+				DuplicateID: 2,
+				Score:       1,
+				Reasons:     models.BookDuplicateReasons{models.BookDuplicateSimilarTitle},
+				Status:      models.BookDuplicatePending,
+			},
+			{
+				BookID:      1,
+				DuplicateID: 3,
+				Score:       1,
+				Reasons:     models.BookDuplicateReasons{models.BookDuplicateSameISBN, models.BookDuplicateSimilarTitle},
+				Status:      models.BookDuplicatePending,
+			},
+			{
+				BookID:      2,
+				DuplicateID: 4,
+				Score:       1,
+				Reasons:     models.BookDuplicateReasons{models.BookDuplicateSimilarTitle},
+				Status:      models.BookDuplicatePending,
+			},
+		}).
+		Return(nil)
+
+	bookDuplicatePipeline := &bookDuplicatePipeline{
+		MySQLBookRepository:          mySQLBookRepoMock,
+		MySQLBookDuplicateRepository: mySQLBookDuplicateRepoMock,
+		ESBookRepository:             esBookRepoMock,
+	}
+
+	count, err := bookDuplicatePipeline.DetectDuplicates(context.TODO())
+	if err != nil {
+		t.Errorf("DetectDuplicates() got error %v", err)
+	}
+	if count != 3 {
+		t.Errorf("DetectDuplicates() got count %d\n expected %d", count, 3)
+	}
+}
+
+func TestBookDuplicatePipelineMergeDuplicate(t *testing.T) {
+	type input struct {
+		ctx        context.Context
+		id         uint
+		survivorID uint
+	}
+	type output struct {
+		book      *models.Book
+		err       error
+		published []eventbus.Event
+	}
+	type mockConfig struct {
+		given                      input
+		expected                   output
+		mySQLBookRepoMock          *mySqlMocks.MockBookRepository
+		mySQLBookDuplicateRepoMock *mySqlMocks.MockBookDuplicateRepository
+	}
+
+	pending := &models.BookDuplicate{
+		ID:          7,
+		BookID:      1,
+		DuplicateID: 2,
+		Status:      models.BookDuplicatePending,
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "older book survives and takes ISBN",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  7,
+			},
+			expectedOutput: output{
+				book: &models.Book{Model: gorm.Model{ID: 1}, Name: "The Alchemist", ISBN: "9780062315007"},
+				published: []eventbus.Event{
+					eventbus.BookUpdated{Book: models.Book{Model: gorm.Model{ID: 1}, Name: "The Alchemist", ISBN: "9780062315007"}},
+					eventbus.BookDeleted{ID: 2, MergedInto: 1},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				loser := &models.Book{Model: gorm.Model{ID: 2}, Name: "The Alchemist.", ISBN: "9780062315007"}
+				conf.mySQLBookDuplicateRepoMock.EXPECT().
+					GetDuplicateByID(gomock.Any(), conf.given.id).
+					Return(pending, nil)
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBookByID(gomock.Any(), uint(1)).
+					Return(&models.Book{Model: gorm.Model{ID: 1}, Name: "The Alchemist"}, nil)
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBookByID(gomock.Any(), uint(2)).
+					Return(loser, nil)
+				conf.mySQLBookDuplicateRepoMock.EXPECT().
+					MergeBooks(gomock.Any(), conf.expected.book, loser).
+					Return(nil)
+			},
+		},
+		{
+			name: "survivor is not book of pair",
+			givenInput: input{
+				ctx:        context.TODO(),
+				id:         7,
+				survivorID: 3,
+			},
+			expectedOutput: output{
+				err: models.ErrInvalid,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLBookDuplicateRepoMock.EXPECT().
+					GetDuplicateByID(gomock.Any(), conf.given.id).
+					Return(pending, nil)
+			},
+		},
+		{
+			name: "duplicate already dismissed",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  7,
+			},
+			expectedOutput: output{
+				err: models.ErrInvalid,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLBookDuplicateRepoMock.EXPECT().
+					GetDuplicateByID(gomock.Any(), conf.given.id).
+					Return(&models.BookDuplicate{ID: 7, BookID: 1, DuplicateID: 2, Status: models.BookDuplicateDismissed}, nil)
+			},
+		},
+		{
+			name: "merge failed",
+			givenInput: input{
+				ctx:        context.TODO(),
+				id:         7,
+				survivorID: 2,
+			},
+			expectedOutput: output{
+				err: errRepository,
+			},
+			configureMock: func(conf mockConfig) {
+				survivor := &models.Book{Model: gorm.Model{ID: 2}, Name: "The Alchemist."}
+				loser := &models.Book{Model: gorm.Model{ID: 1}, Name: "The Alchemist"}
+				conf.mySQLBookDuplicateRepoMock.EXPECT().
+					GetDuplicateByID(gomock.Any(), conf.given.id).
+					Return(pending, nil)
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBookByID(gomock.Any(), survivor.ID).
+					Return(survivor, nil)
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBookByID(gomock.Any(), loser.ID).
+					Return(loser, nil)
+				conf.mySQLBookDuplicateRepoMock.EXPECT().
+					MergeBooks(gomock.Any(), survivor, loser).
+					Return(errRepository)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mySQLBookRepoMock := mySqlMocks.NewMockBookRepository(ctrl)
+			mySQLBookDuplicateRepoMock := mySqlMocks.NewMockBookDuplicateRepository(ctrl)
+
+			var published []eventbus.Event
+			bookDuplicatePipeline := &bookDuplicatePipeline{
+				MySQLBookRepository:          mySQLBookRepoMock,
+				MySQLBookDuplicateRepository: mySQLBookDuplicateRepoMock,
+				bus:                          recordingBus(&published),
+			}
+
+			tt.configureMock(mockConfig{
+				given:                      tt.givenInput,
+				expected:                   tt.expectedOutput,
+				mySQLBookRepoMock:          mySQLBookRepoMock,
+				mySQLBookDuplicateRepoMock: mySQLBookDuplicateRepoMock,
+			})
+
+			book, err := bookDuplicatePipeline.MergeDuplicate(tt.givenInput.ctx, tt.givenInput.id, tt.givenInput.survivorID)
+			if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+				t.Errorf("MergeDuplicate() got error %v\n expected %v",
+					err, expectedError)
+			}
+			if expectedBook := tt.expectedOutput.book; !reflect.DeepEqual(book, expectedBook) {
+				t.Errorf("MergeDuplicate() got book %+v\n expected %+v",
+					book, expectedBook)
+			}
+			if expected := tt.expectedOutput.published; !reflect.DeepEqual(published, expected) {
+				t.Errorf("MergeDuplicate() published %+v\n expected %+v", published, expected)
+			}
+		})
+	}
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected float64
+	}{
+		{a: "The Alchemist", b: "the alchemist.", expected: 1},
+		{a: "Alchemist, The", b: "The Alchemist", expected: 0.83},
+		{a: "The Alchemist", b: "The Hobbit", expected: 0.29},
+		{a: "C", b: "Go", expected: 0},
+	}
+
+	for _, tt := range tests {
+		got := titleSimilarity(tt.a, tt.b)
+		if rounded := float64(int(got*100+0.5)) / 100; rounded != tt.expected {
+			t.Errorf("titleSimilarity(%q, %q) returns %.2f\n expected %.2f",
+				tt.a, tt.b, rounded, tt.expected)
+		}
+	}
+}
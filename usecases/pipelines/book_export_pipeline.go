@@ -0,0 +1,100 @@
+package pipelines
+
+import (
+	"context"
+	"io"
+
+	"book-management-system/entities/models"
+	"book-management-system/repositories"
+	"book-management-system/repositories/elasticsearch"
+	"book-management-system/repositories/mysql"
+)
+
+// exportBatchSize is number of books read from database at once
+const exportBatchSize = 500
+
+// BookExportFilter selects exported books, it mirrors book list filters
+type BookExportFilter struct {
+	Search string
+}
+
+// BookExportPipeline streams books from database into export file
+type BookExportPipeline interface {
+	ExportBooks(context.Context, io.Writer, ExportFormat, BookExportFilter) error
+}
+
+type bookExportPipeline struct {
+	MySQLBookRepository mysql.BookRepository
+	ESBookRepository    elasticsearch.BookRepository
+}
+
+// NewBookExportPipeline returns BookExportPipeline
+func NewBookExportPipeline(repo *repositories.Repository) BookExportPipeline {
+	return &bookExportPipeline{
+		MySQLBookRepository: repo.MySQLBookRepository,
+		ESBookRepository:    repo.ESBookRepository,
+	}
+}
+
+// ExportBooks writes books matching filter to w batch by batch.
+// Nothing is written to w when the filter cannot be resolved.
+func (p *bookExportPipeline) ExportBooks(
+	ctx context.Context,
+	w io.Writer,
+	format ExportFormat,
+	filter BookExportFilter,
+) error {
+	ids, err := p.filterBookIDs(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	writer, err := newBookWriter(w, format)
+	if err != nil {
+		return err
+	}
+	if ids != nil && len(ids) == 0 {
+		return writer.Flush()
+	}
+
+	var afterID uint
+	for {
+		books, err := p.getBooksAfterID(ctx, afterID, ids)
+		if err != nil {
+			return err
+		}
+
+		for i := range books {
+			if err := writer.Write(&books[i]); err != nil {
+				return err
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+
+		if len(books) < exportBatchSize {
+			return nil
+		}
+		afterID = books[len(books)-1].ID
+	}
+}
+
+// filterBookIDs returns IDs of all books matching search keyword, or nil when filter is empty
+func (p *bookExportPipeline) filterBookIDs(ctx context.Context, filter BookExportFilter) ([]uint, error) {
+	if filter.Search == "" {
+		return nil, nil
+	}
+
+	ctx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	return p.ESBookRepository.SearchBookIDs(ctx, filter.Search)
+}
+
+func (p *bookExportPipeline) getBooksAfterID(ctx context.Context, afterID uint, ids []uint) (models.Books, error) {
+	ctx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	return p.MySQLBookRepository.GetBooksAfterID(ctx, afterID, exportBatchSize, ids)
+}
@@ -0,0 +1,251 @@
+package pipelines
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+	esMocks "book-management-system/mocks/repositories/elasticsearch"
+	mySqlMocks "book-management-system/mocks/repositories/mysql"
+	"book-management-system/repositories"
+	"book-management-system/repositories/elasticsearch"
+	"book-management-system/repositories/mysql"
+)
+
+func TestNewBookExportPipeline(t *testing.T) {
+	mySQLBookRepo := mysql.NewBookRepository(nil)
+	esBookRepo := elasticsearch.NewBookRepository(nil)
+	repo := &repositories.Repository{
+		MySQLBookRepository: mySQLBookRepo,
+		ESBookRepository:    esBookRepo,
+	}
+
+	got := NewBookExportPipeline(repo)
+	expected := &bookExportPipeline{
+		MySQLBookRepository: mySQLBookRepo,
+		ESBookRepository:    esBookRepo,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewBookExportPipeline returns %+v\n expected %+v",
+			got, expected)
+	}
+}
+
+func TestBookExportPipelineExportBooks(t *testing.T) {
+	updatedAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	book := models.Book{
+		Model: gorm.Model{
+			ID:        1,
+			CreatedAt: updatedAt,
+			UpdatedAt: updatedAt,
+		},
+		Name: "The Alchemist",
+		ISBN: "9780062315007",
+	}
+	// search of more books than a page of search hits
+	manyIDs := make([]uint, 0, 25)
+	manyBooks := make(models.Books, 0, 25)
+	manyFile := "id,name,isbn,created_at,updated_at\n"
+	for id := uint(1); id <= 25; id++ {
+		manyIDs = append(manyIDs, id)
+		manyBooks = append(manyBooks, models.Book{Model: gorm.Model{ID: id, CreatedAt: updatedAt, UpdatedAt: updatedAt},
+			Name: "The Alchemist"})
+		manyFile += fmt.Sprintf("%d,The Alchemist,,2021-01-02T03:04:05Z,2021-01-02T03:04:05Z\n", id)
+	}
+
+	type input struct {
+		ctx    context.Context
+		format ExportFormat
+		filter BookExportFilter
+	}
+	type output struct {
+		file string
+		err  error
+	}
+	type mockConfig struct {
+		given             input
+		mySQLBookRepoMock *mySqlMocks.MockBookRepository
+		esBookRepoMock    *esMocks.MockBookRepository
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "csv export of all books",
+			givenInput: input{
+				ctx:    context.TODO(),
+				format: ExportFormatCSV,
+			},
+			expectedOutput: output{
+				file: "id,name,isbn,created_at,updated_at\n" +
+					"1,The Alchemist,9780062315007,2021-01-02T03:04:05Z,2021-01-02T03:04:05Z\n",
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBooksAfterID(gomock.Any(), uint(0), exportBatchSize, nil).
+					Return(models.Books{book}, nil)
+			},
+		},
+		{
+			name: "jsonl export of searched books",
+			givenInput: input{
+				ctx:    context.TODO(),
+				format: ExportFormatJSONL,
+				filter: BookExportFilter{Search: "alchemist"},
+			},
+			expectedOutput: output{
+				file: `{"ID":1,"CreatedAt":"2021-01-02T03:04:05Z","UpdatedAt":"2021-01-02T03:04:05Z",` +
+					`"DeletedAt":null,"name":"The Alchemist","isbn":"9780062315007"}` + "\n",
+			},
+			configureMock: func(conf mockConfig) {
+				conf.esBookRepoMock.EXPECT().
+					SearchBookIDs(gomock.Any(), conf.given.filter.Search).
+					Return([]uint{1}, nil)
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBooksAfterID(gomock.Any(), uint(0), exportBatchSize, []uint{1}).
+					Return(models.Books{book}, nil)
+			},
+		},
+		{
+			name: "csv export of search with more matches than a page of hits",
+			givenInput: input{
+				ctx:    context.TODO(),
+				format: ExportFormatCSV,
+				filter: BookExportFilter{Search: "alchemist"},
+			},
+			expectedOutput: output{
+				file: manyFile,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.esBookRepoMock.EXPECT().
+					SearchBookIDs(gomock.Any(), conf.given.filter.Search).
+					Return(manyIDs, nil)
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBooksAfterID(gomock.Any(), uint(0), exportBatchSize, manyIDs).
+					Return(manyBooks, nil)
+			},
+		},
+		{
+			name: "marc export of search without results",
+			givenInput: input{
+				ctx:    context.TODO(),
+				format: ExportFormatMARC,
+				filter: BookExportFilter{Search: "nothing"},
+			},
+			expectedOutput: output{
+				file: "",
+			},
+			configureMock: func(conf mockConfig) {
+				conf.esBookRepoMock.EXPECT().
+					SearchBookIDs(gomock.Any(), conf.given.filter.Search).
+					Return([]uint{}, nil)
+			},
+		},
+		{
+			name: "error search",
+			givenInput: input{
+				ctx:    context.TODO(),
+				format: ExportFormatCSV,
+				filter: BookExportFilter{Search: "alchemist"},
+			},
+			expectedOutput: output{
+				err: errRepository,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.esBookRepoMock.EXPECT().
+					SearchBookIDs(gomock.Any(), conf.given.filter.Search).
+					Return(nil, errRepository)
+			},
+		},
+		{
+			name: "unsupported format",
+			givenInput: input{
+				ctx:    context.TODO(),
+				format: "xml",
+			},
+			expectedOutput: output{
+				err: ErrUnsupportedExportFormat,
+			},
+			configureMock: func(mockConfig) {
+				// do nothing
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mySQLBookRepoMock := mySqlMocks.NewMockBookRepository(ctrl)
+			esBookRepoMock := esMocks.NewMockBookRepository(ctrl)
+
+			bookExportPipeline := &bookExportPipeline{
+				MySQLBookRepository: mySQLBookRepoMock,
+				ESBookRepository:    esBookRepoMock,
+			}
+
+			tt.configureMock(mockConfig{
+				given:             tt.givenInput,
+				mySQLBookRepoMock: mySQLBookRepoMock,
+				esBookRepoMock:    esBookRepoMock,
+			})
+
+			var file bytes.Buffer
+			err := bookExportPipeline.ExportBooks(tt.givenInput.ctx, &file, tt.givenInput.format, tt.givenInput.filter)
+			if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+				t.Errorf("ExportBooks() got error: %v\nexpected: %v",
+					err, expectedError)
+			}
+			if file.String() != tt.expectedOutput.file {
+				t.Errorf("ExportBooks() got file %q\n expected %q",
+					file.String(), tt.expectedOutput.file)
+			}
+		})
+	}
+}
+
+func TestBookExportPipelineExportBooksInBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	firstBatch := make(models.Books, exportBatchSize)
+	for i := range firstBatch {
+		firstBatch[i] = models.Book{Model: gorm.Model{ID: uint(i + 1)}, Name: "Book"}
+	}
+
+	mySQLBookRepoMock := mySqlMocks.NewMockBookRepository(ctrl)
+	gomock.InOrder(
+		mySQLBookRepoMock.EXPECT().
+			GetBooksAfterID(gomock.Any(), uint(0), exportBatchSize, nil).
+			Return(firstBatch, nil),
+		mySQLBookRepoMock.EXPECT().
+			GetBooksAfterID(gomock.Any(), uint(exportBatchSize), exportBatchSize, nil).
+			Return(models.Books{}, nil),
+	)
+
+	bookExportPipeline := &bookExportPipeline{
+		MySQLBookRepository: mySQLBookRepoMock,
+	}
+
+	var file bytes.Buffer
+	if err := bookExportPipeline.ExportBooks(context.TODO(), &file, ExportFormatMARC, BookExportFilter{}); err != nil {
+		t.Fatalf("ExportBooks() got error: %v", err)
+	}
+	if records := bytes.Count(file.Bytes(), []byte{0x1D}); records != exportBatchSize {
+		t.Errorf("ExportBooks() wrote %d records\n expected %d", records, exportBatchSize)
+	}
+}
@@ -0,0 +1,146 @@
+package pipelines
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"book-management-system/entities/models"
+	"book-management-system/usecases/pipelines/marc"
+)
+
+// ExportFormat is file format of book export
+type ExportFormat string
+
+// ExportFormat values
+const (
+	ExportFormatCSV   ExportFormat = "csv"
+	ExportFormatJSONL ExportFormat = "jsonl"
+	ExportFormatMARC  ExportFormat = "marc"
+)
+
+var (
+	// ErrUnsupportedExportFormat returned when export format is not csv, jsonl or marc
+	ErrUnsupportedExportFormat = errors.New("unsupported export format")
+)
+
+// exportCSVHeader is header row of csv export, it can be imported back
+var exportCSVHeader = []string{"id", "name", "isbn", "created_at", "updated_at"}
+
+// ContentType returns media type of export format
+func (format ExportFormat) ContentType() string {
+	switch format {
+	case ExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case ExportFormatJSONL:
+		return "application/x-ndjson"
+	case ExportFormatMARC:
+		return "application/marc"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// FileExtension returns conventional file extension of export format
+func (format ExportFormat) FileExtension() string {
+	if format == ExportFormatMARC {
+		return "mrc"
+	}
+	return string(format)
+}
+
+// bookWriter writes exported books to underlying writer
+type bookWriter interface {
+	Write(*models.Book) error
+	Flush() error
+}
+
+// newBookWriter returns bookWriter of format, csv header is written immediately
+func newBookWriter(w io.Writer, format ExportFormat) (bookWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(exportCSVHeader); err != nil {
+			return nil, err
+		}
+		return &csvBookWriter{writer: writer}, nil
+	case ExportFormatJSONL:
+		return &jsonlBookWriter{encoder: json.NewEncoder(w)}, nil
+	case ExportFormatMARC:
+		return &marcBookWriter{writer: marc.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedExportFormat, format)
+	}
+}
+
+type csvBookWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvBookWriter) Write(book *models.Book) error {
+	return w.writer.Write([]string{
+		strconv.FormatUint(uint64(book.ID), 10),
+		book.Name,
+		book.ISBN.String(),
+		book.CreatedAt.UTC().Format(time.RFC3339),
+		book.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (w *csvBookWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type jsonlBookWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *jsonlBookWriter) Write(book *models.Book) error {
+	return w.encoder.Encode(book)
+}
+
+func (w *jsonlBookWriter) Flush() error {
+	return nil
+}
+
+type marcBookWriter struct {
+	writer *marc.Writer
+}
+
+func (w *marcBookWriter) Write(book *models.Book) error {
+	return w.writer.Write(bookToMARCRecord(book))
+}
+
+func (w *marcBookWriter) Flush() error {
+	return nil
+}
+
+// bookToMARCRecord maps book into bibliographic record:
+// 001 control number, 005 latest transaction, 020 ISBN and 245 title
+func bookToMARCRecord(book *models.Book) marc.Record {
+	fields := []marc.Field{
+		{Tag: "001", Value: strconv.FormatUint(uint64(book.ID), 10)},
+		{Tag: "005", Value: book.UpdatedAt.UTC().Format("20060102150405.0")},
+	}
+	if book.ISBN != "" {
+		fields = append(fields, marc.Field{
+			Tag:       "020",
+			Subfields: []marc.Subfield{{Code: 'a', Value: book.ISBN.String()}},
+		})
+	}
+	fields = append(fields, marc.Field{
+		Tag:        "245",
+		Indicators: [2]byte{'0', '0'},
+		Subfields:  []marc.Subfield{{Code: 'a', Value: book.Name}},
+	})
+
+	return marc.Record{
+		Leader: marc.DefaultLeader,
+		Fields: fields,
+	}
+}
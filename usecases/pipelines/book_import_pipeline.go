@@ -0,0 +1,328 @@
+package pipelines
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+	"book-management-system/lifecycle"
+	"book-management-system/logging"
+	"book-management-system/metrics"
+	"book-management-system/repositories"
+	"book-management-system/repositories/elasticsearch"
+	"book-management-system/repositories/mysql"
+	"book-management-system/tracing"
+	"book-management-system/usecases/eventbus"
+)
+
+// importBatchSize is number of rows inserted in one transaction
+const importBatchSize = 500
+
+// staleImportJobAge is how long a pending or running job goes without update before it is failed on startup.
+// Running jobs store progress after every batch, which is bounded by batchContextTimeout,
+// so a job not updated for much longer is no longer run by any instance.
+const staleImportJobAge = 10 * batchContextTimeout * time.Second
+
+// BookImportPipeline validates, deduplicates, stores and indexes imported books
+type BookImportPipeline interface {
+	ImportBooks(context.Context, []BookImportRecord) *models.BookImportReport
+	ImportCatalogRecords(context.Context, []CatalogImportRecord, bool) *models.BookImportReport
+	StartImportJob(context.Context, ImportFormat, []BookImportRecord) (*models.ImportJob, error)
+	GetImportJob(context.Context, uint) (*models.ImportJob, error)
+	FailStaleImportJobs(context.Context) error
+}
+
+type bookImportPipeline struct {
+	MySQLBookRepository      mysql.BookRepository
+	MySQLImportJobRepository mysql.ImportJobRepository
+	ESBookRepository         elasticsearch.BookRepository
+	bus                      *eventbus.Bus
+}
+
+// NewBookImportPipeline returns BookImportPipeline publishing imported books to bus
+func NewBookImportPipeline(repo *repositories.Repository, bus *eventbus.Bus) BookImportPipeline {
+	return &bookImportPipeline{
+		MySQLBookRepository:      repo.MySQLBookRepository,
+		MySQLImportJobRepository: repo.MySQLImportJobRepository,
+		ESBookRepository:         repo.ESBookRepository,
+		bus:                      bus,
+	}
+}
+
+// ImportBooks imports records synchronously and returns per row report
+func (p *bookImportPipeline) ImportBooks(ctx context.Context, records []BookImportRecord) *models.BookImportReport {
+	report, _ := p.importBooks(ctx, records, func(int) error { return nil })
+	return report
+}
+
+// StartImportJob stores pending job and imports records in background
+func (p *bookImportPipeline) StartImportJob(
+	ctx context.Context,
+	format ImportFormat,
+	records []BookImportRecord,
+) (*models.ImportJob, error) {
+	job := &models.ImportJob{
+		Status: models.ImportJobPending,
+		Format: string(format),
+	}
+
+	batchCtx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+	if err := p.MySQLImportJobRepository.CreateJob(batchCtx, job); err != nil {
+		return nil, err
+	}
+
+	jobCopy := *job
+	done := metrics.StartTask(metrics.TaskBookImport)
+	lifecycle.Go(tracing.Detach(ctx), func(ctx context.Context) {
+		done(p.runImportJob(ctx, &jobCopy, records))
+	})
+	return job, nil
+}
+
+func (p *bookImportPipeline) GetImportJob(ctx context.Context, id uint) (*models.ImportJob, error) {
+	ctx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	return p.MySQLImportJobRepository.GetJobByID(ctx, id)
+}
+
+// FailStaleImportJobs marks jobs left pending or running by a stopped instance as failed,
+// so that clients polling them do not wait forever
+func (p *bookImportPipeline) FailStaleImportJobs(ctx context.Context) error {
+	ctx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	failed, err := p.MySQLImportJobRepository.FailStaleJobs(ctx, time.Now().Add(-staleImportJobAge),
+		"import was interrupted by shutdown of the server")
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		logging.FromContext(ctx, logging.PackagePipelines).
+			Warn("failed stale import jobs", zap.Int64("jobs", failed))
+	}
+	return nil
+}
+
+// runImportJob imports records and stores progress of job. Job interrupted by cancellation of ctx,
+// failure to store its progress or a panic is stored as failed with the error.
+func (p *bookImportPipeline) runImportJob(
+	ctx context.Context,
+	job *models.ImportJob,
+	records []BookImportRecord,
+) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("import panicked: %v", r)
+		}
+		if err != nil {
+			p.failJob(ctx, job, err)
+		}
+	}()
+
+	job.Status = models.ImportJobRunning
+	if err := p.updateJob(ctx, job); err != nil {
+		return fmt.Errorf("failed to store import progress: %w", err)
+	}
+
+	report, err := p.importBooks(ctx, records, func(processed int) error {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("import was interrupted: %w", err)
+		}
+		job.Processed = processed
+		if err := p.updateJob(ctx, job); err != nil {
+			return fmt.Errorf("failed to store import progress: %w", err)
+		}
+		return nil
+	})
+	job.Report = *report
+	if err != nil {
+		return err
+	}
+
+	job.Status = models.ImportJobCompleted
+	if err := p.updateJob(ctx, job); err != nil {
+		return fmt.Errorf("failed to store import progress: %w", err)
+	}
+	return nil
+}
+
+// failJob stores job as failed with err, even when ctx was canceled by shutdown
+func (p *bookImportPipeline) failJob(ctx context.Context, job *models.ImportJob, err error) {
+	logger := logging.FromContext(ctx, logging.PackagePipelines)
+	logger.Error("import job failed", zap.Uint("job_id", job.ID), zap.Error(err))
+
+	job.Status = models.ImportJobFailed
+	job.Error = err.Error()
+	if err := p.updateJob(tracing.Detach(ctx), job); err != nil {
+		logger.Error("failed to store failed import job", zap.Uint("job_id", job.ID), zap.Error(err))
+	}
+}
+
+func (p *bookImportPipeline) updateJob(ctx context.Context, job *models.ImportJob) error {
+	ctx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	return p.MySQLImportJobRepository.UpdateJob(ctx, job)
+}
+
+// importBooks imports records in batches and reports progress after each of them,
+// it stops with the report of imported batches when progress fails
+func (p *bookImportPipeline) importBooks(
+	ctx context.Context,
+	records []BookImportRecord,
+	progress func(processed int) error,
+) (*models.BookImportReport, error) {
+	report := &models.BookImportReport{
+		Rows: make([]models.BookImportRow, 0, len(records)),
+	}
+	seenISBNs := make(map[objects.ISBN]int)
+
+	for start := 0; start < len(records); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+
+		for _, row := range p.importBatch(ctx, records[start:end], seenISBNs) {
+			report.Add(row)
+		}
+		if err := progress(end); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// importBatch returns report rows of batch in the same order as records
+func (p *bookImportPipeline) importBatch(
+	ctx context.Context,
+	records []BookImportRecord,
+	seenISBNs map[objects.ISBN]int,
+) []models.BookImportRow {
+	ctx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	rows := make([]models.BookImportRow, len(records))
+	candidates := make([]int, 0, len(records))
+	isbns := make([]objects.ISBN, 0, len(records))
+
+	for i, record := range records {
+		rows[i] = models.BookImportRow{Row: record.Row, ISBN: record.Book.ISBN}
+
+		err := record.Err
+		if err == nil {
+			err = record.Book.Validate()
+		}
+		if err != nil {
+			rows[i].Status = models.BookImportRowError
+			rows[i].Message = err.Error()
+			continue
+		}
+
+		if isbn := record.Book.ISBN; !isbn.IsZero() {
+			if row, ok := seenISBNs[isbn]; ok {
+				rows[i].Status = models.BookImportRowDuplicate
+				rows[i].Message = fmt.Sprintf("duplicate of row %d", row)
+				continue
+			}
+			seenISBNs[isbn] = record.Row
+			isbns = append(isbns, isbn)
+		}
+		candidates = append(candidates, i)
+	}
+
+	existingBooks := make(map[objects.ISBN]uint)
+	if len(isbns) > 0 {
+		books, err := p.MySQLBookRepository.GetBooksByISBNs(ctx, isbns)
+		if err != nil {
+			for _, i := range candidates {
+				rows[i].Status = models.BookImportRowError
+				rows[i].Message = err.Error()
+			}
+			return rows
+		}
+		for _, book := range books {
+			existingBooks[book.ISBN] = book.ID
+		}
+	}
+
+	books := make(models.Books, 0, len(candidates))
+	positions := make([]int, 0, len(candidates))
+	for _, i := range candidates {
+		if id, ok := existingBooks[records[i].Book.ISBN]; ok {
+			rows[i].Status = models.BookImportRowDuplicate
+			rows[i].BookID = id
+			rows[i].Message = fmt.Sprintf("book with ISBN already exists as book %d", id)
+			continue
+		}
+		books = append(books, records[i].Book)
+		positions = append(positions, i)
+	}
+
+	created := p.createBooks(ctx, books, positions, rows)
+	metrics.BooksCreated.WithLabelValues(metrics.SourceImport).Add(float64(len(created)))
+	p.indexBooks(ctx, nil, created)
+	return rows
+}
+
+// indexBooks indexes books updated and created by import in bulk, then publishes their events.
+// Books of failed bulk are indexed one by one by subscribers of the events.
+func (p *bookImportPipeline) indexBooks(ctx context.Context, updated, created models.Books) {
+	books := make(models.Books, 0, len(updated)+len(created))
+	books = append(append(books, updated...), created...)
+	err := p.ESBookRepository.BulkIndexBooks(ctx, books)
+	if err != nil {
+		logging.FromContext(ctx, logging.PackagePipelines).
+			Error("failed to index imported books", zap.Int("books", len(books)), zap.Error(err))
+	}
+
+	for _, book := range updated {
+		p.bus.Publish(ctx, eventbus.BookUpdated{Book: book, Indexed: err == nil})
+	}
+	for _, book := range created {
+		p.bus.Publish(ctx, eventbus.BookCreated{Book: book, Indexed: err == nil})
+	}
+}
+
+// createBooks inserts books in one transaction and falls back to
+// row by row insert to find failing rows when the transaction fails.
+func (p *bookImportPipeline) createBooks(
+	ctx context.Context,
+	books models.Books,
+	positions []int,
+	rows []models.BookImportRow,
+) models.Books {
+	if len(books) == 0 {
+		return books
+	}
+
+	if err := p.MySQLBookRepository.CreateBooks(ctx, books); err == nil {
+		for j, i := range positions {
+			rows[i].Status = models.BookImportRowCreated
+			rows[i].BookID = books[j].ID
+		}
+		return books
+	}
+
+	created := make(models.Books, 0, len(books))
+	for j, i := range positions {
+		book := books[j]
+		book.ID = 0
+		if err := p.MySQLBookRepository.CreateBook(ctx, &book); err != nil {
+			rows[i].Status = models.BookImportRowError
+			rows[i].Message = err.Error()
+			continue
+		}
+		rows[i].Status = models.BookImportRowCreated
+		rows[i].BookID = book.ID
+		created = append(created, book)
+	}
+	return created
+}
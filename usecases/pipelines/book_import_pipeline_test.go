@@ -0,0 +1,345 @@
+package pipelines
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+	esMocks "book-management-system/mocks/repositories/elasticsearch"
+	mySqlMocks "book-management-system/mocks/repositories/mysql"
+	"book-management-system/repositories"
+	"book-management-system/repositories/elasticsearch"
+	"book-management-system/repositories/mysql"
+	"book-management-system/usecases/eventbus"
+)
+
+func TestNewBookImportPipeline(t *testing.T) {
+	mySQLBookRepo := mysql.NewBookRepository(nil)
+	mySQLImportJobRepo := mysql.NewImportJobRepository(nil)
+	esBookRepo := elasticsearch.NewBookRepository(nil)
+	repo := &repositories.Repository{
+		MySQLBookRepository:      mySQLBookRepo,
+		MySQLImportJobRepository: mySQLImportJobRepo,
+		ESBookRepository:         esBookRepo,
+	}
+
+	bus := eventbus.New()
+
+	got := NewBookImportPipeline(repo, bus)
+	expected := &bookImportPipeline{
+		MySQLBookRepository:      mySQLBookRepo,
+		MySQLImportJobRepository: mySQLImportJobRepo,
+		ESBookRepository:         esBookRepo,
+		bus:                      bus,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewBookImportPipeline returns %+v\n expected %+v",
+			got, expected)
+	}
+}
+
+func TestBookImportPipelineImportBooks(t *testing.T) {
+	type input struct {
+		ctx     context.Context
+		records []BookImportRecord
+	}
+	type output struct {
+		report    *models.BookImportReport
+		published []eventbus.Event
+	}
+	type mockConfig struct {
+		given             input
+		mySQLBookRepoMock *mySqlMocks.MockBookRepository
+		esBookRepoMock    *esMocks.MockBookRepository
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "created, duplicate and invalid rows",
+			givenInput: input{
+				ctx: context.TODO(),
+				records: []BookImportRecord{
+					{Row: 2, Book: models.Book{Name: "Go", ISBN: "9780062315007"}},
+					{Row: 3, Book: models.Book{Name: "Go Again", ISBN: "9780062315007"}},
+					{Row: 4, Book: models.Book{Name: "C++", ISBN: "9780261103283"}},
+					{Row: 5, Book: models.Book{ISBN: "9780140449136"}},
+					{Row: 6, Err: errors.New("parse error")},
+				},
+			},
+			expectedOutput: output{
+				report: &models.BookImportReport{
+					Total:      5,
+					Created:    1,
+					Duplicates: 2,
+					Errors:     2,
+					Rows: []models.BookImportRow{
+						{Row: 2, Status: models.BookImportRowCreated, BookID: 10, ISBN: "9780062315007"},
+						{Row: 3, Status: models.BookImportRowDuplicate, ISBN: "9780062315007", Message: "duplicate of row 2"},
+						{Row: 4, Status: models.BookImportRowDuplicate, BookID: 7, ISBN: "9780261103283",
+							Message: "book with ISBN already exists as book 7"},
+						{Row: 5, Status: models.BookImportRowError, ISBN: "9780140449136", Message: "invalid model: name is required"},
+						{Row: 6, Status: models.BookImportRowError, Message: "parse error"},
+					},
+				},
+				published: []eventbus.Event{
+					eventbus.BookCreated{
+						Book:    models.Book{Model: gorm.Model{ID: 10}, Name: "Go", ISBN: "9780062315007"},
+						Indexed: true,
+					},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBooksByISBNs(gomock.Any(), []objects.ISBN{"9780062315007", "9780261103283"}).
+					Return(models.Books{{Model: gorm.Model{ID: 7}, Name: "C++", ISBN: "9780261103283"}}, nil)
+				conf.mySQLBookRepoMock.EXPECT().
+					CreateBooks(gomock.Any(), models.Books{{Name: "Go", ISBN: "9780062315007"}}).
+					DoAndReturn(func(_ context.Context, books models.Books) error {
+						books[0].ID = 10
+						return nil
+					})
+				conf.esBookRepoMock.EXPECT().
+					BulkIndexBooks(gomock.Any(), models.Books{{Model: gorm.Model{ID: 10}, Name: "Go", ISBN: "9780062315007"}}).
+					Return(nil)
+			},
+		},
+		{
+			name: "failed batch falls back to row by row insert",
+			givenInput: input{
+				ctx: context.TODO(),
+				records: []BookImportRecord{
+					{Row: 1, Book: models.Book{Name: "Go"}},
+					{Row: 2, Book: models.Book{Name: "C++"}},
+				},
+			},
+			expectedOutput: output{
+				report: &models.BookImportReport{
+					Total:   2,
+					Created: 1,
+					Errors:  1,
+					Rows: []models.BookImportRow{
+						{Row: 1, Status: models.BookImportRowCreated, BookID: 1},
+						{Row: 2, Status: models.BookImportRowError, Message: errRepository.Error()},
+					},
+				},
+				published: []eventbus.Event{
+					eventbus.BookCreated{Book: models.Book{Model: gorm.Model{ID: 1}, Name: "Go"}},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLBookRepoMock.EXPECT().
+					CreateBooks(gomock.Any(), gomock.Any()).
+					Return(errRepository)
+				conf.mySQLBookRepoMock.EXPECT().
+					CreateBook(gomock.Any(), &models.Book{Name: "Go"}).
+					DoAndReturn(func(_ context.Context, book *models.Book) error {
+						book.ID = 1
+						return nil
+					})
+				conf.mySQLBookRepoMock.EXPECT().
+					CreateBook(gomock.Any(), &models.Book{Name: "C++"}).
+					Return(errRepository)
+				conf.esBookRepoMock.EXPECT().
+					BulkIndexBooks(gomock.Any(), models.Books{{Model: gorm.Model{ID: 1}, Name: "Go"}}).
+					Return(errRepository)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mySQLBookRepoMock := mySqlMocks.NewMockBookRepository(ctrl)
+			esBookRepoMock := esMocks.NewMockBookRepository(ctrl)
+
+			var published []eventbus.Event
+			bookImportPipeline := &bookImportPipeline{
+				MySQLBookRepository: mySQLBookRepoMock,
+				ESBookRepository:    esBookRepoMock,
+				bus:                 recordingBus(&published),
+			}
+
+			tt.configureMock(mockConfig{
+				given:             tt.givenInput,
+				mySQLBookRepoMock: mySQLBookRepoMock,
+				esBookRepoMock:    esBookRepoMock,
+			})
+
+			report := bookImportPipeline.ImportBooks(tt.givenInput.ctx, tt.givenInput.records)
+			if expectedReport := tt.expectedOutput.report; !reflect.DeepEqual(report, expectedReport) {
+				t.Errorf("ImportBooks() got report %+v\n expected %+v",
+					report, expectedReport)
+			}
+			if expected := tt.expectedOutput.published; !reflect.DeepEqual(published, expected) {
+				t.Errorf("ImportBooks() published %+v\n expected %+v", published, expected)
+			}
+		})
+	}
+}
+
+func TestBookImportPipelineRunImportJob(t *testing.T) {
+	invalidRecords := []BookImportRecord{{Row: 1, Err: errors.New("parse error")}}
+	invalidReport := models.BookImportReport{
+		Total:  1,
+		Errors: 1,
+		Rows:   []models.BookImportRow{{Row: 1, Status: models.BookImportRowError, Message: "parse error"}},
+	}
+	canceledCtx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	type input struct {
+		ctx     context.Context
+		records []BookImportRecord
+	}
+	type output struct {
+		err    string
+		stored []models.ImportJob
+	}
+	type mockConfig struct {
+		mySQLBookRepoMock      *mySqlMocks.MockBookRepository
+		mySQLImportJobRepoMock *mySqlMocks.MockImportJobRepository
+		esBookRepoMock         *esMocks.MockBookRepository
+		stored                 *[]models.ImportJob
+	}
+	storeJob := func(conf mockConfig, err error) {
+		conf.mySQLImportJobRepoMock.EXPECT().
+			UpdateJob(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, job *models.ImportJob) error {
+				*conf.stored = append(*conf.stored, *job)
+				return err
+			})
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name:       "job is completed",
+			givenInput: input{ctx: context.TODO(), records: invalidRecords},
+			expectedOutput: output{
+				stored: []models.ImportJob{
+					{Model: gorm.Model{ID: 1}, Status: models.ImportJobRunning},
+					{Model: gorm.Model{ID: 1}, Status: models.ImportJobRunning, Processed: 1},
+					{Model: gorm.Model{ID: 1}, Status: models.ImportJobCompleted, Processed: 1, Report: invalidReport},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				storeJob(conf, nil)
+				conf.esBookRepoMock.EXPECT().BulkIndexBooks(gomock.Any(), models.Books{}).Return(nil)
+				storeJob(conf, nil)
+				storeJob(conf, nil)
+			},
+		},
+		{
+			name:       "failure to store progress fails job",
+			givenInput: input{ctx: context.TODO(), records: invalidRecords},
+			expectedOutput: output{
+				err: "failed to store import progress: " + errRepository.Error(),
+				stored: []models.ImportJob{
+					{Model: gorm.Model{ID: 1}, Status: models.ImportJobRunning},
+					{Model: gorm.Model{ID: 1}, Status: models.ImportJobRunning, Processed: 1},
+					{Model: gorm.Model{ID: 1}, Status: models.ImportJobFailed, Processed: 1, Report: invalidReport,
+						Error: "failed to store import progress: " + errRepository.Error()},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				storeJob(conf, nil)
+				conf.esBookRepoMock.EXPECT().BulkIndexBooks(gomock.Any(), models.Books{}).Return(nil)
+				storeJob(conf, errRepository)
+				storeJob(conf, nil)
+			},
+		},
+		{
+			name:       "canceled job is failed",
+			givenInput: input{ctx: canceledCtx, records: invalidRecords},
+			expectedOutput: output{
+				err: "import was interrupted: context canceled",
+				stored: []models.ImportJob{
+					{Model: gorm.Model{ID: 1}, Status: models.ImportJobRunning},
+					{Model: gorm.Model{ID: 1}, Status: models.ImportJobFailed, Report: invalidReport,
+						Error: "import was interrupted: context canceled"},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				storeJob(conf, nil)
+				conf.esBookRepoMock.EXPECT().BulkIndexBooks(gomock.Any(), models.Books{}).Return(nil)
+				storeJob(conf, nil)
+			},
+		},
+		{
+			name: "panicking job is failed",
+			givenInput: input{
+				ctx:     context.TODO(),
+				records: []BookImportRecord{{Row: 1, Book: models.Book{Name: "Go"}}},
+			},
+			expectedOutput: output{
+				err: "import panicked: bug",
+				stored: []models.ImportJob{
+					{Model: gorm.Model{ID: 1}, Status: models.ImportJobRunning},
+					{Model: gorm.Model{ID: 1}, Status: models.ImportJobFailed, Error: "import panicked: bug"},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				storeJob(conf, nil)
+				conf.mySQLBookRepoMock.EXPECT().
+					CreateBooks(gomock.Any(), gomock.Any()).
+					Do(func(context.Context, models.Books) { panic("bug") })
+				storeJob(conf, nil)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mySQLBookRepoMock := mySqlMocks.NewMockBookRepository(ctrl)
+			mySQLImportJobRepoMock := mySqlMocks.NewMockImportJobRepository(ctrl)
+			esBookRepoMock := esMocks.NewMockBookRepository(ctrl)
+
+			var stored []models.ImportJob
+			tt.configureMock(mockConfig{
+				mySQLBookRepoMock:      mySQLBookRepoMock,
+				mySQLImportJobRepoMock: mySQLImportJobRepoMock,
+				esBookRepoMock:         esBookRepoMock,
+				stored:                 &stored,
+			})
+
+			bookImportPipeline := &bookImportPipeline{
+				MySQLBookRepository:      mySQLBookRepoMock,
+				MySQLImportJobRepository: mySQLImportJobRepoMock,
+				ESBookRepository:         esBookRepoMock,
+			}
+			job := &models.ImportJob{Model: gorm.Model{ID: 1}, Status: models.ImportJobPending}
+			err := bookImportPipeline.runImportJob(tt.givenInput.ctx, job, tt.givenInput.records)
+			var errMessage string
+			if err != nil {
+				errMessage = err.Error()
+			}
+			if errMessage != tt.expectedOutput.err {
+				t.Errorf("runImportJob() got error %q\n expected %q", errMessage, tt.expectedOutput.err)
+			}
+			if expected := tt.expectedOutput.stored; !reflect.DeepEqual(stored, expected) {
+				t.Errorf("runImportJob() stored %+v\n expected %+v", stored, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package pipelines
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+)
+
+// ImportFormat is file format of book import
+type ImportFormat string
+
+// ImportFormat values
+const (
+	ImportFormatCSV   ImportFormat = "csv"
+	ImportFormatJSONL ImportFormat = "jsonl"
+)
+
+const (
+	bookNameField = "name"
+	bookISBNField = "isbn"
+
+	maxJSONLineSize = 1024 * 1024
+)
+
+var (
+	// ErrUnsupportedImportFormat returned when import format is neither csv nor jsonl
+	ErrUnsupportedImportFormat = errors.New("unsupported import format")
+	// ErrInvalidColumnMapping returned when column mapping cannot be applied
+	ErrInvalidColumnMapping = errors.New("invalid column mapping")
+)
+
+// defaultColumns maps book field to accepted source column names
+var defaultColumns = map[string][]string{
+	bookNameField: {"name", "title"},
+	bookISBNField: {"isbn"},
+}
+
+// BookImportRecord is single parsed row of import file.
+// Err is set when the row cannot be parsed into a book.
+type BookImportRecord struct {
+	Row  int
+	Book models.Book
+	Err  error
+}
+
+// ParseColumnMapping parses "field=Column,field=Column" into map of book field to source column
+func ParseColumnMapping(value string) (map[string]string, error) {
+	columns := make(map[string]string)
+	if strings.TrimSpace(value) == "" {
+		return columns, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("%w: %q is not field=Column", ErrInvalidColumnMapping, pair)
+		}
+
+		field := strings.ToLower(strings.TrimSpace(parts[0]))
+		if _, ok := defaultColumns[field]; !ok {
+			return nil, fmt.Errorf("%w: unknown book field %q", ErrInvalidColumnMapping, field)
+		}
+		columns[field] = strings.TrimSpace(parts[1])
+	}
+	return columns, nil
+}
+
+// ReadBookRecords parses books from r in given format.
+// columns maps book field to source column, unmapped fields use default column names.
+func ReadBookRecords(r io.Reader, format ImportFormat, columns map[string]string) ([]BookImportRecord, error) {
+	switch format {
+	case ImportFormatCSV:
+		return readCSVBookRecords(r, columns)
+	case ImportFormatJSONL:
+		return readJSONLBookRecords(r, columns)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedImportFormat, format)
+	}
+}
+
+func readCSVBookRecords(r io.Reader, columns map[string]string) ([]BookImportRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+
+	indexes, err := csvColumnIndexes(header, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]BookImportRecord, 0)
+	for row := 2; ; row++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		record := BookImportRecord{Row: row}
+		if err != nil {
+			record.Err = err
+			records = append(records, record)
+			continue
+		}
+
+		if index, ok := indexes[bookNameField]; ok && index < len(fields) {
+			record.Book.Name = strings.TrimSpace(fields[index])
+		}
+		if index, ok := indexes[bookISBNField]; ok && index < len(fields) {
+			record.Book.ISBN = objects.NewISBN(fields[index])
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// csvColumnIndexes resolves position of every book field in csv header
+func csvColumnIndexes(header []string, columns map[string]string) (map[string]int, error) {
+	positions := make(map[string]int, len(header))
+	for i, column := range header {
+		positions[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	indexes := make(map[string]int)
+	for field, candidates := range defaultColumns {
+		if column, ok := columns[field]; ok {
+			candidates = []string{column}
+		}
+		for _, candidate := range candidates {
+			if index, ok := positions[strings.ToLower(candidate)]; ok {
+				indexes[field] = index
+				break
+			}
+		}
+		if _, ok := indexes[field]; !ok && columns[field] != "" {
+			return nil, fmt.Errorf("%w: column %q not found in header", ErrInvalidColumnMapping, columns[field])
+		}
+	}
+
+	if _, ok := indexes[bookNameField]; !ok {
+		return nil, fmt.Errorf("%w: header has no %s column", ErrInvalidColumnMapping, bookNameField)
+	}
+	return indexes, nil
+}
+
+func readJSONLBookRecords(r io.Reader, columns map[string]string) ([]BookImportRecord, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLineSize)
+
+	records := make([]BookImportRecord, 0)
+	for row := 1; scanner.Scan(); row++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		record := BookImportRecord{Row: row}
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &object); err != nil {
+			record.Err = err
+			records = append(records, record)
+			continue
+		}
+
+		record.Book.Name = jsonField(object, bookNameField, columns)
+		record.Book.ISBN = objects.NewISBN(jsonField(object, bookISBNField, columns))
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// jsonField returns string value of book field from decoded JSON line
+func jsonField(object map[string]interface{}, field string, columns map[string]string) string {
+	candidates := defaultColumns[field]
+	if column, ok := columns[field]; ok {
+		candidates = []string{column}
+	}
+
+	for _, candidate := range candidates {
+		switch value := object[candidate].(type) {
+		case string:
+			return strings.TrimSpace(value)
+		case float64:
+			return fmt.Sprintf("%.0f", value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+package pipelines
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"book-management-system/entities/models"
+)
+
+func TestParseColumnMapping(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    map[string]string
+		expectedErr error
+	}{
+		{
+			name:     "empty mapping",
+			value:    "",
+			expected: map[string]string{},
+		},
+		{
+			name:  "field mapping",
+			value: "name=Title, ISBN=ISBN 13",
+			expected: map[string]string{
+				"name": "Title",
+				"isbn": "ISBN 13",
+			},
+		},
+		{
+			name:        "unknown field",
+			value:       "author=Author",
+			expectedErr: ErrInvalidColumnMapping,
+		},
+		{
+			name:        "malformed pair",
+			value:       "name",
+			expectedErr: ErrInvalidColumnMapping,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseColumnMapping(tt.value)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ParseColumnMapping() got error %+v, expected %+v", err, tt.expectedErr)
+			}
+			if tt.expectedErr == nil && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseColumnMapping() got %+v, expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadBookRecords(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		format      ImportFormat
+		columns     map[string]string
+		expected    []BookImportRecord
+		expectedErr error
+	}{
+		{
+			name:   "csv with default header",
+			input:  "Title,ISBN,Publisher\nThe Alchemist,9780062315007,HarperOne\n Go , ,\n",
+			format: ImportFormatCSV,
+			expected: []BookImportRecord{
+				{Row: 2, Book: models.Book{Name: "The Alchemist", ISBN: "9780062315007"}},
+				{Row: 3, Book: models.Book{Name: "Go"}},
+			},
+		},
+		{
+			name:    "csv with column mapping",
+			input:   "Book Title,ISBN 13\nThe Alchemist,9780062315007\n",
+			format:  ImportFormatCSV,
+			columns: map[string]string{"name": "Book Title", "isbn": "ISBN 13"},
+			expected: []BookImportRecord{
+				{Row: 2, Book: models.Book{Name: "The Alchemist", ISBN: "9780062315007"}},
+			},
+		},
+		{
+			name:        "csv without name column",
+			input:       "ISBN\n9780062315007\n",
+			format:      ImportFormatCSV,
+			expectedErr: ErrInvalidColumnMapping,
+		},
+		{
+			name:        "csv with unknown mapped column",
+			input:       "name,isbn\nGo,1234\n",
+			format:      ImportFormatCSV,
+			columns:     map[string]string{"isbn": "ISBN 13"},
+			expectedErr: ErrInvalidColumnMapping,
+		},
+		{
+			name:   "jsonl",
+			input:  "{\"name\":\"The Alchemist\",\"isbn\":\"9780062315007\"}\n\n{\"title\":\"Go\",\"isbn\":1234}\n",
+			format: ImportFormatJSONL,
+			expected: []BookImportRecord{
+				{Row: 1, Book: models.Book{Name: "The Alchemist", ISBN: "9780062315007"}},
+				{Row: 3, Book: models.Book{Name: "Go", ISBN: "1234"}},
+			},
+		},
+		{
+			name:        "unsupported format",
+			input:       "",
+			format:      "xml",
+			expectedErr: ErrUnsupportedImportFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadBookRecords(strings.NewReader(tt.input), tt.format, tt.columns)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ReadBookRecords() got error %+v, expected %+v", err, tt.expectedErr)
+			}
+			if tt.expectedErr == nil && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ReadBookRecords() got %+v, expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadBookRecordsInvalidJSONLine(t *testing.T) {
+	records, err := ReadBookRecords(strings.NewReader("{\"name\":\"Go\"}\nnot json\n"), ImportFormatJSONL, nil)
+	if err != nil {
+		t.Fatalf("ReadBookRecords() got error %+v", err)
+	}
+	if len(records) != 2 || records[0].Err != nil || records[1].Err == nil {
+		t.Errorf("ReadBookRecords() got %+v, expected second record with error", records)
+	}
+}
@@ -0,0 +1,61 @@
+package pipelines
+
+import (
+	"context"
+
+	"book-management-system/entities/models"
+	"book-management-system/repositories"
+	"book-management-system/repositories/elasticsearch"
+	"book-management-system/repositories/mysql"
+)
+
+// indexBatchSize is number of books read and bulk indexed at once
+const indexBatchSize = 500
+
+// BookIndexPipeline interface
+type BookIndexPipeline interface {
+	ReindexBooks(context.Context) (int, error)
+}
+
+type bookIndexPipeline struct {
+	MySQLBookRepository mysql.BookRepository
+	ESBookRepository    elasticsearch.BookRepository
+}
+
+// NewBookIndexPipeline returns BookIndexPipeline
+func NewBookIndexPipeline(repo *repositories.Repository) BookIndexPipeline {
+	return &bookIndexPipeline{
+		MySQLBookRepository: repo.MySQLBookRepository,
+		ESBookRepository:    repo.ESBookRepository,
+	}
+}
+
+// ReindexBooks indexes every stored book batch by batch and returns number of indexed books,
+// e.g. after search index was lost or its mapping changed
+func (p *bookIndexPipeline) ReindexBooks(ctx context.Context) (int, error) {
+	var afterID uint
+	var count int
+	for {
+		books, err := p.indexBatch(ctx, afterID)
+		if err != nil {
+			return count, err
+		}
+		count += len(books)
+
+		if len(books) < indexBatchSize {
+			return count, nil
+		}
+		afterID = books[len(books)-1].ID
+	}
+}
+
+func (p *bookIndexPipeline) indexBatch(ctx context.Context, afterID uint) (models.Books, error) {
+	ctx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	books, err := p.MySQLBookRepository.GetBooksAfterID(ctx, afterID, indexBatchSize, nil)
+	if err != nil || len(books) == 0 {
+		return books, err
+	}
+	return books, p.ESBookRepository.BulkIndexBooks(ctx, books)
+}
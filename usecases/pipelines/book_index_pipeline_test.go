@@ -0,0 +1,151 @@
+package pipelines
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+	esMocks "book-management-system/mocks/repositories/elasticsearch"
+	mySqlMocks "book-management-system/mocks/repositories/mysql"
+	"book-management-system/repositories"
+	"book-management-system/repositories/elasticsearch"
+	"book-management-system/repositories/mysql"
+)
+
+func TestNewBookIndexPipeline(t *testing.T) {
+	mySQLBookRepo := mysql.NewBookRepository(nil)
+	esBookRepo := elasticsearch.NewBookRepository(nil)
+	repo := &repositories.Repository{
+		MySQLBookRepository: mySQLBookRepo,
+		ESBookRepository:    esBookRepo,
+	}
+
+	got := NewBookIndexPipeline(repo)
+	expected := &bookIndexPipeline{
+		MySQLBookRepository: mySQLBookRepo,
+		ESBookRepository:    esBookRepo,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewBookIndexPipeline returns %+v\n expected %+v",
+			got, expected)
+	}
+}
+
+func TestBookIndexPipelineReindexBooks(t *testing.T) {
+	fullBatch := make(models.Books, indexBatchSize)
+	for i := range fullBatch {
+		fullBatch[i] = models.Book{Model: gorm.Model{ID: uint(i + 1)}, Name: "Book"}
+	}
+	lastBatch := models.Books{{Model: gorm.Model{ID: indexBatchSize + 1}, Name: "Last Book"}}
+
+	type output struct {
+		count int
+		err   error
+	}
+	type mockConfig struct {
+		mySQLBookRepoMock *mySqlMocks.MockBookRepository
+		esBookRepoMock    *esMocks.MockBookRepository
+	}
+
+	tests := []struct {
+		name           string
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success reindex in batches",
+			expectedOutput: output{
+				count: indexBatchSize + 1,
+			},
+			configureMock: func(conf mockConfig) {
+				gomock.InOrder(
+					conf.mySQLBookRepoMock.EXPECT().
+						GetBooksAfterID(gomock.Any(), uint(0), indexBatchSize, nil).
+						Return(fullBatch, nil),
+					conf.esBookRepoMock.EXPECT().
+						BulkIndexBooks(gomock.Any(), fullBatch).
+						Return(nil),
+					conf.mySQLBookRepoMock.EXPECT().
+						GetBooksAfterID(gomock.Any(), uint(indexBatchSize), indexBatchSize, nil).
+						Return(lastBatch, nil),
+					conf.esBookRepoMock.EXPECT().
+						BulkIndexBooks(gomock.Any(), lastBatch).
+						Return(nil),
+				)
+			},
+		},
+		{
+			name: "success reindex without books",
+			expectedOutput: output{
+				count: 0,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBooksAfterID(gomock.Any(), uint(0), indexBatchSize, nil).
+					Return(models.Books{}, nil)
+			},
+		},
+		{
+			name: "error get books",
+			expectedOutput: output{
+				err: errRepository,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBooksAfterID(gomock.Any(), uint(0), indexBatchSize, nil).
+					Return(nil, errRepository)
+			},
+		},
+		{
+			name: "error index books",
+			expectedOutput: output{
+				count: 0,
+				err:   errRepository,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBooksAfterID(gomock.Any(), uint(0), indexBatchSize, nil).
+					Return(lastBatch, nil)
+				conf.esBookRepoMock.EXPECT().
+					BulkIndexBooks(gomock.Any(), lastBatch).
+					Return(errRepository)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mySQLBookRepoMock := mySqlMocks.NewMockBookRepository(ctrl)
+			esBookRepoMock := esMocks.NewMockBookRepository(ctrl)
+
+			bookIndexPipeline := &bookIndexPipeline{
+				MySQLBookRepository: mySQLBookRepoMock,
+				ESBookRepository:    esBookRepoMock,
+			}
+
+			tt.configureMock(mockConfig{
+				mySQLBookRepoMock: mySQLBookRepoMock,
+				esBookRepoMock:    esBookRepoMock,
+			})
+
+			count, err := bookIndexPipeline.ReindexBooks(context.TODO())
+			if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+				t.Errorf("ReindexBooks() got error: %v\nexpected: %v",
+					err, expectedError)
+			}
+			if count != tt.expectedOutput.count {
+				t.Errorf("ReindexBooks() got count %d\n expected %d",
+					count, tt.expectedOutput.count)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package pipelines
+
+import (
+	"context"
+	"fmt"
+
+	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+)
+
+// ImportCatalogRecords creates books from cataloguing records, or merges them
+// into books with the same ISBN. With dryRun nothing is stored and every report
+// row carries the mapped record.
+func (p *bookImportPipeline) ImportCatalogRecords(
+	ctx context.Context,
+	records []CatalogImportRecord,
+	dryRun bool,
+) *models.BookImportReport {
+	report := &models.BookImportReport{
+		DryRun: dryRun,
+		Rows:   make([]models.BookImportRow, 0, len(records)),
+	}
+	seenISBNs := make(map[objects.ISBN]int)
+
+	for start := 0; start < len(records); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+
+		for _, row := range p.importCatalogBatch(ctx, records[start:end], seenISBNs, dryRun) {
+			report.Add(row)
+		}
+	}
+
+	return report
+}
+
+// importCatalogBatch returns report rows of batch in the same order as records
+func (p *bookImportPipeline) importCatalogBatch(
+	ctx context.Context,
+	records []CatalogImportRecord,
+	seenISBNs map[objects.ISBN]int,
+	dryRun bool,
+) []models.BookImportRow {
+	ctx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	rows := make([]models.BookImportRow, len(records))
+	candidates := make([]int, 0, len(records))
+	isbns := make([]objects.ISBN, 0, len(records))
+
+	for i := range records {
+		record := &records[i]
+		rows[i] = models.BookImportRow{Row: record.Row, ISBN: record.Record.Book.ISBN}
+		if dryRun {
+			rows[i].Record = &record.Record
+		}
+
+		err := record.Err
+		if err == nil {
+			err = record.Record.Book.Validate()
+		}
+		if err != nil {
+			rows[i].Status = models.BookImportRowError
+			rows[i].Message = err.Error()
+			continue
+		}
+
+		if isbn := record.Record.Book.ISBN; !isbn.IsZero() {
+			if row, ok := seenISBNs[isbn]; ok {
+				rows[i].Status = models.BookImportRowDuplicate
+				rows[i].Message = fmt.Sprintf("duplicate of row %d", row)
+				continue
+			}
+			seenISBNs[isbn] = record.Row
+			isbns = append(isbns, isbn)
+		}
+		candidates = append(candidates, i)
+	}
+
+	existingBooks := make(map[objects.ISBN]models.Book)
+	if len(isbns) > 0 {
+		books, err := p.MySQLBookRepository.GetBooksByISBNs(ctx, isbns)
+		if err != nil {
+			for _, i := range candidates {
+				rows[i].Status = models.BookImportRowError
+				rows[i].Message = err.Error()
+			}
+			return rows
+		}
+		for _, book := range books {
+			existingBooks[book.ISBN] = book
+		}
+	}
+
+	books := make(models.Books, 0, len(candidates))
+	positions := make([]int, 0, len(candidates))
+	updated := make(models.Books, 0, len(candidates))
+	for _, i := range candidates {
+		book := records[i].Record.Book
+		existing, ok := existingBooks[book.ISBN]
+		if !ok {
+			if dryRun {
+				rows[i].Status = models.BookImportRowCreated
+				continue
+			}
+			books = append(books, book)
+			positions = append(positions, i)
+			continue
+		}
+
+		rows[i].Status = models.BookImportRowMerged
+		rows[i].BookID = existing.ID
+		if dryRun || existing.Name == book.Name {
+			continue
+		}
+
+		existing.Name = book.Name
+		if err := p.MySQLBookRepository.UpdateBook(ctx, &existing); err != nil {
+			rows[i].Status = models.BookImportRowError
+			rows[i].Message = err.Error()
+			continue
+		}
+		updated = append(updated, existing)
+	}
+
+	if dryRun {
+		return rows
+	}
+
+	p.indexBooks(ctx, updated, p.createBooks(ctx, books, positions, rows))
+	return rows
+}
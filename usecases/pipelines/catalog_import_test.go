@@ -0,0 +1,165 @@
+package pipelines
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+	esMocks "book-management-system/mocks/repositories/elasticsearch"
+	mySqlMocks "book-management-system/mocks/repositories/mysql"
+	"book-management-system/usecases/eventbus"
+)
+
+func TestBookImportPipelineImportCatalogRecords(t *testing.T) {
+	records := []CatalogImportRecord{
+		{Row: 1, Record: models.CatalogRecord{Book: models.Book{Name: "The Alchemist", ISBN: "9780062315007"}}},
+		{Row: 2, Record: models.CatalogRecord{Book: models.Book{Name: "The Hobbit", ISBN: "9780261103283"}, Authors: []string{"Tolkien, J. R. R."}}},
+		{Row: 3, Record: models.CatalogRecord{Book: models.Book{Name: "The Hobbit", ISBN: "9780261103283"}}},
+		{Row: 4, Record: models.CatalogRecord{Book: models.Book{ISBN: "9780140449136"}}},
+	}
+	existing := models.Book{Model: gorm.Model{ID: 7}, Name: "Hobbit", ISBN: "9780261103283"}
+
+	type input struct {
+		ctx     context.Context
+		records []CatalogImportRecord
+		dryRun  bool
+	}
+	type output struct {
+		report    *models.BookImportReport
+		published []eventbus.Event
+	}
+	type mockConfig struct {
+		given             input
+		mySQLBookRepoMock *mySqlMocks.MockBookRepository
+		esBookRepoMock    *esMocks.MockBookRepository
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "dry run previews mapped records",
+			givenInput: input{
+				ctx:     context.TODO(),
+				records: records,
+				dryRun:  true,
+			},
+			expectedOutput: output{
+				report: &models.BookImportReport{
+					DryRun:     true,
+					Total:      4,
+					Created:    1,
+					Merged:     1,
+					Duplicates: 1,
+					Errors:     1,
+					Rows: []models.BookImportRow{
+						{Row: 1, Status: models.BookImportRowCreated, ISBN: "9780062315007", Record: &records[0].Record},
+						{Row: 2, Status: models.BookImportRowMerged, BookID: 7, ISBN: "9780261103283", Record: &records[1].Record},
+						{Row: 3, Status: models.BookImportRowDuplicate, ISBN: "9780261103283", Message: "duplicate of row 2",
+							Record: &records[2].Record},
+						{Row: 4, Status: models.BookImportRowError, ISBN: "9780140449136", Message: "invalid model: name is required",
+							Record: &records[3].Record},
+					},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBooksByISBNs(gomock.Any(), []objects.ISBN{"9780062315007", "9780261103283"}).
+					Return(models.Books{existing}, nil)
+			},
+		},
+		{
+			name: "commit creates and merges by isbn",
+			givenInput: input{
+				ctx:     context.TODO(),
+				records: records,
+			},
+			expectedOutput: output{
+				report: &models.BookImportReport{
+					Total:      4,
+					Created:    1,
+					Merged:     1,
+					Duplicates: 1,
+					Errors:     1,
+					Rows: []models.BookImportRow{
+						{Row: 1, Status: models.BookImportRowCreated, BookID: 10, ISBN: "9780062315007"},
+						{Row: 2, Status: models.BookImportRowMerged, BookID: 7, ISBN: "9780261103283"},
+						{Row: 3, Status: models.BookImportRowDuplicate, ISBN: "9780261103283", Message: "duplicate of row 2"},
+						{Row: 4, Status: models.BookImportRowError, ISBN: "9780140449136", Message: "invalid model: name is required"},
+					},
+				},
+				published: []eventbus.Event{
+					eventbus.BookUpdated{
+						Book:    models.Book{Model: gorm.Model{ID: 7}, Name: "The Hobbit", ISBN: "9780261103283"},
+						Indexed: true,
+					},
+					eventbus.BookCreated{
+						Book:    models.Book{Model: gorm.Model{ID: 10}, Name: "The Alchemist", ISBN: "9780062315007"},
+						Indexed: true,
+					},
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				merged := existing
+				merged.Name = "The Hobbit"
+				created := models.Book{Model: gorm.Model{ID: 10}, Name: "The Alchemist", ISBN: "9780062315007"}
+
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBooksByISBNs(gomock.Any(), []objects.ISBN{"9780062315007", "9780261103283"}).
+					Return(models.Books{existing}, nil)
+				conf.mySQLBookRepoMock.EXPECT().
+					UpdateBook(gomock.Any(), &merged).
+					Return(nil)
+				conf.mySQLBookRepoMock.EXPECT().
+					CreateBooks(gomock.Any(), models.Books{{Name: "The Alchemist", ISBN: "9780062315007"}}).
+					DoAndReturn(func(_ context.Context, books models.Books) error {
+						books[0].ID = created.ID
+						return nil
+					})
+				conf.esBookRepoMock.EXPECT().
+					BulkIndexBooks(gomock.Any(), models.Books{merged, created}).
+					Return(nil)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mySQLBookRepoMock := mySqlMocks.NewMockBookRepository(ctrl)
+			esBookRepoMock := esMocks.NewMockBookRepository(ctrl)
+
+			var published []eventbus.Event
+			bookImportPipeline := &bookImportPipeline{
+				MySQLBookRepository: mySQLBookRepoMock,
+				ESBookRepository:    esBookRepoMock,
+				bus:                 recordingBus(&published),
+			}
+
+			tt.configureMock(mockConfig{
+				given:             tt.givenInput,
+				mySQLBookRepoMock: mySQLBookRepoMock,
+				esBookRepoMock:    esBookRepoMock,
+			})
+
+			report := bookImportPipeline.ImportCatalogRecords(tt.givenInput.ctx, tt.givenInput.records, tt.givenInput.dryRun)
+			if expectedReport := tt.expectedOutput.report; !reflect.DeepEqual(report, expectedReport) {
+				t.Errorf("ImportCatalogRecords() got report %+v\n expected %+v",
+					report, expectedReport)
+			}
+			if expected := tt.expectedOutput.published; !reflect.DeepEqual(published, expected) {
+				t.Errorf("ImportCatalogRecords() published %+v\n expected %+v", published, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package pipelines
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+	"book-management-system/usecases/pipelines/marc"
+)
+
+// ImportFormat values of cataloguing records
+const (
+	ImportFormatMARC    ImportFormat = "marc"
+	ImportFormatMARCXML ImportFormat = "marcxml"
+)
+
+var yearRgx = regexp.MustCompile(`[0-9]{4}`)
+
+// CatalogImportRecord is single parsed cataloguing record.
+// Err is set when the record cannot be parsed.
+type CatalogImportRecord struct {
+	Row    int
+	Record models.CatalogRecord
+	Err    error
+}
+
+type marcReader interface {
+	Read() (marc.Record, error)
+}
+
+// ReadCatalogRecords parses MARC 21 or MARCXML records from r
+func ReadCatalogRecords(r io.Reader, format ImportFormat) ([]CatalogImportRecord, error) {
+	var reader marcReader
+	switch format {
+	case ImportFormatMARC:
+		reader = marc.NewReader(r)
+	case ImportFormatMARCXML:
+		reader = marc.NewXMLReader(r)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedImportFormat, format)
+	}
+
+	records := make([]CatalogImportRecord, 0)
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil && format == ImportFormatMARCXML {
+			// xml decoder cannot resume after syntax error
+			return nil, err
+		}
+
+		importRecord := CatalogImportRecord{Row: row, Err: err}
+		if err == nil {
+			importRecord.Record = catalogRecordFromMARC(record)
+		}
+		records = append(records, importRecord)
+	}
+}
+
+// catalogRecordFromMARC maps 020 ISBN, 100/700 authors, 245 title,
+// 264/260 publisher and year and 650 subjects of record
+func catalogRecordFromMARC(record marc.Record) models.CatalogRecord {
+	var catalogRecord models.CatalogRecord
+
+	for _, field := range record.FieldsByTag("020") {
+		if isbn := isbnFromMARC(field.Subfield('a')); !isbn.IsZero() {
+			catalogRecord.Book.ISBN = isbn
+			break
+		}
+	}
+
+	for _, field := range record.FieldsByTag("245") {
+		title := trimISBDPunctuation(field.Subfield('a'))
+		if remainder := trimISBDPunctuation(field.Subfield('b')); remainder != "" {
+			title += ": " + remainder
+		}
+		catalogRecord.Book.Name = title
+		break
+	}
+
+	for _, tag := range []string{"100", "700"} {
+		for _, field := range record.FieldsByTag(tag) {
+			if author := trimISBDPunctuation(field.Subfield('a')); author != "" {
+				catalogRecord.Authors = append(catalogRecord.Authors, author)
+			}
+		}
+	}
+
+	if field, ok := publicationField(record); ok {
+		catalogRecord.Publisher = trimISBDPunctuation(field.Subfield('b'))
+		if year := yearRgx.FindString(field.Subfield('c')); year != "" {
+			catalogRecord.PublicationYear, _ = strconv.Atoi(year)
+		}
+	}
+
+	for _, field := range record.FieldsByTag("650") {
+		headings := make([]string, 0, len(field.Subfields))
+		for _, subfield := range field.Subfields {
+			switch subfield.Code {
+			case 'a', 'x', 'y', 'z', 'v':
+				if heading := trimISBDPunctuation(subfield.Value); heading != "" {
+					headings = append(headings, heading)
+				}
+			}
+		}
+		if len(headings) > 0 {
+			catalogRecord.Subjects = append(catalogRecord.Subjects, strings.Join(headings, " -- "))
+		}
+	}
+
+	return catalogRecord
+}
+
+// publicationField returns 264 publication statement, or 260 in older records
+func publicationField(record marc.Record) (marc.Field, bool) {
+	for _, field := range record.FieldsByTag("264") {
+		if field.Indicators[1] == '1' {
+			return field, true
+		}
+	}
+	if fields := record.FieldsByTag("260"); len(fields) > 0 {
+		return fields[0], true
+	}
+	return marc.Field{}, false
+}
+
+// isbnFromMARC drops qualifier such as "(pbk.)" from 020 $a
+func isbnFromMARC(value string) objects.ISBN {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return ""
+	}
+	return objects.NewISBN(fields[0])
+}
+
+// trimISBDPunctuation removes punctuation cataloguers put between elements.
+// Final period is kept after initials, e.g. "Tolkien, J. R. R."
+func trimISBDPunctuation(value string) string {
+	value = strings.TrimRight(strings.TrimSpace(value), " /:;,=")
+	if strings.HasSuffix(value, ".") {
+		runes := []rune(value)
+		if len(runes) < 2 || !unicode.IsUpper(runes[len(runes)-2]) {
+			value = strings.TrimSuffix(value, ".")
+		}
+	}
+	return strings.TrimSpace(value)
+}
@@ -0,0 +1,176 @@
+package pipelines
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"book-management-system/entities/models"
+	"book-management-system/usecases/pipelines/marc"
+)
+
+func TestReadCatalogRecords(t *testing.T) {
+	alchemist := marc.Record{
+		Fields: []marc.Field{
+			{Tag: "001", Value: "1"},
+			{Tag: "020", Subfields: []marc.Subfield{{Code: 'a', Value: "978-0-06-231500-7 (pbk.)"}}},
+			{Tag: "100", Indicators: [2]byte{'1', ' '}, Subfields: []marc.Subfield{{Code: 'a', Value: "Coelho, Paulo."}}},
+			{Tag: "245", Indicators: [2]byte{'1', '4'}, Subfields: []marc.Subfield{
+				{Code: 'a', Value: "The alchemist :"},
+				{Code: 'b', Value: "a fable about following your dream /"},
+				{Code: 'c', Value: "Paulo Coelho."},
+			}},
+			{Tag: "260", Subfields: []marc.Subfield{{Code: 'b', Value: "Harper,"}, {Code: 'c', Value: "1998."}}},
+			{Tag: "264", Indicators: [2]byte{' ', '1'}, Subfields: []marc.Subfield{
+				{Code: 'a', Value: "New York :"},
+				{Code: 'b', Value: "HarperOne,"},
+				{Code: 'c', Value: "[2014]"},
+			}},
+			{Tag: "650", Indicators: [2]byte{' ', '0'}, Subfields: []marc.Subfield{
+				{Code: 'a', Value: "Alchemists"},
+				{Code: 'v', Value: "Fiction."},
+			}},
+			{Tag: "700", Indicators: [2]byte{'1', ' '}, Subfields: []marc.Subfield{{Code: 'a', Value: "Clarke, Alan R."}}},
+		},
+	}
+	alchemistRecord := models.CatalogRecord{
+		Book: models.Book{
+			Name: "The alchemist: a fable about following your dream",
+			ISBN: "9780062315007",
+		},
+		Authors:         []string{"Coelho, Paulo", "Clarke, Alan R."},
+		Publisher:       "HarperOne",
+		PublicationYear: 2014,
+		Subjects:        []string{"Alchemists -- Fiction"},
+	}
+
+	encoded, err := marc.Marshal(alchemist)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type input struct {
+		file   string
+		format ImportFormat
+	}
+	type output struct {
+		records []CatalogImportRecord
+		err     error
+		failed  bool
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+	}{
+		{
+			name: "marc records",
+			givenInput: input{
+				file:   string(encoded) + "00030nam a22ABCDE   4500\x1e\x1d",
+				format: ImportFormatMARC,
+			},
+			expectedOutput: output{
+				records: []CatalogImportRecord{
+					{Row: 1, Record: alchemistRecord},
+					{Row: 2, Err: marc.ErrInvalidRecord},
+				},
+			},
+		},
+		{
+			name: "marcxml record with 260 publication",
+			givenInput: input{
+				file: `<record xmlns="http://www.loc.gov/MARC21/slim">
+  <datafield tag="020" ind1=" " ind2=" "><subfield code="a">0261103253</subfield></datafield>
+  <datafield tag="245" ind1="1" ind2="0"><subfield code="a">The hobbit /</subfield></datafield>
+  <datafield tag="260" ind1=" " ind2=" "><subfield code="b">Unwin,</subfield><subfield code="c">c1937.</subfield></datafield>
+</record>`,
+				format: ImportFormatMARCXML,
+			},
+			expectedOutput: output{
+				records: []CatalogImportRecord{
+					{
+						Row: 1,
+						Record: models.CatalogRecord{
+							Book:            models.Book{Name: "The hobbit", ISBN: "9780261103252"},
+							Publisher:       "Unwin",
+							PublicationYear: 1937,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "malformed marcxml",
+			givenInput: input{
+				file:   `<record><datafield tag="245">`,
+				format: ImportFormatMARCXML,
+			},
+			expectedOutput: output{
+				failed: true,
+			},
+		},
+		{
+			name: "unsupported format",
+			givenInput: input{
+				format: ImportFormatCSV,
+			},
+			expectedOutput: output{
+				err:    ErrUnsupportedImportFormat,
+				failed: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := ReadCatalogRecords(strings.NewReader(tt.givenInput.file), tt.givenInput.format)
+			if tt.expectedOutput.failed {
+				if expectedError := tt.expectedOutput.err; err == nil || (expectedError != nil && !errors.Is(err, expectedError)) {
+					t.Errorf("ReadCatalogRecords() got error: %v\nexpected: %v", err, expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadCatalogRecords() got error: %v", err)
+			}
+
+			if len(records) != len(tt.expectedOutput.records) {
+				t.Fatalf("ReadCatalogRecords() got %d records\nexpected %d",
+					len(records), len(tt.expectedOutput.records))
+			}
+			for i, expected := range tt.expectedOutput.records {
+				got := records[i]
+				if !errors.Is(got.Err, expected.Err) {
+					t.Errorf("ReadCatalogRecords() got record %d error: %v\nexpected: %v", i, got.Err, expected.Err)
+				}
+				if got.Row != expected.Row || !reflect.DeepEqual(got.Record, expected.Record) {
+					t.Errorf("ReadCatalogRecords() got record %d %+v\nexpected %+v", i, got, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestExportedMARCCanBeImported(t *testing.T) {
+	book := models.Book{Name: "The Alchemist", ISBN: "9780062315007"}
+
+	var file bytes.Buffer
+	writer, err := newBookWriter(&file, ExportFormatMARC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Write(&book); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := ReadCatalogRecords(&file, ImportFormatMARC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || !reflect.DeepEqual(records[0].Record.Book, book) {
+		t.Errorf("ReadCatalogRecords() got %+v\nexpected book %+v", records, book)
+	}
+}
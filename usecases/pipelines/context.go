@@ -0,0 +1,13 @@
+package pipelines
+
+import (
+	"context"
+	"time"
+)
+
+// batchContextTimeout bounds every batch, not the whole pipeline run
+const batchContextTimeout = 30
+
+func setBatchContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, time.Duration(batchContextTimeout)*time.Second)
+}
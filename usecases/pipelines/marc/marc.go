@@ -0,0 +1,176 @@
+// Package marc reads and writes bibliographic records in MARC 21 exchange format (ISO 2709) and reads MARCXML.
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DefaultLeader is leader of new monograph record with UTF-8 encoding.
+// Record length and base address of data are filled in when the record is encoded.
+const DefaultLeader = "00000nam a2200000   4500"
+
+const (
+	leaderLength         = 24
+	directoryEntryLength = 12
+	maxFieldLength       = 9999
+	maxRecordLength      = 99999
+
+	subfieldDelimiter = 0x1F
+	fieldTerminator   = 0x1E
+	recordTerminator  = 0x1D
+)
+
+var (
+	// ErrInvalidRecord returned when record cannot be represented in MARC 21
+	ErrInvalidRecord = errors.New("invalid marc record")
+)
+
+// Subfield is coded data element of data field
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// Field is variable field of record.
+// Control fields (tags 001-009) only have Value,
+// data fields have Indicators and Subfields.
+type Field struct {
+	Tag        string
+	Value      string
+	Indicators [2]byte
+	Subfields  []Subfield
+}
+
+// IsControl reports whether field is control field
+func (f Field) IsControl() bool {
+	return strings.HasPrefix(f.Tag, "00")
+}
+
+// Subfield returns value of first subfield with code
+func (f Field) Subfield(code byte) string {
+	for _, subfield := range f.Subfields {
+		if subfield.Code == code {
+			return subfield.Value
+		}
+	}
+	return ""
+}
+
+// Record is single MARC 21 bibliographic record
+type Record struct {
+	Leader string
+	Fields []Field
+}
+
+// FieldsByTag returns all fields with tag in record order
+func (r Record) FieldsByTag(tag string) []Field {
+	var fields []Field
+	for _, field := range r.Fields {
+		if field.Tag == tag {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Marshal encodes record in ISO 2709 structure
+func Marshal(record Record) ([]byte, error) {
+	leader := record.Leader
+	if leader == "" {
+		leader = DefaultLeader
+	}
+	if len(leader) != leaderLength {
+		return nil, fmt.Errorf("%w: leader must be %d characters", ErrInvalidRecord, leaderLength)
+	}
+
+	var directory, data bytes.Buffer
+	for _, field := range record.Fields {
+		if len(field.Tag) != 3 {
+			return nil, fmt.Errorf("%w: tag %q must be 3 characters", ErrInvalidRecord, field.Tag)
+		}
+
+		start := data.Len()
+		if field.IsControl() {
+			data.WriteString(clean(field.Value))
+		} else {
+			data.WriteByte(indicator(field.Indicators[0]))
+			data.WriteByte(indicator(field.Indicators[1]))
+			for _, subfield := range field.Subfields {
+				data.WriteByte(subfieldDelimiter)
+				data.WriteByte(subfield.Code)
+				data.WriteString(clean(subfield.Value))
+			}
+		}
+		data.WriteByte(fieldTerminator)
+
+		length := data.Len() - start
+		if length > maxFieldLength {
+			return nil, fmt.Errorf("%w: field %s is longer than %d bytes", ErrInvalidRecord, field.Tag, maxFieldLength)
+		}
+		fmt.Fprintf(&directory, "%s%04d%05d", field.Tag, length, start)
+	}
+	directory.WriteByte(fieldTerminator)
+
+	baseAddress := leaderLength + directory.Len()
+	recordLength := baseAddress + data.Len() + 1
+	if recordLength > maxRecordLength {
+		return nil, fmt.Errorf("%w: record is longer than %d bytes", ErrInvalidRecord, maxRecordLength)
+	}
+
+	encoded := make([]byte, 0, recordLength)
+	encoded = append(encoded, fmt.Sprintf("%05d", recordLength)...)
+	encoded = append(encoded, leader[5:10]...)
+	encoded = append(encoded, "22"...)
+	encoded = append(encoded, fmt.Sprintf("%05d", baseAddress)...)
+	encoded = append(encoded, leader[17:20]...)
+	encoded = append(encoded, "4500"...)
+	encoded = append(encoded, directory.Bytes()...)
+	encoded = append(encoded, data.Bytes()...)
+	encoded = append(encoded, recordTerminator)
+	return encoded, nil
+}
+
+// Writer writes records to underlying writer
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter returns new Writer
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		w: w,
+	}
+}
+
+// Write encodes and writes single record
+func (w *Writer) Write(record Record) error {
+	encoded, err := Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(encoded)
+	return err
+}
+
+// indicator returns blank for unset indicator
+func indicator(value byte) byte {
+	if value == 0 {
+		return ' '
+	}
+	return value
+}
+
+// clean removes structural characters from field value
+func clean(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case subfieldDelimiter, fieldTerminator, recordTerminator:
+			return -1
+		}
+		return r
+	}, value)
+}
@@ -0,0 +1,88 @@
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		record   Record
+		expected string
+		err      error
+	}{
+		{
+			name: "control and data fields",
+			record: Record{
+				Fields: []Field{
+					{Tag: "001", Value: "1"},
+					{Tag: "020", Subfields: []Subfield{{Code: 'a', Value: "9780062315007"}}},
+					{Tag: "245", Indicators: [2]byte{'0', '0'}, Subfields: []Subfield{{Code: 'a', Value: "The Alchemist"}}},
+				},
+			},
+			expected: "00100nam a2200061   4500" +
+				"001000200000" + "020001800002" + "245001800020" + "\x1e" +
+				"1\x1e" +
+				"  \x1fa9780062315007\x1e" +
+				"00\x1faThe Alchemist\x1e" +
+				"\x1d",
+		},
+		{
+			name: "structural characters are removed from values",
+			record: Record{
+				Leader: "00000cam a2200000 i 4500",
+				Fields: []Field{
+					{Tag: "001", Value: "1\x1e2"},
+				},
+			},
+			expected: "00041cam a2200037 i 4500" +
+				"001000300000" + "\x1e" +
+				"12\x1e" +
+				"\x1d",
+		},
+		{
+			name: "invalid tag",
+			record: Record{
+				Fields: []Field{{Tag: "1", Value: "1"}},
+			},
+			err: ErrInvalidRecord,
+		},
+		{
+			name: "field too long",
+			record: Record{
+				Fields: []Field{{Tag: "500", Subfields: []Subfield{{Code: 'a', Value: strings.Repeat("a", maxFieldLength)}}}},
+			},
+			err: ErrInvalidRecord,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Marshal(tt.record)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Marshal() got error: %v\nexpected: %v", err, tt.err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("Marshal() got %q\nexpected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewWriter(&buf)
+
+	for i := 0; i < 2; i++ {
+		if err := writer.Write(Record{Fields: []Field{{Tag: "001", Value: "1"}}}); err != nil {
+			t.Fatalf("Write() got error: %v", err)
+		}
+	}
+
+	if count := strings.Count(buf.String(), "\x1d"); count != 2 {
+		t.Errorf("Write() wrote %d records\nexpected 2", count)
+	}
+}
@@ -0,0 +1,181 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Reader reads records in ISO 2709 structure.
+// Field values are returned as stored, records must be UTF-8 (leader position 09 "a") or ASCII.
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader returns new Reader
+func NewReader(r io.Reader) *Reader {
+	return &Reader{
+		r: bufio.NewReader(r),
+	}
+}
+
+// Read returns next record, io.EOF is returned when there are no more records
+func (r *Reader) Read() (Record, error) {
+	raw, err := r.r.ReadBytes(recordTerminator)
+	if err == io.EOF {
+		// trailing whitespace or newline after last record
+		if len(bytes.TrimSpace(raw)) == 0 {
+			return Record{}, io.EOF
+		}
+		return Record{}, fmt.Errorf("%w: missing record terminator", ErrInvalidRecord)
+	}
+	if err != nil {
+		return Record{}, err
+	}
+	return Unmarshal(bytes.TrimLeft(raw, "\r\n\t "))
+}
+
+// Unmarshal decodes single record in ISO 2709 structure
+func Unmarshal(raw []byte) (Record, error) {
+	if len(raw) < leaderLength+1 {
+		return Record{}, fmt.Errorf("%w: record is shorter than leader", ErrInvalidRecord)
+	}
+
+	leader := string(raw[:leaderLength])
+	baseAddress, err := strconv.Atoi(leader[12:17])
+	if err != nil || baseAddress <= leaderLength || baseAddress > len(raw) {
+		return Record{}, fmt.Errorf("%w: invalid base address of data %q", ErrInvalidRecord, leader[12:17])
+	}
+
+	directory := raw[leaderLength : baseAddress-1]
+	if len(directory)%directoryEntryLength != 0 {
+		return Record{}, fmt.Errorf("%w: invalid directory length %d", ErrInvalidRecord, len(directory))
+	}
+	data := raw[baseAddress:]
+
+	record := Record{Leader: leader}
+	for i := 0; i < len(directory); i += directoryEntryLength {
+		entry := string(directory[i : i+directoryEntryLength])
+		length, lengthErr := strconv.Atoi(entry[3:7])
+		start, startErr := strconv.Atoi(entry[7:12])
+		if lengthErr != nil || startErr != nil || length < 1 || start+length > len(data) {
+			return Record{}, fmt.Errorf("%w: invalid directory entry %q", ErrInvalidRecord, entry)
+		}
+
+		// drop field terminator
+		value := data[start : start+length-1]
+		record.Fields = append(record.Fields, decodeField(entry[:3], value))
+	}
+	return record, nil
+}
+
+func decodeField(tag string, value []byte) Field {
+	field := Field{Tag: tag}
+	if field.IsControl() {
+		field.Value = string(value)
+		return field
+	}
+
+	parts := bytes.Split(value, []byte{subfieldDelimiter})
+	if indicators := parts[0]; len(indicators) >= 2 {
+		field.Indicators = [2]byte{indicators[0], indicators[1]}
+	}
+	for _, part := range parts[1:] {
+		if len(part) == 0 {
+			continue
+		}
+		field.Subfields = append(field.Subfields, Subfield{
+			Code:  part[0],
+			Value: string(part[1:]),
+		})
+	}
+	return field
+}
+
+type xmlRecord struct {
+	Leader        string `xml:"leader"`
+	ControlFields []struct {
+		Tag   string `xml:"tag,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"controlfield"`
+	DataFields []struct {
+		Tag       string `xml:"tag,attr"`
+		Ind1      string `xml:"ind1,attr"`
+		Ind2      string `xml:"ind2,attr"`
+		Subfields []struct {
+			Code  string `xml:"code,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"subfield"`
+	} `xml:"datafield"`
+}
+
+// XMLReader reads records from MARCXML document,
+// either single record element or collection of records
+type XMLReader struct {
+	decoder *xml.Decoder
+}
+
+// NewXMLReader returns new XMLReader
+func NewXMLReader(r io.Reader) *XMLReader {
+	return &XMLReader{
+		decoder: xml.NewDecoder(r),
+	}
+}
+
+// Read returns next record, io.EOF is returned when there are no more records
+func (r *XMLReader) Read() (Record, error) {
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return Record{}, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+
+		var decoded xmlRecord
+		if err := r.decoder.DecodeElement(&decoded, &start); err != nil {
+			return Record{}, err
+		}
+		return decoded.record(), nil
+	}
+}
+
+func (decoded xmlRecord) record() Record {
+	record := Record{Leader: decoded.Leader}
+	for _, controlField := range decoded.ControlFields {
+		record.Fields = append(record.Fields, Field{
+			Tag:   controlField.Tag,
+			Value: controlField.Value,
+		})
+	}
+	for _, dataField := range decoded.DataFields {
+		field := Field{
+			Tag:        dataField.Tag,
+			Indicators: [2]byte{xmlIndicator(dataField.Ind1), xmlIndicator(dataField.Ind2)},
+		}
+		for _, subfield := range dataField.Subfields {
+			if subfield.Code == "" {
+				continue
+			}
+			field.Subfields = append(field.Subfields, Subfield{
+				Code:  subfield.Code[0],
+				Value: subfield.Value,
+			})
+		}
+		record.Fields = append(record.Fields, field)
+	}
+	return record
+}
+
+func xmlIndicator(value string) byte {
+	if value == "" {
+		return ' '
+	}
+	return value[0]
+}
@@ -0,0 +1,118 @@
+package marc
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testRecord = Record{
+	Leader: "00100nam a2200061   4500",
+	Fields: []Field{
+		{Tag: "001", Value: "1"},
+		{Tag: "020", Indicators: [2]byte{' ', ' '}, Subfields: []Subfield{{Code: 'a', Value: "9780062315007"}}},
+		{Tag: "245", Indicators: [2]byte{'1', '0'}, Subfields: []Subfield{
+			{Code: 'a', Value: "The alchemist /"},
+			{Code: 'c', Value: "Paulo Coelho."},
+		}},
+	},
+}
+
+func TestReader(t *testing.T) {
+	encoded, err := Marshal(testRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := testRecord
+	expected.Leader = string(encoded[:leaderLength])
+
+	tests := []struct {
+		name    string
+		input   string
+		records []Record
+		err     error
+	}{
+		{
+			name:    "records separated by newline",
+			input:   string(encoded) + "\n" + string(encoded) + "\n",
+			records: []Record{expected, expected},
+			err:     io.EOF,
+		},
+		{
+			name:  "truncated record",
+			input: string(encoded[:50]),
+			err:   ErrInvalidRecord,
+		},
+		{
+			name:  "invalid base address",
+			input: "00030nam a22ABCDE   4500\x1e\x1d",
+			err:   ErrInvalidRecord,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := NewReader(strings.NewReader(tt.input))
+
+			var records []Record
+			for {
+				record, err := reader.Read()
+				if err != nil {
+					if !errors.Is(err, tt.err) {
+						t.Errorf("Read() got error: %v\nexpected: %v", err, tt.err)
+					}
+					break
+				}
+				records = append(records, record)
+			}
+
+			if !reflect.DeepEqual(records, tt.records) {
+				t.Errorf("Read() got records %+v\nexpected %+v", records, tt.records)
+			}
+		})
+	}
+}
+
+func TestXMLReader(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<collection xmlns="http://www.loc.gov/MARC21/slim">
+  <record>
+    <leader>00100nam a2200061   4500</leader>
+    <controlfield tag="001">1</controlfield>
+    <datafield tag="020" ind1=" " ind2=" ">
+      <subfield code="a">9780062315007</subfield>
+    </datafield>
+    <datafield tag="245" ind1="1" ind2="0">
+      <subfield code="a">The alchemist /</subfield>
+      <subfield code="c">Paulo Coelho.</subfield>
+    </datafield>
+  </record>
+</collection>`
+
+	reader := NewXMLReader(strings.NewReader(input))
+	record, err := reader.Read()
+	if err != nil {
+		t.Fatalf("Read() got error: %v", err)
+	}
+	if !reflect.DeepEqual(record, testRecord) {
+		t.Errorf("Read() got record %+v\nexpected %+v", record, testRecord)
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("Read() got error: %v\nexpected: %v", err, io.EOF)
+	}
+}
+
+func TestRecordFieldsByTag(t *testing.T) {
+	fields := testRecord.FieldsByTag("245")
+	if len(fields) != 1 {
+		t.Fatalf("FieldsByTag() got %d fields\nexpected 1", len(fields))
+	}
+	if title := fields[0].Subfield('a'); title != "The alchemist /" {
+		t.Errorf("Subfield() got %q\nexpected %q", title, "The alchemist /")
+	}
+	if value := fields[0].Subfield('z'); value != "" {
+		t.Errorf("Subfield() got %q\nexpected empty", value)
+	}
+}
@@ -0,0 +1,29 @@
+// Package pipelines contains multi step batch processes
+package pipelines
+
+import (
+	"book-management-system/repositories"
+	"book-management-system/usecases/eventbus"
+)
+
+// Pipelines contains pipelines
+type Pipelines struct {
+	BookImportPipeline    BookImportPipeline
+	BookExportPipeline    BookExportPipeline
+	BookDuplicatePipeline BookDuplicatePipeline
+	BookIndexPipeline     BookIndexPipeline
+	SeedPipeline          SeedPipeline
+	WebhookPipeline       WebhookPipeline
+}
+
+// Init return Pipelines publishing domain events to bus
+func Init(repo *repositories.Repository, bus *eventbus.Bus) *Pipelines {
+	return &Pipelines{
+		BookImportPipeline:    NewBookImportPipeline(repo, bus),
+		BookExportPipeline:    NewBookExportPipeline(repo),
+		BookDuplicatePipeline: NewBookDuplicatePipeline(repo, bus),
+		BookIndexPipeline:     NewBookIndexPipeline(repo),
+		SeedPipeline:          NewSeedPipeline(repo),
+		WebhookPipeline:       NewWebhookPipeline(repo),
+	}
+}
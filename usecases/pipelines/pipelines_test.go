@@ -0,0 +1,43 @@
+package pipelines
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"book-management-system/repositories"
+	"book-management-system/usecases/eventbus"
+)
+
+func TestInitPipelines(t *testing.T) {
+	repo := &repositories.Repository{}
+	bus := eventbus.New()
+
+	got := Init(repo, bus)
+	expected := &Pipelines{
+		BookImportPipeline:    NewBookImportPipeline(repo, bus),
+		BookExportPipeline:    NewBookExportPipeline(repo),
+		BookDuplicatePipeline: NewBookDuplicatePipeline(repo, bus),
+		BookIndexPipeline:     NewBookIndexPipeline(repo),
+		SeedPipeline:          NewSeedPipeline(repo),
+		WebhookPipeline:       NewWebhookPipeline(repo),
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Init returns %+v\n expected %+v",
+			got, expected)
+	}
+}
+
+var errRepository = errors.New("repository error")
+
+// recordingBus returns bus appending published events to published
+func recordingBus(published *[]eventbus.Event) *eventbus.Bus {
+	bus := eventbus.New()
+	bus.Subscribe("recorder", func(_ context.Context, event eventbus.Event) error {
+		*published = append(*published, event)
+		return nil
+	})
+	return bus
+}
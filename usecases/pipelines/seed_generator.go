@@ -0,0 +1,103 @@
+package pipelines
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+)
+
+// Words seed titles and names are made of
+var (
+	seedAdjectives = []string{
+		"Silent", "Hidden", "Last", "Forgotten", "Golden", "Broken", "Secret", "Distant", "Burning", "Quiet",
+		"Crimson", "Endless", "Lost", "Winter", "Wild", "Invisible", "Little", "Long", "Dark", "Bright",
+	}
+	seedNouns = []string{
+		"Garden", "River", "House", "Kingdom", "Letter", "Island", "Road", "Mirror", "Storm", "Library",
+		"Shadow", "Harbor", "Forest", "Promise", "Orchard", "Tower", "Map", "Song", "Lantern", "Bridge",
+	}
+	seedPersons = []string{
+		"Lighthouse Keeper", "Clockmaker", "Cartographer", "Gardener", "Beekeeper", "Astronomer",
+		"Bookseller", "Translator", "Ferryman", "Watchmaker", "Painter", "Apothecary",
+	}
+	seedRelatives = []string{"Daughter", "Son", "Wife", "Apprentice", "Secret", "Journal", "Promise"}
+	seedSubjects  = []string{
+		"Algorithms", "Philosophy", "Economics", "Astronomy", "Statistics", "Botany", "Linguistics",
+		"Architecture", "Cryptography", "Psychology", "Genetics", "Ancient Rome", "the Ottoman Empire",
+		"Jazz", "Typography", "Distributed Systems",
+	}
+	seedFirstNames = []string{
+		"Amelia", "Noah", "Olivia", "Liam", "Sofia", "Mateo", "Aisha", "Kenji", "Ingrid", "Tomasz",
+		"Leila", "Diego", "Hana", "Kwame", "Elena", "Arjun", "Freya", "Omar", "Mei", "Lucas",
+	}
+	seedLastNames = []string{
+		"Smith", "Garcia", "Nakamura", "Okafor", "Novak", "Rossi", "Haddad", "Johansson", "Kowalski",
+		"Chen", "Dubois", "Patel", "Silva", "Müller", "O'Brien", "Yilmaz", "Kim", "Andersen", "Moreau", "Singh",
+	}
+)
+
+// seedTitlePatterns are fiction and non-fiction title shapes, %[n]s are filled by seedGenerator.title
+var seedTitlePatterns = []string{
+	"The %[1]s %[2]s",
+	"The %[2]s of %[3]s",
+	"The %[4]s's %[5]s",
+	"A %[2]s in the %[6]s",
+	"Beyond the %[1]s %[2]s",
+	"Introduction to %[7]s",
+	"A Short History of %[7]s",
+	"%[7]s for Beginners",
+	"The %[2]s and the %[3]s",
+}
+
+// seedGenerator generates plausible books and members, the same seed gives the same sequence
+type seedGenerator struct {
+	rand  *rand.Rand
+	isbns map[objects.ISBN]bool
+}
+
+func newSeedGenerator(seed int64) *seedGenerator {
+	return &seedGenerator{
+		rand:  rand.New(rand.NewSource(seed)),
+		isbns: make(map[objects.ISBN]bool),
+	}
+}
+
+// book returns book with generated title and valid ISBN-13 never returned before
+func (g *seedGenerator) book() models.Book {
+	return models.Book{Name: g.title(), ISBN: g.isbn()}
+}
+
+// member returns member with generated full name
+func (g *seedGenerator) member() models.Member {
+	return models.Member{Name: g.pick(seedFirstNames) + " " + g.pick(seedLastNames)}
+}
+
+func (g *seedGenerator) title() string {
+	return fmt.Sprintf(g.pick(seedTitlePatterns),
+		g.pick(seedAdjectives), g.pick(seedNouns), g.pick(seedNouns), g.pick(seedPersons),
+		g.pick(seedRelatives), g.pick(seedNouns), g.pick(seedSubjects))
+}
+
+// isbn returns ISBN of English language groups 0 and 1
+func (g *seedGenerator) isbn() objects.ISBN {
+	for {
+		var body strings.Builder
+		fmt.Fprintf(&body, "978%d", g.rand.Intn(2))
+		for i := 0; i < 8; i++ {
+			body.WriteByte(byte('0' + g.rand.Intn(10)))
+		}
+
+		isbn, err := objects.ISBNFromBody(body.String())
+		if err == nil && !g.isbns[isbn] {
+			g.isbns[isbn] = true
+			return isbn
+		}
+	}
+}
+
+func (g *seedGenerator) pick(words []string) string {
+	return words[g.rand.Intn(len(words))]
+}
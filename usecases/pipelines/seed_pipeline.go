@@ -0,0 +1,138 @@
+package pipelines
+
+import (
+	"context"
+
+	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+	"book-management-system/repositories"
+	"book-management-system/repositories/elasticsearch"
+	"book-management-system/repositories/mysql"
+)
+
+// seedBatchSize is number of books or members generated and written at once
+const seedBatchSize = 500
+
+// SeedOptions sets volume of generated data, the same Seed generates the same data
+type SeedOptions struct {
+	Books   int
+	Members int
+	Seed    int64
+}
+
+// SeedReport counts seeded records
+type SeedReport struct {
+	Books   int `json:"books"`
+	Members int `json:"members"`
+	Indexed int `json:"indexed"`
+	// Skipped counts generated books whose ISBN was already stored, e.g. by earlier seed with the same Seed
+	Skipped int `json:"skipped"`
+}
+
+// SeedPipeline interface
+type SeedPipeline interface {
+	Seed(context.Context, SeedOptions) (SeedReport, error)
+}
+
+type seedPipeline struct {
+	MySQLBookRepository   mysql.BookRepository
+	MySQLMemberRepository mysql.MemberRepository
+	ESBookRepository      elasticsearch.BookRepository
+}
+
+// NewSeedPipeline returns SeedPipeline
+func NewSeedPipeline(repo *repositories.Repository) SeedPipeline {
+	return &seedPipeline{
+		MySQLBookRepository:   repo.MySQLBookRepository,
+		MySQLMemberRepository: repo.MySQLMemberRepository,
+		ESBookRepository:      repo.ESBookRepository,
+	}
+}
+
+// Seed generates books with valid ISBN-13 and members for demo and load test environments,
+// stores them batch by batch and indexes books into search.
+// Generated book is skipped when its ISBN is stored already and another one is generated instead.
+func (p *seedPipeline) Seed(ctx context.Context, options SeedOptions) (SeedReport, error) {
+	generator := newSeedGenerator(options.Seed)
+	report := SeedReport{}
+
+	for report.Books < options.Books {
+		size := options.Books - report.Books
+		if size > seedBatchSize {
+			size = seedBatchSize
+		}
+		if err := p.seedBooks(ctx, generator, size, &report); err != nil {
+			return report, err
+		}
+	}
+
+	for report.Members < options.Members {
+		size := options.Members - report.Members
+		if size > seedBatchSize {
+			size = seedBatchSize
+		}
+		if err := p.seedMembers(ctx, generator, size, &report); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+func (p *seedPipeline) seedBooks(ctx context.Context, generator *seedGenerator, size int, report *SeedReport) error {
+	ctx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	books := make(models.Books, size)
+	isbns := make([]objects.ISBN, size)
+	for i := range books {
+		books[i] = generator.book()
+		isbns[i] = books[i].ISBN
+	}
+
+	stored, err := p.MySQLBookRepository.GetBooksByISBNs(ctx, isbns)
+	if err != nil {
+		return err
+	}
+	if len(stored) > 0 {
+		storedISBNs := make(map[objects.ISBN]bool, len(stored))
+		for _, book := range stored {
+			storedISBNs[book.ISBN] = true
+		}
+		newBooks := make(models.Books, 0, len(books))
+		for _, book := range books {
+			if !storedISBNs[book.ISBN] {
+				newBooks = append(newBooks, book)
+			}
+		}
+		report.Skipped += len(books) - len(newBooks)
+		books = newBooks
+	}
+	if len(books) == 0 {
+		return nil
+	}
+
+	if err := p.MySQLBookRepository.CreateBooks(ctx, books); err != nil {
+		return err
+	}
+	report.Books += len(books)
+
+	if err := p.ESBookRepository.BulkIndexBooks(ctx, books); err != nil {
+		return err
+	}
+	report.Indexed += len(books)
+	return nil
+}
+
+func (p *seedPipeline) seedMembers(ctx context.Context, generator *seedGenerator, size int, report *SeedReport) error {
+	ctx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	for i := 0; i < size; i++ {
+		member := generator.member()
+		if err := p.MySQLMemberRepository.CreateMember(ctx, &member); err != nil {
+			return err
+		}
+		report.Members++
+	}
+	return nil
+}
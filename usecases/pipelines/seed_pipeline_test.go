@@ -0,0 +1,208 @@
+package pipelines
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"book-management-system/entities/models"
+	esMocks "book-management-system/mocks/repositories/elasticsearch"
+	mySqlMocks "book-management-system/mocks/repositories/mysql"
+	"book-management-system/repositories"
+	"book-management-system/repositories/elasticsearch"
+	"book-management-system/repositories/mysql"
+)
+
+func TestNewSeedPipeline(t *testing.T) {
+	mySQLBookRepo := mysql.NewBookRepository(nil)
+	mySQLMemberRepo := mysql.NewMemberRepository(nil)
+	esBookRepo := elasticsearch.NewBookRepository(nil)
+	repo := &repositories.Repository{
+		MySQLBookRepository:   mySQLBookRepo,
+		MySQLMemberRepository: mySQLMemberRepo,
+		ESBookRepository:      esBookRepo,
+	}
+
+	got := NewSeedPipeline(repo)
+	expected := &seedPipeline{
+		MySQLBookRepository:   mySQLBookRepo,
+		MySQLMemberRepository: mySQLMemberRepo,
+		ESBookRepository:      esBookRepo,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewSeedPipeline returns %+v\n expected %+v",
+			got, expected)
+	}
+}
+
+func TestSeedGenerator(t *testing.T) {
+	first, second := newSeedGenerator(1), newSeedGenerator(1)
+	for i := 0; i < 100; i++ {
+		book := first.book()
+		if err := book.Validate(); err != nil {
+			t.Fatalf("book() got invalid book %+v: %v", book, err)
+		}
+		if other := second.book(); other != book {
+			t.Fatalf("book() of the same seed got %+v\n expected %+v", other, book)
+		}
+	}
+	if len(first.isbns) != 100 {
+		t.Errorf("book() got %d distinct ISBNs\n expected 100", len(first.isbns))
+	}
+
+	if member := first.member(); member.Validate() != nil || member != second.member() {
+		t.Errorf("member() got %+v\n expected valid member same for the same seed", member)
+	}
+}
+
+func TestSeedPipelineSeed(t *testing.T) {
+	// books generated by seed 1, before and after one whose ISBN is stored
+	generator := newSeedGenerator(1)
+	generated := make(models.Books, 4)
+	for i := range generated {
+		generated[i] = generator.book()
+	}
+	firstBatch := generated[:3]
+	newBooks := models.Books{generated[0], generated[2]}
+	lastBatch := generated[3:]
+
+	type output struct {
+		report SeedReport
+		err    error
+	}
+	type mockConfig struct {
+		mySQLBookRepoMock   *mySqlMocks.MockBookRepository
+		mySQLMemberRepoMock *mySqlMocks.MockMemberRepository
+		esBookRepoMock      *esMocks.MockBookRepository
+	}
+
+	tests := []struct {
+		name           string
+		input          SeedOptions
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name:  "success seed skipping stored ISBN",
+			input: SeedOptions{Books: 3, Members: 2, Seed: 1},
+			expectedOutput: output{
+				report: SeedReport{Books: 3, Members: 2, Indexed: 3, Skipped: 1},
+			},
+			configureMock: func(conf mockConfig) {
+				gomock.InOrder(
+					conf.mySQLBookRepoMock.EXPECT().
+						GetBooksByISBNs(gomock.Any(), gomock.Len(3)).
+						Return(models.Books{{Name: "Stored", ISBN: firstBatch[1].ISBN}}, nil),
+					conf.mySQLBookRepoMock.EXPECT().
+						CreateBooks(gomock.Any(), newBooks).
+						Return(nil),
+					conf.esBookRepoMock.EXPECT().
+						BulkIndexBooks(gomock.Any(), newBooks).
+						Return(nil),
+					conf.mySQLBookRepoMock.EXPECT().
+						GetBooksByISBNs(gomock.Any(), gomock.Len(1)).
+						Return(models.Books{}, nil),
+					conf.mySQLBookRepoMock.EXPECT().
+						CreateBooks(gomock.Any(), lastBatch).
+						Return(nil),
+					conf.esBookRepoMock.EXPECT().
+						BulkIndexBooks(gomock.Any(), lastBatch).
+						Return(nil),
+				)
+				conf.mySQLMemberRepoMock.EXPECT().
+					CreateMember(gomock.Any(), gomock.Any()).
+					Return(nil).
+					Times(2)
+			},
+		},
+		{
+			name:  "error create books",
+			input: SeedOptions{Books: 3, Members: 2, Seed: 1},
+			expectedOutput: output{
+				err: errRepository,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBooksByISBNs(gomock.Any(), gomock.Len(3)).
+					Return(models.Books{}, nil)
+				conf.mySQLBookRepoMock.EXPECT().
+					CreateBooks(gomock.Any(), firstBatch).
+					Return(errRepository)
+			},
+		},
+		{
+			name:  "error index books",
+			input: SeedOptions{Books: 3, Seed: 1},
+			expectedOutput: output{
+				report: SeedReport{Books: 3},
+				err:    errRepository,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBooksByISBNs(gomock.Any(), gomock.Len(3)).
+					Return(models.Books{}, nil)
+				conf.mySQLBookRepoMock.EXPECT().
+					CreateBooks(gomock.Any(), firstBatch).
+					Return(nil)
+				conf.esBookRepoMock.EXPECT().
+					BulkIndexBooks(gomock.Any(), firstBatch).
+					Return(errRepository)
+			},
+		},
+		{
+			name:  "error create member",
+			input: SeedOptions{Members: 2, Seed: 1},
+			expectedOutput: output{
+				report: SeedReport{Members: 1},
+				err:    errRepository,
+			},
+			configureMock: func(conf mockConfig) {
+				gomock.InOrder(
+					conf.mySQLMemberRepoMock.EXPECT().
+						CreateMember(gomock.Any(), gomock.Any()).
+						Return(nil),
+					conf.mySQLMemberRepoMock.EXPECT().
+						CreateMember(gomock.Any(), gomock.Any()).
+						Return(errRepository),
+				)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mySQLBookRepoMock := mySqlMocks.NewMockBookRepository(ctrl)
+			mySQLMemberRepoMock := mySqlMocks.NewMockMemberRepository(ctrl)
+			esBookRepoMock := esMocks.NewMockBookRepository(ctrl)
+
+			seedPipeline := &seedPipeline{
+				MySQLBookRepository:   mySQLBookRepoMock,
+				MySQLMemberRepository: mySQLMemberRepoMock,
+				ESBookRepository:      esBookRepoMock,
+			}
+
+			tt.configureMock(mockConfig{
+				mySQLBookRepoMock:   mySQLBookRepoMock,
+				mySQLMemberRepoMock: mySQLMemberRepoMock,
+				esBookRepoMock:      esBookRepoMock,
+			})
+
+			report, err := seedPipeline.Seed(context.TODO(), tt.input)
+			if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+				t.Errorf("Seed() got error: %v\nexpected: %v",
+					err, expectedError)
+			}
+			if report != tt.expectedOutput.report {
+				t.Errorf("Seed() got report %+v\n expected %+v",
+					report, tt.expectedOutput.report)
+			}
+		})
+	}
+}
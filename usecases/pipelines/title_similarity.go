@@ -0,0 +1,54 @@
+package pipelines
+
+import (
+	"strings"
+	"unicode"
+)
+
+// titleSimilarity returns Sørensen–Dice coefficient of character bigrams of normalized titles,
+// 1 for equal titles and 0 for titles with nothing in common
+func titleSimilarity(a, b string) float64 {
+	a, b = normalizeTitle(a), normalizeTitle(b)
+	if a == b {
+		return 1
+	}
+
+	aBigrams, bBigrams := bigrams(a), bigrams(b)
+	total := len(aBigrams) + len(bBigrams)
+	if total == 0 {
+		return 0
+	}
+
+	counts := make(map[string]int, len(aBigrams))
+	for _, bigram := range aBigrams {
+		counts[bigram]++
+	}
+	common := 0
+	for _, bigram := range bBigrams {
+		if counts[bigram] > 0 {
+			counts[bigram]--
+			common++
+		}
+	}
+	return float64(2*common) / float64(total)
+}
+
+// normalizeTitle lowercases title and replaces punctuation by single spaces
+func normalizeTitle(title string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+func bigrams(value string) []string {
+	runes := []rune(value)
+	if len(runes) < 2 {
+		return nil
+	}
+
+	result := make([]string, 0, len(runes)-1)
+	for i := 0; i < len(runes)-1; i++ {
+		result = append(result, string(runes[i:i+2]))
+	}
+	return result
+}
@@ -0,0 +1,366 @@
+package pipelines
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"book-management-system/configs"
+	"book-management-system/entities/constants"
+	"book-management-system/entities/models"
+	"book-management-system/events"
+	"book-management-system/logging"
+	"book-management-system/metrics"
+	"book-management-system/repositories"
+	"book-management-system/repositories/mysql"
+)
+
+// Headers of webhook requests. Receivers verify X-Webhook-Signature, which is
+// sha256= followed by hex HMAC-SHA256 of X-Webhook-Timestamp, a dot and the body keyed by the webhook secret,
+// and reject old timestamps to prevent replays. X-Webhook-Delivery is the same for redeliveries of an event.
+const (
+	HeaderWebhookDelivery  = "X-Webhook-Delivery"
+	HeaderWebhookEvent     = "X-Webhook-Event"
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp"
+	HeaderWebhookSignature = "X-Webhook-Signature"
+)
+
+const (
+	// dispatchBuffer is number of events the dispatcher may fall behind the feed before resuming from its log
+	dispatchBuffer = 1024
+	// dispatchBatchSize is number of events whose deliveries are enqueued at once
+	dispatchBatchSize = 100
+	// dispatchDrainTimeout bounds enqueueing of events left in the buffer on shutdown
+	dispatchDrainTimeout = 5 * time.Second
+	// deliveryPollInterval is how often due deliveries are looked up
+	deliveryPollInterval = time.Second
+	// deliveryBatchSize is number of due deliveries read at once
+	deliveryBatchSize = 50
+	// deliveryClaimMargin is added to request timeout while delivery is claimed,
+	// delivery of instance stopped meanwhile is retried after the claim expires
+	deliveryClaimMargin = 30 * time.Second
+	// deliveryResponseLimit is how much of response body is read, so that connections are reused
+	deliveryResponseLimit = 64 << 10
+)
+
+// webhookClient does not follow redirects, endpoints must answer themselves
+var webhookClient = &http.Client{
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// WebhookPipeline turns events of the change feed into webhook deliveries and sends them with retries.
+// Deliveries of one webhook are not ordered, receivers order events by their ID.
+type WebhookPipeline interface {
+	DispatchEvents(context.Context)
+	EnqueueDeliveries(context.Context, []events.Event) (int, error)
+	DeliverWebhooks(context.Context)
+	DeliverDue(context.Context) (int, error)
+}
+
+type webhookPipeline struct {
+	MySQLWebhookRepository mysql.WebhookRepository
+	feed                   *events.Feed
+	workers                int
+	timeout                time.Duration
+	maxAttempts            int
+	backoff                time.Duration
+	maxBackoff             time.Duration
+}
+
+// NewWebhookPipeline returns WebhookPipeline
+func NewWebhookPipeline(repo *repositories.Repository) WebhookPipeline {
+	cfg := configs.GetConfig().Webhooks
+	return &webhookPipeline{
+		MySQLWebhookRepository: repo.MySQLWebhookRepository,
+		feed:                   repo.Events,
+		workers:                cfg.Workers,
+		timeout:                time.Duration(cfg.Timeout) * time.Second,
+		maxAttempts:            cfg.MaxAttempts,
+		backoff:                time.Duration(cfg.Backoff) * time.Second,
+		maxBackoff:             time.Duration(cfg.MaxBackoff) * time.Second,
+	}
+}
+
+// DispatchEvents enqueues deliveries of events published to the feed until ctx is done, events left in
+// its buffer are enqueued before returning. Falling behind the feed resumes from the feed log,
+// events no longer kept there are missed.
+func (p *webhookPipeline) DispatchEvents(ctx context.Context) {
+	var lastID uint64
+	for ctx.Err() == nil {
+		sub := p.feed.Subscribe(nil, lastID, dispatchBuffer)
+		if sub.Reset {
+			metrics.WebhookEventsMissed.Inc()
+			logging.Logger(logging.PackagePipelines).Error("webhook dispatcher fell behind, missed events are not delivered",
+				zap.Uint64("last_event_id", lastID))
+		}
+		lastID = p.dispatch(ctx, sub, lastID)
+		sub.Close()
+	}
+}
+
+// dispatch enqueues deliveries of events of sub until ctx is done or sub is dropped, it returns ID of last event
+func (p *webhookPipeline) dispatch(ctx context.Context, sub *events.Subscription, lastID uint64) uint64 {
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return lastID
+			}
+			batch := receiveEvents(sub, []events.Event{event})
+			p.enqueue(ctx, batch)
+			lastID = batch[len(batch)-1].ID
+		case <-ctx.Done():
+			// events published while requests were drained are delivered as well
+			drainCtx, cancel := context.WithTimeout(context.Background(), dispatchDrainTimeout)
+			defer cancel()
+			for batch := receiveEvents(sub, nil); len(batch) > 0; batch = receiveEvents(sub, nil) {
+				p.enqueue(drainCtx, batch)
+				lastID = batch[len(batch)-1].ID
+			}
+			return lastID
+		}
+	}
+}
+
+// receiveEvents appends events buffered by sub to batch without waiting, up to dispatchBatchSize
+func receiveEvents(sub *events.Subscription, batch []events.Event) []events.Event {
+	for len(batch) < dispatchBatchSize {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return batch
+			}
+			batch = append(batch, event)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+func (p *webhookPipeline) enqueue(ctx context.Context, batch []events.Event) {
+	if _, err := p.EnqueueDeliveries(ctx, batch); err != nil {
+		logging.FromContext(ctx, logging.PackagePipelines).Error("failed to enqueue webhook deliveries",
+			zap.Uint64("first_event_id", batch[0].ID), zap.Int("events", len(batch)), zap.Error(err))
+	}
+}
+
+// EnqueueDeliveries stores pending deliveries of events to webhooks subscribed to them
+// and returns number of deliveries
+func (p *webhookPipeline) EnqueueDeliveries(ctx context.Context, batch []events.Event) (int, error) {
+	ctx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	webhooks, err := p.MySQLWebhookRepository.GetWebhooks(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var deliveries []models.WebhookDelivery
+	for _, event := range batch {
+		var payload []byte
+		for _, webhook := range webhooks {
+			if !webhook.Matches(event.Type) {
+				continue
+			}
+			if payload == nil {
+				if payload, err = json.Marshal(event); err != nil {
+					return 0, fmt.Errorf("failed to encode event %d: %w", event.ID, err)
+				}
+			}
+			deliveries = append(deliveries, models.WebhookDelivery{
+				WebhookID:     webhook.ID,
+				EventID:       event.ID,
+				EventType:     event.Type,
+				Payload:       models.JSONText(payload),
+				Status:        models.WebhookDeliveryPending,
+				NextAttemptAt: now,
+			})
+		}
+	}
+	return len(deliveries), p.MySQLWebhookRepository.CreateDeliveries(ctx, deliveries)
+}
+
+// DeliverWebhooks sends due deliveries every deliveryPollInterval until ctx is done
+func (p *webhookPipeline) DeliverWebhooks(ctx context.Context) {
+	ticker := time.NewTicker(deliveryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// full batches are followed at once by the next one
+		for ctx.Err() == nil {
+			count, err := p.DeliverDue(ctx)
+			if err != nil {
+				logging.Logger(logging.PackagePipelines).Error("failed to deliver webhooks", zap.Error(err))
+				break
+			}
+			if count < deliveryBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// DeliverDue sends one batch of due deliveries, up to configured number of them at once.
+// It returns number of due deliveries, some may have been sent by other instances meanwhile.
+func (p *webhookPipeline) DeliverDue(ctx context.Context) (int, error) {
+	readCtx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+
+	due, err := p.MySQLWebhookRepository.GetDueDeliveries(readCtx, time.Now(), deliveryBatchSize)
+	if err != nil || len(due) == 0 {
+		return 0, err
+	}
+	webhooks, err := p.MySQLWebhookRepository.GetWebhooks(readCtx)
+	if err != nil {
+		return 0, err
+	}
+	byID := make(map[uint]*models.Webhook, len(webhooks))
+	for i := range webhooks {
+		byID[webhooks[i].ID] = &webhooks[i]
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, p.workers)
+	for i := range due {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(delivery *models.WebhookDelivery) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			p.deliver(ctx, byID[delivery.WebhookID], delivery)
+		}(&due[i])
+	}
+	wg.Wait()
+	return len(due), nil
+}
+
+// deliver claims delivery and sends it to webhook, nil when the webhook was deleted,
+// then records the result. Failed attempts are retried with backoff until they run out and the delivery is dead.
+func (p *webhookPipeline) deliver(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) {
+	logger := logging.FromContext(ctx, logging.PackagePipelines).
+		With(zap.Uint("delivery_id", delivery.ID), zap.Uint("webhook_id", delivery.WebhookID))
+
+	claimed, err := p.MySQLWebhookRepository.ClaimDelivery(ctx, delivery,
+		time.Now().Add(p.timeout+deliveryClaimMargin))
+	if err != nil {
+		logger.Error("failed to claim webhook delivery", zap.Error(err))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	// deliveries of deleted and disabled webhooks are dead at once, they may be redelivered once it is enabled
+	retry := true
+	switch {
+	case webhook == nil:
+		err, retry = errors.New("webhook was deleted"), false
+	case webhook.Disabled:
+		err, retry = errors.New("webhook is disabled"), false
+	default:
+		delivery.ResponseCode, err = p.send(ctx, webhook, delivery)
+	}
+	if ctx.Err() != nil {
+		// interrupted attempt is retried once the claim expires
+		return
+	}
+
+	result := "succeeded"
+	if err == nil {
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.Error = ""
+	} else {
+		delivery.Error = err.Error()
+		if !retry || delivery.Attempts >= p.maxAttempts {
+			result = "dead"
+			delivery.Status = models.WebhookDeliveryDead
+			logger.Warn("webhook delivery failed all attempts", zap.Int("attempts", delivery.Attempts), zap.Error(err))
+		} else {
+			result = "failed"
+			delivery.NextAttemptAt = time.Now().Add(p.retryDelay(delivery.Attempts))
+		}
+	}
+	metrics.WebhookDeliveries.WithLabelValues(result).Inc()
+
+	updateCtx, cancel := setBatchContextTimeout(ctx)
+	defer cancel()
+	if err := p.MySQLWebhookRepository.UpdateDelivery(updateCtx, delivery); err != nil {
+		logger.Error("failed to record webhook delivery", zap.String("result", result), zap.Error(err))
+	}
+}
+
+// retryDelay returns backoff after failed attempt, doubling with every attempt up to maxBackoff
+func (p *webhookPipeline) retryDelay(attempts int) time.Duration {
+	delay := p.backoff
+	for i := 1; i < attempts && delay < p.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.maxBackoff {
+		delay = p.maxBackoff
+	}
+	return delay
+}
+
+// send posts payload of delivery signed by secret of webhook and returns response status code,
+// responses other than 2xx are errors
+func (p *webhookPipeline) send(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", strings.ReplaceAll(constants.ServiceName, " ", "-")+"/"+constants.ServiceVersion)
+	req.Header.Set(HeaderWebhookDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderWebhookEvent, delivery.EventType)
+	req.Header.Set(HeaderWebhookTimestamp, timestamp)
+	req.Header.Set(HeaderWebhookSignature, SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, deliveryResponseLimit))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload returns X-Webhook-Signature of body sent at timestamp in Unix seconds
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = io.WriteString(mac, timestamp)
+	_, _ = io.WriteString(mac, ".")
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
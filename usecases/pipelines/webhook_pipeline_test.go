@@ -0,0 +1,351 @@
+package pipelines
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+
+	"book-management-system/configs"
+	"book-management-system/entities/models"
+	"book-management-system/events"
+	mySqlMocks "book-management-system/mocks/repositories/mysql"
+	"book-management-system/repositories"
+	"book-management-system/repositories/mysql"
+)
+
+const testWebhookSecret = "0123456789abcdef0123456789abcdef"
+
+func TestNewWebhookPipeline(t *testing.T) {
+	mySQLWebhookRepo := mysql.NewWebhookRepository(nil)
+	feed := events.NewFeed(1)
+	repo := &repositories.Repository{
+		MySQLWebhookRepository: mySQLWebhookRepo,
+		Events:                 feed,
+	}
+	cfg := configs.GetConfig().Webhooks
+
+	got := NewWebhookPipeline(repo)
+	expected := &webhookPipeline{
+		MySQLWebhookRepository: mySQLWebhookRepo,
+		feed:                   feed,
+		workers:                cfg.Workers,
+		timeout:                time.Duration(cfg.Timeout) * time.Second,
+		maxAttempts:            cfg.MaxAttempts,
+		backoff:                time.Duration(cfg.Backoff) * time.Second,
+		maxBackoff:             time.Duration(cfg.MaxBackoff) * time.Second,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewWebhookPipeline returns %+v\n expected %+v",
+			got, expected)
+	}
+}
+
+func TestWebhookPipelineEnqueueDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mySQLWebhookRepoMock := mySqlMocks.NewMockWebhookRepository(ctrl)
+	mySQLWebhookRepoMock.EXPECT().
+		GetWebhooks(gomock.Any()).
+		Return([]models.Webhook{
+			{Model: gorm.Model{ID: 1}},
+			{Model: gorm.Model{ID: 2}, EventTypes: models.WebhookEventTypes{events.TypeMemberCreated}},
+			{Model: gorm.Model{ID: 3}, Disabled: true},
+		}, nil)
+	var got []models.WebhookDelivery
+	mySQLWebhookRepoMock.EXPECT().
+		CreateDeliveries(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, deliveries []models.WebhookDelivery) error {
+			got = deliveries
+			return nil
+		})
+	p := &webhookPipeline{MySQLWebhookRepository: mySQLWebhookRepoMock}
+
+	batch := []events.Event{
+		{ID: 11, Type: events.TypeBookCreated, Data: []byte(`{"name":"Go"}`)},
+		{ID: 12, Type: events.TypeMemberCreated, Data: []byte(`{"name":"John Lennon"}`)},
+	}
+	count, err := p.EnqueueDeliveries(context.TODO(), batch)
+	if err != nil || count != 3 {
+		t.Fatalf("EnqueueDeliveries() returns %d, %v\n expected %d deliveries", count, err, 3)
+	}
+
+	expected := []struct {
+		webhookID uint
+		eventID   uint64
+	}{{1, 11}, {1, 12}, {2, 12}}
+	for i, delivery := range got {
+		if delivery.WebhookID != expected[i].webhookID || delivery.EventID != expected[i].eventID ||
+			delivery.Status != models.WebhookDeliveryPending || delivery.NextAttemptAt.IsZero() {
+			t.Errorf("EnqueueDeliveries() delivery %d is %+v\n expected pending delivery of event %d to webhook %d",
+				i, delivery, expected[i].eventID, expected[i].webhookID)
+		}
+	}
+	if payload := string(got[0].Payload); payload != `{"id":11,"type":"book.created","time":"0001-01-01T00:00:00Z",`+
+		`"data":{"name":"Go"}}` {
+		t.Errorf("EnqueueDeliveries() payload is %s\n expected event as JSON", payload)
+	}
+}
+
+func TestWebhookPipelineDeliverDue(t *testing.T) {
+	type request struct {
+		header http.Header
+		body   string
+	}
+	var mu sync.Mutex
+	var requests []request
+	responseCode := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, request{header: r.Header, body: string(body)})
+		w.WriteHeader(responseCode)
+	}))
+	defer server.Close()
+
+	webhook := models.Webhook{Model: gorm.Model{ID: 1}, URL: server.URL, Secret: testWebhookSecret}
+	payload := models.JSONText(`{"id":11,"type":"book.created"}`)
+
+	tests := []struct {
+		name         string
+		webhooks     []models.Webhook
+		responseCode int
+		attempts     int
+		claimed      bool
+		sent         bool
+		status       models.WebhookDeliveryStatus
+		retryIn      time.Duration
+	}{
+		{
+			name:         "success delivered",
+			webhooks:     []models.Webhook{webhook},
+			responseCode: http.StatusNoContent,
+			claimed:      true,
+			sent:         true,
+			status:       models.WebhookDeliverySucceeded,
+		},
+		{
+			name:         "failed attempt retried with backoff",
+			webhooks:     []models.Webhook{webhook},
+			responseCode: http.StatusInternalServerError,
+			attempts:     1,
+			claimed:      true,
+			sent:         true,
+			status:       models.WebhookDeliveryPending,
+			retryIn:      2 * time.Minute,
+		},
+		{
+			name:         "last failed attempt is dead",
+			webhooks:     []models.Webhook{webhook},
+			responseCode: http.StatusServiceUnavailable,
+			attempts:     2,
+			claimed:      true,
+			sent:         true,
+			status:       models.WebhookDeliveryDead,
+		},
+		{
+			name:     "deleted webhook is dead",
+			webhooks: []models.Webhook{},
+			claimed:  true,
+			status:   models.WebhookDeliveryDead,
+		},
+		{
+			name:     "claimed by other instance",
+			webhooks: []models.Webhook{webhook},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests, responseCode = nil, tt.responseCode
+			mySQLWebhookRepoMock := mySqlMocks.NewMockWebhookRepository(ctrl)
+			p := &webhookPipeline{
+				MySQLWebhookRepository: mySQLWebhookRepoMock,
+				workers:                2,
+				timeout:                time.Second,
+				maxAttempts:            3,
+				backoff:                time.Minute,
+				maxBackoff:             time.Hour,
+			}
+
+			due := models.WebhookDelivery{
+				ID:        5,
+				WebhookID: 1,
+				EventID:   11,
+				EventType: events.TypeBookCreated,
+				Payload:   payload,
+				Status:    models.WebhookDeliveryPending,
+				Attempts:  tt.attempts,
+			}
+			mySQLWebhookRepoMock.EXPECT().
+				GetDueDeliveries(gomock.Any(), gomock.Any(), deliveryBatchSize).
+				Return([]models.WebhookDelivery{due}, nil)
+			mySQLWebhookRepoMock.EXPECT().
+				GetWebhooks(gomock.Any()).
+				Return(tt.webhooks, nil)
+			mySQLWebhookRepoMock.EXPECT().
+				ClaimDelivery(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, delivery *models.WebhookDelivery, _ time.Time) (bool, error) {
+					if tt.claimed {
+						delivery.Attempts++
+					}
+					return tt.claimed, nil
+				})
+			var updated *models.WebhookDelivery
+			if tt.claimed {
+				mySQLWebhookRepoMock.EXPECT().
+					UpdateDelivery(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, delivery *models.WebhookDelivery) error {
+						updated = delivery
+						return nil
+					})
+			}
+
+			count, err := p.DeliverDue(context.TODO())
+			if err != nil || count != 1 {
+				t.Fatalf("DeliverDue() returns %d, %v\n expected %d", count, err, 1)
+			}
+
+			if tt.sent != (len(requests) == 1) {
+				t.Fatalf("DeliverDue() sent %d requests, expected sent %v", len(requests), tt.sent)
+			}
+			if tt.sent {
+				req := requests[0]
+				timestamp := req.header.Get(HeaderWebhookTimestamp)
+				mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+				mac.Write([]byte(timestamp + "." + req.body))
+				signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+				if req.body != string(payload) || req.header.Get(HeaderWebhookSignature) != signature {
+					t.Errorf("DeliverDue() sent %s signed %s\n expected %s signed %s",
+						req.body, req.header.Get(HeaderWebhookSignature), payload, signature)
+				}
+				if sent, err := strconv.ParseInt(timestamp, 10, 64); err != nil || time.Since(time.Unix(sent, 0)) > time.Minute {
+					t.Errorf("DeliverDue() sent timestamp %q\n expected current Unix time", timestamp)
+				}
+				if req.header.Get(HeaderWebhookDelivery) != "5" || req.header.Get(HeaderWebhookEvent) != events.TypeBookCreated {
+					t.Errorf("DeliverDue() sent headers %v\n expected delivery 5 of %s", req.header, events.TypeBookCreated)
+				}
+			}
+
+			if !tt.claimed {
+				return
+			}
+			if updated.Status != tt.status || updated.Attempts != tt.attempts+1 {
+				t.Errorf("DeliverDue() recorded %+v\n expected status %s after %d attempts",
+					updated, tt.status, tt.attempts+1)
+			}
+			if tt.sent && updated.ResponseCode != tt.responseCode {
+				t.Errorf("DeliverDue() recorded response code %d, expected %d", updated.ResponseCode, tt.responseCode)
+			}
+			if (tt.status == models.WebhookDeliverySucceeded) != (updated.Error == "") {
+				t.Errorf("DeliverDue() recorded error %q with status %s", updated.Error, updated.Status)
+			}
+			if tt.retryIn > 0 {
+				if retryIn := time.Until(updated.NextAttemptAt); retryIn <= tt.retryIn-time.Minute || retryIn > tt.retryIn {
+					t.Errorf("DeliverDue() retries in %v, expected %v", retryIn, tt.retryIn)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookPipelineRetryDelay(t *testing.T) {
+	p := &webhookPipeline{backoff: 30 * time.Second, maxBackoff: time.Hour}
+
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 1, expected: 30 * time.Second},
+		{attempts: 2, expected: time.Minute},
+		{attempts: 5, expected: 8 * time.Minute},
+		{attempts: 8, expected: time.Hour},
+		{attempts: 100, expected: time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := p.retryDelay(tt.attempts); got != tt.expected {
+			t.Errorf("retryDelay(%d) returns %v\n expected %v", tt.attempts, got, tt.expected)
+		}
+	}
+}
+
+func TestWebhookPipelineDispatchEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	feed := events.NewFeed(10)
+	mySQLWebhookRepoMock := mySqlMocks.NewMockWebhookRepository(ctrl)
+	mySQLWebhookRepoMock.EXPECT().
+		GetWebhooks(gomock.Any()).
+		Return([]models.Webhook{{Model: gorm.Model{ID: 1}}}, nil).
+		AnyTimes()
+	var mu sync.Mutex
+	var enqueued []uint64
+	mySQLWebhookRepoMock.EXPECT().
+		CreateDeliveries(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, deliveries []models.WebhookDelivery) error {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, delivery := range deliveries {
+				enqueued = append(enqueued, delivery.EventID)
+			}
+			return nil
+		}).
+		AnyTimes()
+	p := &webhookPipeline{MySQLWebhookRepository: mySQLWebhookRepoMock, feed: feed}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.DispatchEvents(ctx)
+	}()
+
+	// waits for the dispatcher to subscribe
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_ = feed.Publish(events.TypeBookCreated, nil)
+		mu.Lock()
+		started := len(enqueued) > 0
+		mu.Unlock()
+		if started || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for i := 0; i < 3; i++ {
+		_ = feed.Publish(events.TypeMemberCreated, nil)
+	}
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(enqueued) < 4 {
+		t.Fatalf("DispatchEvents() enqueued events %v\n expected events published before it stopped", enqueued)
+	}
+	for i := 1; i < len(enqueued); i++ {
+		if enqueued[i] != enqueued[i-1]+1 {
+			t.Errorf("DispatchEvents() enqueued events %v\n expected consecutive events", enqueued)
+			break
+		}
+	}
+}
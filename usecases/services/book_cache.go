@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+
+	"book-management-system/cache"
+	"book-management-system/configs"
+	"book-management-system/logging"
+)
+
+// bookCache caches catalogue reads as JSON, concurrent misses of the same key share one fetch.
+// Any change of books invalidates all cached reads through the namespace, see subscribers of usecases.
+// Nil bookCache caches nothing.
+type bookCache struct {
+	namespace cache.Namespace
+	ttl       time.Duration
+	group     *singleflight.Group
+}
+
+// newBookCache returns bookCache of c, nil when caching is disabled
+func newBookCache(c cache.Cache) *bookCache {
+	if c == nil {
+		return nil
+	}
+	return &bookCache{
+		namespace: cache.NewNamespace(c, cache.NamespaceBooks),
+		ttl:       time.Duration(configs.GetConfig().Cache.TTL) * time.Second,
+		group:     &singleflight.Group{},
+	}
+}
+
+// load decodes cached value of key into value, on miss it stores value fetched by fetch.
+// Errors of fetch are returned and not cached, failing cache is logged and bypassed.
+func (c *bookCache) load(ctx context.Context, key string, value interface{}, fetch func() (interface{}, error)) error {
+	if c == nil {
+		return assignFetched(fetch, value)
+	}
+
+	logger := logging.FromContext(ctx, logging.PackageServices)
+	key, err := c.namespace.Key(ctx, key)
+	if err != nil {
+		logger.Warn("failed to read book cache", zap.Error(err))
+		return assignFetched(fetch, value)
+	}
+	if cached, ok, err := c.namespace.Get(ctx, key); err != nil {
+		logger.Warn("failed to read book cache", zap.String("key", key), zap.Error(err))
+	} else if ok {
+		return json.Unmarshal(cached, value)
+	}
+
+	// each caller decodes its own copy, so that callers changing the value do not affect others
+	encoded, err, _ := c.group.Do(key, func() (interface{}, error) {
+		fetched, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := json.Marshal(fetched)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.namespace.Set(ctx, key, encoded, c.ttl); err != nil {
+			logger.Warn("failed to write book cache", zap.String("key", key), zap.Error(err))
+		}
+		return encoded, nil
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded.([]byte), value)
+}
+
+// assignFetched sets value, pointer to type fetch returns, to fetched value as is, also when fetch fails
+func assignFetched(fetch func() (interface{}, error), value interface{}) error {
+	fetched, err := fetch()
+	if fetched != nil {
+		reflect.ValueOf(value).Elem().Set(reflect.ValueOf(fetched))
+	}
+	return err
+}
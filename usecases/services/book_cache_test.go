@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"book-management-system/cache"
+	"book-management-system/entities/models"
+)
+
+func TestBookCacheLoad(t *testing.T) {
+	ctx := context.TODO()
+	c := newBookCache(cache.NewLRU(10))
+	expected := models.Books{{Model: gorm.Model{ID: 1}, Name: "Go"}}
+
+	var fetches int32
+	release := make(chan struct{})
+	fetch := func() (interface{}, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return expected, nil
+	}
+
+	// concurrent misses share one fetch
+	var wg sync.WaitGroup
+	results := make([]models.Books, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := c.load(ctx, "all", &results[i], fetch); err != nil {
+				t.Errorf("load() got error %v", err)
+			}
+		}(i)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if fetches != 1 {
+		t.Errorf("load() fetched %d times\n expected %d", fetches, 1)
+	}
+	for _, books := range results {
+		if !reflect.DeepEqual(books, expected) {
+			t.Errorf("load() got books %+v\n expected %+v", books, expected)
+		}
+	}
+
+	var books models.Books
+	_ = c.load(ctx, "all", &books, fetch)
+	if fetches != 1 {
+		t.Errorf("load() of cached key fetched %d times\n expected %d", fetches, 1)
+	}
+
+	_ = c.namespace.Invalidate(ctx)
+	_ = c.load(ctx, "all", &books, fetch)
+	if fetches != 2 {
+		t.Errorf("load() after Invalidate fetched %d times\n expected %d", fetches, 2)
+	}
+}
+
+func TestBookCacheLoadError(t *testing.T) {
+	ctx := context.TODO()
+	fetchErr := errors.New("fetch error")
+
+	for _, c := range []*bookCache{nil, newBookCache(cache.NewLRU(10))} {
+		var fetches int
+		fetch := func() (interface{}, error) {
+			fetches++
+			return nil, fetchErr
+		}
+
+		var book *models.Book
+		for i := 0; i < 2; i++ {
+			if err := c.load(ctx, "id:1", &book, fetch); !errors.Is(err, fetchErr) {
+				t.Errorf("load() got error %v\n expected %v", err, fetchErr)
+			}
+		}
+		if fetches != 2 {
+			t.Errorf("load() fetched %d times\n expected errors not to be cached", fetches)
+		}
+	}
+}
@@ -2,32 +2,46 @@ package services
 
 import (
 	"context"
-	"log"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 
 	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
+	"book-management-system/metrics"
 	"book-management-system/repositories"
 	"book-management-system/repositories/elasticsearch"
 	"book-management-system/repositories/mysql"
+	"book-management-system/usecases/eventbus"
 )
 
 // BookService handle business logic related to book
 type BookService interface {
 	GetBooks(context.Context) (models.Books, error)
+	GetBook(context.Context, uint) (*models.Book, error)
+	GetBookByISBN(context.Context, string) (*models.Book, error)
 	CreateBook(context.Context, *models.Book) error
 	UpdateBook(context.Context, *models.Book) error
 	SearchBooks(context.Context, string) (models.Books, error)
 }
 
 type bookService struct {
-	MySQLBookRepository mysql.BookRepository
-	ESBookRepository    elasticsearch.BookRepository
+	MySQLBookRepository          mysql.BookRepository
+	MySQLBookDuplicateRepository mysql.BookDuplicateRepository
+	ESBookRepository             elasticsearch.BookRepository
+	cache                        *bookCache
+	bus                          *eventbus.Bus
 }
 
-// NewBookService returns BookService
-func NewBookService(repo *repositories.Repository) BookService {
+// NewBookService returns BookService publishing changes of books to bus
+func NewBookService(repo *repositories.Repository, bus *eventbus.Bus) BookService {
 	return &bookService{
-		MySQLBookRepository: repo.MySQLBookRepository,
-		ESBookRepository:    repo.ESBookRepository,
+		MySQLBookRepository:          repo.MySQLBookRepository,
+		MySQLBookDuplicateRepository: repo.MySQLBookDuplicateRepository,
+		ESBookRepository:             repo.ESBookRepository,
+		cache:                        newBookCache(repo.Cache),
+		bus:                          bus,
 	}
 }
 
@@ -35,10 +49,55 @@ func (svc *bookService) GetBooks(ctx context.Context) (models.Books, error) {
 	ctx, cancel := setContextTimeout(ctx)
 	defer cancel()
 
-	return svc.MySQLBookRepository.GetAll(ctx)
+	var books models.Books
+	err := svc.cache.load(ctx, "all", &books, func() (interface{}, error) {
+		return svc.MySQLBookRepository.GetAll(ctx)
+	})
+	return books, err
+}
+
+// GetBook returns book by ID, MovedError when the book was merged into another book
+func (svc *bookService) GetBook(ctx context.Context, id uint) (*models.Book, error) {
+	ctx, cancel := setContextTimeout(ctx)
+	defer cancel()
+
+	var book *models.Book
+	err := svc.cache.load(ctx, fmt.Sprintf("id:%d", id), &book, func() (interface{}, error) {
+		return svc.MySQLBookRepository.GetBookByID(ctx, id)
+	})
+	if errors.Is(err, models.ErrNotFound) {
+		if toID, redirectErr := svc.MySQLBookDuplicateRepository.GetRedirect(ctx, id); redirectErr == nil {
+			return nil, &models.MovedError{ID: toID}
+		}
+	}
+	return book, err
+}
+
+// GetBookByISBN returns book by ISBN-10 or ISBN-13 in any notation
+func (svc *bookService) GetBookByISBN(ctx context.Context, value string) (*models.Book, error) {
+	isbn, err := objects.ParseISBN(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", models.ErrInvalid, err)
+	}
+	if isbn.IsZero() {
+		return nil, fmt.Errorf("%w: ISBN is required", models.ErrInvalid)
+	}
+
+	ctx, cancel := setContextTimeout(ctx)
+	defer cancel()
+
+	var book *models.Book
+	err = svc.cache.load(ctx, "isbn:"+isbn.String(), &book, func() (interface{}, error) {
+		return svc.MySQLBookRepository.GetBookByISBN(ctx, isbn)
+	})
+	return book, err
 }
 
 func (svc *bookService) CreateBook(ctx context.Context, book *models.Book) error {
+	if err := book.Validate(); err != nil {
+		return err
+	}
+
 	ctx, cancel := setContextTimeout(ctx)
 	defer cancel()
 
@@ -46,17 +105,17 @@ func (svc *bookService) CreateBook(ctx context.Context, book *models.Book) error
 	if err != nil {
 		return err
 	}
+	svc.bus.Publish(ctx, eventbus.BookCreated{Book: *book})
+	metrics.BooksCreated.WithLabelValues(metrics.SourceAPI).Inc()
 
-	go func() {
-		err := svc.ESBookRepository.IndexBook(context.Background(), book)
-		if err != nil {
-			log.Printf("error create book in elasticsearch %s", err)
-		}
-	}()
 	return nil
 }
 
 func (svc *bookService) UpdateBook(ctx context.Context, book *models.Book) error {
+	if err := book.Validate(); err != nil {
+		return err
+	}
+
 	ctx, cancel := setContextTimeout(ctx)
 	defer cancel()
 
@@ -64,13 +123,9 @@ func (svc *bookService) UpdateBook(ctx context.Context, book *models.Book) error
 	if err != nil {
 		return err
 	}
+	svc.bus.Publish(ctx, eventbus.BookUpdated{Book: *book})
+	metrics.BooksUpdated.Inc()
 
-	go func() {
-		err := svc.ESBookRepository.IndexBook(context.Background(), book)
-		if err != nil {
-			log.Printf("error update book in elasticsearch %s", err)
-		}
-	}()
 	return nil
 }
 
@@ -78,5 +133,11 @@ func (svc *bookService) SearchBooks(ctx context.Context, keyword string) (models
 	ctx, cancel := setContextTimeout(ctx)
 	defer cancel()
 
-	return svc.ESBookRepository.SearchBook(ctx, keyword)
+	// keywords are hashed to keep keys short for shared caches
+	sum := sha256.Sum256([]byte(keyword))
+	var books models.Books
+	err := svc.cache.load(ctx, "search:"+hex.EncodeToString(sum[:16]), &books, func() (interface{}, error) {
+		return svc.ESBookRepository.SearchBook(ctx, keyword)
+	})
+	return books, err
 }
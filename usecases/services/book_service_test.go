@@ -4,31 +4,38 @@ import (
 	"context"
 	"errors"
 	"reflect"
-	"sync"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 
 	"book-management-system/entities/models"
+	"book-management-system/entities/objects"
 	esMocks "book-management-system/mocks/repositories/elasticsearch"
 	mySqlMocks "book-management-system/mocks/repositories/mysql"
 	"book-management-system/repositories"
 	"book-management-system/repositories/elasticsearch"
 	"book-management-system/repositories/mysql"
+	"book-management-system/usecases/eventbus"
 )
 
 func TestNewBookService(t *testing.T) {
 	mySQLBookRepo := mysql.NewBookRepository(nil)
+	mySQLBookDuplicateRepo := mysql.NewBookDuplicateRepository(nil)
 	esBookRepo := elasticsearch.NewBookRepository(nil)
 	repo := &repositories.Repository{
-		MySQLBookRepository: mySQLBookRepo,
-		ESBookRepository:    esBookRepo,
+		MySQLBookRepository:          mySQLBookRepo,
+		MySQLBookDuplicateRepository: mySQLBookDuplicateRepo,
+		ESBookRepository:             esBookRepo,
 	}
 
-	got := NewBookService(repo)
+	bus := eventbus.New()
+
+	got := NewBookService(repo, bus)
 	expected := &bookService{
-		MySQLBookRepository: mySQLBookRepo,
-		ESBookRepository:    esBookRepo,
+		MySQLBookRepository:          mySQLBookRepo,
+		MySQLBookDuplicateRepository: mySQLBookDuplicateRepo,
+		ESBookRepository:             esBookRepo,
+		bus:                          bus,
 	}
 
 	if !reflect.DeepEqual(got, expected) {
@@ -46,14 +53,13 @@ func TestBookServiceCreateBook(t *testing.T) {
 		book *models.Book
 	}
 	type output struct {
-		err error
+		err       error
+		published []eventbus.Event
 	}
 	type mockConfig struct {
-		wg                *sync.WaitGroup
 		given             input
 		expected          output
 		mySQLBookRepoMock *mySqlMocks.MockBookRepository
-		esBookRepoMock    *esMocks.MockBookRepository
 	}
 
 	tests := []struct {
@@ -68,29 +74,23 @@ func TestBookServiceCreateBook(t *testing.T) {
 				ctx: context.TODO(),
 				book: &models.Book{
 					Name: "C++",
-					ISBN: "1234",
+					ISBN: "9780062315007",
 				},
 			},
 			expectedOutput: output{
 				err: nil,
+				published: []eventbus.Event{
+					eventbus.BookCreated{Book: models.Book{Name: "C++", ISBN: "9780062315007"}},
+				},
 			},
 			configureMock: func(conf mockConfig) {
 				conf.mySQLBookRepoMock.EXPECT().
 					CreateBook(gomock.Any(), conf.given.book).
 					Return(conf.expected.err)
-
-				conf.wg.Add(1)
-
-				conf.esBookRepoMock.EXPECT().
-					IndexBook(context.Background(), conf.given.book).
-					DoAndReturn(func(interface{}, *models.Book) error {
-						conf.wg.Done()
-						return nil
-					})
 			},
 		},
 		{
-			name: "failed create book",
+			name: "invalid book",
 			givenInput: input{
 				ctx: context.TODO(),
 				book: &models.Book{
@@ -98,6 +98,22 @@ func TestBookServiceCreateBook(t *testing.T) {
 					ISBN: "1234",
 				},
 			},
+			expectedOutput: output{
+				err: models.ErrInvalid,
+			},
+			configureMock: func(mockConfig) {
+				// do nothing
+			},
+		},
+		{
+			name: "failed create book",
+			givenInput: input{
+				ctx: context.TODO(),
+				book: &models.Book{
+					Name: "C++",
+					ISBN: "9780062315007",
+				},
+			},
 			expectedOutput: output{
 				err: errRepository,
 			},
@@ -115,28 +131,27 @@ func TestBookServiceCreateBook(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mySQLBookRepoMock := mySqlMocks.NewMockBookRepository(ctrl)
-			esBookRepoMock := esMocks.NewMockBookRepository(ctrl)
 
+			var published []eventbus.Event
 			bookService := &bookService{
 				MySQLBookRepository: mySQLBookRepoMock,
-				ESBookRepository:    esBookRepoMock,
+				bus:                 recordingBus(&published),
 			}
-			wg := sync.WaitGroup{}
 
 			tt.configureMock(mockConfig{
-				wg:                &wg,
 				given:             tt.givenInput,
 				expected:          tt.expectedOutput,
 				mySQLBookRepoMock: mySQLBookRepoMock,
-				esBookRepoMock:    esBookRepoMock,
 			})
 
 			err := bookService.CreateBook(tt.givenInput.ctx, tt.givenInput.book)
-			wg.Wait()
 			if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
 				t.Errorf("CreateBook() got error %+v, expected %+v",
 					err, expectedError)
 			}
+			if expected := tt.expectedOutput.published; !reflect.DeepEqual(published, expected) {
+				t.Errorf("CreateBook() published %+v, expected %+v", published, expected)
+			}
 		})
 	}
 }
@@ -170,7 +185,7 @@ func TestBookServiceGetBook(t *testing.T) {
 				books: models.Books{
 					{
 						Name: "C++",
-						ISBN: "1234",
+						ISBN: "9780062315007",
 					},
 				},
 				err: nil,
@@ -234,20 +249,225 @@ func TestBookServiceGetBook(t *testing.T) {
 	}
 }
 
+func TestBookServiceGetBookByID(t *testing.T) {
+	type input struct {
+		ctx context.Context
+		id  uint
+	}
+	type output struct {
+		book *models.Book
+		err  error
+	}
+	type mockConfig struct {
+		given                      input
+		expected                   output
+		mySQLBookRepoMock          *mySqlMocks.MockBookRepository
+		mySQLBookDuplicateRepoMock *mySqlMocks.MockBookDuplicateRepository
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success get book",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  1,
+			},
+			expectedOutput: output{
+				book: &models.Book{
+					Name: "C++",
+					ISBN: "9780062315007",
+				},
+				err: nil,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBookByID(gomock.Any(), conf.given.id).
+					Return(
+						conf.expected.book,
+						conf.expected.err,
+					)
+			},
+		},
+		{
+			name: "book not found",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  1,
+			},
+			expectedOutput: output{
+				err: models.ErrNotFound,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBookByID(gomock.Any(), conf.given.id).
+					Return(
+						conf.expected.book,
+						conf.expected.err,
+					)
+				conf.mySQLBookDuplicateRepoMock.EXPECT().
+					GetRedirect(gomock.Any(), conf.given.id).
+					Return(uint(0), models.ErrNotFound)
+			},
+		},
+		{
+			name: "book merged into another book",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  2,
+			},
+			expectedOutput: output{
+				err: &models.MovedError{ID: 1},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBookByID(gomock.Any(), conf.given.id).
+					Return(nil, models.ErrNotFound)
+				conf.mySQLBookDuplicateRepoMock.EXPECT().
+					GetRedirect(gomock.Any(), conf.given.id).
+					Return(uint(1), nil)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mySQLBookRepoMock := mySqlMocks.NewMockBookRepository(ctrl)
+			mySQLBookDuplicateRepoMock := mySqlMocks.NewMockBookDuplicateRepository(ctrl)
+
+			bookService := &bookService{
+				MySQLBookRepository:          mySQLBookRepoMock,
+				MySQLBookDuplicateRepository: mySQLBookDuplicateRepoMock,
+			}
+
+			tt.configureMock(mockConfig{
+				given:                      tt.givenInput,
+				expected:                   tt.expectedOutput,
+				mySQLBookRepoMock:          mySQLBookRepoMock,
+				mySQLBookDuplicateRepoMock: mySQLBookDuplicateRepoMock,
+			})
+
+			book, err := bookService.GetBook(tt.givenInput.ctx, tt.givenInput.id)
+			if expectedError := tt.expectedOutput.err; !reflect.DeepEqual(err, expectedError) {
+				t.Errorf("GetBook() got error %+v, expected %+v",
+					err, expectedError)
+			}
+			if expectedBook := tt.expectedOutput.book; !reflect.DeepEqual(book, expectedBook) {
+				t.Errorf("GetBook() got book %+v, expected %+v",
+					book, expectedBook)
+			}
+		})
+	}
+}
+
+func TestBookServiceGetBookByISBN(t *testing.T) {
+	type input struct {
+		ctx  context.Context
+		isbn string
+	}
+	type output struct {
+		book *models.Book
+		err  error
+	}
+	type mockConfig struct {
+		given             input
+		expected          output
+		mySQLBookRepoMock *mySqlMocks.MockBookRepository
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success get book by isbn-10",
+			givenInput: input{
+				ctx:  context.TODO(),
+				isbn: "0-06-231500-5",
+			},
+			expectedOutput: output{
+				book: &models.Book{
+					Name: "C++",
+					ISBN: "9780062315007",
+				},
+				err: nil,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLBookRepoMock.EXPECT().
+					GetBookByISBN(gomock.Any(), objects.ISBN("9780062315007")).
+					Return(
+						conf.expected.book,
+						conf.expected.err,
+					)
+			},
+		},
+		{
+			name: "invalid isbn",
+			givenInput: input{
+				ctx:  context.TODO(),
+				isbn: "1234",
+			},
+			expectedOutput: output{
+				err: models.ErrInvalid,
+			},
+			configureMock: func(mockConfig) {
+				// do nothing
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mySQLBookRepoMock := mySqlMocks.NewMockBookRepository(ctrl)
+
+			bookService := &bookService{
+				MySQLBookRepository: mySQLBookRepoMock,
+			}
+
+			tt.configureMock(mockConfig{
+				given:             tt.givenInput,
+				expected:          tt.expectedOutput,
+				mySQLBookRepoMock: mySQLBookRepoMock,
+			})
+
+			book, err := bookService.GetBookByISBN(tt.givenInput.ctx, tt.givenInput.isbn)
+			if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+				t.Errorf("GetBookByISBN() got error %+v, expected %+v",
+					err, expectedError)
+			}
+			if expectedBook := tt.expectedOutput.book; !reflect.DeepEqual(book, expectedBook) {
+				t.Errorf("GetBookByISBN() got book %+v, expected %+v",
+					book, expectedBook)
+			}
+		})
+	}
+}
+
 func TestBookServiceUpdateBook(t *testing.T) {
 	type input struct {
 		ctx  context.Context
 		book *models.Book
 	}
 	type output struct {
-		err error
+		err       error
+		published []eventbus.Event
 	}
 	type mockConfig struct {
-		wg                *sync.WaitGroup
 		given             input
 		expected          output
 		mySQLBookRepoMock *mySqlMocks.MockBookRepository
-		esBookRepoMock    *esMocks.MockBookRepository
 	}
 
 	tests := []struct {
@@ -262,25 +482,19 @@ func TestBookServiceUpdateBook(t *testing.T) {
 				ctx: context.TODO(),
 				book: &models.Book{
 					Name: "C++",
-					ISBN: "1234",
+					ISBN: "9780062315007",
 				},
 			},
 			expectedOutput: output{
 				err: nil,
+				published: []eventbus.Event{
+					eventbus.BookUpdated{Book: models.Book{Name: "C++", ISBN: "9780062315007"}},
+				},
 			},
 			configureMock: func(conf mockConfig) {
 				conf.mySQLBookRepoMock.EXPECT().
 					UpdateBook(gomock.Any(), conf.given.book).
 					Return(conf.expected.err)
-
-				conf.wg.Add(1)
-
-				conf.esBookRepoMock.EXPECT().
-					IndexBook(context.Background(), conf.given.book).
-					DoAndReturn(func(interface{}, *models.Book) error {
-						conf.wg.Done()
-						return nil
-					})
 			},
 		},
 		{
@@ -289,7 +503,7 @@ func TestBookServiceUpdateBook(t *testing.T) {
 				ctx: context.TODO(),
 				book: &models.Book{
 					Name: "C++",
-					ISBN: "1234",
+					ISBN: "9780062315007",
 				},
 			},
 			expectedOutput: output{
@@ -301,6 +515,19 @@ func TestBookServiceUpdateBook(t *testing.T) {
 					Return(conf.expected.err)
 			},
 		},
+		{
+			name: "invalid book",
+			givenInput: input{
+				ctx:  context.TODO(),
+				book: &models.Book{},
+			},
+			expectedOutput: output{
+				err: models.ErrInvalid,
+			},
+			configureMock: func(mockConfig) {
+				// do nothing
+			},
+		},
 	}
 
 	ctrl := gomock.NewController(t)
@@ -309,28 +536,27 @@ func TestBookServiceUpdateBook(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mySQLBookRepoMock := mySqlMocks.NewMockBookRepository(ctrl)
-			esBookRepoMock := esMocks.NewMockBookRepository(ctrl)
 
+			var published []eventbus.Event
 			bookService := &bookService{
 				MySQLBookRepository: mySQLBookRepoMock,
-				ESBookRepository:    esBookRepoMock,
+				bus:                 recordingBus(&published),
 			}
-			wg := sync.WaitGroup{}
 
 			tt.configureMock(mockConfig{
-				wg:                &wg,
 				given:             tt.givenInput,
 				expected:          tt.expectedOutput,
 				mySQLBookRepoMock: mySQLBookRepoMock,
-				esBookRepoMock:    esBookRepoMock,
 			})
 
 			err := bookService.UpdateBook(tt.givenInput.ctx, tt.givenInput.book)
-			wg.Wait()
 			if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
 				t.Errorf("UpdateBook() got error %+v, expected %+v",
 					err, expectedError)
 			}
+			if expected := tt.expectedOutput.published; !reflect.DeepEqual(published, expected) {
+				t.Errorf("UpdateBook() published %+v, expected %+v", published, expected)
+			}
 		})
 	}
 }
@@ -366,7 +592,7 @@ func TestBookServiceSearchBook(t *testing.T) {
 				books: models.Books{
 					{
 						Name: "C++",
-						ISBN: "1234",
+						ISBN: "9780062315007",
 					},
 				},
 				err: nil,
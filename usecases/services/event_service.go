@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"book-management-system/configs"
+	"book-management-system/entities/models"
+	"book-management-system/events"
+	"book-management-system/repositories"
+)
+
+// EventService handle subscriptions to the change feed
+type EventService interface {
+	Subscribe(ctx context.Context, types []string, lastEventID uint64) (*events.Subscription, error)
+}
+
+type eventService struct {
+	feed   *events.Feed
+	buffer int
+}
+
+// NewEventService returns EventService
+func NewEventService(repo *repositories.Repository) EventService {
+	return &eventService{
+		feed:   repo.Events,
+		buffer: configs.GetConfig().Events.Buffer,
+	}
+}
+
+// Subscribe returns subscription to events of types, all types when there are none, resuming after lastEventID
+func (svc *eventService) Subscribe(_ context.Context, types []string, lastEventID uint64) (*events.Subscription, error) {
+	for _, t := range types {
+		if !knownEventType(t) {
+			return nil, fmt.Errorf("%w: unknown event type %q", models.ErrInvalid, t)
+		}
+	}
+	return svc.feed.Subscribe(types, lastEventID, svc.buffer), nil
+}
+
+func knownEventType(eventType string) bool {
+	for _, t := range events.Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"book-management-system/entities/models"
+	"book-management-system/events"
+	"book-management-system/repositories"
+)
+
+func TestNewEventService(t *testing.T) {
+	feed := events.NewFeed(1)
+	repo := &repositories.Repository{
+		Events: feed,
+	}
+
+	got := NewEventService(repo)
+	expected := &eventService{
+		feed:   feed,
+		buffer: 256,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewEventService returns %+v\n expected %+v",
+			got, expected)
+	}
+}
+
+func TestEventServiceSubscribe(t *testing.T) {
+	tests := []struct {
+		name  string
+		types []string
+		err   error
+	}{
+		{name: "all types"},
+		{name: "known types", types: []string{events.TypeBookCreated, events.TypeMemberUpdated}},
+		{name: "unknown type", types: []string{events.TypeBookCreated, "loan.created"}, err: models.ErrInvalid},
+	}
+
+	eventService := &eventService{feed: events.NewFeed(1), buffer: 1}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, err := eventService.Subscribe(context.TODO(), tt.types, 0)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Subscribe() got error %v\n expected %v", err, tt.err)
+			}
+			if (sub != nil) != (tt.err == nil) {
+				t.Errorf("Subscribe() got subscription %v with error %v", sub, err)
+			}
+			if sub != nil {
+				sub.Close()
+			}
+		})
+	}
+}
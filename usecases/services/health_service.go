@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+
+	"book-management-system/health"
+	"book-management-system/repositories"
+)
+
+// HealthService checks whether the service is alive and ready to serve requests
+type HealthService interface {
+	Liveness(context.Context) health.Report
+	Readiness(context.Context) health.Report
+}
+
+type healthService struct {
+	HealthChecks map[string]health.Check
+}
+
+// NewHealthService returns HealthService
+func NewHealthService(repo *repositories.Repository) HealthService {
+	return &healthService{
+		HealthChecks: repo.HealthChecks,
+	}
+}
+
+// Liveness checks background workers, backends being down does not make the service dead
+func (svc *healthService) Liveness(ctx context.Context) health.Report {
+	return health.Run(ctx, map[string]health.Check{
+		"workers": health.Workers,
+	})
+}
+
+// Readiness checks database and search backends
+func (svc *healthService) Readiness(ctx context.Context) health.Report {
+	return health.Run(ctx, svc.HealthChecks)
+}
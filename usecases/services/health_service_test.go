@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"book-management-system/health"
+	"book-management-system/repositories"
+)
+
+func TestNewHealthService(t *testing.T) {
+	repo := &repositories.Repository{
+		HealthChecks: map[string]health.Check{"database": func(context.Context) error { return nil }},
+	}
+
+	got := NewHealthService(repo).(*healthService)
+	if len(got.HealthChecks) != 1 || got.HealthChecks["database"] == nil {
+		t.Errorf("NewHealthService returns %+v\n expected database check", got)
+	}
+}
+
+func TestHealthServiceReadiness(t *testing.T) {
+	tests := []struct {
+		name     string
+		checks   map[string]health.Check
+		expected string
+	}{
+		{
+			name:     "memory backends",
+			expected: health.StatusUp,
+		},
+		{
+			name: "backends up",
+			checks: map[string]health.Check{
+				"database": func(context.Context) error { return nil },
+				"search":   func(context.Context) error { return nil },
+			},
+			expected: health.StatusUp,
+		},
+		{
+			name: "search down",
+			checks: map[string]health.Check{
+				"database": func(context.Context) error { return nil },
+				"search":   func(context.Context) error { return errRepository },
+			},
+			expected: health.StatusDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewHealthService(&repositories.Repository{HealthChecks: tt.checks})
+
+			got := svc.Readiness(context.TODO())
+			if got.Status != tt.expected || len(got.Checks) != len(tt.checks) {
+				t.Errorf("Readiness() got %+v\n expected status %q of %d checks", got, tt.expected, len(tt.checks))
+			}
+		})
+	}
+}
+
+func TestHealthServiceLiveness(t *testing.T) {
+	svc := NewHealthService(&repositories.Repository{})
+
+	got := svc.Liveness(context.TODO())
+	if !got.Up() || got.Checks["workers"].Status != health.StatusUp {
+		t.Errorf("Liveness() got %+v\n expected workers up", got)
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"book-management-system/entities/models"
+	"book-management-system/repositories"
+	"book-management-system/repositories/mysql"
+)
+
+const (
+	// idempotencyLockTimeout is how long a key stays in progress without its request extending the lock,
+	// before it is considered abandoned by a crashed request
+	idempotencyLockTimeout = 30 * time.Second
+	// IdempotencyLockRefresh is how often a running request extends the lock of its key
+	IdempotencyLockRefresh = idempotencyLockTimeout / 3
+
+	idempotencyTokenBytes = 16
+)
+
+// IdempotencyService handle idempotency keys of retried requests
+type IdempotencyService interface {
+	BeginRequest(ctx context.Context, key, requestHash string, ttl time.Duration) (*models.IdempotencyKey, error)
+	ExtendRequest(context.Context, *models.IdempotencyKey) error
+	CompleteRequest(context.Context, *models.IdempotencyKey) error
+	ReleaseRequest(context.Context, *models.IdempotencyKey) error
+	PurgeExpiredKeys(context.Context) error
+}
+
+type idempotencyService struct {
+	MySQLIdempotencyRepository mysql.IdempotencyRepository
+}
+
+// NewIdempotencyService returns IdempotencyService
+func NewIdempotencyService(repo *repositories.Repository) IdempotencyService {
+	return &idempotencyService{
+		MySQLIdempotencyRepository: repo.MySQLIdempotencyRepository,
+	}
+}
+
+// BeginRequest reserves key for request identified by requestHash.
+// It returns not completed key when caller should process the request,
+// or completed key holding the stored response when request is a replay.
+// The caller keeps not completed key locked with ExtendRequest while processing the request.
+func (svc *idempotencyService) BeginRequest(
+	ctx context.Context,
+	key, requestHash string,
+	ttl time.Duration,
+) (*models.IdempotencyKey, error) {
+	ctx, cancel := setContextTimeout(ctx)
+	defer cancel()
+
+	// second attempt happens only after stale key has been removed
+	for attempt := 0; attempt < 2; attempt++ {
+		token, err := generateIdempotencyToken()
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		idempotencyKey := &models.IdempotencyKey{
+			Key:         key,
+			Token:       token,
+			RequestHash: requestHash,
+			CreatedAt:   now,
+			LockedUntil: now.Add(idempotencyLockTimeout),
+			ExpiresAt:   now.Add(ttl),
+		}
+
+		created, err := svc.MySQLIdempotencyRepository.CreateKey(ctx, idempotencyKey)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			return idempotencyKey, nil
+		}
+
+		existing, err := svc.MySQLIdempotencyRepository.GetKey(ctx, key)
+		if errors.Is(err, models.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if existing.IsExpired(now) || existing.IsAbandoned(now) {
+			deleted, err := svc.MySQLIdempotencyRepository.DeleteKey(ctx, existing)
+			if err != nil {
+				return nil, err
+			}
+			if !deleted {
+				// concurrent request took over the stale key first and processes it now
+				return nil, models.ErrIdempotencyKeyInProgress
+			}
+			continue
+		}
+
+		if existing.RequestHash != requestHash {
+			return nil, models.ErrIdempotencyKeyMismatch
+		}
+		if !existing.Completed {
+			return nil, models.ErrIdempotencyKeyInProgress
+		}
+		return existing, nil
+	}
+
+	return nil, models.ErrIdempotencyKeyInProgress
+}
+
+// ExtendRequest keeps key of running request locked for another lock timeout.
+// It returns models.ErrIdempotencyKeyLost when the key was taken over by another request meanwhile.
+func (svc *idempotencyService) ExtendRequest(ctx context.Context, idempotencyKey *models.IdempotencyKey) error {
+	ctx, cancel := setContextTimeout(ctx)
+	defer cancel()
+
+	idempotencyKey.LockedUntil = time.Now().Add(idempotencyLockTimeout)
+	extended, err := svc.MySQLIdempotencyRepository.ExtendKey(ctx, idempotencyKey)
+	if err != nil {
+		return err
+	}
+	if !extended {
+		return models.ErrIdempotencyKeyLost
+	}
+	return nil
+}
+
+// CompleteRequest stores response of request so later replays return it
+func (svc *idempotencyService) CompleteRequest(ctx context.Context, idempotencyKey *models.IdempotencyKey) error {
+	ctx, cancel := setContextTimeout(ctx)
+	defer cancel()
+
+	idempotencyKey.Completed = true
+	return svc.MySQLIdempotencyRepository.CompleteKey(ctx, idempotencyKey)
+}
+
+// ReleaseRequest removes key so failed request can be retried, unless another request took it over meanwhile
+func (svc *idempotencyService) ReleaseRequest(ctx context.Context, idempotencyKey *models.IdempotencyKey) error {
+	ctx, cancel := setContextTimeout(ctx)
+	defer cancel()
+
+	_, err := svc.MySQLIdempotencyRepository.DeleteKey(ctx, idempotencyKey)
+	return err
+}
+
+func (svc *idempotencyService) PurgeExpiredKeys(ctx context.Context) error {
+	ctx, cancel := setContextTimeout(ctx)
+	defer cancel()
+
+	return svc.MySQLIdempotencyRepository.DeleteExpiredKeys(ctx, time.Now())
+}
+
+// generateIdempotencyToken returns random token telling apart requests reserving the same key
+func generateIdempotencyToken() (string, error) {
+	token := make([]byte, idempotencyTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate idempotency token: %w", err)
+	}
+	return hex.EncodeToString(token), nil
+}
@@ -0,0 +1,361 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"book-management-system/entities/models"
+	mySqlMocks "book-management-system/mocks/repositories/mysql"
+	"book-management-system/repositories"
+	"book-management-system/repositories/memory"
+	"book-management-system/repositories/mysql"
+)
+
+func TestNewIdempotencyService(t *testing.T) {
+	mySQLIdempotencyRepo := mysql.NewIdempotencyRepository(nil)
+	repo := &repositories.Repository{
+		MySQLIdempotencyRepository: mySQLIdempotencyRepo,
+	}
+
+	got := NewIdempotencyService(repo)
+	expected := &idempotencyService{
+		MySQLIdempotencyRepository: mySQLIdempotencyRepo,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewIdempotencyService returns %+v\n expected %+v",
+			got, expected)
+	}
+}
+
+func TestIdempotencyServiceBeginRequest(t *testing.T) {
+	type input struct {
+		ctx         context.Context
+		key         string
+		requestHash string
+	}
+	type output struct {
+		completed bool
+		err       error
+	}
+	type mockConfig struct {
+		given                    input
+		mySQLIdempotencyRepoMock *mySqlMocks.MockIdempotencyRepository
+	}
+
+	given := input{
+		ctx:         context.TODO(),
+		key:         "key",
+		requestHash: "hash",
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name:       "new key",
+			givenInput: given,
+			expectedOutput: output{
+				completed: false,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLIdempotencyRepoMock.EXPECT().
+					CreateKey(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+			},
+		},
+		{
+			name:       "replay completed request",
+			givenInput: given,
+			expectedOutput: output{
+				completed: true,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLIdempotencyRepoMock.EXPECT().
+					CreateKey(gomock.Any(), gomock.Any()).
+					Return(false, nil)
+				conf.mySQLIdempotencyRepoMock.EXPECT().
+					GetKey(gomock.Any(), conf.given.key).
+					Return(&models.IdempotencyKey{
+						Key:         conf.given.key,
+						RequestHash: conf.given.requestHash,
+						Completed:   true,
+						CreatedAt:   time.Now(),
+						ExpiresAt:   time.Now().Add(time.Hour),
+					}, nil)
+			},
+		},
+		{
+			name:       "key reused with different request",
+			givenInput: given,
+			expectedOutput: output{
+				err: models.ErrIdempotencyKeyMismatch,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLIdempotencyRepoMock.EXPECT().
+					CreateKey(gomock.Any(), gomock.Any()).
+					Return(false, nil)
+				conf.mySQLIdempotencyRepoMock.EXPECT().
+					GetKey(gomock.Any(), conf.given.key).
+					Return(&models.IdempotencyKey{
+						Key:         conf.given.key,
+						RequestHash: "other hash",
+						Completed:   true,
+						CreatedAt:   time.Now(),
+						ExpiresAt:   time.Now().Add(time.Hour),
+					}, nil)
+			},
+		},
+		{
+			name:       "concurrent request in progress",
+			givenInput: given,
+			expectedOutput: output{
+				err: models.ErrIdempotencyKeyInProgress,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLIdempotencyRepoMock.EXPECT().
+					CreateKey(gomock.Any(), gomock.Any()).
+					Return(false, nil)
+				conf.mySQLIdempotencyRepoMock.EXPECT().
+					GetKey(gomock.Any(), conf.given.key).
+					Return(&models.IdempotencyKey{
+						Key:         conf.given.key,
+						RequestHash: conf.given.requestHash,
+						CreatedAt:   time.Now().Add(-time.Hour),
+						LockedUntil: time.Now().Add(time.Minute),
+						ExpiresAt:   time.Now().Add(time.Hour),
+					}, nil)
+			},
+		},
+		{
+			name:       "expired key replaced",
+			givenInput: given,
+			expectedOutput: output{
+				completed: false,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLIdempotencyRepoMock.EXPECT().
+					CreateKey(gomock.Any(), gomock.Any()).
+					Return(false, nil)
+				conf.mySQLIdempotencyRepoMock.EXPECT().
+					GetKey(gomock.Any(), conf.given.key).
+					Return(&models.IdempotencyKey{
+						Key:         conf.given.key,
+						RequestHash: "other hash",
+						Completed:   true,
+						CreatedAt:   time.Now().Add(-2 * time.Hour),
+						ExpiresAt:   time.Now().Add(-time.Hour),
+					}, nil)
+				conf.mySQLIdempotencyRepoMock.EXPECT().
+					DeleteKey(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+				conf.mySQLIdempotencyRepoMock.EXPECT().
+					CreateKey(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+			},
+		},
+		{
+			name:       "abandoned key taken over by concurrent request",
+			givenInput: given,
+			expectedOutput: output{
+				err: models.ErrIdempotencyKeyInProgress,
+			},
+			configureMock: func(conf mockConfig) {
+				abandoned := &models.IdempotencyKey{
+					Key:         conf.given.key,
+					Token:       "token",
+					RequestHash: conf.given.requestHash,
+					CreatedAt:   time.Now().Add(-time.Hour),
+					LockedUntil: time.Now().Add(-time.Minute),
+					ExpiresAt:   time.Now().Add(time.Hour),
+				}
+				conf.mySQLIdempotencyRepoMock.EXPECT().
+					CreateKey(gomock.Any(), gomock.Any()).
+					Return(false, nil)
+				conf.mySQLIdempotencyRepoMock.EXPECT().
+					GetKey(gomock.Any(), conf.given.key).
+					Return(abandoned, nil)
+				conf.mySQLIdempotencyRepoMock.EXPECT().
+					DeleteKey(gomock.Any(), abandoned).
+					Return(false, nil)
+			},
+		},
+		{
+			name:       "failed create key",
+			givenInput: given,
+			expectedOutput: output{
+				err: errRepository,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLIdempotencyRepoMock.EXPECT().
+					CreateKey(gomock.Any(), gomock.Any()).
+					Return(false, errRepository)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mySQLIdempotencyRepoMock := mySqlMocks.NewMockIdempotencyRepository(ctrl)
+
+			idempotencyService := &idempotencyService{
+				MySQLIdempotencyRepository: mySQLIdempotencyRepoMock,
+			}
+
+			tt.configureMock(mockConfig{
+				given:                    tt.givenInput,
+				mySQLIdempotencyRepoMock: mySQLIdempotencyRepoMock,
+			})
+
+			key, err := idempotencyService.BeginRequest(
+				tt.givenInput.ctx,
+				tt.givenInput.key,
+				tt.givenInput.requestHash,
+				time.Hour,
+			)
+			if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+				t.Fatalf("BeginRequest() got error %+v, expected %+v",
+					err, expectedError)
+			}
+			if err == nil && key.Completed != tt.expectedOutput.completed {
+				t.Errorf("BeginRequest() got completed %t, expected %t",
+					key.Completed, tt.expectedOutput.completed)
+			}
+		})
+	}
+}
+
+func TestIdempotencyServiceCompleteRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mySQLIdempotencyRepoMock := mySqlMocks.NewMockIdempotencyRepository(ctrl)
+	idempotencyService := &idempotencyService{
+		MySQLIdempotencyRepository: mySQLIdempotencyRepoMock,
+	}
+
+	key := &models.IdempotencyKey{
+		Key:        "key",
+		StatusCode: 201,
+	}
+	mySQLIdempotencyRepoMock.EXPECT().
+		CompleteKey(gomock.Any(), key).
+		Return(nil)
+
+	if err := idempotencyService.CompleteRequest(context.TODO(), key); err != nil {
+		t.Errorf("CompleteRequest() got error %+v", err)
+	}
+	if !key.Completed {
+		t.Errorf("CompleteRequest() does not mark key as completed")
+	}
+}
+
+// TestIdempotencyServiceBeginRequestTakeover races requests taking over the same abandoned key,
+// all of them read it before any removes it
+func TestIdempotencyServiceBeginRequestTakeover(t *testing.T) {
+	const requests = 2
+	db := memory.NewDB()
+	repo := &readBarrierRepository{
+		IdempotencyRepository: memory.NewIdempotencyRepository(db),
+		readers:               requests,
+		read:                  make(chan struct{}),
+	}
+	now := time.Now()
+	if _, err := repo.CreateKey(context.TODO(), &models.IdempotencyKey{
+		Key:         "key",
+		Token:       "crashed",
+		RequestHash: "hash",
+		CreatedAt:   now.Add(-time.Hour),
+		LockedUntil: now.Add(-time.Minute),
+		ExpiresAt:   now.Add(time.Hour),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	idempotencyService := &idempotencyService{MySQLIdempotencyRepository: repo}
+
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		go func() {
+			_, err := idempotencyService.BeginRequest(context.TODO(), "key", "hash", time.Hour)
+			errs <- err
+		}()
+	}
+
+	began := 0
+	for i := 0; i < requests; i++ {
+		err := <-errs
+		switch {
+		case err == nil:
+			began++
+		case !errors.Is(err, models.ErrIdempotencyKeyInProgress):
+			t.Errorf("BeginRequest() got error %v\n expected %v", err, models.ErrIdempotencyKeyInProgress)
+		}
+	}
+	if began != 1 {
+		t.Errorf("BeginRequest() of %d requests taking over abandoned key began %d\n expected 1", requests, began)
+	}
+}
+
+// readBarrierRepository holds first readers of a key until all of them read it
+type readBarrierRepository struct {
+	mysql.IdempotencyRepository
+	mu      sync.Mutex
+	readers int
+	read    chan struct{}
+}
+
+func (repo *readBarrierRepository) GetKey(ctx context.Context, key string) (*models.IdempotencyKey, error) {
+	idempotencyKey, err := repo.IdempotencyRepository.GetKey(ctx, key)
+
+	repo.mu.Lock()
+	if repo.readers == 0 {
+		repo.mu.Unlock()
+		return idempotencyKey, err
+	}
+	repo.readers--
+	if repo.readers == 0 {
+		close(repo.read)
+	}
+	repo.mu.Unlock()
+
+	<-repo.read
+	return idempotencyKey, err
+}
+
+func TestIdempotencyServiceExtendRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mySQLIdempotencyRepoMock := mySqlMocks.NewMockIdempotencyRepository(ctrl)
+	idempotencyService := &idempotencyService{
+		MySQLIdempotencyRepository: mySQLIdempotencyRepoMock,
+	}
+
+	key := &models.IdempotencyKey{Key: "key", Token: "token"}
+	gomock.InOrder(
+		mySQLIdempotencyRepoMock.EXPECT().ExtendKey(gomock.Any(), key).Return(true, nil),
+		mySQLIdempotencyRepoMock.EXPECT().ExtendKey(gomock.Any(), key).Return(false, nil),
+	)
+
+	if err := idempotencyService.ExtendRequest(context.TODO(), key); err != nil {
+		t.Errorf("ExtendRequest() got error %+v", err)
+	}
+	if !key.LockedUntil.After(time.Now()) {
+		t.Errorf("ExtendRequest() got locked until %v\n expected future time", key.LockedUntil)
+	}
+	if err := idempotencyService.ExtendRequest(context.TODO(), key); !errors.Is(err, models.ErrIdempotencyKeyLost) {
+		t.Errorf("ExtendRequest() of key taken over got error %+v\n expected %+v", err, models.ErrIdempotencyKeyLost)
+	}
+}
@@ -4,25 +4,30 @@ import (
 	"context"
 
 	"book-management-system/entities/models"
+	"book-management-system/metrics"
 	"book-management-system/repositories"
 	"book-management-system/repositories/mysql"
+	"book-management-system/usecases/eventbus"
 )
 
 // MemberService handle business logic related to book
 type MemberService interface {
 	GetMembers(context.Context) (models.Members, error)
+	GetMember(context.Context, uint) (*models.Member, error)
 	CreateMember(context.Context, *models.Member) error
 	UpdateMember(context.Context, *models.Member) error
 }
 
 type memberService struct {
 	MySQLMemberRepository mysql.MemberRepository
+	bus                   *eventbus.Bus
 }
 
-// NewMemberService returns MemberService
-func NewMemberService(repo *repositories.Repository) MemberService {
+// NewMemberService returns MemberService publishing changes of members to bus
+func NewMemberService(repo *repositories.Repository, bus *eventbus.Bus) MemberService {
 	return &memberService{
 		MySQLMemberRepository: repo.MySQLMemberRepository,
+		bus:                   bus,
 	}
 }
 
@@ -33,6 +38,13 @@ func (svc *memberService) GetMembers(ctx context.Context) (models.Members, error
 	return svc.MySQLMemberRepository.GetAll(ctx)
 }
 
+func (svc *memberService) GetMember(ctx context.Context, id uint) (*models.Member, error) {
+	ctx, cancel := setContextTimeout(ctx)
+	defer cancel()
+
+	return svc.MySQLMemberRepository.GetMemberByID(ctx, id)
+}
+
 func (svc *memberService) CreateMember(ctx context.Context, member *models.Member) error {
 	ctx, cancel := setContextTimeout(ctx)
 	defer cancel()
@@ -41,11 +53,17 @@ func (svc *memberService) CreateMember(ctx context.Context, member *models.Membe
 	if err != nil {
 		return err
 	}
+	svc.bus.Publish(ctx, eventbus.MemberCreated{Member: *member})
+	metrics.MembersCreated.Inc()
 
 	return nil
 }
 
 func (svc *memberService) UpdateMember(ctx context.Context, member *models.Member) error {
+	if err := member.Validate(); err != nil {
+		return err
+	}
+
 	ctx, cancel := setContextTimeout(ctx)
 	defer cancel()
 
@@ -53,6 +71,7 @@ func (svc *memberService) UpdateMember(ctx context.Context, member *models.Membe
 	if err != nil {
 		return err
 	}
+	svc.bus.Publish(ctx, eventbus.MemberUpdated{Member: *member})
 
 	return nil
 }
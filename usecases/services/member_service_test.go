@@ -12,6 +12,7 @@ import (
 	mySqlMocks "book-management-system/mocks/repositories/mysql"
 	"book-management-system/repositories"
 	"book-management-system/repositories/mysql"
+	"book-management-system/usecases/eventbus"
 )
 
 func TestNewMemberService(t *testing.T) {
@@ -20,9 +21,12 @@ func TestNewMemberService(t *testing.T) {
 		MySQLMemberRepository: mySQLMemberRepo,
 	}
 
-	got := NewMemberService(repo)
+	bus := eventbus.New()
+
+	got := NewMemberService(repo, bus)
 	expected := &memberService{
 		MySQLMemberRepository: mySQLMemberRepo,
+		bus:                   bus,
 	}
 
 	if !reflect.DeepEqual(got, expected) {
@@ -40,7 +44,8 @@ func TestMemberServiceCreateMember(t *testing.T) {
 		member *models.Member
 	}
 	type output struct {
-		err error
+		err       error
+		published []eventbus.Event
 	}
 	type mockConfig struct {
 		given               input
@@ -59,11 +64,14 @@ func TestMemberServiceCreateMember(t *testing.T) {
 			givenInput: input{
 				ctx: context.TODO(),
 				member: &models.Member{
-					Name: "",
+					Name: "John Lennon",
 				},
 			},
 			expectedOutput: output{
 				err: nil,
+				published: []eventbus.Event{
+					eventbus.MemberCreated{Member: models.Member{Name: "John Lennon"}},
+				},
 			},
 			configureMock: func(conf mockConfig) {
 				conf.mySQLMemberRepoMock.EXPECT().
@@ -76,7 +84,7 @@ func TestMemberServiceCreateMember(t *testing.T) {
 			givenInput: input{
 				ctx: context.TODO(),
 				member: &models.Member{
-					Name: "",
+					Name: "John Lennon",
 				},
 			},
 			expectedOutput: output{
@@ -96,9 +104,10 @@ func TestMemberServiceCreateMember(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mySQLMemberRepoMock := mySqlMocks.NewMockMemberRepository(ctrl)
-
+			var published []eventbus.Event
 			memberService := &memberService{
 				MySQLMemberRepository: mySQLMemberRepoMock,
+				bus:                   recordingBus(&published),
 			}
 
 			tt.configureMock(mockConfig{
@@ -112,6 +121,9 @@ func TestMemberServiceCreateMember(t *testing.T) {
 				t.Errorf("CreateMember() got error %+v, expected %+v",
 					err, expectedError)
 			}
+			if expected := tt.expectedOutput.published; !reflect.DeepEqual(published, expected) {
+				t.Errorf("CreateMember() published %v, expected %v", published, expected)
+			}
 		})
 	}
 }
@@ -144,7 +156,7 @@ func TestMemberServiceGetMembers(t *testing.T) {
 			expectedOutput: output{
 				members: models.Members{
 					{
-						Name: "",
+						Name: "John Lennon",
 					},
 				},
 				err: nil,
@@ -208,6 +220,98 @@ func TestMemberServiceGetMembers(t *testing.T) {
 	}
 }
 
+func TestMemberServiceGetMemberByID(t *testing.T) {
+	type input struct {
+		ctx context.Context
+		id  uint
+	}
+	type output struct {
+		member *models.Member
+		err    error
+	}
+	type mockConfig struct {
+		given               input
+		expected            output
+		mySQLMemberRepoMock *mySqlMocks.MockMemberRepository
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success get member",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  1,
+			},
+			expectedOutput: output{
+				member: &models.Member{
+					Name: "John Lennon",
+				},
+				err: nil,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLMemberRepoMock.EXPECT().
+					GetMemberByID(gomock.Any(), conf.given.id).
+					Return(
+						conf.expected.member,
+						conf.expected.err,
+					)
+			},
+		},
+		{
+			name: "member not found",
+			givenInput: input{
+				ctx: context.TODO(),
+				id:  1,
+			},
+			expectedOutput: output{
+				err: models.ErrNotFound,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLMemberRepoMock.EXPECT().
+					GetMemberByID(gomock.Any(), conf.given.id).
+					Return(
+						conf.expected.member,
+						conf.expected.err,
+					)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mySQLMemberRepoMock := mySqlMocks.NewMockMemberRepository(ctrl)
+
+			memberService := &memberService{
+				MySQLMemberRepository: mySQLMemberRepoMock,
+			}
+
+			tt.configureMock(mockConfig{
+				given:               tt.givenInput,
+				expected:            tt.expectedOutput,
+				mySQLMemberRepoMock: mySQLMemberRepoMock,
+			})
+
+			member, err := memberService.GetMember(tt.givenInput.ctx, tt.givenInput.id)
+			if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+				t.Errorf("GetMember() got error %+v, expected %+v",
+					err, expectedError)
+			}
+			if expectedMember := tt.expectedOutput.member; !reflect.DeepEqual(member, expectedMember) {
+				t.Errorf("GetMember() got member %+v, expected %+v",
+					member, expectedMember)
+			}
+		})
+	}
+}
+
 func TestMemberServiceUpdateMember(t *testing.T) {
 	type input struct {
 		ctx    context.Context
@@ -233,7 +337,7 @@ func TestMemberServiceUpdateMember(t *testing.T) {
 			givenInput: input{
 				ctx: context.TODO(),
 				member: &models.Member{
-					Name: "",
+					Name: "John Lennon",
 				},
 			},
 			expectedOutput: output{
@@ -250,7 +354,7 @@ func TestMemberServiceUpdateMember(t *testing.T) {
 			givenInput: input{
 				ctx: context.TODO(),
 				member: &models.Member{
-					Name: "",
+					Name: "John Lennon",
 				},
 			},
 			expectedOutput: output{
@@ -262,6 +366,19 @@ func TestMemberServiceUpdateMember(t *testing.T) {
 					Return(conf.expected.err)
 			},
 		},
+		{
+			name: "invalid member",
+			givenInput: input{
+				ctx:    context.TODO(),
+				member: &models.Member{},
+			},
+			expectedOutput: output{
+				err: models.ErrInvalid,
+			},
+			configureMock: func(mockConfig) {
+				// do nothing
+			},
+		},
 	}
 
 	ctrl := gomock.NewController(t)
@@ -3,18 +3,27 @@ package services
 
 import (
 	"book-management-system/repositories"
+	"book-management-system/usecases/eventbus"
 )
 
 // Services contains services
 type Services struct {
-	BookService   BookService
-	MemberService MemberService
+	BookService        BookService
+	MemberService      MemberService
+	IdempotencyService IdempotencyService
+	HealthService      HealthService
+	EventService       EventService
+	WebhookService     WebhookService
 }
 
-// Init return Services
-func Init(repo *repositories.Repository) *Services {
+// Init return Services publishing domain events to bus
+func Init(repo *repositories.Repository, bus *eventbus.Bus) *Services {
 	return &Services{
-		BookService:   NewBookService(repo),
-		MemberService: NewMemberService(repo),
+		BookService:        NewBookService(repo, bus),
+		MemberService:      NewMemberService(repo, bus),
+		IdempotencyService: NewIdempotencyService(repo),
+		HealthService:      NewHealthService(repo),
+		EventService:       NewEventService(repo),
+		WebhookService:     NewWebhookService(repo),
 	}
 }
@@ -1,20 +1,27 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"testing"
 
 	"book-management-system/repositories"
+	"book-management-system/usecases/eventbus"
 )
 
 func TestInitServices(t *testing.T) {
 	repo := &repositories.Repository{}
+	bus := eventbus.New()
 
-	got := Init(repo)
+	got := Init(repo, bus)
 	expected := &Services{
-		BookService:   NewBookService(repo),
-		MemberService: NewMemberService(repo),
+		BookService:        NewBookService(repo, bus),
+		MemberService:      NewMemberService(repo, bus),
+		IdempotencyService: NewIdempotencyService(repo),
+		HealthService:      NewHealthService(repo),
+		EventService:       NewEventService(repo),
+		WebhookService:     NewWebhookService(repo),
 	}
 
 	if !reflect.DeepEqual(got, expected) {
@@ -24,3 +31,13 @@ func TestInitServices(t *testing.T) {
 }
 
 var errRepository = errors.New("repository error")
+
+// recordingBus returns bus appending published events to published
+func recordingBus(published *[]eventbus.Event) *eventbus.Bus {
+	bus := eventbus.New()
+	bus.Subscribe("recorder", func(_ context.Context, event eventbus.Event) error {
+		*published = append(*published, event)
+		return nil
+	})
+	return bus
+}
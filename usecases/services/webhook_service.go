@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"book-management-system/entities/models"
+	"book-management-system/repositories"
+	"book-management-system/repositories/mysql"
+)
+
+const (
+	// webhookSecretBytes is size of generated webhook secrets before hex encoding
+	webhookSecretBytes = 32
+	// deliveryLogLimit is number of newest deliveries returned from the delivery log
+	deliveryLogLimit = 100
+)
+
+// WebhookService handle webhooks of partner systems and their delivery log.
+// Secrets are returned only by CreateWebhook and by UpdateWebhook rotating them.
+type WebhookService interface {
+	GetWebhooks(context.Context) ([]models.Webhook, error)
+	GetWebhook(context.Context, uint) (*models.Webhook, error)
+	CreateWebhook(context.Context, *models.Webhook) error
+	UpdateWebhook(context.Context, *models.Webhook) error
+	DeleteWebhook(context.Context, uint) error
+	GetDeliveries(context.Context, uint, models.WebhookDeliveryStatus) ([]models.WebhookDelivery, error)
+	Redeliver(context.Context, uint) (*models.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	MySQLWebhookRepository mysql.WebhookRepository
+}
+
+// NewWebhookService returns WebhookService
+func NewWebhookService(repo *repositories.Repository) WebhookService {
+	return &webhookService{
+		MySQLWebhookRepository: repo.MySQLWebhookRepository,
+	}
+}
+
+func (svc *webhookService) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	ctx, cancel := setContextTimeout(ctx)
+	defer cancel()
+
+	webhooks, err := svc.MySQLWebhookRepository.GetWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+func (svc *webhookService) GetWebhook(ctx context.Context, id uint) (*models.Webhook, error) {
+	ctx, cancel := setContextTimeout(ctx)
+	defer cancel()
+
+	webhook, err := svc.MySQLWebhookRepository.GetWebhookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// CreateWebhook stores webhook, generating its secret unless one is given
+func (svc *webhookService) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	if webhook.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return err
+		}
+		webhook.Secret = secret
+	}
+	if err := validateWebhook(webhook); err != nil {
+		return err
+	}
+
+	ctx, cancel := setContextTimeout(ctx)
+	defer cancel()
+
+	return svc.MySQLWebhookRepository.CreateWebhook(ctx, webhook)
+}
+
+// UpdateWebhook replaces URL, event types and disabled flag of webhook, secret is rotated when one is given
+func (svc *webhookService) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	ctx, cancel := setContextTimeout(ctx)
+	defer cancel()
+
+	stored, err := svc.MySQLWebhookRepository.GetWebhookByID(ctx, webhook.ID)
+	if err != nil {
+		return err
+	}
+	rotated := webhook.Secret != ""
+	if !rotated {
+		webhook.Secret = stored.Secret
+	}
+	webhook.CreatedAt = stored.CreatedAt
+	if err := validateWebhook(webhook); err != nil {
+		return err
+	}
+
+	if err := svc.MySQLWebhookRepository.UpdateWebhook(ctx, webhook); err != nil {
+		return err
+	}
+	if !rotated {
+		webhook.Secret = ""
+	}
+	return nil
+}
+
+// DeleteWebhook stops deliveries to webhook, its delivery log is kept
+func (svc *webhookService) DeleteWebhook(ctx context.Context, id uint) error {
+	ctx, cancel := setContextTimeout(ctx)
+	defer cancel()
+
+	return svc.MySQLWebhookRepository.DeleteWebhook(ctx, id)
+}
+
+// GetDeliveries returns newest deliveries of webhook, of all webhooks when webhookID is 0,
+// with status, all statuses when it is empty. Dead deliveries are the dead letters waiting for Redeliver.
+func (svc *webhookService) GetDeliveries(
+	ctx context.Context,
+	webhookID uint,
+	status models.WebhookDeliveryStatus,
+) ([]models.WebhookDelivery, error) {
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryDead:
+	default:
+		return nil, fmt.Errorf("%w: unknown delivery status %q", models.ErrInvalid, status)
+	}
+
+	ctx, cancel := setContextTimeout(ctx)
+	defer cancel()
+
+	if webhookID != 0 {
+		if _, err := svc.MySQLWebhookRepository.GetWebhookByID(ctx, webhookID); err != nil {
+			return nil, err
+		}
+	}
+	return svc.MySQLWebhookRepository.GetDeliveries(ctx, webhookID, status, deliveryLogLimit)
+}
+
+// Redeliver sends delivery again as soon as possible with fresh attempts, whatever its status
+func (svc *webhookService) Redeliver(ctx context.Context, id uint) (*models.WebhookDelivery, error) {
+	ctx, cancel := setContextTimeout(ctx)
+	defer cancel()
+
+	delivery, err := svc.MySQLWebhookRepository.GetDeliveryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.NextAttemptAt = time.Now()
+	delivery.Attempts = 0
+	delivery.ResponseCode = 0
+	delivery.Error = ""
+	if err := svc.MySQLWebhookRepository.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// validateWebhook validates webhook and its event types, which models do not know
+func validateWebhook(webhook *models.Webhook) error {
+	if err := webhook.Validate(); err != nil {
+		return err
+	}
+	for _, t := range webhook.EventTypes {
+		if !knownEventType(t) {
+			return fmt.Errorf("%w: unknown event type %q", models.ErrInvalid, t)
+		}
+	}
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(secret), nil
+}
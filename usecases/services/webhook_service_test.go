@@ -0,0 +1,502 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+
+	"book-management-system/entities/models"
+	"book-management-system/events"
+	mySqlMocks "book-management-system/mocks/repositories/mysql"
+	"book-management-system/repositories"
+	"book-management-system/repositories/mysql"
+)
+
+const testWebhookSecret = "0123456789abcdef0123456789abcdef"
+
+func TestNewWebhookService(t *testing.T) {
+	mySQLWebhookRepo := mysql.NewWebhookRepository(nil)
+	repo := &repositories.Repository{
+		MySQLWebhookRepository: mySQLWebhookRepo,
+	}
+
+	got := NewWebhookService(repo)
+	expected := &webhookService{
+		MySQLWebhookRepository: mySQLWebhookRepo,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("NewWebhookService returns %+v\n expected %+v",
+			got, expected)
+	}
+	if _, ok := got.(WebhookService); !ok {
+		t.Errorf("NewWebhookService returns object not implements WebhookService")
+	}
+}
+
+func TestWebhookServiceCreateWebhook(t *testing.T) {
+	type input struct {
+		ctx     context.Context
+		webhook *models.Webhook
+	}
+	type output struct {
+		secretLength int
+		err          error
+	}
+	type mockConfig struct {
+		given                input
+		expected             output
+		mySQLWebhookRepoMock *mySqlMocks.MockWebhookRepository
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success create webhook with generated secret",
+			givenInput: input{
+				ctx: context.TODO(),
+				webhook: &models.Webhook{
+					URL:        "https://accounting.example.com/hooks/library",
+					EventTypes: models.WebhookEventTypes{events.TypeBookCreated},
+				},
+			},
+			expectedOutput: output{
+				secretLength: 2 * webhookSecretBytes,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLWebhookRepoMock.EXPECT().
+					CreateWebhook(gomock.Any(), conf.given.webhook).
+					Return(nil)
+			},
+		},
+		{
+			name: "success create webhook with given secret",
+			givenInput: input{
+				ctx: context.TODO(),
+				webhook: &models.Webhook{
+					URL:    "http://messaging.internal/webhooks",
+					Secret: testWebhookSecret,
+				},
+			},
+			expectedOutput: output{
+				secretLength: len(testWebhookSecret),
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLWebhookRepoMock.EXPECT().
+					CreateWebhook(gomock.Any(), conf.given.webhook).
+					Return(nil)
+			},
+		},
+		{
+			name: "error relative url",
+			givenInput: input{
+				ctx:     context.TODO(),
+				webhook: &models.Webhook{URL: "/hooks/library"},
+			},
+			expectedOutput: output{
+				secretLength: 2 * webhookSecretBytes,
+				err:          models.ErrInvalid,
+			},
+			configureMock: func(conf mockConfig) {},
+		},
+		{
+			name: "error short secret",
+			givenInput: input{
+				ctx:     context.TODO(),
+				webhook: &models.Webhook{URL: "https://accounting.example.com/hooks/library", Secret: "secret"},
+			},
+			expectedOutput: output{
+				secretLength: len("secret"),
+				err:          models.ErrInvalid,
+			},
+			configureMock: func(conf mockConfig) {},
+		},
+		{
+			name: "error unknown event type",
+			givenInput: input{
+				ctx: context.TODO(),
+				webhook: &models.Webhook{
+					URL:        "https://accounting.example.com/hooks/library",
+					EventTypes: models.WebhookEventTypes{"fine.paid"},
+				},
+			},
+			expectedOutput: output{
+				secretLength: 2 * webhookSecretBytes,
+				err:          models.ErrInvalid,
+			},
+			configureMock: func(conf mockConfig) {},
+		},
+		{
+			name: "failed create webhook",
+			givenInput: input{
+				ctx:     context.TODO(),
+				webhook: &models.Webhook{URL: "https://accounting.example.com/hooks/library"},
+			},
+			expectedOutput: output{
+				secretLength: 2 * webhookSecretBytes,
+				err:          errRepository,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLWebhookRepoMock.EXPECT().
+					CreateWebhook(gomock.Any(), conf.given.webhook).
+					Return(conf.expected.err)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mySQLWebhookRepoMock := mySqlMocks.NewMockWebhookRepository(ctrl)
+			webhookService := &webhookService{
+				MySQLWebhookRepository: mySQLWebhookRepoMock,
+			}
+
+			tt.configureMock(mockConfig{
+				given:                tt.givenInput,
+				expected:             tt.expectedOutput,
+				mySQLWebhookRepoMock: mySQLWebhookRepoMock,
+			})
+
+			err := webhookService.CreateWebhook(tt.givenInput.ctx, tt.givenInput.webhook)
+			if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+				t.Errorf("CreateWebhook() got error %+v, expected %+v",
+					err, expectedError)
+			}
+			if got := len(tt.givenInput.webhook.Secret); got != tt.expectedOutput.secretLength {
+				t.Errorf("CreateWebhook() set secret of length %d, expected %d",
+					got, tt.expectedOutput.secretLength)
+			}
+		})
+	}
+}
+
+func TestWebhookServiceUpdateWebhook(t *testing.T) {
+	type input struct {
+		ctx     context.Context
+		webhook *models.Webhook
+	}
+	type output struct {
+		stored *models.Webhook
+		secret string
+		err    error
+	}
+	type mockConfig struct {
+		given                input
+		expected             output
+		mySQLWebhookRepoMock *mySqlMocks.MockWebhookRepository
+	}
+
+	stored := func() *models.Webhook {
+		return &models.Webhook{
+			Model:  gorm.Model{ID: 1},
+			URL:    "https://accounting.example.com/hooks/library",
+			Secret: testWebhookSecret,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success update webhook keeping secret",
+			givenInput: input{
+				ctx: context.TODO(),
+				webhook: &models.Webhook{
+					Model:    gorm.Model{ID: 1},
+					URL:      "https://accounting.example.com/hooks/v2",
+					Disabled: true,
+				},
+			},
+			expectedOutput: output{
+				stored: &models.Webhook{
+					Model:    gorm.Model{ID: 1},
+					URL:      "https://accounting.example.com/hooks/v2",
+					Secret:   testWebhookSecret,
+					Disabled: true,
+				},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLWebhookRepoMock.EXPECT().
+					GetWebhookByID(gomock.Any(), uint(1)).
+					Return(stored(), nil)
+				conf.mySQLWebhookRepoMock.EXPECT().
+					UpdateWebhook(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, webhook *models.Webhook) error {
+						if !reflect.DeepEqual(webhook, conf.expected.stored) {
+							t.Errorf("UpdateWebhook() stored %+v, expected %+v", webhook, conf.expected.stored)
+						}
+						return nil
+					})
+			},
+		},
+		{
+			name: "success rotate secret",
+			givenInput: input{
+				ctx: context.TODO(),
+				webhook: &models.Webhook{
+					Model:  gorm.Model{ID: 1},
+					URL:    "https://accounting.example.com/hooks/library",
+					Secret: "fedcba9876543210fedcba9876543210",
+				},
+			},
+			expectedOutput: output{
+				secret: "fedcba9876543210fedcba9876543210",
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLWebhookRepoMock.EXPECT().
+					GetWebhookByID(gomock.Any(), uint(1)).
+					Return(stored(), nil)
+				conf.mySQLWebhookRepoMock.EXPECT().
+					UpdateWebhook(gomock.Any(), conf.given.webhook).
+					Return(nil)
+			},
+		},
+		{
+			name: "error not found",
+			givenInput: input{
+				ctx:     context.TODO(),
+				webhook: &models.Webhook{Model: gorm.Model{ID: 2}, URL: "https://accounting.example.com/hooks"},
+			},
+			expectedOutput: output{
+				err: models.ErrNotFound,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLWebhookRepoMock.EXPECT().
+					GetWebhookByID(gomock.Any(), uint(2)).
+					Return(nil, models.ErrNotFound)
+			},
+		},
+		{
+			name: "error invalid url",
+			givenInput: input{
+				ctx:     context.TODO(),
+				webhook: &models.Webhook{Model: gorm.Model{ID: 1}, URL: "ftp://accounting.example.com/hooks"},
+			},
+			expectedOutput: output{
+				secret: testWebhookSecret,
+				err:    models.ErrInvalid,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLWebhookRepoMock.EXPECT().
+					GetWebhookByID(gomock.Any(), uint(1)).
+					Return(stored(), nil)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mySQLWebhookRepoMock := mySqlMocks.NewMockWebhookRepository(ctrl)
+			webhookService := &webhookService{
+				MySQLWebhookRepository: mySQLWebhookRepoMock,
+			}
+
+			tt.configureMock(mockConfig{
+				given:                tt.givenInput,
+				expected:             tt.expectedOutput,
+				mySQLWebhookRepoMock: mySQLWebhookRepoMock,
+			})
+
+			err := webhookService.UpdateWebhook(tt.givenInput.ctx, tt.givenInput.webhook)
+			if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+				t.Errorf("UpdateWebhook() got error %+v, expected %+v",
+					err, expectedError)
+			}
+			if got := tt.givenInput.webhook.Secret; got != tt.expectedOutput.secret {
+				t.Errorf("UpdateWebhook() returns secret %q, expected %q", got, tt.expectedOutput.secret)
+			}
+		})
+	}
+}
+
+func TestWebhookServiceGetWebhooksHidesSecrets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mySQLWebhookRepoMock := mySqlMocks.NewMockWebhookRepository(ctrl)
+	mySQLWebhookRepoMock.EXPECT().
+		GetWebhooks(gomock.Any()).
+		Return([]models.Webhook{{Model: gorm.Model{ID: 1}, Secret: testWebhookSecret}}, nil)
+	mySQLWebhookRepoMock.EXPECT().
+		GetWebhookByID(gomock.Any(), uint(1)).
+		Return(&models.Webhook{Model: gorm.Model{ID: 1}, Secret: testWebhookSecret}, nil)
+	webhookService := &webhookService{
+		MySQLWebhookRepository: mySQLWebhookRepoMock,
+	}
+
+	webhooks, err := webhookService.GetWebhooks(context.TODO())
+	if err != nil || len(webhooks) != 1 || webhooks[0].Secret != "" {
+		t.Errorf("GetWebhooks() returns %+v, %v\n expected webhook without secret", webhooks, err)
+	}
+	webhook, err := webhookService.GetWebhook(context.TODO(), 1)
+	if err != nil || webhook.Secret != "" {
+		t.Errorf("GetWebhook() returns %+v, %v\n expected webhook without secret", webhook, err)
+	}
+}
+
+func TestWebhookServiceGetDeliveries(t *testing.T) {
+	type input struct {
+		ctx       context.Context
+		webhookID uint
+		status    models.WebhookDeliveryStatus
+	}
+	type output struct {
+		deliveries []models.WebhookDelivery
+		err        error
+	}
+	type mockConfig struct {
+		given                input
+		expected             output
+		mySQLWebhookRepoMock *mySqlMocks.MockWebhookRepository
+	}
+
+	tests := []struct {
+		name           string
+		givenInput     input
+		expectedOutput output
+		configureMock  func(mockConfig)
+	}{
+		{
+			name: "success get dead letters",
+			givenInput: input{
+				ctx:    context.TODO(),
+				status: models.WebhookDeliveryDead,
+			},
+			expectedOutput: output{
+				deliveries: []models.WebhookDelivery{{ID: 1, Status: models.WebhookDeliveryDead}},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLWebhookRepoMock.EXPECT().
+					GetDeliveries(gomock.Any(), uint(0), conf.given.status, deliveryLogLimit).
+					Return(conf.expected.deliveries, nil)
+			},
+		},
+		{
+			name: "success get deliveries of webhook",
+			givenInput: input{
+				ctx:       context.TODO(),
+				webhookID: 1,
+			},
+			expectedOutput: output{
+				deliveries: []models.WebhookDelivery{{ID: 1, WebhookID: 1, Status: models.WebhookDeliverySucceeded}},
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLWebhookRepoMock.EXPECT().
+					GetWebhookByID(gomock.Any(), conf.given.webhookID).
+					Return(&models.Webhook{Model: gorm.Model{ID: 1}}, nil)
+				conf.mySQLWebhookRepoMock.EXPECT().
+					GetDeliveries(gomock.Any(), conf.given.webhookID, models.WebhookDeliveryStatus(""), deliveryLogLimit).
+					Return(conf.expected.deliveries, nil)
+			},
+		},
+		{
+			name: "error webhook not found",
+			givenInput: input{
+				ctx:       context.TODO(),
+				webhookID: 2,
+			},
+			expectedOutput: output{
+				err: models.ErrNotFound,
+			},
+			configureMock: func(conf mockConfig) {
+				conf.mySQLWebhookRepoMock.EXPECT().
+					GetWebhookByID(gomock.Any(), conf.given.webhookID).
+					Return(nil, models.ErrNotFound)
+			},
+		},
+		{
+			name: "error unknown status",
+			givenInput: input{
+				ctx:    context.TODO(),
+				status: "failed",
+			},
+			expectedOutput: output{
+				err: models.ErrInvalid,
+			},
+			configureMock: func(conf mockConfig) {},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mySQLWebhookRepoMock := mySqlMocks.NewMockWebhookRepository(ctrl)
+			webhookService := &webhookService{
+				MySQLWebhookRepository: mySQLWebhookRepoMock,
+			}
+
+			tt.configureMock(mockConfig{
+				given:                tt.givenInput,
+				expected:             tt.expectedOutput,
+				mySQLWebhookRepoMock: mySQLWebhookRepoMock,
+			})
+
+			deliveries, err := webhookService.GetDeliveries(tt.givenInput.ctx, tt.givenInput.webhookID,
+				tt.givenInput.status)
+			if expectedError := tt.expectedOutput.err; !errors.Is(err, expectedError) {
+				t.Errorf("GetDeliveries() got error %+v, expected %+v",
+					err, expectedError)
+			}
+			if !reflect.DeepEqual(deliveries, tt.expectedOutput.deliveries) {
+				t.Errorf("GetDeliveries() returns %+v, expected %+v", deliveries, tt.expectedOutput.deliveries)
+			}
+		})
+	}
+}
+
+func TestWebhookServiceRedeliver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mySQLWebhookRepoMock := mySqlMocks.NewMockWebhookRepository(ctrl)
+	mySQLWebhookRepoMock.EXPECT().
+		GetDeliveryByID(gomock.Any(), uint(1)).
+		Return(&models.WebhookDelivery{
+			ID:           1,
+			Status:       models.WebhookDeliveryDead,
+			Attempts:     8,
+			ResponseCode: 500,
+			Error:        "unexpected response status 500 Internal Server Error",
+		}, nil)
+	mySQLWebhookRepoMock.EXPECT().
+		UpdateDelivery(gomock.Any(), gomock.Any()).
+		Return(nil)
+	mySQLWebhookRepoMock.EXPECT().
+		GetDeliveryByID(gomock.Any(), uint(2)).
+		Return(nil, models.ErrNotFound)
+	webhookService := &webhookService{
+		MySQLWebhookRepository: mySQLWebhookRepoMock,
+	}
+
+	delivery, err := webhookService.Redeliver(context.TODO(), 1)
+	if err != nil {
+		t.Fatalf("Redeliver() got error %+v", err)
+	}
+	if delivery.Status != models.WebhookDeliveryPending || delivery.Attempts != 0 ||
+		delivery.ResponseCode != 0 || delivery.Error != "" || delivery.NextAttemptAt.IsZero() {
+		t.Errorf("Redeliver() returns %+v\n expected pending delivery without attempts due now", delivery)
+	}
+
+	if _, err := webhookService.Redeliver(context.TODO(), 2); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("Redeliver() of unknown delivery got error %+v, expected %+v", err, models.ErrNotFound)
+	}
+}
@@ -0,0 +1,86 @@
+package usecases
+
+import (
+	"context"
+
+	"book-management-system/cache"
+	"book-management-system/events"
+	"book-management-system/metrics"
+	"book-management-system/repositories"
+	"book-management-system/repositories/elasticsearch"
+	"book-management-system/tracing"
+	"book-management-system/usecases/eventbus"
+)
+
+// subscribe registers reactions to domain events published by services and pipelines.
+// Cached reads are dropped before the change is fed to subscribers of the process, e.g. webhooks,
+// so that those fetching the changed book do not read it from cache.
+func subscribe(bus *eventbus.Bus, repo *repositories.Repository) {
+	bookCache := cache.NewNamespace(repo.Cache, cache.NamespaceBooks)
+
+	bus.Subscribe("book cache", invalidateBookCache(bookCache),
+		events.TypeBookCreated, events.TypeBookUpdated, events.TypeBookDeleted)
+	bus.Subscribe("change feed", publishToFeed(repo.Events))
+	bus.SubscribeAsync(metrics.TaskSearchIndex, indexBook(repo.ESBookRepository, bookCache),
+		events.TypeBookCreated, events.TypeBookUpdated, events.TypeBookDeleted)
+}
+
+func invalidateBookCache(bookCache cache.Namespace) eventbus.Handler {
+	return func(ctx context.Context, _ eventbus.Event) error {
+		return bookCache.Invalidate(ctx)
+	}
+}
+
+// publishToFeed publishes events to the change feed with the changed entity as data
+func publishToFeed(feed *events.Feed) eventbus.Handler {
+	return func(_ context.Context, event eventbus.Event) error {
+		var data interface{}
+		switch e := event.(type) {
+		case eventbus.BookCreated:
+			data = e.Book
+		case eventbus.BookUpdated:
+			data = e.Book
+		case eventbus.BookDeleted:
+			data = events.Deletion{ID: e.ID, MergedInto: e.MergedInto}
+		case eventbus.MemberCreated:
+			data = e.Member
+		case eventbus.MemberUpdated:
+			data = e.Member
+		default:
+			return nil
+		}
+		return feed.Publish(event.Name(), data)
+	}
+}
+
+// indexBook keeps the search index in line with stored books, unless the publisher indexed them already
+func indexBook(search elasticsearch.BookRepository, bookCache cache.Namespace) eventbus.Handler {
+	return func(ctx context.Context, event eventbus.Event) error {
+		ctx, span := tracing.Tracer().Start(ctx, "index book")
+		defer span.End()
+
+		var err error
+		switch e := event.(type) {
+		case eventbus.BookCreated:
+			if e.Indexed {
+				return nil
+			}
+			err = search.IndexBook(ctx, &e.Book)
+		case eventbus.BookUpdated:
+			if e.Indexed {
+				return nil
+			}
+			err = search.IndexBook(ctx, &e.Book)
+		case eventbus.BookDeleted:
+			err = search.DeleteBook(ctx, e.ID)
+		default:
+			return nil
+		}
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+		// search results cached before the book was indexed miss it
+		return bookCache.Invalidate(ctx)
+	}
+}
@@ -0,0 +1,91 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+
+	"book-management-system/cache"
+	"book-management-system/entities/models"
+	"book-management-system/events"
+	esMocks "book-management-system/mocks/repositories/elasticsearch"
+	"book-management-system/usecases/eventbus"
+)
+
+func TestIndexBook(t *testing.T) {
+	book := models.Book{Model: gorm.Model{ID: 1}, Name: "The Alchemist", ISBN: "9780062315007"}
+	errSearch := errors.New("search error")
+
+	tests := []struct {
+		name          string
+		event         eventbus.Event
+		err           error
+		configureMock func(*esMocks.MockBookRepository)
+	}{
+		{
+			name:  "created book is indexed",
+			event: eventbus.BookCreated{Book: book},
+			configureMock: func(mock *esMocks.MockBookRepository) {
+				mock.EXPECT().IndexBook(gomock.Any(), &book).Return(nil)
+			},
+		},
+		{
+			name:          "book indexed by publisher is skipped",
+			event:         eventbus.BookUpdated{Book: book, Indexed: true},
+			configureMock: func(*esMocks.MockBookRepository) {},
+		},
+		{
+			name:  "deleted book is removed",
+			event: eventbus.BookDeleted{ID: 2, MergedInto: 1},
+			err:   errSearch,
+			configureMock: func(mock *esMocks.MockBookRepository) {
+				mock.EXPECT().DeleteBook(gomock.Any(), uint(2)).Return(errSearch)
+			},
+		},
+		{
+			name:          "member events are ignored",
+			event:         eventbus.MemberCreated{Member: models.Member{Name: "John Lennon"}},
+			configureMock: func(*esMocks.MockBookRepository) {},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esBookRepoMock := esMocks.NewMockBookRepository(ctrl)
+			tt.configureMock(esBookRepoMock)
+
+			handler := indexBook(esBookRepoMock, cache.NewNamespace(cache.NewLRU(10), cache.NamespaceBooks))
+			if err := handler(context.TODO(), tt.event); !errors.Is(err, tt.err) {
+				t.Errorf("indexBook() got error %v\n expected %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestPublishToFeed(t *testing.T) {
+	feed := events.NewFeed(10)
+	sub := feed.Subscribe(nil, 0, 10)
+	handler := publishToFeed(feed)
+
+	_ = handler(context.TODO(), eventbus.BookDeleted{ID: 2, MergedInto: 1})
+	_ = handler(context.TODO(), eventbus.MemberUpdated{Member: models.Member{Model: gorm.Model{ID: 3}, Name: "Paul"}})
+	sub.Close()
+
+	var got []events.Event
+	for event := range sub.Events() {
+		got = append(got, event)
+	}
+	if len(got) != 2 || got[0].Type != events.TypeBookDeleted || got[1].Type != events.TypeMemberUpdated {
+		t.Fatalf("publishToFeed() published %+v\n expected %s and %s events",
+			got, events.TypeBookDeleted, events.TypeMemberUpdated)
+	}
+	if expected := `{"id":2,"merged_into":1}`; string(got[0].Data) != expected {
+		t.Errorf("publishToFeed() published deletion %s\n expected %s", got[0].Data, expected)
+	}
+}
@@ -3,17 +3,24 @@ package usecases
 
 import (
 	"book-management-system/repositories"
+	"book-management-system/usecases/eventbus"
+	"book-management-system/usecases/pipelines"
 	"book-management-system/usecases/services"
 )
 
 // UseCase contains usecases
 type UseCase struct {
-	Service *services.Services
+	Service  *services.Services
+	Pipeline *pipelines.Pipelines
 }
 
-// Init returns UseCase
+// Init returns UseCase, domain events of services and pipelines are dispatched by one event bus
 func Init(repo *repositories.Repository) *UseCase {
+	bus := eventbus.New()
+	subscribe(bus, repo)
+
 	return &UseCase{
-		Service: services.Init(repo),
+		Service:  services.Init(repo, bus),
+		Pipeline: pipelines.Init(repo, bus),
 	}
 }